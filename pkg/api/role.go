@@ -24,3 +24,22 @@ func GetRole(s string) Role {
 	}
 
 }
+
+func rolePriority(r Role) int {
+	switch r {
+	case ViewerRole:
+		return 0
+	case EditorRole:
+		return 1
+	case AdminRole:
+		return 2
+	default:
+		return -1
+	}
+}
+
+// AtLeast reports whether r has at least the privileges of min. Unknown roles have none.
+func (r Role) AtLeast(min Role) bool {
+	p := rolePriority(r)
+	return p >= 0 && p >= rolePriority(min)
+}
@@ -0,0 +1,257 @@
+// Package describetest provides an in-memory DescribeService and EsSinkService for
+// integration-testing describers without the real scheduler.
+package describetest
+
+import (
+	"context"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	golang "github.com/opengovern/og-util/proto/src/golang"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+// Method names accepted by Server.InjectFault.
+const (
+	MethodDeliverResult = "DeliverResult"
+	MethodSetInProgress = "SetInProgress"
+	MethodIngest        = "Ingest"
+)
+
+const bufSize = 1024 * 1024
+
+// Fault describes a failure injected into a method.
+type Fault struct {
+	Delay time.Duration // Wait before handling (honors the call context)
+	Drop  bool          // Acknowledge the call but do not record it
+	Code  codes.Code    // Fail with this status code if not codes.OK
+	// Times limits the fault to the next N calls; 0 applies it to every call.
+	Times int
+}
+
+// Server records every call it receives. It implements both DescribeServiceServer and
+// EsSinkServiceServer. The zero value is not usable; use NewServer.
+type Server struct {
+	golang.UnimplementedDescribeServiceServer
+	golang.UnimplementedEsSinkServiceServer
+
+	mu         sync.Mutex
+	changed    chan struct{} // Closed and replaced whenever a call is recorded
+	results    []*golang.DeliverResultRequest
+	inProgress []uint32
+	docs       []*anypb.Any
+	faults     map[string]*Fault
+}
+
+func NewServer() *Server {
+	return &Server{
+		changed: make(chan struct{}),
+		faults:  make(map[string]*Fault),
+	}
+}
+
+// Start serves s over an in-memory listener until the test ends and returns a client
+// connection to it.
+func (s *Server) Start(t testing.TB) *grpc.ClientConn {
+	t.Helper()
+
+	listener := bufconn.Listen(bufSize)
+	grpcServer := grpc.NewServer()
+	golang.RegisterDescribeServiceServer(grpcServer, s)
+	golang.RegisterEsSinkServiceServer(grpcServer, s)
+	go func() {
+		_ = grpcServer.Serve(listener)
+	}()
+
+	conn, err := grpc.NewClient("passthrough:///bufnet",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) {
+			return listener.DialContext(ctx)
+		}),
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+	)
+	if err != nil {
+		t.Fatalf("describetest: dial mock server: %v", err)
+	}
+	t.Cleanup(func() {
+		_ = conn.Close()
+		grpcServer.Stop()
+	})
+	return conn
+}
+
+// InjectFault applies fault to subsequent calls of method. A nil fault clears it.
+func (s *Server) InjectFault(method string, fault *Fault) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if fault == nil {
+		delete(s.faults, method)
+		return
+	}
+	f := *fault
+	s.faults[method] = &f
+}
+
+func (s *Server) DeliverResult(ctx context.Context, req *golang.DeliverResultRequest) (*golang.ResponseOK, error) {
+	return s.handle(ctx, MethodDeliverResult, func() {
+		s.results = append(s.results, proto.Clone(req).(*golang.DeliverResultRequest))
+	})
+}
+
+func (s *Server) SetInProgress(ctx context.Context, req *golang.SetInProgressRequest) (*golang.ResponseOK, error) {
+	return s.handle(ctx, MethodSetInProgress, func() {
+		s.inProgress = append(s.inProgress, req.GetJobId())
+	})
+}
+
+func (s *Server) Ingest(ctx context.Context, req *golang.IngestRequest) (*golang.ResponseOK, error) {
+	return s.handle(ctx, MethodIngest, func() {
+		for _, doc := range req.GetDocs() {
+			s.docs = append(s.docs, proto.Clone(doc).(*anypb.Any))
+		}
+	})
+}
+
+// handle applies any fault for method and otherwise runs record under the lock.
+func (s *Server) handle(ctx context.Context, method string, record func()) (*golang.ResponseOK, error) {
+	fault := s.takeFault(method)
+	if fault != nil && fault.Delay > 0 {
+		select {
+		case <-time.After(fault.Delay):
+		case <-ctx.Done():
+			return nil, status.FromContextError(ctx.Err()).Err()
+		}
+	}
+	if fault != nil && fault.Code != codes.OK {
+		return nil, status.Errorf(fault.Code, "describetest: injected %s fault", method)
+	}
+	if fault != nil && fault.Drop {
+		return &golang.ResponseOK{}, nil
+	}
+
+	s.mu.Lock()
+	record()
+	close(s.changed)
+	s.changed = make(chan struct{})
+	s.mu.Unlock()
+	return &golang.ResponseOK{}, nil
+}
+
+func (s *Server) takeFault(method string) *Fault {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	fault, ok := s.faults[method]
+	if !ok {
+		return nil
+	}
+	f := *fault
+	if fault.Times > 0 {
+		fault.Times--
+		if fault.Times == 0 {
+			delete(s.faults, method)
+		}
+	}
+	return &f
+}
+
+// Results returns the recorded DeliverResult requests in arrival order.
+func (s *Server) Results() []*golang.DeliverResultRequest {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*golang.DeliverResultRequest(nil), s.results...)
+}
+
+// InProgressJobs returns the job ids recorded by SetInProgress in arrival order.
+func (s *Server) InProgressJobs() []uint32 {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]uint32(nil), s.inProgress...)
+}
+
+// IngestedDocs returns every document recorded by Ingest in arrival order.
+func (s *Server) IngestedDocs() []*anypb.Any {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return append([]*anypb.Any(nil), s.docs...)
+}
+
+// Reset clears all recorded calls and faults.
+func (s *Server) Reset() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.results, s.inProgress, s.docs = nil, nil, nil
+	s.faults = make(map[string]*Fault)
+}
+
+// WaitForResult blocks until a DeliverResult for jobID is recorded and returns it,
+// failing the test after timeout.
+func (s *Server) WaitForResult(t testing.TB, jobID uint32, timeout time.Duration) *golang.DeliverResultRequest {
+	t.Helper()
+	deadline := time.After(timeout)
+	for {
+		s.mu.Lock()
+		for _, r := range s.results {
+			if r.GetJobId() == jobID {
+				s.mu.Unlock()
+				return r
+			}
+		}
+		changed := s.changed
+		s.mu.Unlock()
+
+		select {
+		case <-changed:
+		case <-deadline:
+			t.Fatalf("describetest: no result for job %d within %s", jobID, timeout)
+			return nil
+		}
+	}
+}
+
+// AssertResultStatus fails the test unless the last recorded result for jobID has status.
+func (s *Server) AssertResultStatus(t testing.TB, jobID uint32, status string) {
+	t.Helper()
+	results := s.Results()
+	for i := len(results) - 1; i >= 0; i-- {
+		if results[i].GetJobId() != jobID {
+			continue
+		}
+		if results[i].GetStatus() != status {
+			t.Errorf("describetest: job %d status = %q, want %q (error: %q)", jobID, results[i].GetStatus(), status, results[i].GetError())
+		}
+		return
+	}
+	t.Errorf("describetest: no result recorded for job %d", jobID)
+}
+
+// AssertDescribedResources fails the test unless the last recorded result for jobID
+// reports exactly count described resource ids.
+func (s *Server) AssertDescribedResources(t testing.TB, jobID uint32, count int) {
+	t.Helper()
+	results := s.Results()
+	for i := len(results) - 1; i >= 0; i-- {
+		if results[i].GetJobId() != jobID {
+			continue
+		}
+		if got := len(results[i].GetDescribedResourceIds()); got != count {
+			t.Errorf("describetest: job %d described %d resources, want %d", jobID, got, count)
+		}
+		return
+	}
+	t.Errorf("describetest: no result recorded for job %d", jobID)
+}
+
+// AssertIngestedCount fails the test unless exactly count documents were ingested.
+func (s *Server) AssertIngestedCount(t testing.TB, count int) {
+	t.Helper()
+	if got := len(s.IngestedDocs()); got != count {
+		t.Errorf("describetest: ingested %d documents, want %d", got, count)
+	}
+}
@@ -0,0 +1,34 @@
+package describetest_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/opengovern/og-util/pkg/describe/describetest"
+	golang "github.com/opengovern/og-util/proto/src/golang"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestServerRecordsAndInjectsFaults(t *testing.T) {
+	require := require.New(t)
+	server := describetest.NewServer()
+	client := golang.NewDescribeServiceClient(server.Start(t))
+	ctx := context.Background()
+
+	server.InjectFault(describetest.MethodDeliverResult, &describetest.Fault{Code: codes.Unavailable, Times: 1})
+	_, err := client.DeliverResult(ctx, &golang.DeliverResultRequest{JobId: 1, Status: "SUCCEEDED"})
+	require.Equal(codes.Unavailable, status.Code(err))
+
+	_, err = client.SetInProgress(ctx, &golang.SetInProgressRequest{JobId: 1})
+	require.NoError(err)
+	_, err = client.DeliverResult(ctx, &golang.DeliverResultRequest{JobId: 1, Status: "SUCCEEDED", DescribedResourceIds: []string{"a", "b"}})
+	require.NoError(err)
+
+	require.Equal(uint32(1), server.WaitForResult(t, 1, time.Second).GetJobId())
+	require.Equal([]uint32{1}, server.InProgressJobs())
+	server.AssertResultStatus(t, 1, "SUCCEEDED")
+	server.AssertDescribedResources(t, 1, 2)
+}
@@ -0,0 +1,282 @@
+package describe
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	golang "github.com/opengovern/og-util/proto/src/golang"
+	describeproto "github.com/opengovern/og-util/proto/src/golang/describe"
+	describev2 "github.com/opengovern/og-util/proto/src/golang/describe/v2"
+)
+
+// ExtraInputs keys that carry the region and parent of a fanned-out child job to the
+// describer.
+const (
+	ExtraInputPartition   = "partition"
+	ExtraInputRegion      = "region"
+	ExtraInputParentJobID = "parent_job_id"
+)
+
+// Region identifies a describe target region, following the partition/region model of
+// AWSResource. Partition may be empty for providers without partitions.
+type Region struct {
+	Partition string
+	Name      string
+}
+
+func (r Region) String() string {
+	if r.Partition == "" {
+		return r.Name
+	}
+	return r.Partition + "/" + r.Name
+}
+
+// ChildRegion returns the region a fanned-out child job should describe, or false if the
+// input is not a child of a fan-out.
+func ChildRegion(input DescribeWorkerInput) (Region, bool) {
+	name, ok := input.ExtraInputs[ExtraInputRegion]
+	if !ok || name == "" {
+		return Region{}, false
+	}
+	return Region{Partition: input.ExtraInputs[ExtraInputPartition], Name: name}, true
+}
+
+// ParentJobID returns the job ID of the fan-out parent of input, for the child to report
+// as parent_job_id in its results, or false if the input is not a child of a fan-out.
+func ParentJobID(input DescribeWorkerInput) (uint, bool) {
+	id, err := strconv.ParseUint(input.ExtraInputs[ExtraInputParentJobID], 10, 0)
+	if err != nil {
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// ChildJob is one per-region job created by FanOut.
+type ChildJob struct {
+	Region Region
+	Input  DescribeWorkerInput
+}
+
+// FanOutOptions configures FanOut.
+type FanOutOptions struct {
+	Regions []Region
+	// NextJobID allocates the job ID of each child, e.g. from the scheduler's database.
+	NextJobID func() (uint, error)
+}
+
+// FanOutJob tracks the children of a describe job expanded per region and aggregates their
+// DeliverResult statuses into one result for the parent. It is safe for concurrent use.
+type FanOutJob struct {
+	parent   DescribeWorkerInput
+	children []ChildJob
+
+	mu      sync.Mutex
+	results map[uint]*describev2.DeliverResultRequest
+}
+
+// FanOut expands parent into one child job per region. Each child copies the parent's
+// input with a new job ID, and its region and the parent's job ID in ExtraInputs (see
+// ChildRegion and ParentJobID).
+func FanOut(parent DescribeWorkerInput, opts FanOutOptions) (*FanOutJob, error) {
+	if len(opts.Regions) == 0 {
+		return nil, errors.New("fan-out requires at least one region")
+	}
+	if opts.NextJobID == nil {
+		return nil, errors.New("fan-out requires NextJobID")
+	}
+	f := &FanOutJob{
+		parent:  parent,
+		results: make(map[uint]*describev2.DeliverResultRequest),
+	}
+	seen := make(map[Region]bool, len(opts.Regions))
+	for _, region := range opts.Regions {
+		if region.Name == "" {
+			return nil, errors.New("fan-out region name cannot be empty")
+		}
+		if seen[region] {
+			continue
+		}
+		seen[region] = true
+
+		jobID, err := opts.NextJobID()
+		if err != nil {
+			return nil, fmt.Errorf("allocate job id for region %s: %w", region, err)
+		}
+		input := parent
+		input.DescribeJob.JobID = jobID
+		input.DescribeJob.IntegrationLabels = copyStringMap(parent.DescribeJob.IntegrationLabels)
+		input.DescribeJob.IntegrationAnnotations = copyStringMap(parent.DescribeJob.IntegrationAnnotations)
+		input.ExtraInputs = copyStringMap(parent.ExtraInputs)
+		if input.ExtraInputs == nil {
+			input.ExtraInputs = make(map[string]string, 3)
+		}
+		input.ExtraInputs[ExtraInputParentJobID] = strconv.FormatUint(uint64(parent.DescribeJob.JobID), 10)
+		input.ExtraInputs[ExtraInputRegion] = region.Name
+		if region.Partition != "" {
+			input.ExtraInputs[ExtraInputPartition] = region.Partition
+		}
+		f.children = append(f.children, ChildJob{Region: region, Input: input})
+	}
+	return f, nil
+}
+
+// Children returns the child jobs in region order.
+func (f *FanOutJob) Children() []ChildJob {
+	return append([]ChildJob(nil), f.children...)
+}
+
+// Deliver records the final result of a child. In-progress results are ignored; a later
+// result for the same child replaces the earlier one, e.g. after a retry.
+func (f *FanOutJob) Deliver(result *describev2.DeliverResultRequest) error {
+	if result == nil {
+		return errors.New("nil deliver result")
+	}
+	jobID := uint(result.GetJobId())
+	if f.child(jobID) == nil {
+		return fmt.Errorf("job %d is not a child of fan-out job %d", jobID, f.parent.DescribeJob.JobID)
+	}
+	switch result.GetStatus() {
+	case describev2.DescribeJobStatus_DESCRIBE_JOB_STATUS_UNSPECIFIED,
+		describev2.DescribeJobStatus_DESCRIBE_JOB_STATUS_IN_PROGRESS:
+		return nil
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.results[jobID] = result
+	return nil
+}
+
+// DeliverV1 records a v1 result; see Deliver.
+func (f *FanOutJob) DeliverV1(result *golang.DeliverResultRequest) error {
+	if result == nil {
+		return errors.New("nil deliver result")
+	}
+	return f.Deliver(describeproto.UpgradeDeliverResult(result))
+}
+
+// Done reports whether every child has delivered a final result.
+func (f *FanOutJob) Done() bool {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return len(f.results) == len(f.children)
+}
+
+// Pending returns the children that have not delivered a final result yet.
+func (f *FanOutJob) Pending() []ChildJob {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var pending []ChildJob
+	for _, c := range f.children {
+		if _, ok := f.results[c.Input.DescribeJob.JobID]; !ok {
+			pending = append(pending, c)
+		}
+	}
+	return pending
+}
+
+// Result aggregates the children into a result for the parent job. The status is
+// IN_PROGRESS until all children are done, then FAILED if any child failed, TIMEOUT if
+// any timed out, and SUCCEEDED otherwise. Errors are prefixed with their region and the
+// aggregate error is retryable only if every failed child is.
+func (f *FanOutJob) Result() *describev2.DeliverResultRequest {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	out := &describev2.DeliverResultRequest{
+		JobId:  uint32(f.parent.DescribeJob.JobID),
+		Status: describev2.DescribeJobStatus_DESCRIBE_JOB_STATUS_SUCCEEDED,
+	}
+	var failed, timedOut bool
+	retryable := true
+	var messages, codes []string
+	seenIDs := make(map[string]bool)
+	for _, c := range f.children {
+		result, ok := f.results[c.Input.DescribeJob.JobID]
+		if !ok {
+			continue
+		}
+		for _, id := range result.GetDescribedResourceIds() {
+			if !seenIDs[id] {
+				seenIDs[id] = true
+				out.DescribedResourceIds = append(out.DescribedResourceIds, id)
+			}
+		}
+		switch result.GetStatus() {
+		case describev2.DescribeJobStatus_DESCRIBE_JOB_STATUS_FAILED:
+			failed = true
+		case describev2.DescribeJobStatus_DESCRIBE_JOB_STATUS_TIMEOUT:
+			timedOut = true
+		default:
+			continue
+		}
+		if e := result.GetError(); e != nil {
+			messages = append(messages, fmt.Sprintf("%s: %s", c.Region, e.GetMessage()))
+			if e.GetCode() != "" {
+				codes = append(codes, e.GetCode())
+			}
+			retryable = retryable && e.GetRetryable()
+		} else {
+			messages = append(messages, fmt.Sprintf("%s: %s", c.Region, strings.ToLower(strings.TrimPrefix(result.GetStatus().String(), "DESCRIBE_JOB_STATUS_"))))
+			retryable = false
+		}
+	}
+
+	switch {
+	case len(f.results) < len(f.children):
+		out.Status = describev2.DescribeJobStatus_DESCRIBE_JOB_STATUS_IN_PROGRESS
+	case failed:
+		out.Status = describev2.DescribeJobStatus_DESCRIBE_JOB_STATUS_FAILED
+	case timedOut:
+		out.Status = describev2.DescribeJobStatus_DESCRIBE_JOB_STATUS_TIMEOUT
+	}
+	if len(messages) > 0 {
+		out.Error = &describev2.DescribeError{
+			Code:      strings.Join(uniqueSorted(codes), ","),
+			Message:   strings.Join(messages, "; "),
+			Retryable: retryable,
+		}
+	}
+	return out
+}
+
+// ResultV1 is Result converted for v1 describe servers.
+func (f *FanOutJob) ResultV1() *golang.DeliverResultRequest {
+	return describeproto.DowngradeDeliverResult(f.Result())
+}
+
+func (f *FanOutJob) child(jobID uint) *ChildJob {
+	for i := range f.children {
+		if f.children[i].Input.DescribeJob.JobID == jobID {
+			return &f.children[i]
+		}
+	}
+	return nil
+}
+
+func copyStringMap(in map[string]string) map[string]string {
+	if in == nil {
+		return nil
+	}
+	out := make(map[string]string, len(in))
+	for k, v := range in {
+		out[k] = v
+	}
+	return out
+}
+
+func uniqueSorted(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	var out []string
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	sort.Strings(out)
+	return out
+}
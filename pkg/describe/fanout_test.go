@@ -0,0 +1,60 @@
+package describe_test
+
+import (
+	"testing"
+
+	"github.com/opengovern/og-util/pkg/describe"
+	golang "github.com/opengovern/og-util/proto/src/golang"
+	describev2 "github.com/opengovern/og-util/proto/src/golang/describe/v2"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFanOutAggregatesChildResults(t *testing.T) {
+	require := require.New(t)
+	nextID := uint(100)
+	parent := describe.DescribeWorkerInput{
+		DescribeJob: describe.DescribeJob{JobID: 1, ResourceType: "AWS::EC2::Instance"},
+		ExtraInputs: map[string]string{"k": "v"},
+	}
+	job, err := describe.FanOut(parent, describe.FanOutOptions{
+		Regions: []describe.Region{{Partition: "aws", Name: "us-east-1"}, {Partition: "aws", Name: "eu-west-1"}},
+		NextJobID: func() (uint, error) {
+			nextID++
+			return nextID, nil
+		},
+	})
+	require.NoError(err)
+	children := job.Children()
+	require.Len(children, 2)
+	require.Empty(parent.ExtraInputs["region"], "parent inputs must not be modified")
+	region, ok := describe.ChildRegion(children[1].Input)
+	require.True(ok)
+	require.Equal("aws/eu-west-1", region.String())
+	for _, child := range children {
+		parentID, ok := describe.ParentJobID(child.Input)
+		require.True(ok)
+		require.Equal(uint(1), parentID)
+	}
+	_, ok = describe.ParentJobID(parent)
+	require.False(ok)
+
+	require.NoError(job.DeliverV1(&golang.DeliverResultRequest{JobId: 101, Status: "SUCCEEDED", DescribedResourceIds: []string{"a", "b"}}))
+	require.False(job.Done())
+	require.Equal(describev2.DescribeJobStatus_DESCRIBE_JOB_STATUS_IN_PROGRESS, job.Result().GetStatus())
+
+	require.NoError(job.Deliver(&describev2.DeliverResultRequest{
+		JobId:                102,
+		Status:               describev2.DescribeJobStatus_DESCRIBE_JOB_STATUS_FAILED,
+		Error:                &describev2.DescribeError{Code: "Throttling", Message: "rate exceeded", Retryable: true},
+		DescribedResourceIds: []string{"b", "c"},
+	}))
+	require.True(job.Done())
+	result := job.Result()
+	require.Equal(uint32(1), result.GetJobId())
+	require.Equal(describev2.DescribeJobStatus_DESCRIBE_JOB_STATUS_FAILED, result.GetStatus())
+	require.Equal([]string{"a", "b", "c"}, result.GetDescribedResourceIds())
+	require.Equal("aws/eu-west-1: rate exceeded", result.GetError().GetMessage())
+	require.True(result.GetError().GetRetryable())
+
+	require.Error(job.DeliverV1(&golang.DeliverResultRequest{JobId: 7, Status: "SUCCEEDED"}))
+}
@@ -0,0 +1,361 @@
+package es
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/opensearch-project/opensearch-go/v2"
+)
+
+const (
+	defaultBulkMaxDocs        = 1000
+	defaultBulkMaxBytes       = 5 << 20 // 5 MiB
+	defaultBulkMaxRetries     = 3
+	defaultBulkInitialBackoff = 500 * time.Millisecond
+	defaultBulkMaxBackoff     = 30 * time.Second
+)
+
+// BulkIndexerConfig configures a BulkIndexer. Zero values use the defaults noted on each field.
+type BulkIndexerConfig struct {
+	Client *opensearch.Client
+
+	MaxDocs  int // Documents per bulk request (default 1000)
+	MaxBytes int // Approximate body size per bulk request (default 5 MiB)
+
+	MaxRetries     int           // Retries of transient failures per batch (default 3; negative disables retries)
+	InitialBackoff time.Duration // Delay before the first retry, doubled on each retry (default 500ms)
+	MaxBackoff     time.Duration // Maximum delay between retries (default 30s)
+
+	// OnFailure, if set, is called for every document that could not be indexed, after
+	// retries are exhausted. Batches are sent concurrently, so it must be safe for
+	// concurrent use.
+	OnFailure func(failure BulkFailure)
+}
+
+// BulkFailure is a document that could not be indexed.
+type BulkFailure struct {
+	Doc    Doc
+	Index  string
+	ID     string
+	Status int // HTTP status of the item, or of the bulk request if it failed as a whole; 0 without a response
+	Err    error
+}
+
+// BulkIndexerStats are the counters of a BulkIndexer.
+type BulkIndexerStats struct {
+	Added    int64 // Documents passed to Add
+	Indexed  int64 // Documents indexed successfully
+	Failed   int64 // Documents reported to OnFailure
+	Requests int64 // Bulk requests sent, including retries
+	Retries  int64 // Retried bulk requests
+}
+
+// BulkIndexer batches Doc values into bulk index requests by count and size, retries
+// transient failures (429, 500, 502, 503 and 504, for the whole request or single
+// documents) with exponential backoff, and reports documents that still fail through
+// OnFailure. It is safe for concurrent use.
+type BulkIndexer struct {
+	cfg BulkIndexerConfig
+
+	mu       sync.Mutex
+	batch    []bulkItem
+	size     int
+	stats    BulkIndexerStats
+	inflight int        // Batches being sent
+	idle     *sync.Cond // Signalled when inflight drops
+}
+
+type bulkItem struct {
+	doc   Doc
+	index string
+	id    string
+	body  []byte // Action and source lines
+}
+
+// NewBulkIndexer creates a bulk indexer for cfg.
+func NewBulkIndexer(cfg BulkIndexerConfig) (*BulkIndexer, error) {
+	if cfg.Client == nil {
+		return nil, errors.New("bulk indexer requires a client")
+	}
+	if cfg.MaxDocs <= 0 {
+		cfg.MaxDocs = defaultBulkMaxDocs
+	}
+	if cfg.MaxBytes <= 0 {
+		cfg.MaxBytes = defaultBulkMaxBytes
+	}
+	if cfg.MaxRetries < 0 {
+		cfg.MaxRetries = 0
+	} else if cfg.MaxRetries == 0 {
+		cfg.MaxRetries = defaultBulkMaxRetries
+	}
+	if cfg.InitialBackoff <= 0 {
+		cfg.InitialBackoff = defaultBulkInitialBackoff
+	}
+	if cfg.MaxBackoff <= 0 {
+		cfg.MaxBackoff = defaultBulkMaxBackoff
+	}
+	b := &BulkIndexer{cfg: cfg}
+	b.idle = sync.NewCond(&b.mu)
+	return b, nil
+}
+
+// Add queues doc for indexing into the index and under the id (the hash of its keys)
+// given by KeysAndIndex, and sends the batch once it is full. A VersionedDoc is only
+// written if the stored document still has its version; a conflict is reported to
+// OnFailure with an error wrapping ErrVersionConflict and is never retried. The returned
+// error is only set if doc cannot be encoded or ctx is done; indexing failures go to
+// OnFailure.
+func (b *BulkIndexer) Add(ctx context.Context, doc Doc) error {
+	keys, index := doc.KeysAndIndex()
+	id := HashOf(keys...)
+	source, err := json.Marshal(doc)
+	if err != nil {
+		return fmt.Errorf("marshal document: %w", err)
+	}
+	action, err := json.Marshal(versionedAction(doc, index, id))
+	if err != nil {
+		return fmt.Errorf("marshal action: %w", err)
+	}
+	body := make([]byte, 0, len(action)+len(source)+2)
+	body = append(append(append(append(body, action...), '\n'), source...), '\n')
+
+	// The batch is swapped out under the lock and sent without it, so that other Add
+	// calls keep queueing while a full batch is retried
+	var full []bulkItem
+	b.mu.Lock()
+	if len(b.batch) > 0 && b.size+len(body) > b.cfg.MaxBytes {
+		full = b.takeLocked()
+	}
+	b.batch = append(b.batch, bulkItem{doc: doc, index: index, id: id, body: body})
+	b.size += len(body)
+	b.stats.Added++
+	var last []bulkItem
+	if len(b.batch) >= b.cfg.MaxDocs || b.size >= b.cfg.MaxBytes {
+		last = b.takeLocked()
+	}
+	b.mu.Unlock()
+
+	if full != nil {
+		if err := b.flush(ctx, full); err != nil {
+			if last != nil {
+				b.fail(last, 0, err)
+				b.done()
+			}
+			return err
+		}
+	}
+	if last != nil {
+		return b.flush(ctx, last)
+	}
+	return nil
+}
+
+// Flush sends the queued documents. It returns only when every document, including
+// those of batches sent concurrently by Add, was indexed or reported to OnFailure, or
+// with ctx.Err() if ctx is done while backing off.
+func (b *BulkIndexer) Flush(ctx context.Context) error {
+	b.mu.Lock()
+	items := b.takeLocked()
+	b.mu.Unlock()
+	if err := b.flush(ctx, items); err != nil {
+		return err
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for b.inflight > 0 {
+		b.idle.Wait()
+	}
+	return nil
+}
+
+// Close flushes the queued documents.
+func (b *BulkIndexer) Close(ctx context.Context) error {
+	return b.Flush(ctx)
+}
+
+// Stats returns a snapshot of the indexer's counters.
+func (b *BulkIndexer) Stats() BulkIndexerStats {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.stats
+}
+
+// takeLocked swaps out the queued documents and counts them as in flight until flush
+// returns.
+func (b *BulkIndexer) takeLocked() []bulkItem {
+	items := b.batch
+	b.batch, b.size = nil, 0
+	b.inflight++
+	return items
+}
+
+func (b *BulkIndexer) done() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.inflight--
+	b.idle.Broadcast()
+}
+
+// flush sends items taken by takeLocked, retrying transient failures, without holding
+// the lock.
+func (b *BulkIndexer) flush(ctx context.Context, items []bulkItem) error {
+	defer b.done()
+
+	backoff := b.cfg.InitialBackoff
+	for attempt := 0; len(items) > 0; attempt++ {
+		if attempt > 0 {
+			b.count(func(stats *BulkIndexerStats) { stats.Retries++ })
+			if err := sleepContext(ctx, backoff); err != nil {
+				b.fail(items, 0, err)
+				return err
+			}
+			backoff = min(2*backoff, b.cfg.MaxBackoff)
+		}
+		lastAttempt := attempt >= b.cfg.MaxRetries
+
+		results, err := b.send(ctx, items)
+		if err != nil {
+			if ctx.Err() != nil {
+				b.fail(items, 0, err)
+				return ctx.Err()
+			}
+			status := 0
+			var statusErr *bulkStatusError
+			if errors.As(err, &statusErr) {
+				status = statusErr.status
+			}
+			if lastAttempt || (statusErr != nil && !isTransientStatus(status)) {
+				b.fail(items, status, err)
+				return nil
+			}
+			continue
+		}
+
+		var retry []bulkItem
+		var indexed int64
+		for i, item := range items {
+			result := results[i]
+			switch {
+			case result.status >= 200 && result.status < 300:
+				indexed++
+			case isTransientStatus(result.status) && !lastAttempt:
+				retry = append(retry, item)
+			default:
+				b.fail([]bulkItem{item}, result.status, result.err)
+			}
+		}
+		b.count(func(stats *BulkIndexerStats) { stats.Indexed += indexed })
+		items = retry
+	}
+	return nil
+}
+
+func (b *BulkIndexer) count(update func(stats *BulkIndexerStats)) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	update(&b.stats)
+}
+
+func (b *BulkIndexer) fail(items []bulkItem, status int, err error) {
+	b.count(func(stats *BulkIndexerStats) { stats.Failed += int64(len(items)) })
+	if b.cfg.OnFailure == nil {
+		return
+	}
+	for _, item := range items {
+		b.cfg.OnFailure(BulkFailure{Doc: item.doc, Index: item.index, ID: item.id, Status: status, Err: err})
+	}
+}
+
+type bulkItemResult struct {
+	status int
+	err    error
+}
+
+type bulkStatusError struct {
+	status int
+	body   string
+}
+
+func (e *bulkStatusError) Error() string {
+	return fmt.Sprintf("bulk request failed with status %d: %s", e.status, e.body)
+}
+
+type bulkResponse struct {
+	Items []map[string]struct {
+		Status int `json:"status"`
+		Error  *struct {
+			Type   string `json:"type"`
+			Reason string `json:"reason"`
+		} `json:"error"`
+	} `json:"items"`
+}
+
+// send issues one bulk request and returns the result of each item, in order.
+func (b *BulkIndexer) send(ctx context.Context, items []bulkItem) ([]bulkItemResult, error) {
+	var body bytes.Buffer
+	for _, item := range items {
+		body.Write(item.body)
+	}
+	b.count(func(stats *BulkIndexerStats) { stats.Requests++ })
+
+	client := b.cfg.Client
+	res, err := client.Bulk(&body, client.Bulk.WithContext(ctx))
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	if res.IsError() {
+		return nil, &bulkStatusError{status: res.StatusCode, body: string(data)}
+	}
+
+	var response bulkResponse
+	if err := json.Unmarshal(data, &response); err != nil {
+		return nil, fmt.Errorf("unmarshal response: %w", err)
+	}
+	if len(response.Items) != len(items) {
+		return nil, fmt.Errorf("bulk response has %d items for %d documents", len(response.Items), len(items))
+	}
+	results := make([]bulkItemResult, len(items))
+	for i, item := range response.Items {
+		for _, result := range item { // A single action key, e.g. "index"
+			results[i].status = result.Status
+			if result.Status == http.StatusConflict {
+				results[i].err = fmt.Errorf("%w: %s/%s", ErrVersionConflict, items[i].index, items[i].id)
+			} else if result.Error != nil {
+				results[i].err = fmt.Errorf("%s: %s", result.Error.Type, result.Error.Reason)
+			}
+		}
+	}
+	return results, nil
+}
+
+func isTransientStatus(status int) bool {
+	switch status {
+	case http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+func sleepContext(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,196 @@
+package es_test
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/opengovern/og-util/pkg/es"
+	"github.com/opensearch-project/opensearch-go/v2"
+	"github.com/stretchr/testify/require"
+)
+
+type bulkDoc struct {
+	Name string `json:"name"`
+}
+
+func (d bulkDoc) KeysAndIndex() ([]string, string) {
+	return []string{d.Name}, "inventory"
+}
+
+// bulkServer answers each bulk request with the next of responses: a whole-request
+// status, or per-document statuses keyed by document name.
+type bulkServer struct {
+	mu        sync.Mutex
+	responses []any // int or map[string]int
+	requests  [][]string
+}
+
+func (s *bulkServer) ServeHTTP(w http.ResponseWriter, req *http.Request) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	var names []string
+	scanner := bufio.NewScanner(req.Body)
+	for i := 0; scanner.Scan(); i++ {
+		if i%2 == 1 {
+			var doc bulkDoc
+			if err := json.Unmarshal(scanner.Bytes(), &doc); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			names = append(names, doc.Name)
+		}
+	}
+	s.requests = append(s.requests, names)
+
+	w.Header().Set("Content-Type", "application/json")
+	response := s.responses[0]
+	if len(s.responses) > 1 {
+		s.responses = s.responses[1:]
+	}
+	if status, ok := response.(int); ok {
+		w.WriteHeader(status)
+		w.Write([]byte(`{"error":"unavailable"}`))
+		return
+	}
+	statuses := response.(map[string]int)
+	items := make([]string, len(names))
+	for i, name := range names {
+		status, ok := statuses[name]
+		if !ok {
+			status = http.StatusCreated
+		}
+		if status >= 300 {
+			items[i] = fmt.Sprintf(`{"index":{"status":%d,"error":{"type":"error_%d","reason":"%s failed"}}}`, status, status, name)
+		} else {
+			items[i] = fmt.Sprintf(`{"index":{"status":%d}}`, status)
+		}
+	}
+	fmt.Fprintf(w, `{"items":[%s]}`, strings.Join(items, ","))
+}
+
+func newBulkIndexer(t *testing.T, s *bulkServer, failures *[]es.BulkFailure) *es.BulkIndexer {
+	server := httptest.NewServer(s)
+	t.Cleanup(server.Close)
+	client, err := opensearch.NewClient(opensearch.Config{Addresses: []string{server.URL}, DisableRetry: true})
+	require.NoError(t, err)
+	indexer, err := es.NewBulkIndexer(es.BulkIndexerConfig{
+		Client:         client,
+		MaxDocs:        4,
+		MaxRetries:     2,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     2 * time.Millisecond,
+		OnFailure:      func(failure es.BulkFailure) { *failures = append(*failures, failure) },
+	})
+	require.NoError(t, err)
+	return indexer
+}
+
+func TestBulkIndexerRetries(t *testing.T) {
+	r := require.New(t)
+
+	s := &bulkServer{responses: []any{
+		http.StatusServiceUnavailable, // Whole request retried
+		map[string]int{"b": http.StatusTooManyRequests, "c": http.StatusBadRequest, "d": http.StatusInternalServerError},
+		map[string]int{}, // b and d retried
+	}}
+	var failures []es.BulkFailure
+	indexer := newBulkIndexer(t, s, &failures)
+
+	ctx := context.Background()
+	for _, name := range []string{"a", "b", "c", "d"} { // Flushed by MaxDocs
+		r.NoError(indexer.Add(ctx, bulkDoc{Name: name}))
+	}
+	r.NoError(indexer.Close(ctx))
+
+	r.Equal([][]string{{"a", "b", "c", "d"}, {"a", "b", "c", "d"}, {"b", "d"}}, s.requests)
+	r.Len(failures, 1)
+	r.Equal("c", failures[0].Doc.(bulkDoc).Name)
+	r.Equal(http.StatusBadRequest, failures[0].Status)
+	r.ErrorContains(failures[0].Err, "error_400: c failed")
+	r.Equal(es.HashOf("c"), failures[0].ID)
+	r.Equal("inventory", failures[0].Index)
+	r.Equal(es.BulkIndexerStats{Added: 4, Indexed: 3, Failed: 1, Requests: 3, Retries: 2}, indexer.Stats())
+}
+
+func TestBulkIndexerGivesUp(t *testing.T) {
+	r := require.New(t)
+
+	// Retries are exhausted for transient failures, and never made for other ones
+	s := &bulkServer{responses: []any{http.StatusBadGateway}}
+	var failures []es.BulkFailure
+	indexer := newBulkIndexer(t, s, &failures)
+	r.NoError(indexer.Add(context.Background(), bulkDoc{Name: "a"}))
+	r.NoError(indexer.Flush(context.Background()))
+	r.Len(s.requests, 3)
+	r.Len(failures, 1)
+	r.Equal(http.StatusBadGateway, failures[0].Status)
+
+	s = &bulkServer{responses: []any{http.StatusForbidden}}
+	failures = nil
+	indexer = newBulkIndexer(t, s, &failures)
+	r.NoError(indexer.Add(context.Background(), bulkDoc{Name: "a"}))
+	r.NoError(indexer.Flush(context.Background()))
+	r.Len(s.requests, 1)
+	r.Len(failures, 1)
+	r.Equal(http.StatusForbidden, failures[0].Status)
+}
+
+func TestBulkIndexerAddDuringRetry(t *testing.T) {
+	r := require.New(t)
+
+	s := &bulkServer{responses: []any{map[string]int{}}}
+	started, release := make(chan struct{}), make(chan struct{})
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		if requests.Add(1) == 1 {
+			close(started)
+			<-release
+		}
+		s.ServeHTTP(w, req)
+	}))
+	defer server.Close()
+	unblock := sync.OnceFunc(func() { close(release) })
+	defer unblock() // Before closing the server, even if the test fails
+	client, err := opensearch.NewClient(opensearch.Config{Addresses: []string{server.URL}, DisableRetry: true})
+	r.NoError(err)
+	indexer, err := es.NewBulkIndexer(es.BulkIndexerConfig{Client: client, MaxDocs: 2})
+	r.NoError(err)
+
+	ctx := context.Background()
+	r.NoError(indexer.Add(ctx, bulkDoc{Name: "a"}))
+	sent := make(chan error)
+	go func() { sent <- indexer.Add(ctx, bulkDoc{Name: "b"}) }() // Fills the batch
+	<-started
+
+	// The first batch is still being sent, which must not block queueing
+	added := make(chan error)
+	go func() { added <- indexer.Add(ctx, bulkDoc{Name: "c"}) }()
+	select {
+	case err := <-added:
+		r.NoError(err)
+	case <-time.After(5 * time.Second):
+		t.Fatal("Add blocked while a batch was being sent")
+	}
+
+	flushed := make(chan error)
+	go func() { flushed <- indexer.Flush(ctx) }()
+	select {
+	case <-flushed:
+		t.Fatal("Flush returned while a batch was still being sent")
+	case <-time.After(50 * time.Millisecond):
+	}
+	unblock()
+	r.NoError(<-sent)
+	r.NoError(<-flushed)
+	r.ElementsMatch([][]string{{"a", "b"}, {"c"}}, s.requests)
+	r.Equal(es.BulkIndexerStats{Added: 3, Indexed: 3, Requests: 2}, indexer.Stats())
+}
@@ -0,0 +1,291 @@
+package es
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"strings"
+)
+
+// SizeUnit is a unit of data size. Binary units are powers of 1024, decimal units powers of 1000.
+type SizeUnit string
+
+const (
+	SizeUnitBytes SizeUnit = "B"
+	SizeUnitKiB   SizeUnit = "KiB"
+	SizeUnitMiB   SizeUnit = "MiB"
+	SizeUnitGiB   SizeUnit = "GiB"
+	SizeUnitTiB   SizeUnit = "TiB"
+	SizeUnitKB    SizeUnit = "KB"
+	SizeUnitMB    SizeUnit = "MB"
+	SizeUnitGB    SizeUnit = "GB"
+	SizeUnitTB    SizeUnit = "TB"
+)
+
+var sizeUnitBytes = map[SizeUnit]float64{
+	SizeUnitBytes: 1,
+	SizeUnitKiB:   1 << 10,
+	SizeUnitMiB:   1 << 20,
+	SizeUnitGiB:   1 << 30,
+	SizeUnitTiB:   1 << 40,
+	SizeUnitKB:    1e3,
+	SizeUnitMB:    1e6,
+	SizeUnitGB:    1e9,
+	SizeUnitTB:    1e12,
+}
+
+// ConvertSize converts value from one size unit to another.
+func ConvertSize(value float64, from, to SizeUnit) (float64, error) {
+	fromBytes, ok := sizeUnitBytes[from]
+	if !ok {
+		return 0, fmt.Errorf("unknown size unit %q", from)
+	}
+	toBytes, ok := sizeUnitBytes[to]
+	if !ok {
+		return 0, fmt.Errorf("unknown size unit %q", to)
+	}
+	return value * fromBytes / toBytes, nil
+}
+
+// ConversionKind selects how a result field is converted.
+type ConversionKind string
+
+const (
+	ConversionSize     ConversionKind = "size"
+	ConversionCurrency ConversionKind = "currency"
+)
+
+// FieldConversion declares a numeric field of query results that holds a size or an amount.
+type FieldConversion struct {
+	// Path is the dotted path of the field, e.g. "Description.Volume.Size" or "cost.list_price".
+	// Arrays along the path are traversed.
+	Path string
+	Kind ConversionKind
+	// SourceUnit is the unit sizes are stored in; bytes if empty.
+	SourceUnit SizeUnit
+	// CurrencyField is the sibling field holding the amount's currency, "currency" if empty.
+	// It is rewritten to the target currency after conversion.
+	CurrencyField string
+	// UnitField, if set, is a sibling field that is set to the target size unit.
+	UnitField string
+}
+
+// UnitPreferences are the caller's preferred display units. Empty fields leave values as stored.
+type UnitPreferences struct {
+	Currency string
+	SizeUnit SizeUnit
+	// Decimals rounds converted values to that many decimal places; 0 leaves them unrounded.
+	Decimals int
+}
+
+// ResultConverter applies unit and currency conversions to query results so API layers
+// share one implementation.
+type ResultConverter struct {
+	fields []FieldConversion
+	rates  CurrencyConverter
+}
+
+// NewResultConverter creates a converter for the given fields. rates may be nil if no
+// currency fields are converted.
+func NewResultConverter(rates CurrencyConverter, fields ...FieldConversion) *ResultConverter {
+	return &ResultConverter{fields: fields, rates: rates}
+}
+
+// Convert converts the fields of a decoded document in place.
+func (c *ResultConverter) Convert(ctx context.Context, doc map[string]any, prefs UnitPreferences) error {
+	state := &conversionState{rates: make(map[string]float64)}
+	for _, field := range c.fields {
+		segments := strings.Split(field.Path, ".")
+		if err := c.convertPath(ctx, doc, segments, field, prefs, state); err != nil {
+			return fmt.Errorf("convert %s: %w", field.Path, err)
+		}
+	}
+	// Currency fields are rewritten last, as several amounts may share one
+	for _, rewrite := range state.currencies {
+		rewrite.parent[rewrite.field] = strings.ToUpper(prefs.Currency)
+	}
+	return nil
+}
+
+// conversionState is shared by the fields of one Convert call.
+type conversionState struct {
+	rates      map[string]float64 // Cached "FROM/TO" rates
+	currencies []currencyRewrite
+}
+
+type currencyRewrite struct {
+	parent map[string]any
+	field  string
+}
+
+// ConvertJSON converts a JSON document or array of documents and returns the result.
+func (c *ResultConverter) ConvertJSON(ctx context.Context, data []byte, prefs UnitPreferences) ([]byte, error) {
+	var decoded any
+	if err := json.Unmarshal(data, &decoded); err != nil {
+		return nil, fmt.Errorf("unmarshal result: %w", err)
+	}
+	switch v := decoded.(type) {
+	case map[string]any:
+		if err := c.Convert(ctx, v, prefs); err != nil {
+			return nil, err
+		}
+	case []any:
+		for _, item := range v {
+			if doc, ok := item.(map[string]any); ok {
+				if err := c.Convert(ctx, doc, prefs); err != nil {
+					return nil, err
+				}
+			}
+		}
+	default:
+		return nil, fmt.Errorf("result must be a JSON object or array, got %T", decoded)
+	}
+	return json.Marshal(decoded)
+}
+
+func (c *ResultConverter) convertPath(ctx context.Context, node any, segments []string, field FieldConversion, prefs UnitPreferences, state *conversionState) error {
+	switch v := node.(type) {
+	case []any:
+		for _, item := range v {
+			if err := c.convertPath(ctx, item, segments, field, prefs, state); err != nil {
+				return err
+			}
+		}
+		return nil
+	case map[string]any:
+		child, ok := v[segments[0]]
+		if !ok || child == nil {
+			return nil
+		}
+		if len(segments) > 1 {
+			return c.convertPath(ctx, child, segments[1:], field, prefs, state)
+		}
+		if items, ok := child.([]any); ok {
+			anyConverted := false
+			for i, item := range items {
+				converted, ok, err := c.convertValue(ctx, v, item, field, prefs, state)
+				if err != nil {
+					return err
+				}
+				items[i] = converted
+				anyConverted = anyConverted || ok
+			}
+			if anyConverted {
+				c.finishParent(v, field, prefs, state)
+			}
+			return nil
+		}
+		converted, ok, err := c.convertValue(ctx, v, child, field, prefs, state)
+		if err != nil {
+			return err
+		}
+		v[segments[0]] = converted
+		if ok {
+			c.finishParent(v, field, prefs, state)
+		}
+		return nil
+	default:
+		return nil
+	}
+}
+
+// convertValue converts one numeric value whose containing object is parent. ok reports
+// whether the returned value is in the preferred unit, i.e. whether the unit or currency
+// field next to it must be updated; it is false for values left as stored.
+func (c *ResultConverter) convertValue(ctx context.Context, parent map[string]any, value any, field FieldConversion, prefs UnitPreferences, state *conversionState) (result any, ok bool, err error) {
+	number, ok := toFloat(value)
+	if !ok {
+		return value, false, nil
+	}
+	var converted float64
+	switch field.Kind {
+	case ConversionSize:
+		if prefs.SizeUnit == "" {
+			return value, false, nil
+		}
+		from := field.SourceUnit
+		if from == "" {
+			from = SizeUnitBytes
+		}
+		if converted, err = ConvertSize(number, from, prefs.SizeUnit); err != nil {
+			return nil, false, err
+		}
+	case ConversionCurrency:
+		from, _ := parent[currencyField(field)].(string)
+		if prefs.Currency == "" || from == "" {
+			return value, false, nil
+		} else if strings.EqualFold(from, prefs.Currency) {
+			return value, true, nil
+		}
+		rate, err := c.rate(ctx, from, prefs.Currency, state.rates)
+		if err != nil {
+			return nil, false, err
+		}
+		converted = number * rate
+	default:
+		return nil, false, fmt.Errorf("unknown conversion kind %q", field.Kind)
+	}
+	if prefs.Decimals > 0 {
+		scale := math.Pow10(prefs.Decimals)
+		converted = math.Round(converted*scale) / scale
+	}
+	return converted, true, nil
+}
+
+// finishParent updates the unit field next to a converted value and queues the
+// currency field for rewriting. It is only called once a value was converted, so that
+// values left as stored keep their unit.
+func (c *ResultConverter) finishParent(parent map[string]any, field FieldConversion, prefs UnitPreferences, state *conversionState) {
+	switch field.Kind {
+	case ConversionSize:
+		if field.UnitField != "" && prefs.SizeUnit != "" {
+			parent[field.UnitField] = string(prefs.SizeUnit)
+		}
+	case ConversionCurrency:
+		if _, ok := parent[currencyField(field)].(string); ok && prefs.Currency != "" {
+			state.currencies = append(state.currencies, currencyRewrite{parent: parent, field: currencyField(field)})
+		}
+	}
+}
+
+func (c *ResultConverter) rate(ctx context.Context, from, to string, cache map[string]float64) (float64, error) {
+	key := strings.ToUpper(from) + "/" + strings.ToUpper(to)
+	if rate, ok := cache[key]; ok {
+		return rate, nil
+	}
+	if c.rates == nil {
+		return 0, fmt.Errorf("converting %s to %s requires a currency converter", from, to)
+	}
+	rate, err := c.rates.Rate(ctx, from, to)
+	if err != nil {
+		return 0, err
+	}
+	cache[key] = rate
+	return rate, nil
+}
+
+func currencyField(field FieldConversion) string {
+	if field.CurrencyField == "" {
+		return "currency"
+	}
+	return field.CurrencyField
+}
+
+func toFloat(value any) (float64, bool) {
+	switch v := value.(type) {
+	case float64:
+		return v, true
+	case float32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case json.Number:
+		f, err := v.Float64()
+		return f, err == nil
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,32 @@
+package es_test
+
+import (
+	"context"
+	"testing"
+
+	"github.com/opengovern/og-util/pkg/es"
+	"github.com/stretchr/testify/require"
+)
+
+func TestResultConverterCurrency(t *testing.T) {
+	r := require.New(t)
+
+	converter := es.NewResultConverter(es.StaticRates{"USD": 1, "EUR": 2},
+		es.FieldConversion{Path: "cost.amount", Kind: es.ConversionCurrency},
+		es.FieldConversion{Path: "size", Kind: es.ConversionSize, UnitField: "size_unit"},
+	)
+	prefs := es.UnitPreferences{Currency: "usd", SizeUnit: es.SizeUnitKiB}
+	out, err := converter.ConvertJSON(context.Background(), []byte(`[
+		{"cost":{"amount":10,"currency":"EUR"},"size":2048,"size_unit":"B"},
+		{"cost":{"amount":"n/a","currency":"EUR"},"size":"unknown","size_unit":"B"},
+		{"cost":{"amount":[1,"n/a"],"currency":"EUR"}},
+		{"cost":{"amount":5,"currency":"usd"}}
+	]`), prefs)
+	r.NoError(err)
+	r.JSONEq(`[
+		{"cost":{"amount":20,"currency":"USD"},"size":2,"size_unit":"KiB"},
+		{"cost":{"amount":"n/a","currency":"EUR"},"size":"unknown","size_unit":"B"},
+		{"cost":{"amount":[2,"n/a"],"currency":"USD"}},
+		{"cost":{"amount":5,"currency":"USD"}}
+	]`, string(out))
+}
@@ -0,0 +1,187 @@
+package es
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// CostPeriod is the billing period a price applies to.
+type CostPeriod string
+
+const (
+	CostPeriodHour  CostPeriod = "hour"
+	CostPeriodMonth CostPeriod = "month"
+	CostPeriodYear  CostPeriod = "year"
+)
+
+// HoursPerMonth is the average number of hours in a month used by cloud price lists.
+const HoursPerMonth = 730
+
+// ResourceCost is optional cost data delivered with a resource by FinOps-style plugins.
+type ResourceCost struct {
+	// ListPrice is the recurring price per Period in Currency.
+	ListPrice float64 `json:"list_price"`
+	// Currency is the ISO 4217 code of all amounts, e.g. "USD".
+	Currency string `json:"currency"`
+	// Period is the period ListPrice applies to.
+	Period CostPeriod `json:"period"`
+	// UpfrontPrice is a one-time payment, e.g. for a reservation, amortized over TermMonths.
+	UpfrontPrice float64 `json:"upfront_price,omitempty"`
+	// TermMonths is the commitment term UpfrontPrice covers.
+	TermMonths int `json:"term_months,omitempty"`
+	// BillingTags are the cost allocation tags the provider bills the resource under.
+	BillingTags []Tag `json:"billing_tags,omitempty"`
+}
+
+// CurrencyConverter converts amounts between currencies, e.g. backed by a rates API.
+type CurrencyConverter interface {
+	// Rate returns how many units of to one unit of from is worth.
+	Rate(ctx context.Context, from, to string) (float64, error)
+}
+
+// StaticRates is a CurrencyConverter with fixed rates, expressed as the value of one unit
+// of each currency in a common base currency (e.g. {"USD": 1, "EUR": 1.08}).
+type StaticRates map[string]float64
+
+func (r StaticRates) Rate(_ context.Context, from, to string) (float64, error) {
+	fromValue, ok := r[strings.ToUpper(from)]
+	if !ok || fromValue <= 0 {
+		return 0, fmt.Errorf("no rate for currency %s", from)
+	}
+	toValue, ok := r[strings.ToUpper(to)]
+	if !ok || toValue <= 0 {
+		return 0, fmt.Errorf("no rate for currency %s", to)
+	}
+	return fromValue / toValue, nil
+}
+
+// Validate checks the currency code, period and amounts.
+func (c ResourceCost) Validate() error {
+	if !isCurrencyCode(c.Currency) {
+		return fmt.Errorf("invalid currency code %q", c.Currency)
+	}
+	if _, err := hoursIn(c.Period); err != nil {
+		return err
+	}
+	if c.ListPrice < 0 || c.UpfrontPrice < 0 {
+		return fmt.Errorf("cost amounts cannot be negative")
+	}
+	if c.UpfrontPrice > 0 && c.TermMonths <= 0 {
+		return fmt.Errorf("upfront price requires a positive term")
+	}
+	return nil
+}
+
+// AmortizedPrice returns the effective price per period including the share of
+// UpfrontPrice that falls into one period of the term.
+func (c ResourceCost) AmortizedPrice(period CostPeriod) (float64, error) {
+	recurring, err := convertPeriod(c.ListPrice, c.Period, period)
+	if err != nil {
+		return 0, err
+	}
+	if c.UpfrontPrice == 0 {
+		return recurring, nil
+	}
+	if c.TermMonths <= 0 {
+		return 0, fmt.Errorf("upfront price requires a positive term")
+	}
+	upfront, err := convertPeriod(c.UpfrontPrice/float64(c.TermMonths), CostPeriodMonth, period)
+	if err != nil {
+		return 0, err
+	}
+	return recurring + upfront, nil
+}
+
+// NormalizeCost converts cost to currency and period, folding the upfront price into the
+// list price by amortization, and normalizes the billing tags. converter may be nil when
+// no currency conversion is needed.
+func NormalizeCost(ctx context.Context, cost ResourceCost, currency string, period CostPeriod, converter CurrencyConverter) (ResourceCost, error) {
+	if err := cost.Validate(); err != nil {
+		return ResourceCost{}, err
+	}
+	if !isCurrencyCode(currency) {
+		return ResourceCost{}, fmt.Errorf("invalid currency code %q", currency)
+	}
+	price, err := cost.AmortizedPrice(period)
+	if err != nil {
+		return ResourceCost{}, err
+	}
+
+	from, to := strings.ToUpper(cost.Currency), strings.ToUpper(currency)
+	if from != to {
+		if converter == nil {
+			return ResourceCost{}, fmt.Errorf("converting %s to %s requires a currency converter", from, to)
+		}
+		rate, err := converter.Rate(ctx, from, to)
+		if err != nil {
+			return ResourceCost{}, fmt.Errorf("convert %s to %s: %w", from, to, err)
+		}
+		price *= rate
+	}
+
+	return ResourceCost{
+		ListPrice:   price,
+		Currency:    to,
+		Period:      period,
+		BillingTags: NormalizeBillingTags(cost.BillingTags),
+	}, nil
+}
+
+// NormalizeBillingTags lowercases and trims keys, drops empty keys and duplicates (the
+// first value wins) and sorts by key.
+func NormalizeBillingTags(tags []Tag) []Tag {
+	if len(tags) == 0 {
+		return nil
+	}
+	seen := make(map[string]bool, len(tags))
+	out := make([]Tag, 0, len(tags))
+	for _, tag := range tags {
+		key := strings.ToLower(strings.TrimSpace(tag.Key))
+		if key == "" || seen[key] {
+			continue
+		}
+		seen[key] = true
+		out = append(out, Tag{Key: key, Value: strings.TrimSpace(tag.Value)})
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].Key < out[j].Key })
+	return out
+}
+
+func convertPeriod(amount float64, from, to CostPeriod) (float64, error) {
+	fromHours, err := hoursIn(from)
+	if err != nil {
+		return 0, err
+	}
+	toHours, err := hoursIn(to)
+	if err != nil {
+		return 0, err
+	}
+	return amount / fromHours * toHours, nil
+}
+
+func hoursIn(period CostPeriod) (float64, error) {
+	switch period {
+	case CostPeriodHour:
+		return 1, nil
+	case CostPeriodMonth:
+		return HoursPerMonth, nil
+	case CostPeriodYear:
+		return 12 * HoursPerMonth, nil
+	default:
+		return 0, fmt.Errorf("invalid cost period %q", period)
+	}
+}
+
+func isCurrencyCode(code string) bool {
+	if len(code) != 3 {
+		return false
+	}
+	for _, r := range code {
+		if (r < 'A' || r > 'Z') && (r < 'a' || r > 'z') {
+			return false
+		}
+	}
+	return true
+}
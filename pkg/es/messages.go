@@ -45,6 +45,8 @@ type Resource struct {
 	DescribedBy string `json:"described_by"`
 	// DescribedAt is when the DescribeSourceJob is created
 	DescribedAt int64 `json:"described_at"`
+	// Cost is optional cost data delivered by cost-aware plugins
+	Cost *ResourceCost `json:"cost,omitempty"`
 }
 
 func (r Resource) KeysAndIndex() ([]string, string) {
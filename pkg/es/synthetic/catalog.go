@@ -0,0 +1,193 @@
+package synthetic
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/opengovern/og-util/pkg/integration"
+)
+
+// resourceContext carries the values shared by a resource's ID and description.
+type resourceContext struct {
+	rand          *rand.Rand
+	integrationID string
+	region        string
+	name          string
+	seq           int
+}
+
+type resourceType struct {
+	name      string
+	shortName string
+	weight    int // Relative frequency
+	id        func(c *resourceContext) string
+	describe  func(c *resourceContext) map[string]any
+}
+
+type providerCatalog struct {
+	integrationType integration.Type
+	regions         []string
+	integrationID   func(r *rand.Rand) string
+	types           []resourceType
+}
+
+func (p providerCatalog) lookup(name string) (resourceType, bool) {
+	for _, t := range p.types {
+		if strings.EqualFold(t.name, name) {
+			return t, true
+		}
+	}
+	return resourceType{}, false
+}
+
+var catalogs = map[Provider]providerCatalog{
+	ProviderAWS: {
+		integrationType: IntegrationTypeAWS,
+		regions:         []string{"us-east-1", "us-west-2", "eu-west-1", "eu-central-1", "ap-southeast-2", "ap-northeast-1", "sa-east-1", "ca-central-1"},
+		integrationID:   func(r *rand.Rand) string { return fmt.Sprintf("%012d", r.Int63n(1e12)) },
+		types: []resourceType{
+			{
+				name: "AWS::EC2::Instance", shortName: "ec2", weight: 30,
+				id: func(c *resourceContext) string { return "i-" + hexString(c.rand, 17) },
+				describe: func(c *resourceContext) map[string]any {
+					return map[string]any{
+						"Instance": map[string]any{
+							"InstanceType":     pick(c.rand, "t3.micro", "t3.large", "m5.xlarge", "c6i.2xlarge", "r6g.large"),
+							"State":            map[string]any{"Name": pick(c.rand, "running", "running", "running", "stopped")},
+							"PrivateIpAddress": fmt.Sprintf("10.%d.%d.%d", c.rand.Intn(256), c.rand.Intn(256), 1+c.rand.Intn(254)),
+							"LaunchTime":       randomTime(c.rand).Format(time.RFC3339),
+							"Placement":        map[string]any{"AvailabilityZone": c.region + pick(c.rand, "a", "b", "c")},
+							"Monitoring":       map[string]any{"State": pick(c.rand, "enabled", "disabled")},
+						},
+					}
+				},
+			},
+			{
+				name: "AWS::S3::Bucket", shortName: "s3", weight: 15,
+				id: func(c *resourceContext) string { return fmt.Sprintf("arn:aws:s3:::%s", c.name) },
+				describe: func(c *resourceContext) map[string]any {
+					return map[string]any{
+						"Bucket":            map[string]any{"Name": c.name, "CreationDate": randomTime(c.rand).Format(time.RFC3339)},
+						"Region":            c.region,
+						"BucketVersioning":  map[string]any{"Status": pick(c.rand, "Enabled", "Suspended", "")},
+						"PublicAccessBlock": map[string]any{"BlockPublicAcls": c.rand.Intn(5) > 0, "RestrictPublicBuckets": c.rand.Intn(5) > 0},
+						"SizeBytes":         c.rand.Int63n(1 << 40),
+					}
+				},
+			},
+			{
+				name: "AWS::IAM::Role", shortName: "role", weight: 25,
+				id: func(c *resourceContext) string {
+					return fmt.Sprintf("arn:aws:iam::%s:role/%s", c.integrationID, c.name)
+				},
+				describe: func(c *resourceContext) map[string]any {
+					return map[string]any{
+						"Role": map[string]any{
+							"RoleName":           c.name,
+							"CreateDate":         randomTime(c.rand).Format(time.RFC3339),
+							"MaxSessionDuration": pick(c.rand, 3600, 7200, 43200),
+						},
+						"AttachedPolicyArns": []any{"arn:aws:iam::aws:policy/" + pick(c.rand, "ReadOnlyAccess", "AdministratorAccess", "AmazonS3ReadOnlyAccess")},
+					}
+				},
+			},
+			{
+				name: "AWS::RDS::DBInstance", shortName: "rds", weight: 5,
+				id: func(c *resourceContext) string {
+					return fmt.Sprintf("arn:aws:rds:%s:%s:db:%s", c.region, c.integrationID, c.name)
+				},
+				describe: func(c *resourceContext) map[string]any {
+					return map[string]any{
+						"DBInstance": map[string]any{
+							"Engine":           pick(c.rand, "postgres", "mysql", "aurora-postgresql"),
+							"DBInstanceClass":  pick(c.rand, "db.t3.medium", "db.r6g.large", "db.m5.xlarge"),
+							"AllocatedStorage": 20 + c.rand.Intn(2000),
+							"MultiAZ":          c.rand.Intn(2) == 0,
+							"StorageEncrypted": c.rand.Intn(10) > 0,
+						},
+					}
+				},
+			},
+		},
+	},
+	ProviderAzure: {
+		integrationType: IntegrationTypeAzure,
+		regions:         []string{"eastus", "westeurope", "northeurope", "westus2", "southeastasia", "uksouth", "australiaeast", "centralus"},
+		integrationID:   func(r *rand.Rand) string { return uuidFrom(r) },
+		types: []resourceType{
+			{
+				name: "Microsoft.Compute/virtualMachines", shortName: "vm", weight: 30,
+				id:       func(c *resourceContext) string { return azureID(c, "Microsoft.Compute/virtualMachines") },
+				describe: azureDescription("Standard_B2s", "Standard_D4s_v5", "Standard_E8s_v5"),
+			},
+			{
+				name: "Microsoft.Storage/storageAccounts", shortName: "st", weight: 15,
+				id:       func(c *resourceContext) string { return azureID(c, "Microsoft.Storage/storageAccounts") },
+				describe: azureDescription("Standard_LRS", "Standard_GRS", "Premium_LRS"),
+			},
+			{
+				name: "Microsoft.Network/networkSecurityGroups", shortName: "nsg", weight: 20,
+				id:       func(c *resourceContext) string { return azureID(c, "Microsoft.Network/networkSecurityGroups") },
+				describe: azureDescription(),
+			},
+			{
+				name: "Microsoft.KeyVault/vaults", shortName: "kv", weight: 5,
+				id:       func(c *resourceContext) string { return azureID(c, "Microsoft.KeyVault/vaults") },
+				describe: azureDescription("standard", "premium"),
+			},
+		},
+	},
+}
+
+func azureID(c *resourceContext, resourceType string) string {
+	return fmt.Sprintf("/subscriptions/%s/resourceGroups/rg-%s/providers/%s/%s", c.integrationID, words[c.seq%len(words)], resourceType, c.name)
+}
+
+// azureDescription describes an Azure resource with one of the given SKUs, if any.
+func azureDescription(skus ...string) func(c *resourceContext) map[string]any {
+	return func(c *resourceContext) map[string]any {
+		properties := map[string]any{
+			"provisioningState": pick(c.rand, "Succeeded", "Succeeded", "Succeeded", "Failed"),
+			"createdTime":       randomTime(c.rand).Format(time.RFC3339),
+		}
+		description := map[string]any{
+			"ResourceGroup": fmt.Sprintf("rg-%s", words[c.seq%len(words)]),
+			"Location":      c.region,
+			"Properties":    properties,
+		}
+		if len(skus) > 0 {
+			description["Sku"] = map[string]any{"Name": skus[c.rand.Intn(len(skus))]}
+		}
+		return description
+	}
+}
+
+func pick[T any](r *rand.Rand, values ...T) T {
+	return values[r.Intn(len(values))]
+}
+
+func hexString(r *rand.Rand, n int) string {
+	const digits = "0123456789abcdef"
+	b := make([]byte, n)
+	for i := range b {
+		b[i] = digits[r.Intn(len(digits))]
+	}
+	return string(b)
+}
+
+// uuidFrom derives a UUID from r, so seeded generators stay reproducible.
+func uuidFrom(r *rand.Rand) string {
+	var b [16]byte
+	r.Read(b[:])
+	id, _ := uuid.FromBytes(b[:])
+	return id.String()
+}
+
+// randomTime returns a time within the three years before 2025-01-01.
+func randomTime(r *rand.Rand) time.Time {
+	base := time.Date(2025, 1, 1, 0, 0, 0, 0, time.UTC)
+	return base.Add(-time.Duration(r.Int63n(int64(3 * 365 * 24 * time.Hour))))
+}
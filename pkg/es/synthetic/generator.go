@@ -0,0 +1,193 @@
+// Package synthetic generates realistic but entirely fake resource documents for
+// performance testing queries and retention jobs without customer data.
+package synthetic
+
+import (
+	"fmt"
+	"math/rand"
+	"strings"
+	"time"
+
+	"github.com/opengovern/og-util/pkg/es"
+	"github.com/opengovern/og-util/pkg/integration"
+)
+
+// Provider selects the shape of generated resources.
+type Provider string
+
+const (
+	ProviderAWS   Provider = "aws"
+	ProviderAzure Provider = "azure"
+)
+
+// Integration types stamped on generated resources.
+const (
+	IntegrationTypeAWS   integration.Type = "aws_cloud_account"
+	IntegrationTypeAzure integration.Type = "azure_subscription"
+)
+
+// Options configures a Generator. Zero values use the defaults noted on each field.
+type Options struct {
+	Provider Provider // ProviderAWS if empty
+	// Seed makes the output reproducible; the same seed and options yield the same documents.
+	Seed int64
+	// Integrations is the number of distinct accounts or subscriptions (default 10).
+	Integrations int
+	// Regions is the number of distinct regions used, capped by the built-in list (default 5).
+	Regions int
+	// ResourceTypes restricts generation to these types; all built-in types of the provider if empty.
+	ResourceTypes []string
+	// TagKeys is the number of distinct tag keys (default 20). Keys are chosen with a Zipf
+	// distribution, so a few keys are on most resources and most keys are rare.
+	TagKeys int
+	// TagValuesPerKey is the cardinality of each tag key's values (default 10).
+	TagValuesPerKey int
+	// MaxTagsPerResource caps the tags on one resource (default 8); the count is uniform in [0, max].
+	MaxTagsPerResource int
+	// DescribedAt is the describe time stamped on documents; time.Now() if zero.
+	DescribedAt time.Time
+}
+
+// Generator produces synthetic resources. It is not safe for concurrent use.
+type Generator struct {
+	opts         Options
+	rand         *rand.Rand
+	types        []resourceType
+	integrations []string
+	regions      []string
+	tagKeys      *rand.Zipf
+	seq          int
+}
+
+// NewGenerator creates a generator for opts.
+func NewGenerator(opts Options) (*Generator, error) {
+	if opts.Provider == "" {
+		opts.Provider = ProviderAWS
+	}
+	setDefault(&opts.Integrations, 10)
+	setDefault(&opts.Regions, 5)
+	setDefault(&opts.TagKeys, 20)
+	setDefault(&opts.TagValuesPerKey, 10)
+	setDefault(&opts.MaxTagsPerResource, 8)
+	if opts.DescribedAt.IsZero() {
+		opts.DescribedAt = time.Now()
+	}
+
+	catalog, ok := catalogs[opts.Provider]
+	if !ok {
+		return nil, fmt.Errorf("unsupported provider %q", opts.Provider)
+	}
+	types := catalog.types
+	if len(opts.ResourceTypes) > 0 {
+		types = nil
+		for _, name := range opts.ResourceTypes {
+			t, ok := catalog.lookup(name)
+			if !ok {
+				return nil, fmt.Errorf("no generator for resource type %q of provider %s", name, opts.Provider)
+			}
+			types = append(types, t)
+		}
+	}
+
+	r := rand.New(rand.NewSource(opts.Seed))
+	g := &Generator{
+		opts:    opts,
+		rand:    r,
+		types:   types,
+		regions: catalog.regions[:min(opts.Regions, len(catalog.regions))],
+		tagKeys: rand.NewZipf(r, 1.2, 1, uint64(opts.TagKeys-1)),
+	}
+	for i := 0; i < opts.Integrations; i++ {
+		g.integrations = append(g.integrations, catalog.integrationID(r))
+	}
+	return g, nil
+}
+
+func setDefault(v *int, def int) {
+	if *v <= 0 {
+		*v = def
+	}
+}
+
+// Next returns the next synthetic resource.
+func (g *Generator) Next() es.Resource {
+	g.seq++
+	t := g.pick()
+	integrationID := g.integrations[g.rand.Intn(len(g.integrations))]
+	region := g.regions[g.rand.Intn(len(g.regions))]
+	name := fmt.Sprintf("%s-%s-%05d", words[g.rand.Intn(len(words))], t.shortName, g.seq)
+
+	ctx := &resourceContext{rand: g.rand, integrationID: integrationID, region: region, name: name, seq: g.seq}
+	resourceID := t.id(ctx)
+	resource := es.Resource{
+		PlatformID:      fmt.Sprintf("%s:::%s:::%s", integrationID, strings.ToLower(t.name), resourceID),
+		ResourceID:      resourceID,
+		ResourceName:    name,
+		Description:     t.describe(ctx),
+		IntegrationType: catalogs[g.opts.Provider].integrationType,
+		ResourceType:    t.name,
+		IntegrationID:   integrationID,
+		Metadata:        map[string]string{"region": region, "synthetic": "true"},
+		CanonicalTags:   g.tags(),
+		DescribedBy:     "synthetic",
+		DescribedAt:     g.opts.DescribedAt.UnixMilli(),
+	}
+	keys, index := resource.KeysAndIndex()
+	resource.EsID = es.HashOf(keys...)
+	resource.EsIndex = index
+	return resource
+}
+
+// Generate returns n synthetic resources.
+func (g *Generator) Generate(n int) []es.Resource {
+	resources := make([]es.Resource, n)
+	for i := range resources {
+		resources[i] = g.Next()
+	}
+	return resources
+}
+
+// pick chooses a resource type by weight.
+func (g *Generator) pick() resourceType {
+	total := 0
+	for _, t := range g.types {
+		total += t.weight
+	}
+	n := g.rand.Intn(total)
+	for _, t := range g.types {
+		if n < t.weight {
+			return t
+		}
+		n -= t.weight
+	}
+	return g.types[len(g.types)-1]
+}
+
+func (g *Generator) tags() []es.Tag {
+	count := g.rand.Intn(g.opts.MaxTagsPerResource + 1)
+	seen := make(map[uint64]bool, count)
+	tags := make([]es.Tag, 0, count)
+	for len(tags) < count && len(seen) < g.opts.TagKeys {
+		k := g.tagKeys.Uint64()
+		if seen[k] {
+			continue
+		}
+		seen[k] = true
+		tags = append(tags, es.Tag{
+			Key:   tagKeyName(int(k)),
+			Value: fmt.Sprintf("%s-%d", words[int(k)%len(words)], g.rand.Intn(g.opts.TagValuesPerKey)),
+		})
+	}
+	return tags
+}
+
+var wellKnownTagKeys = []string{"environment", "owner", "team", "cost-center", "application", "project", "managed-by", "compliance"}
+
+func tagKeyName(k int) string {
+	if k < len(wellKnownTagKeys) {
+		return wellKnownTagKeys[k]
+	}
+	return fmt.Sprintf("custom-tag-%d", k)
+}
+
+var words = []string{"alpha", "bravo", "cedar", "delta", "ember", "falcon", "granite", "harbor", "iris", "juniper", "kestrel", "lumen", "maple", "nova", "orbit", "pine", "quartz", "raven", "sierra", "tundra"}
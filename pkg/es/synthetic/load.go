@@ -0,0 +1,96 @@
+package synthetic
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	opengovernance "github.com/opengovern/og-util/pkg/opengovernance-es-sdk"
+)
+
+// LoadOptions configures Load.
+type LoadOptions struct {
+	Count     int    // Number of resources to generate and index
+	BatchSize int    // Documents per bulk request (default 1000)
+	Refresh   string // Refresh policy of the last batch, e.g. "wait_for"; none if empty
+	// OnBatch, if set, is called after each bulk request with the running totals.
+	OnBatch func(stats LoadStats)
+}
+
+// LoadStats summarizes a Load run.
+type LoadStats struct {
+	Indexed  int
+	Failed   int
+	Batches  int
+	Duration time.Duration
+	Indices  map[string]int // Documents indexed per index
+}
+
+// Load generates opts.Count resources and bulk-indexes them into the resource-type
+// indices. Per-document failures are counted, not returned; the returned error is set
+// when a bulk request fails as a whole.
+func Load(ctx context.Context, client opengovernance.Client, generator *Generator, opts LoadOptions) (LoadStats, error) {
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = 1000
+	}
+	stats := LoadStats{Indices: make(map[string]int)}
+	start := time.Now()
+
+	for remaining := opts.Count; remaining > 0; {
+		if err := ctx.Err(); err != nil {
+			return stats, err
+		}
+		n := min(batchSize, remaining)
+		remaining -= n
+
+		docs := make([]opengovernance.UpsertDocument, 0, n)
+		for _, resource := range generator.Generate(n) {
+			doc, err := toMap(resource)
+			if err != nil {
+				return stats, err
+			}
+			docs = append(docs, opengovernance.UpsertDocument{Index: resource.EsIndex, ID: resource.EsID, Doc: doc})
+		}
+		upsertOpts := opengovernance.UpsertOptions{}
+		if remaining == 0 {
+			upsertOpts.Refresh = opts.Refresh
+		}
+		response, err := client.Upsert(ctx, docs, upsertOpts)
+		if err != nil {
+			return stats, fmt.Errorf("bulk load batch %d: %w", stats.Batches+1, err)
+		}
+
+		failed := make(map[string]bool, len(response.Failed))
+		for _, f := range response.Failed {
+			failed[f.Index+"/"+f.ID] = true
+		}
+		for _, doc := range docs {
+			if !failed[doc.Index+"/"+doc.ID] {
+				stats.Indices[doc.Index]++
+			}
+		}
+		stats.Indexed += response.Succeeded
+		stats.Failed += len(response.Failed)
+		stats.Batches++
+		stats.Duration = time.Since(start)
+		if opts.OnBatch != nil {
+			opts.OnBatch(stats)
+		}
+	}
+	stats.Duration = time.Since(start)
+	return stats, nil
+}
+
+func toMap(v any) (map[string]any, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, fmt.Errorf("marshal resource: %w", err)
+	}
+	var m map[string]any
+	if err := json.Unmarshal(b, &m); err != nil {
+		return nil, fmt.Errorf("unmarshal resource: %w", err)
+	}
+	return m, nil
+}
@@ -0,0 +1,186 @@
+package es
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+
+	"github.com/opensearch-project/opensearch-go/v2"
+	"github.com/opensearch-project/opensearch-go/v2/opensearchapi"
+)
+
+// ErrVersionConflict is wrapped by the errors of writes whose document changed since it
+// was read, i.e. writing it would have lost the other update.
+var ErrVersionConflict = errors.New("version conflict")
+
+// DocVersion is the sequence number and primary term of a document as last read, from
+// the _seq_no and _primary_term metadata of get and search hits (the latter with
+// seq_no_primary_term). A Doc type embedding it implements VersionedDoc; its fields are
+// never encoded, since the cluster rejects metadata fields inside _source.
+type DocVersion struct {
+	SeqNo       int64 `json:"-"`
+	PrimaryTerm int64 `json:"-"`
+}
+
+// hitVersion decodes the version metadata of a response.
+type hitVersion struct {
+	SeqNo       int64 `json:"_seq_no"`
+	PrimaryTerm int64 `json:"_primary_term"`
+}
+
+func (v hitVersion) version() DocVersion {
+	return DocVersion{SeqNo: v.SeqNo, PrimaryTerm: v.PrimaryTerm}
+}
+
+// IsZero reports whether the version is unknown, i.e. the document was never read.
+// Primary terms start at 1, while 0 is a valid sequence number.
+func (v DocVersion) IsZero() bool {
+	return v.PrimaryTerm == 0
+}
+
+// Version implements VersionedDoc for Doc types embedding DocVersion.
+func (v DocVersion) Version() DocVersion {
+	return v
+}
+
+func (v DocVersion) String() string {
+	return fmt.Sprintf("seq_no %d, primary_term %d", v.SeqNo, v.PrimaryTerm)
+}
+
+// VersionedDoc is a Doc written with optimistic concurrency control: the write only
+// succeeds if the stored document still has Version, else it fails with
+// ErrVersionConflict. A zero Version expects the document not to exist yet. Implement it
+// by embedding DocVersion, or wrap a Doc with WithVersion.
+type VersionedDoc interface {
+	Doc
+	Version() DocVersion
+}
+
+type versionedDoc struct {
+	Doc
+	version DocVersion
+}
+
+// WithVersion returns doc as a VersionedDoc of version, e.g. as returned by GetVersioned
+// when doc was read.
+func WithVersion(doc Doc, version DocVersion) VersionedDoc {
+	return versionedDoc{Doc: doc, version: version}
+}
+
+func (d versionedDoc) Version() DocVersion {
+	return d.version
+}
+
+// MarshalJSON encodes the wrapped document only, so the version never ends up in the
+// source.
+func (d versionedDoc) MarshalJSON() ([]byte, error) {
+	return json.Marshal(d.Doc)
+}
+
+// versionedAction returns the bulk action of doc, with if_seq_no/if_primary_term if it
+// is a VersionedDoc.
+func versionedAction(doc Doc, index, id string) map[string]any {
+	meta := map[string]any{"_index": index, "_id": id}
+	versioned, ok := doc.(VersionedDoc)
+	if !ok {
+		return map[string]any{"index": meta}
+	}
+	version := versioned.Version()
+	if version.IsZero() {
+		return map[string]any{"create": meta}
+	}
+	meta["if_seq_no"] = version.SeqNo
+	meta["if_primary_term"] = version.PrimaryTerm
+	return map[string]any{"index": meta}
+}
+
+// GetVersioned reads the source of the document id of index into source and returns its
+// version, to write it back with WithVersion. found is false if there is no such document.
+func GetVersioned(ctx context.Context, client *opensearch.Client, index, id string, source any) (version DocVersion, found bool, err error) {
+	res, err := client.Get(index, id, client.Get.WithContext(ctx))
+	if err != nil {
+		return DocVersion{}, false, err
+	}
+	defer res.Body.Close()
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return DocVersion{}, false, fmt.Errorf("read response: %w", err)
+	}
+	if res.StatusCode == http.StatusNotFound {
+		return DocVersion{}, false, nil
+	} else if res.IsError() {
+		return DocVersion{}, false, fmt.Errorf("get %s/%s failed with status %d: %s", index, id, res.StatusCode, data)
+	}
+
+	var response struct {
+		hitVersion
+		Found  bool            `json:"found"`
+		Source json.RawMessage `json:"_source"`
+	}
+	if err := json.Unmarshal(data, &response); err != nil {
+		return DocVersion{}, false, fmt.Errorf("unmarshal response: %w", err)
+	}
+	if !response.Found {
+		return DocVersion{}, false, nil
+	}
+	if source != nil {
+		if err := json.Unmarshal(response.Source, source); err != nil {
+			return DocVersion{}, false, fmt.Errorf("unmarshal source: %w", err)
+		}
+	}
+	return response.version(), true, nil
+}
+
+// IndexVersioned writes doc into the index and under the id (the hash of its keys) given
+// by KeysAndIndex, if the stored document still has doc's version, and returns the new
+// version. If another writer updated (or, for a zero version, created) the document in
+// the meantime, the error wraps ErrVersionConflict: read it again, reapply the change
+// and retry.
+func IndexVersioned(ctx context.Context, client *opensearch.Client, doc VersionedDoc) (DocVersion, error) {
+	keys, index := doc.KeysAndIndex()
+	id := HashOf(keys...)
+	source, err := json.Marshal(doc)
+	if err != nil {
+		return DocVersion{}, fmt.Errorf("marshal document: %w", err)
+	}
+
+	version := doc.Version()
+	opts := []func(*opensearchapi.IndexRequest){
+		client.Index.WithContext(ctx),
+		client.Index.WithDocumentID(id),
+	}
+	if version.IsZero() {
+		opts = append(opts, client.Index.WithOpType("create"))
+	} else {
+		opts = append(opts,
+			client.Index.WithIfSeqNo(int(version.SeqNo)),
+			client.Index.WithIfPrimaryTerm(int(version.PrimaryTerm)))
+	}
+	res, err := client.Index(index, bytes.NewReader(source), opts...)
+	if err != nil {
+		return DocVersion{}, err
+	}
+	defer res.Body.Close()
+	data, err := io.ReadAll(res.Body)
+	if err != nil {
+		return DocVersion{}, fmt.Errorf("read response: %w", err)
+	}
+	if res.StatusCode == http.StatusConflict {
+		if version.IsZero() {
+			return DocVersion{}, fmt.Errorf("%w: document %s/%s already exists", ErrVersionConflict, index, id)
+		}
+		return DocVersion{}, fmt.Errorf("%w: document %s/%s is no longer at %s", ErrVersionConflict, index, id, version)
+	} else if res.IsError() {
+		return DocVersion{}, fmt.Errorf("index %s/%s failed with status %d: %s", index, id, res.StatusCode, data)
+	}
+
+	var written hitVersion
+	if err := json.Unmarshal(data, &written); err != nil {
+		return DocVersion{}, fmt.Errorf("unmarshal response: %w", err)
+	}
+	return written.version(), nil
+}
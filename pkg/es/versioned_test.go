@@ -0,0 +1,83 @@
+package es_test
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/opengovern/og-util/pkg/es"
+	"github.com/opensearch-project/opensearch-go/v2"
+	"github.com/stretchr/testify/require"
+)
+
+type versionedResource struct {
+	es.DocVersion
+	Name string `json:"name"`
+}
+
+func (r versionedResource) KeysAndIndex() ([]string, string) {
+	return []string{r.Name}, "inventory"
+}
+
+func TestIndexVersioned(t *testing.T) {
+	r := require.New(t)
+
+	var lastQuery, lastBody string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		b, _ := io.ReadAll(req.Body)
+		lastQuery, lastBody = req.URL.RawQuery, string(b)
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case req.Method == http.MethodGet && strings.HasSuffix(req.URL.Path, "/missing"):
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"_index":"inventory","_id":"missing","found":false}`))
+		case req.Method == http.MethodGet:
+			w.Write([]byte(`{"_index":"inventory","_id":"x","_seq_no":0,"_primary_term":3,"found":true,"_source":{"name":"a"}}`))
+		case strings.Contains(req.URL.RawQuery, "if_seq_no=0"):
+			w.Write([]byte(`{"_seq_no":1,"_primary_term":3,"result":"updated"}`))
+		default:
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(`{"error":{"type":"version_conflict_engine_exception"},"status":409}`))
+		}
+	}))
+	defer server.Close()
+	client, err := opensearch.NewClient(opensearch.Config{Addresses: []string{server.URL}})
+	r.NoError(err)
+	ctx := context.Background()
+
+	_, found, err := es.GetVersioned(ctx, client, "inventory", "missing", nil)
+	r.NoError(err)
+	r.False(found)
+
+	var doc versionedResource
+	version, found, err := es.GetVersioned(ctx, client, "inventory", "x", &doc)
+	r.NoError(err)
+	r.True(found)
+	r.Equal(es.DocVersion{SeqNo: 0, PrimaryTerm: 3}, version)
+	r.False(version.IsZero())
+	r.Equal("a", doc.Name)
+
+	// The embedded version is sent as a precondition, not in the source
+	doc.DocVersion = version
+	written, err := es.IndexVersioned(ctx, client, doc)
+	r.NoError(err)
+	r.Equal(es.DocVersion{SeqNo: 1, PrimaryTerm: 3}, written)
+	r.Contains(lastQuery, "if_primary_term=3")
+	r.JSONEq(`{"name":"a"}`, lastBody)
+
+	// A stale version or an existing document is a conflict
+	_, err = es.IndexVersioned(ctx, client, es.WithVersion(versionedResource{Name: "a"}, es.DocVersion{SeqNo: 5, PrimaryTerm: 3}))
+	r.ErrorIs(err, es.ErrVersionConflict)
+	_, err = es.IndexVersioned(ctx, client, es.WithVersion(versionedResource{Name: "a"}, es.DocVersion{}))
+	r.ErrorIs(err, es.ErrVersionConflict)
+	r.Contains(lastQuery, "op_type=create")
+	r.JSONEq(`{"name":"a"}`, lastBody)
+
+	b, err := json.Marshal(versionedResource{DocVersion: version, Name: "a"})
+	r.NoError(err)
+	r.JSONEq(`{"name":"a"}`, string(b))
+}
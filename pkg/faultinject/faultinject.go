@@ -0,0 +1,238 @@
+// Package faultinject injects errors, latency and timeouts into outgoing requests so
+// services can exercise their resilience paths against og-util dependencies in test
+// environments. It is disabled unless explicitly enabled in configuration.
+package faultinject
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"sync"
+	"time"
+)
+
+// ErrInjected matches every error produced by an Injector with errors.Is.
+var ErrInjected = errors.New("injected fault")
+
+// Fault kinds reported by InjectedError.
+const (
+	KindError   = "error"
+	KindTimeout = "timeout"
+)
+
+// Rule describes the faults injected into one operation. Rates are probabilities in [0, 1].
+type Rule struct {
+	// ErrorRate is the probability a request fails with an injected error.
+	ErrorRate float64 `json:"error_rate" koanf:"error_rate"`
+	// StatusCode, if set, makes HTTP transports answer failed requests with this status
+	// (e.g. 429 or 503) instead of returning a transport error.
+	StatusCode int `json:"status_code" koanf:"status_code"`
+	// LatencyRate is the probability Latency is added before a request is sent.
+	LatencyRate float64       `json:"latency_rate" koanf:"latency_rate"`
+	Latency     time.Duration `json:"latency" koanf:"latency"`
+	// TimeoutRate is the probability a request hangs until its context is done, or for
+	// Timeout if that is set, and then fails with a timeout error.
+	TimeoutRate float64       `json:"timeout_rate" koanf:"timeout_rate"`
+	Timeout     time.Duration `json:"timeout" koanf:"timeout"`
+}
+
+// Config configures an Injector.
+type Config struct {
+	Enabled bool `json:"enabled" koanf:"enabled"`
+	// Seed makes injection decisions reproducible; a time-based seed is used if zero.
+	Seed int64 `json:"seed" koanf:"seed"`
+	// Default applies to operations without an entry in Operations.
+	Default Rule `json:"default" koanf:"default"`
+	// Operations overrides Default per operation name, e.g. "_search" or "artifact-download".
+	Operations map[string]Rule `json:"operations" koanf:"operations"`
+}
+
+func (c Config) validate() error {
+	rules := map[string]Rule{"default": c.Default}
+	for op, rule := range c.Operations {
+		rules[op] = rule
+	}
+	for op, rule := range rules {
+		for name, rate := range map[string]float64{"error_rate": rule.ErrorRate, "latency_rate": rule.LatencyRate, "timeout_rate": rule.TimeoutRate} {
+			if rate < 0 || rate > 1 {
+				return fmt.Errorf("fault injection rule %s: %s must be between 0 and 1, got %v", op, name, rate)
+			}
+		}
+		if rule.Latency < 0 || rule.Timeout < 0 {
+			return fmt.Errorf("fault injection rule %s: durations cannot be negative", op)
+		}
+		if rule.StatusCode != 0 && (rule.StatusCode < 400 || rule.StatusCode > 599) {
+			return fmt.Errorf("fault injection rule %s: status_code must be an HTTP error status, got %d", op, rule.StatusCode)
+		}
+	}
+	return nil
+}
+
+// InjectedError is returned for injected errors and timeouts. Timeouts satisfy
+// net.Error's Timeout method so callers take their timeout paths.
+type InjectedError struct {
+	Operation string
+	Kind      string
+}
+
+func (e *InjectedError) Error() string {
+	return fmt.Sprintf("injected %s for operation %s", e.Kind, e.Operation)
+}
+
+func (e *InjectedError) Is(target error) bool {
+	return target == ErrInjected
+}
+
+func (e *InjectedError) Timeout() bool {
+	return e.Kind == KindTimeout
+}
+
+func (e *InjectedError) Temporary() bool {
+	return true
+}
+
+// Injector decides which requests fail. A nil *Injector injects nothing, so callers can
+// use the result of New unconditionally. It is safe for concurrent use.
+type Injector struct {
+	cfg Config
+
+	mu   sync.Mutex
+	rand *rand.Rand
+}
+
+// New returns an Injector for cfg, or nil if cfg is not enabled.
+func New(cfg Config) (*Injector, error) {
+	if !cfg.Enabled {
+		return nil, nil
+	}
+	if err := cfg.validate(); err != nil {
+		return nil, err
+	}
+	seed := cfg.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &Injector{cfg: cfg, rand: rand.New(rand.NewSource(seed))}, nil
+}
+
+func (i *Injector) rule(operation string) Rule {
+	if rule, ok := i.cfg.Operations[operation]; ok {
+		return rule
+	}
+	return i.cfg.Default
+}
+
+func (i *Injector) roll(rate float64) bool {
+	if rate <= 0 {
+		return false
+	}
+	i.mu.Lock()
+	defer i.mu.Unlock()
+	return i.rand.Float64() < rate
+}
+
+// Inject applies the faults configured for operation: it may sleep, hang until a
+// timeout, or return an *InjectedError. It returns ctx.Err() if ctx is done while sleeping.
+func (i *Injector) Inject(ctx context.Context, operation string) error {
+	if i == nil {
+		return nil
+	}
+	rule := i.rule(operation)
+	if i.roll(rule.LatencyRate) {
+		if err := sleep(ctx, rule.Latency); err != nil {
+			return err
+		}
+	}
+	if i.roll(rule.TimeoutRate) {
+		if rule.Timeout > 0 {
+			if err := sleep(ctx, rule.Timeout); err != nil {
+				return err
+			}
+		} else {
+			<-ctx.Done()
+		}
+		return &InjectedError{Operation: operation, Kind: KindTimeout}
+	}
+	if i.roll(rule.ErrorRate) {
+		return &InjectedError{Operation: operation, Kind: KindError}
+	}
+	return nil
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// Classifier names the operation of an HTTP request for rule lookup.
+type Classifier func(req *http.Request) string
+
+// Transport wraps base (http.DefaultTransport if nil) so requests are subject to
+// injection. It returns base unchanged when i is nil.
+func (i *Injector) Transport(base http.RoundTripper, classify Classifier) http.RoundTripper {
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	if i == nil {
+		return base
+	}
+	return &transport{injector: i, base: base, classify: classify}
+}
+
+// Client returns a copy of client whose transport is wrapped with Transport. It returns
+// client unchanged when i is nil.
+func (i *Injector) Client(client *http.Client, classify Classifier) *http.Client {
+	if i == nil {
+		return client
+	}
+	wrapped := *client
+	wrapped.Transport = i.Transport(client.Transport, classify)
+	return &wrapped
+}
+
+type transport struct {
+	injector *Injector
+	base     http.RoundTripper
+	classify Classifier
+}
+
+func (t *transport) RoundTrip(req *http.Request) (*http.Response, error) {
+	operation := req.Method
+	if t.classify != nil {
+		operation = t.classify(req)
+	}
+	err := t.injector.Inject(req.Context(), operation)
+	if err == nil {
+		return t.base.RoundTrip(req)
+	}
+	// A RoundTripper must close the request body, even on errors
+	if req.Body != nil {
+		req.Body.Close()
+	}
+	var injected *InjectedError
+	if status := t.injector.rule(operation).StatusCode; status != 0 && errors.As(err, &injected) && injected.Kind == KindError {
+		body := fmt.Sprintf(`{"error":{"type":"injected_fault","reason":%q},"status":%d}`, injected.Error(), status)
+		return &http.Response{
+			Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+			StatusCode:    status,
+			Proto:         "HTTP/1.1",
+			ProtoMajor:    1,
+			ProtoMinor:    1,
+			Header:        http.Header{"Content-Type": []string{"application/json"}},
+			Body:          io.NopCloser(bytes.NewBufferString(body)),
+			ContentLength: int64(len(body)),
+			Request:       req,
+		}, nil
+	}
+	return nil, err
+}
@@ -0,0 +1,67 @@
+package faultinject_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/opengovern/og-util/pkg/faultinject"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDisabledInjectorIsNoop(t *testing.T) {
+	require := require.New(t)
+
+	injector, err := faultinject.New(faultinject.Config{Default: faultinject.Rule{ErrorRate: 1}})
+	require.NoError(err)
+	require.Nil(injector)
+	require.NoError(injector.Inject(context.Background(), "_search"))
+	require.Equal(http.DefaultTransport, injector.Transport(nil, nil))
+}
+
+func TestInvalidRate(t *testing.T) {
+	_, err := faultinject.New(faultinject.Config{Enabled: true, Operations: map[string]faultinject.Rule{"_bulk": {ErrorRate: 1.5}}})
+	require.Error(t, err)
+}
+
+func TestTransport(t *testing.T) {
+	require := require.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	injector, err := faultinject.New(faultinject.Config{
+		Enabled: true,
+		Seed:    1,
+		Operations: map[string]faultinject.Rule{
+			"/error":   {ErrorRate: 1},
+			"/status":  {ErrorRate: 1, StatusCode: http.StatusTooManyRequests},
+			"/timeout": {TimeoutRate: 1, Timeout: 10 * time.Millisecond},
+		},
+	})
+	require.NoError(err)
+	client := injector.Client(server.Client(), func(req *http.Request) string { return req.URL.Path })
+
+	resp, err := client.Get(server.URL + "/ok")
+	require.NoError(err)
+	resp.Body.Close()
+	require.Equal(http.StatusOK, resp.StatusCode)
+
+	_, err = client.Get(server.URL + "/error")
+	require.ErrorIs(err, faultinject.ErrInjected)
+
+	resp, err = client.Get(server.URL + "/status")
+	require.NoError(err)
+	resp.Body.Close()
+	require.Equal(http.StatusTooManyRequests, resp.StatusCode)
+
+	_, err = client.Get(server.URL + "/timeout")
+	var injected *faultinject.InjectedError
+	require.True(errors.As(err, &injected))
+	require.True(injected.Timeout())
+}
@@ -10,6 +10,9 @@ type ResourceTypeConfiguration struct {
 	Description     string           `json:"description"`
 	Params          []Param          `json:"params"`
 	Table 			string			`json:"table"`
+	// Tagless marks resource types that cannot carry tags; see
+	// opengovernance.LoadTaglessResourceTypeCatalog. Unset if the plugin doesn't say.
+	Tagless *bool `json:"tagless,omitempty"`
 }
 
 type Param struct {
@@ -0,0 +1,319 @@
+package opengovernance
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// Aggregation is an interface for all aggregations (TermsAgg, DateHistogramAgg, etc.). A
+// map[string]Aggregation marshals to the "aggs" object of a search.
+type Aggregation interface {
+	IsAggregation()
+}
+
+// withSubAggs adds the "aggs" of sub-aggregations to an aggregation body.
+func withSubAggs(body map[string]any, subAggs map[string]Aggregation) map[string]any {
+	if len(subAggs) > 0 {
+		body["aggs"] = subAggs
+	}
+	return body
+}
+
+func addSubAgg(subAggs map[string]Aggregation, name string, agg Aggregation) map[string]Aggregation {
+	out := make(map[string]Aggregation, len(subAggs)+1)
+	for k, v := range subAggs {
+		out[k] = v
+	}
+	out[name] = agg
+	return out
+}
+
+// TermsAgg buckets documents by the values of a field.
+type TermsAgg struct {
+	field   string
+	size    int
+	missing string
+	subAggs map[string]Aggregation
+}
+
+// NewTermsAgg returns a terms aggregation over field with up to size buckets (the ES
+// default of 10 if size <= 0).
+func NewTermsAgg(field string, size int) TermsAgg {
+	return TermsAgg{field: field, size: size}
+}
+
+// Missing puts documents without the field into a bucket with key value.
+func (a TermsAgg) Missing(value string) TermsAgg {
+	a.missing = value
+	return a
+}
+
+// SubAgg adds a sub-aggregation computed per bucket.
+func (a TermsAgg) SubAgg(name string, agg Aggregation) TermsAgg {
+	a.subAggs = addSubAgg(a.subAggs, name, agg)
+	return a
+}
+
+func (a TermsAgg) MarshalJSON() ([]byte, error) {
+	terms := map[string]any{"field": a.field}
+	if a.size > 0 {
+		terms["size"] = a.size
+	}
+	if a.missing != "" {
+		terms["missing"] = a.missing
+	}
+	return json.Marshal(withSubAggs(map[string]any{"terms": terms}, a.subAggs))
+}
+func (a TermsAgg) IsAggregation() {}
+
+// DateHistogramAgg buckets documents by calendar intervals of a date field.
+type DateHistogramAgg struct {
+	field       string
+	interval    string
+	format      string
+	timeZone    string
+	minDocCount *int
+	subAggs     map[string]Aggregation
+}
+
+// NewDateHistogramAgg returns a date histogram over field with a calendar interval such
+// as "day", "week" or "month".
+func NewDateHistogramAgg(field, calendarInterval string) DateHistogramAgg {
+	return DateHistogramAgg{field: field, interval: calendarInterval}
+}
+
+// Format sets the date format of the bucket keys, e.g. "yyyy-MM-dd".
+func (a DateHistogramAgg) Format(format string) DateHistogramAgg {
+	a.format = format
+	return a
+}
+
+// TimeZone sets the time zone buckets are aligned to, e.g. "UTC" or "+02:00".
+func (a DateHistogramAgg) TimeZone(timeZone string) DateHistogramAgg {
+	a.timeZone = timeZone
+	return a
+}
+
+// MinDocCount omits buckets with fewer documents; 0 returns empty buckets too.
+func (a DateHistogramAgg) MinDocCount(count int) DateHistogramAgg {
+	a.minDocCount = &count
+	return a
+}
+
+// SubAgg adds a sub-aggregation computed per bucket.
+func (a DateHistogramAgg) SubAgg(name string, agg Aggregation) DateHistogramAgg {
+	a.subAggs = addSubAgg(a.subAggs, name, agg)
+	return a
+}
+
+func (a DateHistogramAgg) MarshalJSON() ([]byte, error) {
+	histogram := map[string]any{
+		"field":             a.field,
+		"calendar_interval": a.interval,
+	}
+	if a.format != "" {
+		histogram["format"] = a.format
+	}
+	if a.timeZone != "" {
+		histogram["time_zone"] = a.timeZone
+	}
+	if a.minDocCount != nil {
+		histogram["min_doc_count"] = *a.minDocCount
+	}
+	return json.Marshal(withSubAggs(map[string]any{"date_histogram": histogram}, a.subAggs))
+}
+func (a DateHistogramAgg) IsAggregation() {}
+
+// CardinalityAgg approximates the number of distinct values of a field.
+type CardinalityAgg struct {
+	field              string
+	precisionThreshold int
+}
+
+// NewCardinalityAgg returns a cardinality aggregation over field.
+func NewCardinalityAgg(field string) CardinalityAgg {
+	return CardinalityAgg{field: field}
+}
+
+// PrecisionThreshold sets the count below which results are expected to be exact.
+func (a CardinalityAgg) PrecisionThreshold(threshold int) CardinalityAgg {
+	a.precisionThreshold = threshold
+	return a
+}
+
+func (a CardinalityAgg) MarshalJSON() ([]byte, error) {
+	cardinality := map[string]any{"field": a.field}
+	if a.precisionThreshold > 0 {
+		cardinality["precision_threshold"] = a.precisionThreshold
+	}
+	return json.Marshal(map[string]any{"cardinality": cardinality})
+}
+func (a CardinalityAgg) IsAggregation() {}
+
+// NestedAgg aggregates over the nested documents at path, e.g. canonical_tags.
+type NestedAgg struct {
+	path    string
+	subAggs map[string]Aggregation
+}
+
+// NewNestedAgg returns a nested aggregation; add the aggregations to run over the nested
+// documents with SubAgg.
+func NewNestedAgg(path string) NestedAgg {
+	return NestedAgg{path: path}
+}
+
+// SubAgg adds an aggregation over the nested documents.
+func (a NestedAgg) SubAgg(name string, agg Aggregation) NestedAgg {
+	a.subAggs = addSubAgg(a.subAggs, name, agg)
+	return a
+}
+
+func (a NestedAgg) MarshalJSON() ([]byte, error) {
+	return json.Marshal(withSubAggs(map[string]any{"nested": map[string]any{"path": a.path}}, a.subAggs))
+}
+func (a NestedAgg) IsAggregation() {}
+
+// AggregationResponse decodes the aggregations of a search response.
+type AggregationResponse struct {
+	Aggregations AggregationResults `json:"aggregations"`
+}
+
+// AggregationResults holds raw aggregation results by name, decoded on access with the
+// accessor matching the aggregation type.
+type AggregationResults map[string]json.RawMessage
+
+// Buckets decodes the terms or date histogram aggregation name.
+func (r AggregationResults) Buckets(name string) (*BucketAggregation, error) {
+	var result BucketAggregation
+	if err := r.decode(name, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Cardinality decodes the cardinality aggregation name.
+func (r AggregationResults) Cardinality(name string) (int64, error) {
+	var result struct {
+		Value int64 `json:"value"`
+	}
+	if err := r.decode(name, &result); err != nil {
+		return 0, err
+	}
+	return result.Value, nil
+}
+
+// Nested decodes the nested aggregation name.
+func (r AggregationResults) Nested(name string) (*NestedAggregation, error) {
+	var result NestedAggregation
+	if err := r.decode(name, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+func (r AggregationResults) decode(name string, v any) error {
+	raw, ok := r[name]
+	if !ok {
+		return fmt.Errorf("aggregation %s not found in response", name)
+	}
+	if err := json.Unmarshal(raw, v); err != nil {
+		return fmt.Errorf("unmarshal aggregation %s: %w", name, err)
+	}
+	return nil
+}
+
+// BucketAggregation is the result of a terms or date histogram aggregation.
+type BucketAggregation struct {
+	Buckets                 []Bucket `json:"buckets"`
+	DocCountErrorUpperBound int64    `json:"doc_count_error_upper_bound"`
+	SumOtherDocCount        int64    `json:"sum_other_doc_count"`
+}
+
+// Bucket is one bucket of a BucketAggregation with its sub-aggregation results.
+type Bucket struct {
+	Key          any    // string for terms on keyword fields, a number for numbers and dates
+	KeyAsString  string // Formatted key of date histograms
+	DocCount     int64
+	Aggregations AggregationResults
+}
+
+// KeyString returns the bucket key as a string, preferring the formatted key.
+func (b Bucket) KeyString() string {
+	if b.KeyAsString != "" {
+		return b.KeyAsString
+	}
+	switch key := b.Key.(type) {
+	case string:
+		return key
+	case json.Number:
+		return key.String()
+	case float64:
+		return strconv.FormatFloat(key, 'f', -1, 64)
+	default:
+		return fmt.Sprint(key)
+	}
+}
+
+func (b *Bucket) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	*b = Bucket{}
+	for name, raw := range fields {
+		var err error
+		switch name {
+		case "key":
+			err = unmarshalNumber(raw, &b.Key)
+		case "key_as_string":
+			err = json.Unmarshal(raw, &b.KeyAsString)
+		case "doc_count":
+			err = json.Unmarshal(raw, &b.DocCount)
+		default:
+			if b.Aggregations == nil {
+				b.Aggregations = make(AggregationResults)
+			}
+			b.Aggregations[name] = raw
+		}
+		if err != nil {
+			return fmt.Errorf("unmarshal bucket field %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// NestedAggregation is the result of a nested aggregation.
+type NestedAggregation struct {
+	DocCount     int64
+	Aggregations AggregationResults
+}
+
+func (n *NestedAggregation) UnmarshalJSON(data []byte) error {
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal(data, &fields); err != nil {
+		return err
+	}
+	*n = NestedAggregation{Aggregations: make(AggregationResults)}
+	for name, raw := range fields {
+		if name == "doc_count" {
+			if err := json.Unmarshal(raw, &n.DocCount); err != nil {
+				return fmt.Errorf("unmarshal doc_count: %w", err)
+			}
+			continue
+		}
+		n.Aggregations[name] = raw
+	}
+	return nil
+}
+
+// unmarshalNumber decodes raw keeping numbers as json.Number, so large keys such as epoch
+// milliseconds stay exact.
+func unmarshalNumber(raw json.RawMessage, v *any) error {
+	var number json.Number
+	if err := json.Unmarshal(raw, &number); err == nil {
+		*v = number
+		return nil
+	}
+	return json.Unmarshal(raw, v)
+}
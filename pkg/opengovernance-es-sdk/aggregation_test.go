@@ -0,0 +1,68 @@
+package opengovernance_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	opengovernance "github.com/opengovern/og-util/pkg/opengovernance-es-sdk"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAggregations(t *testing.T) {
+	require := require.New(t)
+
+	aggs := map[string]opengovernance.Aggregation{
+		"by_type": opengovernance.NewTermsAgg("resource_type", 50).
+			SubAgg("integrations", opengovernance.NewCardinalityAgg("integration_id").PrecisionThreshold(1000)).
+			SubAgg("per_day", opengovernance.NewDateHistogramAgg("described_at", "day").Format("yyyy-MM-dd").MinDocCount(0)),
+		"tags": opengovernance.NewNestedAgg("canonical_tags").
+			SubAgg("keys", opengovernance.NewTermsAgg("canonical_tags.key", 0)),
+	}
+	data, err := json.Marshal(aggs)
+	require.NoError(err)
+	require.JSONEq(`{
+		"by_type": {
+			"terms": {"field": "resource_type", "size": 50},
+			"aggs": {
+				"integrations": {"cardinality": {"field": "integration_id", "precision_threshold": 1000}},
+				"per_day": {"date_histogram": {"field": "described_at", "calendar_interval": "day", "format": "yyyy-MM-dd", "min_doc_count": 0}}
+			}
+		},
+		"tags": {"nested": {"path": "canonical_tags"}, "aggs": {"keys": {"terms": {"field": "canonical_tags.key"}}}}
+	}`, string(data))
+
+	var response opengovernance.AggregationResponse
+	require.NoError(json.Unmarshal([]byte(`{"aggregations": {
+		"by_type": {"sum_other_doc_count": 3, "buckets": [{
+			"key": "AWS::EC2::Instance", "doc_count": 7,
+			"integrations": {"value": 2},
+			"per_day": {"buckets": [{"key": 1717200000000, "key_as_string": "2024-06-01", "doc_count": 7}]}
+		}]},
+		"tags": {"doc_count": 12, "keys": {"buckets": [{"key": "env", "doc_count": 5}]}}
+	}}`), &response))
+
+	byType, err := response.Aggregations.Buckets("by_type")
+	require.NoError(err)
+	require.Equal(int64(3), byType.SumOtherDocCount)
+	require.Len(byType.Buckets, 1)
+	bucket := byType.Buckets[0]
+	require.Equal("AWS::EC2::Instance", bucket.KeyString())
+	require.Equal(int64(7), bucket.DocCount)
+	integrations, err := bucket.Aggregations.Cardinality("integrations")
+	require.NoError(err)
+	require.Equal(int64(2), integrations)
+	perDay, err := bucket.Aggregations.Buckets("per_day")
+	require.NoError(err)
+	require.Equal("2024-06-01", perDay.Buckets[0].KeyString())
+	require.Equal(json.Number("1717200000000"), perDay.Buckets[0].Key)
+
+	tags, err := response.Aggregations.Nested("tags")
+	require.NoError(err)
+	require.Equal(int64(12), tags.DocCount)
+	keys, err := tags.Aggregations.Buckets("keys")
+	require.NoError(err)
+	require.Equal("env", keys.Buckets[0].KeyString())
+
+	_, err = response.Aggregations.Buckets("missing")
+	require.Error(err)
+}
@@ -0,0 +1,226 @@
+package opengovernance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"golang.org/x/time/rate"
+)
+
+// BackfillCheckpoint records how far a backfill got. A run resumes after SearchAfter, the
+// last document of the last page it wrote.
+type BackfillCheckpoint struct {
+	SearchAfter SearchAfter `json:"search_after"`
+	Processed   int64       `json:"processed"` // Documents read
+	Updated     int64       `json:"updated"`   // Documents written
+	Skipped     int64       `json:"skipped"`   // Documents the transform left unchanged
+	Failed      int64       `json:"failed"`    // Documents whose update failed
+	Done        bool        `json:"done"`
+	UpdatedAt   time.Time   `json:"updated_at"`
+}
+
+// BackfillCheckpointStore persists backfill checkpoints by run name.
+type BackfillCheckpointStore interface {
+	// Load returns the checkpoint of name, or nil if the run never saved one.
+	Load(ctx context.Context, name string) (*BackfillCheckpoint, error)
+	Save(ctx context.Context, name string, checkpoint BackfillCheckpoint) error
+}
+
+// BackfillOptions configures Backfill.
+type BackfillOptions struct {
+	Index         string
+	Filters       []BoolFilter             // Restricts the documents backfilled
+	BatchSize     int64                    // Documents read and written at a time (default DefaultPageSize)
+	RatePerSecond float64                  // Maximum documents processed per second; unlimited if zero
+	Strategies    map[string]MergeStrategy // Merge strategies of the written fields
+	Refresh       string                   // Refresh of each bulk write, see UpsertOptions
+
+	// Name and Checkpoints make the run resumable: progress is saved after every batch
+	// and a run with the same name continues where the last one stopped.
+	Name        string
+	Checkpoints BackfillCheckpointStore
+
+	OnProgress func(BackfillCheckpoint) // Called after every batch
+}
+
+// BackfillTransform returns the fields to write to the document of hit, or nil to leave it
+// unchanged. Returning an error stops the backfill before its batch is written.
+type BackfillTransform[T any] func(hit Hit[T]) (map[string]any, error)
+
+// Backfill runs transform over every document of opts.Index matching opts.Filters, e.g.
+// to populate a newly added derived field, and writes the returned fields as partial
+// updates with one bulk request per batch. Documents deleted in the meantime are not
+// recreated. Failed updates are counted and logged but don't stop the run.
+//
+// Documents are read in _id order, so a run resumed from a checkpoint repeats at most the
+// batch that was in flight; transforms should be idempotent. The returned checkpoint
+// reflects the progress made, also on error.
+func Backfill[T any](ctx context.Context, c Client, opts BackfillOptions, transform BackfillTransform[T]) (BackfillCheckpoint, error) {
+	var checkpoint BackfillCheckpoint
+	if opts.Index == "" {
+		return checkpoint, errors.New("backfill requires an index")
+	}
+	if opts.Checkpoints != nil && opts.Name == "" {
+		return checkpoint, errors.New("backfill with checkpoints requires a name")
+	}
+
+	if opts.Checkpoints != nil {
+		saved, err := opts.Checkpoints.Load(ctx, opts.Name)
+		if err != nil {
+			return checkpoint, fmt.Errorf("load checkpoint: %w", err)
+		}
+		if saved != nil {
+			checkpoint = *saved
+		}
+		if checkpoint.Done {
+			return checkpoint, nil
+		}
+	}
+
+	p, err := NewPaginatorWithSort(c.ES(), opts.Index, opts.Filters, nil, []map[string]any{{"_id": "asc"}})
+	if err != nil {
+		return checkpoint, err
+	}
+	batchSize := opts.BatchSize
+	if batchSize <= 0 {
+		batchSize = DefaultPageSize()
+	}
+	// Rate limiting may hold a page for longer than the PIT's keep-alive
+	p.WithLogger(c.logger).WithPageSize(batchSize).WithAutoKeepAlive(PITKeepAliveOptions{})
+	p.searchAfter = checkpoint.SearchAfter
+	defer p.Close(context.WithoutCancel(ctx))
+
+	var limiter *rate.Limiter
+	if opts.RatePerSecond > 0 {
+		limiter = rate.NewLimiter(rate.Limit(opts.RatePerSecond), int(p.PageSize()))
+	}
+
+	if checkpoint.Processed > 0 {
+		logAt(ctx, c.logger, zapcore.InfoLevel, "resuming backfill",
+			zap.String("name", opts.Name), zap.String("index", opts.Index), zap.Int64("processed", checkpoint.Processed))
+	}
+	for !p.Done() {
+		hits, err := NextPage[T](ctx, p)
+		if err != nil {
+			return checkpoint, err
+		}
+		if len(hits) == 0 {
+			break
+		}
+
+		docs := make([]UpsertDocument, 0, len(hits))
+		for _, hit := range hits {
+			fields, err := transform(hit)
+			if err != nil {
+				return checkpoint, fmt.Errorf("transform %s/%s: %w", hit.Index, hit.ID, err)
+			}
+			if fields == nil {
+				checkpoint.Skipped++
+				continue
+			}
+			docs = append(docs, UpsertDocument{Index: hit.Index, ID: hit.ID, Doc: fields, Strategies: opts.Strategies})
+		}
+
+		if limiter != nil {
+			if err := limiter.WaitN(ctx, min(len(hits), limiter.Burst())); err != nil {
+				return checkpoint, err
+			}
+		}
+		res, err := c.Upsert(ctx, docs, UpsertOptions{Refresh: opts.Refresh, UpdateOnly: true})
+		if err != nil {
+			return checkpoint, fmt.Errorf("write batch: %w", err)
+		}
+		checkpoint.Updated += int64(res.Succeeded)
+		for _, failure := range res.Failed {
+			if failure.Status == http.StatusNotFound {
+				// Deleted since it was read
+				checkpoint.Skipped++
+				continue
+			}
+			checkpoint.Failed++
+			logAt(ctx, c.logger, zapcore.WarnLevel, "backfill update failed",
+				zap.String("name", opts.Name), zap.Error(failure))
+		}
+
+		checkpoint.Processed += int64(len(hits))
+		checkpoint.SearchAfter = hits[len(hits)-1].Sort
+		checkpoint.Done = p.Done()
+		if err := saveBackfillCheckpoint(ctx, opts, &checkpoint); err != nil {
+			return checkpoint, err
+		}
+	}
+
+	if !checkpoint.Done {
+		checkpoint.Done = true
+		if err := saveBackfillCheckpoint(ctx, opts, &checkpoint); err != nil {
+			return checkpoint, err
+		}
+	}
+	logAt(ctx, c.logger, zapcore.InfoLevel, "backfill done",
+		zap.String("name", opts.Name), zap.String("index", opts.Index),
+		zap.Int64("processed", checkpoint.Processed), zap.Int64("updated", checkpoint.Updated),
+		zap.Int64("skipped", checkpoint.Skipped), zap.Int64("failed", checkpoint.Failed))
+	return checkpoint, nil
+}
+
+func saveBackfillCheckpoint(ctx context.Context, opts BackfillOptions, checkpoint *BackfillCheckpoint) error {
+	checkpoint.UpdatedAt = time.Now().UTC()
+	if opts.Checkpoints != nil {
+		if err := opts.Checkpoints.Save(ctx, opts.Name, *checkpoint); err != nil {
+			return fmt.Errorf("save checkpoint: %w", err)
+		}
+	}
+	if opts.OnProgress != nil {
+		opts.OnProgress(*checkpoint)
+	}
+	return nil
+}
+
+// IndexCheckpointStore is a BackfillCheckpointStore keeping each checkpoint as a document
+// of an index, named after the run, so that any replica can resume a backfill.
+type IndexCheckpointStore struct {
+	client Client
+	index  string
+}
+
+// NewIndexCheckpointStore returns a store keeping checkpoints in index.
+func NewIndexCheckpointStore(client Client, index string) *IndexCheckpointStore {
+	return &IndexCheckpointStore{client: client, index: index}
+}
+
+func (s *IndexCheckpointStore) Load(ctx context.Context, name string) (*BackfillCheckpoint, error) {
+	result, err := MGetMulti[BackfillCheckpoint](ctx, s.client, map[string][]string{s.index: {name}}, MGetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	checkpoint, ok := result.Get(s.index, name)
+	if !ok {
+		return nil, nil
+	}
+	return &checkpoint, nil
+}
+
+func (s *IndexCheckpointStore) Save(ctx context.Context, name string, checkpoint BackfillCheckpoint) error {
+	b, err := json.Marshal(checkpoint)
+	if err != nil {
+		return fmt.Errorf("marshal checkpoint: %w", err)
+	}
+	var doc map[string]any
+	if err := json.Unmarshal(b, &doc); err != nil {
+		return fmt.Errorf("unmarshal checkpoint: %w", err)
+	}
+	res, err := s.client.Upsert(ctx, []UpsertDocument{{Index: s.index, ID: name, Doc: doc}}, UpsertOptions{})
+	if err != nil {
+		return err
+	}
+	if len(res.Failed) > 0 {
+		return res.Failed[0]
+	}
+	return nil
+}
@@ -0,0 +1,132 @@
+package opengovernance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	backpressureBaseCooldown = 5 * time.Second
+	backpressureMaxCooldown  = 2 * time.Minute
+)
+
+// backpressureErrorTypes are the ES/OpenSearch error types that signal the cluster is
+// shedding load rather than failing the request itself.
+var backpressureErrorTypes = []string{
+	"circuit_breaking_exception",
+	"es_rejected_execution_exception",
+	"rejected_execution_exception",
+}
+
+// BackpressureError is returned when the cluster rejects a request because it is
+// overloaded (HTTP 429, circuit breaker tripped, or thread pool queue full). Callers
+// such as ingestion pipelines should pause for at least RetryAfter instead of
+// retrying immediately.
+type BackpressureError struct {
+	StatusCode int
+	Type       string
+	Reason     string
+	RetryAfter time.Duration // Server-provided Retry-After if present, otherwise the base cool-down
+	cause      error
+}
+
+func (e *BackpressureError) Error() string {
+	return fmt.Sprintf("cluster backpressure (status %d, %s): %s; retry after %s", e.StatusCode, e.Type, e.Reason, e.RetryAfter)
+}
+
+// Unwrap exposes the underlying ErrorResponse so errors.As/IsIndexNotFoundErr keep working.
+func (e *BackpressureError) Unwrap() error {
+	return e.cause
+}
+
+// IsBackpressureErr checks if error is a BackpressureError
+func IsBackpressureErr(err error) bool {
+	var e *BackpressureError
+	return errors.As(err, &e)
+}
+
+// BackpressureCooldown returns how long to wait before retry attempt number attempt
+// (0-based) after err. Backpressure errors get an exponential cool-down starting at
+// 5s and capped at 2m, raised to the server's Retry-After when that is longer.
+// Other errors get a linear one-second-per-attempt delay, matching previous behavior.
+func BackpressureCooldown(err error, attempt int) time.Duration {
+	var e *BackpressureError
+	if !errors.As(err, &e) {
+		return time.Duration(attempt+1) * time.Second
+	}
+	cooldown := backpressureBaseCooldown
+	for i := 0; i < attempt && cooldown < backpressureMaxCooldown; i++ {
+		cooldown *= 2
+	}
+	if cooldown > backpressureMaxCooldown {
+		cooldown = backpressureMaxCooldown
+	}
+	if e.RetryAfter > cooldown {
+		cooldown = e.RetryAfter
+	}
+	return cooldown
+}
+
+// WaitBackpressure sleeps for BackpressureCooldown(err, attempt), returning early with
+// ctx.Err() if the context is cancelled first.
+func WaitBackpressure(ctx context.Context, err error, attempt int) error {
+	timer := time.NewTimer(BackpressureCooldown(err, attempt))
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+// classifyError wraps e as a BackpressureError if the status code or error type
+// (including any root cause) indicates the cluster is shedding load.
+func classifyError(statusCode int, header http.Header, e ErrorResponse) error {
+	errType, isBackpressure := backpressureType(e.Info)
+	if !isBackpressure && statusCode != http.StatusTooManyRequests {
+		return e
+	}
+	if errType == "" {
+		errType = e.Info.Type
+	}
+	return &BackpressureError{
+		StatusCode: statusCode,
+		Type:       errType,
+		Reason:     e.Info.Reason,
+		RetryAfter: parseRetryAfter(header),
+		cause:      e,
+	}
+}
+
+// backpressureType returns the first backpressure error type found in info or its root causes.
+func backpressureType(info ErrorInfo) (string, bool) {
+	for _, t := range backpressureErrorTypes {
+		if strings.EqualFold(info.Type, t) {
+			return info.Type, true
+		}
+	}
+	for _, rc := range info.RootCause {
+		if t, ok := backpressureType(rc); ok {
+			return t, true
+		}
+	}
+	return "", false
+}
+
+// parseRetryAfter reads a Retry-After header in seconds, falling back to the base cool-down.
+func parseRetryAfter(header http.Header) time.Duration {
+	if header != nil {
+		if v := header.Get("Retry-After"); v != "" {
+			if seconds, err := strconv.Atoi(strings.TrimSpace(v)); err == nil && seconds > 0 {
+				return time.Duration(seconds) * time.Second
+			}
+		}
+	}
+	return backpressureBaseCooldown
+}
@@ -2,16 +2,15 @@ package opengovernance
 
 import (
 	"context"
-	"crypto/tls"
-	"fmt"
 	"io"
 	"strconv"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
 	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/opengovern/og-util/pkg/faultinject"
 	"github.com/opensearch-project/opensearch-go/v2/opensearchapi"
 
-	"net/http"
 	"os"
 
 	"github.com/aws/aws-sdk-go-v2/config"
@@ -20,16 +19,10 @@ import (
 	"github.com/turbot/steampipe-plugin-sdk/v5/connection"
 	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
 	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/schema"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
-type ResourceCollectionFilter struct {
-	Connectors    []string          `json:"connectors"`
-	AccountIDs    []string          `json:"account_ids"`
-	Regions       []string          `json:"regions"`
-	ResourceTypes []string          `json:"resource_types"`
-	Tags          map[string]string `json:"tags"`
-}
-
 type ClientConfig struct {
 	Addresses []string `cty:"addresses"`
 	Username  *string  `cty:"username"`
@@ -37,10 +30,25 @@ type ClientConfig struct {
 
 	IsOpenSearch  *bool   `cty:"is_open_search"`
 	AwsRegion     *string `cty:"aws_region"`
+	AwsService    *string `cty:"aws_service"` // SigV4 service name: "es" (default) or "aoss" for serverless collections
 	AssumeRoleArn *string `cty:"assume_role_arn"`
 	ExternalID    *string `cty:"external_id"`
 
 	IsOnAks *bool `cty:"is_on_aks"`
+
+	// FaultInjection enables injected errors and latency on ES requests in test environments.
+	// If nil, it is read as JSON from ELASTICSEARCH_FAULT_INJECTION.
+	FaultInjection *faultinject.Config
+
+	// Transport configures TLS verification, client certificates and the proxy. If nil,
+	// it is read from the ELASTICSEARCH_CA_CERT_FILE, ELASTICSEARCH_CLIENT_CERT_FILE,
+	// ELASTICSEARCH_CLIENT_KEY_FILE and ELASTICSEARCH_PROXY environment variables; if
+	// none are set, server certificates are not verified.
+	Transport *TransportConfig
+
+	// Logger, if set, is used by the client instead of the context's steampipe logger or
+	// the default logger; see SetLogger.
+	Logger *zap.Logger
 }
 
 func ConfigSchema() map[string]*schema.Attribute {
@@ -63,6 +71,10 @@ func ConfigSchema() map[string]*schema.Attribute {
 			Type:     schema.TypeString,
 			Required: false,
 		},
+		"aws_service": {
+			Type:     schema.TypeString,
+			Required: false,
+		},
 		"assume_role_arn": {
 			Type:     schema.TypeString,
 			Required: false,
@@ -87,7 +99,22 @@ func GetConfig(connection *plugin.Connection) ClientConfig {
 }
 
 type Client struct {
-	es *opensearch.Client
+	es      *opensearch.Client
+	limiter *searchLimiter // nil unless SetSearchLimits was called
+	hedger  *hedger        // nil unless SetHedging was called
+	retrier *retrier       // nil unless SetRetryPolicy was called
+
+	staleCache *staleCache // nil unless SetStaleCache was called
+
+	strictIndices   bool                    // See SetStrictIndices
+	responseHooks   []ResponseHook          // See AddResponseHook
+	instrumentation []SearchInstrumentation // See AddSearchInstrumentation
+	logger          *zap.Logger             // See SetLogger
+
+	searchTimeout time.Duration // See SetSearchTimeout
+	pitTimeout    time.Duration // See SetPITTimeout
+
+	transport *reconnectingTransport // nil for clients set with SetES
 }
 
 func NewClientCached(c ClientConfig, cache *connection.ConnectionCache, ctx context.Context) (Client, error) {
@@ -139,6 +166,13 @@ func NewClient(c ClientConfig) (Client, error) {
 		}
 	}
 
+	if c.AwsService == nil || len(*c.AwsService) == 0 {
+		awsService := os.Getenv("ELASTICSEARCH_AWS_SERVICE")
+		if len(awsService) > 0 {
+			c.AwsService = &awsService
+		}
+	}
+
 	if c.AssumeRoleArn == nil || len(*c.AssumeRoleArn) == 0 {
 		assumeRoleArn := os.Getenv("ELASTICSEARCH_ASSUME_ROLE_ARN")
 		if len(assumeRoleArn) > 0 {
@@ -161,22 +195,44 @@ func NewClient(c ClientConfig) (Client, error) {
 		}
 	}
 
-	fmt.Println("ES Addresses:", c.Addresses)
-	fmt.Println("ES Username:", c.Username)
-	fmt.Println("ES IsOpenSearch:", c.IsOpenSearch)
-	fmt.Println("ES AwsRegion:", c.AwsRegion)
-	fmt.Println("ES AssumeRoleArn:", c.AssumeRoleArn)
-	fmt.Println("ES ExternalID:", c.ExternalID)
+	logAt(context.Background(), c.Logger, zapcore.InfoLevel, "creating elasticsearch client",
+		zap.Strings("addresses", c.Addresses),
+		zap.Stringp("username", c.Username),
+		zap.Boolp("is_open_search", c.IsOpenSearch),
+		zap.Stringp("aws_region", c.AwsRegion),
+		zap.Stringp("aws_service", c.AwsService),
+		zap.Stringp("assume_role_arn", c.AssumeRoleArn),
+		zap.Stringp("external_id", c.ExternalID),
+	)
+	if c.Transport == nil {
+		transportConfig, err := transportConfigFromEnv()
+		if err != nil {
+			return Client{}, err
+		}
+		c.Transport = transportConfig
+	}
+	transport, err := newReconnectingTransport(c.Transport)
+	if err != nil {
+		return Client{}, err
+	}
 	cfg := opensearch.Config{
 		Addresses:           c.Addresses,
 		Username:            *c.Username,
 		Password:            *c.Password,
 		CompressRequestBody: true,
-		Transport: &http.Transport{
-			TLSClientConfig: &tls.Config{
-				InsecureSkipVerify: true, //nolint,gosec
-			},
-		},
+		Transport:           transport,
+	}
+
+	faultConfig, err := faultInjectionConfig(c)
+	if err != nil {
+		return Client{}, err
+	}
+	if faultConfig != nil {
+		injector, err := faultinject.New(*faultConfig)
+		if err != nil {
+			return Client{}, err
+		}
+		cfg.Transport = injector.Transport(cfg.Transport, esOperation)
 	}
 
 	if c.IsOpenSearch != nil && *c.IsOpenSearch && (c.IsOnAks == nil || *c.IsOnAks == false) {
@@ -191,6 +247,7 @@ func NewClient(c ClientConfig) (Client, error) {
 		if c.AssumeRoleArn != nil && len(*c.AssumeRoleArn) > 0 {
 			awsConfig, err = config.LoadDefaultConfig(
 				context.Background(),
+				config.WithRegion(awsConfig.Region),
 				config.WithCredentialsProvider(
 					stscreds.NewAssumeRoleProvider(
 						sts.NewFromConfig(awsConfig),
@@ -206,7 +263,11 @@ func NewClient(c ClientConfig) (Client, error) {
 			}
 		}
 
-		awsSigner, err := signer.NewSigner(awsConfig)
+		service := "es"
+		if c.AwsService != nil && len(*c.AwsService) > 0 {
+			service = *c.AwsService
+		}
+		awsSigner, err := signer.NewSignerWithService(awsConfig, service)
 		if err != nil {
 			return Client{}, err
 		}
@@ -218,7 +279,27 @@ func NewClient(c ClientConfig) (Client, error) {
 		return Client{}, err
 	}
 
-	return Client{es: es}, nil
+	return Client{es: es, logger: c.Logger, transport: transport}, nil
+}
+
+// NewClientWithSigV4 creates a client for an AWS managed OpenSearch domain, or a
+// serverless collection with service "aoss", signing requests with SigV4 using the
+// ambient AWS credentials. An empty region uses the ambient region and an empty service
+// defaults to "es".
+func NewClientWithSigV4(addresses []string, region string, service string) (Client, error) {
+	isOpenSearch, isOnAks := true, false
+	c := ClientConfig{
+		Addresses:    addresses,
+		IsOpenSearch: &isOpenSearch,
+		IsOnAks:      &isOnAks,
+	}
+	if region != "" {
+		c.AwsRegion = &region
+	}
+	if service != "" {
+		c.AwsService = &service
+	}
+	return NewClient(c)
 }
 
 func (c Client) ES() *opensearch.Client {
@@ -227,6 +308,7 @@ func (c Client) ES() *opensearch.Client {
 
 func (c *Client) SetES(es *opensearch.Client) {
 	c.es = es
+	c.transport = nil
 }
 
 func (c *Client) Delete(docID, index string) error {
@@ -241,14 +323,16 @@ func (c *Client) Delete(docID, index string) error {
 		if res != nil {
 			b, _ = io.ReadAll(res.Body)
 		}
-		fmt.Printf("failure while querying es: %v\n%s\n", err, string(b))
+		logAt(context.Background(), c.logger, zapcore.WarnLevel, "failure while querying es",
+			zap.Error(err), zap.String("index", index), zap.String("response", string(b)))
 		return err
 	} else if err := CheckError(res); err != nil {
 		var b []byte
 		if res != nil {
 			b, _ = io.ReadAll(res.Body)
 		}
-		fmt.Printf("failure while querying es: %v\n%s\n", err, string(b))
+		logAt(context.Background(), c.logger, zapcore.WarnLevel, "failure while querying es",
+			zap.Error(err), zap.String("index", index), zap.String("response", string(b)))
 		return err
 	}
 
@@ -0,0 +1,112 @@
+package opengovernance
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+// TransportConfig configures certificate verification, client certificates and the
+// proxy of the client's HTTP transport.
+type TransportConfig struct {
+	CACert             []byte // PEM CA bundle trusted in addition to the system roots
+	ClientCert         []byte // PEM client certificate for mTLS; requires ClientKey
+	ClientKey          []byte // PEM private key of ClientCert
+	InsecureSkipVerify bool   // Don't verify the server certificate
+	Proxy              string // Proxy URL; HTTPS_PROXY and HTTP_PROXY are used if empty
+}
+
+// NewClientFromConfig creates a client like NewClient, with the TLS and proxy settings of
+// transport.
+func NewClientFromConfig(c ClientConfig, transport TransportConfig) (Client, error) {
+	c.Transport = &transport
+	return NewClient(c)
+}
+
+// transportConfigFromEnv reads a TransportConfig from the PEM files named by
+// ELASTICSEARCH_CA_CERT_FILE, ELASTICSEARCH_CLIENT_CERT_FILE and
+// ELASTICSEARCH_CLIENT_KEY_FILE and the URL in ELASTICSEARCH_PROXY. It returns nil if
+// none are set.
+func transportConfigFromEnv() (*TransportConfig, error) {
+	var t TransportConfig
+	set := false
+	for _, file := range []struct {
+		env  string
+		dest *[]byte
+	}{
+		{"ELASTICSEARCH_CA_CERT_FILE", &t.CACert},
+		{"ELASTICSEARCH_CLIENT_CERT_FILE", &t.ClientCert},
+		{"ELASTICSEARCH_CLIENT_KEY_FILE", &t.ClientKey},
+	} {
+		path := os.Getenv(file.env)
+		if path == "" {
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", file.env, err)
+		}
+		*file.dest = data
+		set = true
+	}
+	if proxy := os.Getenv("ELASTICSEARCH_PROXY"); proxy != "" {
+		t.Proxy = proxy
+		set = true
+	}
+	if !set {
+		return nil, nil
+	}
+	return &t, nil
+}
+
+// newHTTPTransport builds the client's transport. Without a config, server certificates
+// are not verified.
+func newHTTPTransport(t *TransportConfig) (*http.Transport, error) {
+	if t == nil {
+		return &http.Transport{
+			TLSClientConfig: &tls.Config{
+				InsecureSkipVerify: true, //nolint,gosec
+			},
+		}, nil
+	}
+
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		InsecureSkipVerify: t.InsecureSkipVerify, //nolint,gosec
+	}
+	if len(t.CACert) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil {
+			pool = x509.NewCertPool()
+		}
+		if !pool.AppendCertsFromPEM(t.CACert) {
+			return nil, errors.New("no certificates found in CA bundle")
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if len(t.ClientCert) > 0 || len(t.ClientKey) > 0 {
+		if len(t.ClientCert) == 0 || len(t.ClientKey) == 0 {
+			return nil, errors.New("client certificate and key must be set together")
+		}
+		cert, err := tls.X509KeyPair(t.ClientCert, t.ClientKey)
+		if err != nil {
+			return nil, fmt.Errorf("load client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+	if t.Proxy != "" {
+		proxy, err := url.Parse(t.Proxy)
+		if err != nil {
+			return nil, fmt.Errorf("parse proxy url: %w", err)
+		}
+		transport.Proxy = http.ProxyURL(proxy)
+	}
+	return transport, nil
+}
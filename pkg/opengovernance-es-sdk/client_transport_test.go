@@ -0,0 +1,77 @@
+package opengovernance_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	opengovernance "github.com/opengovern/og-util/pkg/opengovernance-es-sdk"
+	"github.com/stretchr/testify/require"
+)
+
+func TestNewClientFromConfigMutualTLS(t *testing.T) {
+	require := require.New(t)
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "og-client"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(err)
+	clientCert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	clientKey := pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if len(r.TLS.PeerCertificates) == 0 || r.TLS.PeerCertificates[0].Subject.CommonName != "og-client" {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"count": 7}`))
+	}))
+	server.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	server.StartTLS()
+	defer server.Close()
+	caCert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	username, password := "", ""
+	config := opengovernance.ClientConfig{Addresses: []string{server.URL}, Username: &username, Password: &password}
+
+	client, err := opengovernance.NewClientFromConfig(config, opengovernance.TransportConfig{
+		CACert: caCert, ClientCert: clientCert, ClientKey: clientKey,
+	})
+	require.NoError(err)
+	count, err := client.Count(context.Background(), "index")
+	require.NoError(err)
+	require.Equal(int64(7), count)
+
+	// Without the CA the server certificate is rejected
+	client, err = opengovernance.NewClientFromConfig(config, opengovernance.TransportConfig{
+		ClientCert: clientCert, ClientKey: clientKey,
+	})
+	require.NoError(err)
+	_, err = client.Count(context.Background(), "index")
+	require.ErrorContains(err, "certificate")
+
+	_, err = opengovernance.NewClientFromConfig(config, opengovernance.TransportConfig{ClientCert: clientCert})
+	require.ErrorContains(err, "client certificate and key must be set together")
+	_, err = opengovernance.NewClientFromConfig(config, opengovernance.TransportConfig{CACert: []byte("not pem")})
+	require.ErrorContains(err, "no certificates found in CA bundle")
+}
@@ -0,0 +1,339 @@
+package opengovernance
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// Flat cluster setting keys managed by the typed helpers below.
+const (
+	SettingAllocationExcludeName = "cluster.routing.allocation.exclude._name"
+	SettingAllocationExcludeIP   = "cluster.routing.allocation.exclude._ip"
+	SettingAllocationExcludeHost = "cluster.routing.allocation.exclude._host"
+	SettingWatermarkLow          = "cluster.routing.allocation.disk.watermark.low"
+	SettingWatermarkHigh         = "cluster.routing.allocation.disk.watermark.high"
+	SettingWatermarkFloodStage   = "cluster.routing.allocation.disk.watermark.flood_stage"
+	SettingMaxShardsPerNode      = "cluster.max_shards_per_node"
+)
+
+// AllocationExclusions are the nodes shards are moved away from. A node matching any
+// list is drained.
+type AllocationExclusions struct {
+	Names []string
+	IPs   []string
+	Hosts []string
+}
+
+// DiskWatermarks are either all percentages/ratios of used disk ("85%", "0.85") or all
+// absolute free-space values ("50gb").
+type DiskWatermarks struct {
+	Low        string
+	High       string
+	FloodStage string
+}
+
+// ClusterSettings are the effective values of the managed settings: transient overrides
+// persistent, which overrides the node defaults.
+type ClusterSettings struct {
+	Exclusions       AllocationExclusions
+	Watermarks       DiskWatermarks
+	MaxShardsPerNode int
+}
+
+// ClusterSettingsUpdate lists the settings to change. Nil fields are left untouched; an
+// empty exclusion list or watermark string resets the setting to its default.
+type ClusterSettingsUpdate struct {
+	ExcludeNames     *[]string
+	ExcludeIPs       *[]string
+	ExcludeHosts     *[]string
+	Watermarks       *DiskWatermarks
+	MaxShardsPerNode *int
+}
+
+// ClusterSettingsOptions configures PutClusterSettings.
+type ClusterSettingsOptions struct {
+	Persistent bool // Survive a full cluster restart; transient otherwise
+	DryRun     bool // Validate and return the request body without sending it
+}
+
+type clusterSettingsResponse struct {
+	Persistent map[string]any `json:"persistent"`
+	Transient  map[string]any `json:"transient"`
+	Defaults   map[string]any `json:"defaults"`
+}
+
+// GetClusterSettings returns the effective values of the managed cluster settings.
+func (c Client) GetClusterSettings(ctx context.Context) (*ClusterSettings, error) {
+	res, err := c.es.Cluster.GetSettings(
+		c.es.Cluster.GetSettings.WithContext(ctx),
+		c.es.Cluster.GetSettings.WithFlatSettings(true),
+		c.es.Cluster.GetSettings.WithIncludeDefaults(true),
+	)
+	defer CloseSafe(res)
+	if err != nil {
+		return nil, err
+	} else if err := CheckError(res); err != nil {
+		return nil, err
+	}
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	var response clusterSettingsResponse
+	if err := json.Unmarshal(b, &response); err != nil {
+		return nil, fmt.Errorf("unmarshal response: %w", err)
+	}
+
+	lookup := func(key string) string {
+		for _, m := range []map[string]any{response.Transient, response.Persistent, response.Defaults} {
+			if v, ok := m[key]; ok && v != nil {
+				return fmt.Sprint(v)
+			}
+		}
+		return ""
+	}
+
+	settings := &ClusterSettings{
+		Exclusions: AllocationExclusions{
+			Names: splitSettingList(lookup(SettingAllocationExcludeName)),
+			IPs:   splitSettingList(lookup(SettingAllocationExcludeIP)),
+			Hosts: splitSettingList(lookup(SettingAllocationExcludeHost)),
+		},
+		Watermarks: DiskWatermarks{
+			Low:        lookup(SettingWatermarkLow),
+			High:       lookup(SettingWatermarkHigh),
+			FloodStage: lookup(SettingWatermarkFloodStage),
+		},
+	}
+	if v := lookup(SettingMaxShardsPerNode); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid %s %q: %w", SettingMaxShardsPerNode, v, err)
+		}
+		settings.MaxShardsPerNode = n
+	}
+	return settings, nil
+}
+
+// PutClusterSettings validates update and applies it. It returns the flat settings that
+// were (or, with DryRun, would be) sent; a nil value resets that setting.
+func (c Client) PutClusterSettings(ctx context.Context, update ClusterSettingsUpdate, opts ClusterSettingsOptions) (map[string]any, error) {
+	if err := update.Validate(); err != nil {
+		return nil, err
+	}
+	settings := update.flatten()
+	if len(settings) == 0 {
+		return settings, nil
+	}
+	if opts.DryRun {
+		return settings, nil
+	}
+
+	scope := "transient"
+	if opts.Persistent {
+		scope = "persistent"
+	}
+	body, err := json.Marshal(map[string]any{scope: settings})
+	if err != nil {
+		return nil, fmt.Errorf("marshal cluster settings: %w", err)
+	}
+	res, err := c.es.Cluster.PutSettings(bytes.NewReader(body),
+		c.es.Cluster.PutSettings.WithContext(ctx),
+		c.es.Cluster.PutSettings.WithFlatSettings(true),
+	)
+	defer CloseSafe(res)
+	if err != nil {
+		return nil, err
+	} else if err := CheckError(res); err != nil {
+		return nil, err
+	}
+	return settings, nil
+}
+
+// DrainNode excludes node (by name) from shard allocation so its shards relocate to
+// other nodes. Existing exclusions are kept.
+func (c Client) DrainNode(ctx context.Context, node string, opts ClusterSettingsOptions) (map[string]any, error) {
+	if node == "" {
+		return nil, errors.New("node name is required")
+	}
+	current, err := c.GetClusterSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, name := range current.Exclusions.Names {
+		if name == node {
+			return map[string]any{}, nil
+		}
+	}
+	names := append(current.Exclusions.Names, node)
+	return c.PutClusterSettings(ctx, ClusterSettingsUpdate{ExcludeNames: &names}, opts)
+}
+
+// UndrainNode removes node from the allocation exclusions set by DrainNode.
+func (c Client) UndrainNode(ctx context.Context, node string, opts ClusterSettingsOptions) (map[string]any, error) {
+	current, err := c.GetClusterSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]string, 0, len(current.Exclusions.Names))
+	for _, name := range current.Exclusions.Names {
+		if name != node {
+			names = append(names, name)
+		}
+	}
+	if len(names) == len(current.Exclusions.Names) {
+		return map[string]any{}, nil
+	}
+	return c.PutClusterSettings(ctx, ClusterSettingsUpdate{ExcludeNames: &names}, opts)
+}
+
+// Validate checks the update before it is sent to the cluster.
+func (u ClusterSettingsUpdate) Validate() error {
+	for _, list := range []*[]string{u.ExcludeNames, u.ExcludeIPs, u.ExcludeHosts} {
+		if list == nil {
+			continue
+		}
+		for _, v := range *list {
+			if strings.TrimSpace(v) == "" || strings.Contains(v, ",") {
+				return fmt.Errorf("invalid allocation exclusion %q", v)
+			}
+		}
+	}
+	if u.MaxShardsPerNode != nil && *u.MaxShardsPerNode < 1 {
+		return fmt.Errorf("%s must be at least 1, got %d", SettingMaxShardsPerNode, *u.MaxShardsPerNode)
+	}
+	if u.Watermarks != nil {
+		return u.Watermarks.Validate()
+	}
+	return nil
+}
+
+// Validate checks that the watermarks use one kind of value and are ordered so the
+// cluster stops allocating before it blocks writes: low <= high <= flood_stage for
+// used-disk percentages, and low >= high >= flood_stage for free-space byte values.
+func (w DiskWatermarks) Validate() error {
+	values := []string{w.Low, w.High, w.FloodStage}
+	names := []string{"low", "high", "flood_stage"}
+	if values[0] == "" && values[1] == "" && values[2] == "" {
+		return nil
+	}
+	for i, v := range values {
+		if v == "" {
+			return fmt.Errorf("disk watermark %s is required when setting watermarks", names[i])
+		}
+	}
+
+	parsed := make([]float64, len(values))
+	var ratios, sizes int
+	for i, v := range values {
+		if ratio, ok := parseWatermarkRatio(v); ok {
+			parsed[i] = ratio
+			ratios++
+		} else if size, ok := parseByteSize(v); ok {
+			parsed[i] = float64(size)
+			sizes++
+		} else {
+			return fmt.Errorf("invalid disk watermark %s %q", names[i], v)
+		}
+	}
+	if ratios > 0 && sizes > 0 {
+		return errors.New("disk watermarks must be all percentages or all byte values")
+	}
+	for i := 1; i < len(parsed); i++ {
+		if ratios > 0 && parsed[i] < parsed[i-1] {
+			return fmt.Errorf("disk watermark %s (%s) must not be lower than %s (%s)", names[i], values[i], names[i-1], values[i-1])
+		}
+		if sizes > 0 && parsed[i] > parsed[i-1] {
+			return fmt.Errorf("disk watermark %s (%s) must not be more free space than %s (%s)", names[i], values[i], names[i-1], values[i-1])
+		}
+	}
+	return nil
+}
+
+func (u ClusterSettingsUpdate) flatten() map[string]any {
+	settings := make(map[string]any)
+	setList := func(key string, list *[]string) {
+		if list == nil {
+			return
+		}
+		if len(*list) == 0 {
+			settings[key] = nil
+		} else {
+			settings[key] = strings.Join(*list, ",")
+		}
+	}
+	setList(SettingAllocationExcludeName, u.ExcludeNames)
+	setList(SettingAllocationExcludeIP, u.ExcludeIPs)
+	setList(SettingAllocationExcludeHost, u.ExcludeHosts)
+	if u.Watermarks != nil {
+		for key, v := range map[string]string{
+			SettingWatermarkLow:        u.Watermarks.Low,
+			SettingWatermarkHigh:       u.Watermarks.High,
+			SettingWatermarkFloodStage: u.Watermarks.FloodStage,
+		} {
+			if v == "" {
+				settings[key] = nil
+			} else {
+				settings[key] = v
+			}
+		}
+	}
+	if u.MaxShardsPerNode != nil {
+		settings[SettingMaxShardsPerNode] = *u.MaxShardsPerNode
+	}
+	return settings
+}
+
+func splitSettingList(v string) []string {
+	var list []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			list = append(list, item)
+		}
+	}
+	return list
+}
+
+// parseWatermarkRatio parses "85%", "85.5%" or "0.85" as a used-disk ratio.
+func parseWatermarkRatio(v string) (float64, bool) {
+	if p, ok := strings.CutSuffix(v, "%"); ok {
+		f, err := strconv.ParseFloat(p, 64)
+		if err != nil || f < 0 || f > 100 {
+			return 0, false
+		}
+		return f / 100, true
+	}
+	f, err := strconv.ParseFloat(v, 64)
+	if err != nil || f < 0 || f > 1 {
+		return 0, false
+	}
+	return f, true
+}
+
+var byteSizeUnits = []struct {
+	suffix string
+	factor int64
+}{
+	{"pb", 1 << 50}, {"tb", 1 << 40}, {"gb", 1 << 30}, {"mb", 1 << 20}, {"kb", 1 << 10}, {"b", 1},
+}
+
+// parseByteSize parses a byte value with a unit suffix, e.g. "50gb".
+func parseByteSize(v string) (int64, bool) {
+	v = strings.ToLower(strings.TrimSpace(v))
+	for _, unit := range byteSizeUnits {
+		if n, ok := strings.CutSuffix(v, unit.suffix); ok {
+			f, err := strconv.ParseFloat(n, 64)
+			if err != nil || f < 0 {
+				return 0, false
+			}
+			return int64(f * float64(unit.factor)), true
+		}
+	}
+	return 0, false
+}
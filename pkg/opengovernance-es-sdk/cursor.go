@@ -0,0 +1,140 @@
+package opengovernance
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const cursorVersion = 1
+
+// ErrCursorMismatch is returned by RestoreCursor when the cursor was taken from a
+// paginator over another index, query, sort or slice.
+var ErrCursorMismatch = errors.New("cursor does not match the paginator's query")
+
+// paginatorCursor is the serialized position of a BaseESPaginator.
+type paginatorCursor struct {
+	Version     int    `json:"version"`
+	Index       string `json:"index"`
+	QueryHash   string `json:"query_hash"`
+	PitID       string `json:"pit_id,omitempty"`
+	ScrollID    string `json:"scroll_id,omitempty"`
+	SearchAfter []any  `json:"search_after,omitempty"`
+	Queried     int64  `json:"queried"`
+	Done        bool   `json:"done,omitempty"`
+}
+
+// MarshalCursor serializes the position of the paginator: its point in time or scroll
+// context, the sort values of the last hit, the number of documents queried so far and a
+// hash of the query. Save it after each processed page so that a job can resume with
+// RestoreCursor after a restart instead of paging from the start.
+func (p *BaseESPaginator) MarshalCursor() ([]byte, error) {
+	hash, err := p.queryHash()
+	if err != nil {
+		return nil, err
+	}
+	return json.Marshal(paginatorCursor{
+		Version:     cursorVersion,
+		Index:       p.index,
+		QueryHash:   hash,
+		PitID:       p.pitID,
+		ScrollID:    p.scrollID,
+		SearchAfter: p.searchAfter,
+		Queried:     p.queried,
+		Done:        p.done,
+	})
+}
+
+// RestoreCursor resumes the paginator at a position returned by MarshalCursor. It must be
+// called before the first Search on a paginator built with the same index, filters, sort
+// and slice, otherwise ErrCursorMismatch is returned. If the point in time expired
+// meanwhile, the next Search opens a new one and continues after the last sort values,
+// so documents indexed since may be included; an expired scroll context cannot be
+// resumed and Search fails.
+func (p *BaseESPaginator) RestoreCursor(data []byte) error {
+	if p.queried > 0 || p.searchAfter != nil || p.pitID != "" || p.scrollID != "" {
+		return errors.New("paginator was already searched")
+	}
+
+	// Keep sort values as json.Number so that large integers survive the round trip
+	dec := json.NewDecoder(bytes.NewReader(data))
+	dec.UseNumber()
+	var cursor paginatorCursor
+	if err := dec.Decode(&cursor); err != nil {
+		return fmt.Errorf("unmarshal cursor: %w", err)
+	}
+	if cursor.Version != cursorVersion {
+		return fmt.Errorf("unsupported cursor version: %d", cursor.Version)
+	}
+	hash, err := p.queryHash()
+	if err != nil {
+		return err
+	}
+	if cursor.Index != p.index || cursor.QueryHash != hash {
+		return ErrCursorMismatch
+	}
+	if cursor.Queried < 0 {
+		return fmt.Errorf("invalid cursor queried count: %d", cursor.Queried)
+	}
+
+	p.queried = cursor.Queried
+	p.searchAfter = cursor.SearchAfter
+	p.scrollID = cursor.ScrollID
+	p.setPitID(cursor.PitID)
+	p.done = cursor.Done || p.queried >= p.limit
+	p.restoredPit = cursor.PitID != ""
+	return nil
+}
+
+// queryHash identifies what the paginator pages through: the documents it matches and
+// their order.
+func (p *BaseESPaginator) queryHash() (string, error) {
+	b, err := json.Marshal(struct {
+		Index string           `json:"index"`
+		Query map[string]any   `json:"query"`
+		Sort  []map[string]any `json:"sort"`
+		Slice *SearchSlice     `json:"slice,omitempty"`
+	}{p.index, p.query, p.sort, p.slice})
+	if err != nil {
+		return "", fmt.Errorf("marshal query: %w", err)
+	}
+	sum := sha256.Sum256(b)
+	return hex.EncodeToString(sum[:]), nil
+}
+
+// reopenExpiredPit drops a point in time restored from a cursor that no longer exists on
+// the cluster, so that the next search opens a new one. It reports whether it did.
+func (p *BaseESPaginator) reopenExpiredPit(ctx context.Context, err error) bool {
+	if !p.restoredPit || p.scrolling() || !isSearchContextMissingErr(err) {
+		return false
+	}
+	p.restoredPit = false
+	p.log(ctx, zapcore.WarnLevel, "restored point in time expired, opening a new one",
+		zap.String("pit_id", p.pitID), zap.Error(err))
+	p.setPitID("")
+	return true
+}
+
+func isSearchContextMissingErr(err error) bool {
+	var e ErrorResponse
+	if !errors.As(err, &e) {
+		return false
+	}
+	if strings.EqualFold(e.Info.Type, "search_context_missing_exception") {
+		return true
+	}
+	for _, cause := range e.Info.RootCause {
+		if strings.EqualFold(cause.Type, "search_context_missing_exception") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,132 @@
+package opengovernance_test
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	opengovernance "github.com/opengovern/og-util/pkg/opengovernance-es-sdk"
+	"github.com/stretchr/testify/require"
+)
+
+type cursorPage struct {
+	PitID string `json:"pit_id"`
+	Hits  struct {
+		Hits []struct {
+			Source sliceDoc `json:"_source"`
+			Sort   []any    `json:"sort"`
+		} `json:"hits"`
+	} `json:"hits"`
+}
+
+// newCursorServer serves docs 0..count-1 sorted by n through PITs numbered from 1. Only
+// the latest PIT is alive, as if the earlier ones expired.
+func newCursorServer(t *testing.T, count int) *httptest.Server {
+	var pits atomic.Int32
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(req.URL.Path, "/_search/point_in_time") && req.Method == http.MethodPost:
+			fmt.Fprintf(w, `{"pit_id":"pit-%d"}`, pits.Add(1))
+		case req.URL.Path == "/_search":
+			var body struct {
+				Size        int   `json:"size"`
+				SearchAfter []any `json:"search_after"`
+				PIT         struct {
+					ID string `json:"id"`
+				} `json:"pit"`
+			}
+			reader := io.Reader(req.Body)
+			if req.Header.Get("Content-Encoding") == "gzip" {
+				gz, err := gzip.NewReader(req.Body)
+				if err != nil {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				reader = gz
+			}
+			if err := json.NewDecoder(reader).Decode(&body); err != nil {
+				w.WriteHeader(http.StatusBadRequest)
+				return
+			}
+			if body.PIT.ID != fmt.Sprintf("pit-%d", pits.Load()) {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"error":{"root_cause":[{"type":"search_context_missing_exception","reason":"No search context found"}],"type":"search_phase_execution_exception","reason":"all shards failed"}}`))
+				return
+			}
+			after := -1
+			if len(body.SearchAfter) > 0 {
+				after = int(body.SearchAfter[0].(float64))
+			}
+			var hits []string
+			for n := after + 1; n < count && len(hits) < body.Size; n++ {
+				hits = append(hits, fmt.Sprintf(`{"_id":"%d","_source":{"n":%d},"sort":[%d,"%d"]}`, n, n, n, n))
+			}
+			fmt.Fprintf(w, `{"pit_id":%q,"hits":{"hits":[%s]}}`, body.PIT.ID, strings.Join(hits, ","))
+		default:
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{}`))
+		}
+	}))
+}
+
+func searchCursorPage(t *testing.T, p *opengovernance.BaseESPaginator) []int {
+	var page cursorPage
+	require.NoError(t, p.Search(context.Background(), &page))
+	var ns []int
+	var searchAfter []any
+	for _, hit := range page.Hits.Hits {
+		ns = append(ns, hit.Source.N)
+		searchAfter = hit.Sort
+	}
+	p.UpdateState(int64(len(page.Hits.Hits)), searchAfter, page.PitID)
+	return ns
+}
+
+func TestPaginatorCursor(t *testing.T) {
+	r := require.New(t)
+
+	server := newCursorServer(t, 50)
+	defer server.Close()
+
+	var seen []int
+	p := newSlicedPaginator(t, server, nil)
+	for range 3 {
+		seen = append(seen, searchCursorPage(t, p)...)
+	}
+	cursor, err := p.MarshalCursor()
+	r.NoError(err)
+
+	// A paginator over another query cannot resume from the cursor
+	username, password := "", ""
+	client, err := opengovernance.NewClient(opengovernance.ClientConfig{
+		Addresses: []string{server.URL},
+		Username:  &username,
+		Password:  &password,
+	})
+	r.NoError(err)
+	other, err := opengovernance.NewPaginatorWithSort(client.ES(), "inventory", nil, nil, []map[string]any{{"n": "desc"}})
+	r.NoError(err)
+	r.ErrorIs(other.RestoreCursor(cursor), opengovernance.ErrCursorMismatch)
+
+	// Opening pit-2 expires pit-1: the resumed paginator opens pit-3 and continues after
+	// the last page instead of from the start
+	r.NoError(other.CreatePit(context.Background()))
+	resumed := newSlicedPaginator(t, server, nil)
+	r.NoError(resumed.RestoreCursor(cursor))
+	r.Error(resumed.RestoreCursor(cursor))
+	for !resumed.Done() {
+		seen = append(seen, searchCursorPage(t, resumed)...)
+	}
+	r.Len(seen, 50)
+	for i, n := range seen {
+		r.Equal(i, n)
+	}
+}
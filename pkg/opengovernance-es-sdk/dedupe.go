@@ -0,0 +1,215 @@
+package opengovernance
+
+import (
+	"fmt"
+	"hash/fnv"
+	"math"
+	"reflect"
+	"strings"
+	"sync"
+)
+
+const defaultBloomFalsePositiveRate = 0.001
+
+// DedupeOptions configures a Deduper.
+type DedupeOptions struct {
+	// BloomCapacity, if positive, bounds memory by tracking keys in a rolling bloom filter
+	// instead of a set. The filter remembers at least the last BloomCapacity keys and at
+	// most twice as many; duplicates further apart are not detected, and a new key is
+	// dropped as a duplicate with probability FalsePositiveRate.
+	BloomCapacity     int
+	FalsePositiveRate float64 // Default 0.001
+}
+
+// Deduper drops items whose key was already seen, e.g. hits returned twice by a
+// paginator after a PIT to scroll fallback or a retried page. Filtering keeps the first
+// occurrence of each key and the relative order of items, so sorted output stays sorted.
+// It is safe for concurrent use.
+type Deduper[T any] struct {
+	key func(T) string
+
+	mu      sync.Mutex
+	seen    map[string]struct{}
+	bloom   *rollingBloom
+	dropped int64
+}
+
+// NewDeduper returns a Deduper identifying items by key.
+func NewDeduper[T any](key func(T) string, opts DedupeOptions) *Deduper[T] {
+	d := &Deduper[T]{key: key}
+	if opts.BloomCapacity > 0 {
+		rate := opts.FalsePositiveRate
+		if rate <= 0 || rate >= 1 {
+			rate = defaultBloomFalsePositiveRate
+		}
+		d.bloom = newRollingBloom(opts.BloomCapacity, rate)
+	} else {
+		d.seen = make(map[string]struct{})
+	}
+	return d
+}
+
+// NewEsIDDeduper returns a Deduper identifying items by their es_id: the field tagged
+// `json:"es_id"` of a struct (or pointer to one, searching embedded structs), or the
+// "es_id" key of a map[string]any. It fails if T has no such field.
+func NewEsIDDeduper[T any](opts DedupeOptions) (*Deduper[T], error) {
+	key, err := esIDKey[T]()
+	if err != nil {
+		return nil, err
+	}
+	return NewDeduper(key, opts), nil
+}
+
+// Seen records item and reports whether its key was seen before.
+func (d *Deduper[T]) Seen(item T) bool {
+	key := d.key(item)
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	var seen bool
+	if d.bloom != nil {
+		seen = d.bloom.testAndAdd(key)
+	} else {
+		_, seen = d.seen[key]
+		d.seen[key] = struct{}{}
+	}
+	if seen {
+		d.dropped++
+	}
+	return seen
+}
+
+// Filter returns the items not seen before, in their original order. It reuses the
+// backing array of items.
+func (d *Deduper[T]) Filter(items []T) []T {
+	out := items[:0]
+	for _, item := range items {
+		if !d.Seen(item) {
+			out = append(out, item)
+		}
+	}
+	var zero T
+	for i := len(out); i < len(items); i++ {
+		items[i] = zero // Release dropped items
+	}
+	return out
+}
+
+// Dropped returns the number of duplicates seen so far.
+func (d *Deduper[T]) Dropped() int64 {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.dropped
+}
+
+func esIDKey[T any]() (func(T) string, error) {
+	typ := reflect.TypeFor[T]()
+	if typ.Kind() == reflect.Map && typ.Key().Kind() == reflect.String {
+		return func(item T) string {
+			value := reflect.ValueOf(item).MapIndex(reflect.ValueOf("es_id"))
+			if !value.IsValid() {
+				return ""
+			}
+			return fmt.Sprint(value.Interface())
+		}, nil
+	}
+	pointer := typ.Kind() == reflect.Pointer
+	if pointer {
+		typ = typ.Elem()
+	}
+	if typ.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("type %s has no es_id", typ)
+	}
+	index, ok := findEsIDField(typ)
+	if !ok {
+		return nil, fmt.Errorf("type %s has no field tagged json:\"es_id\"", typ)
+	}
+	return func(item T) string {
+		value := reflect.ValueOf(item)
+		if pointer {
+			if value.IsNil() {
+				return ""
+			}
+			value = value.Elem()
+		}
+		field, err := value.FieldByIndexErr(index)
+		if err != nil { // Nil embedded pointer
+			return ""
+		}
+		return fmt.Sprint(field.Interface())
+	}, nil
+}
+
+func findEsIDField(typ reflect.Type) ([]int, bool) {
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if name, _, _ := strings.Cut(field.Tag.Get("json"), ","); name == "es_id" {
+			return field.Index, true
+		}
+	}
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		if !field.Anonymous {
+			continue
+		}
+		embedded := field.Type
+		if embedded.Kind() == reflect.Pointer {
+			embedded = embedded.Elem()
+		}
+		if embedded.Kind() != reflect.Struct {
+			continue
+		}
+		if index, ok := findEsIDField(embedded); ok {
+			return append([]int{i}, index...), true
+		}
+	}
+	return nil, false
+}
+
+// rollingBloom is two bloom filter generations: keys are added to the current one, and
+// once it holds capacity keys the previous one is discarded and a new one started.
+type rollingBloom struct {
+	capacity, count int
+	bits            uint64 // Bits per generation
+	hashes          int
+	current, prev   []uint64
+}
+
+func newRollingBloom(capacity int, falsePositiveRate float64) *rollingBloom {
+	// Each lookup tests two generations, so halve the rate per generation
+	rate := falsePositiveRate / 2
+	bits := uint64(math.Ceil(-float64(capacity) * math.Log(rate) / (math.Ln2 * math.Ln2)))
+	hashes := max(1, int(math.Round(float64(bits)/float64(capacity)*math.Ln2)))
+	return &rollingBloom{
+		capacity: capacity,
+		bits:     bits,
+		hashes:   hashes,
+		current:  make([]uint64, (bits+63)/64),
+	}
+}
+
+func (b *rollingBloom) testAndAdd(key string) bool {
+	h := fnv.New64a()
+	h.Write([]byte(key))
+	h1 := h.Sum64()
+	h2 := h1>>33 | h1<<31 | 1 // Odd, so the probes differ
+
+	seenCurrent, seenPrev := true, b.prev != nil
+	for i := 0; i < b.hashes; i++ {
+		bit := (h1 + uint64(i)*h2) % b.bits
+		word, mask := bit/64, uint64(1)<<(bit%64)
+		if b.current[word]&mask == 0 {
+			seenCurrent = false
+			b.current[word] |= mask
+		}
+		if seenPrev && b.prev[word]&mask == 0 {
+			seenPrev = false
+		}
+	}
+	if !seenCurrent { // Keys only in the previous generation are carried over
+		b.count++
+		if b.count >= b.capacity {
+			b.prev, b.current, b.count = b.current, make([]uint64, len(b.current)), 0
+		}
+	}
+	return seenCurrent || seenPrev
+}
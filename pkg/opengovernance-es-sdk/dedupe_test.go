@@ -0,0 +1,68 @@
+package opengovernance_test
+
+import (
+	"fmt"
+	"testing"
+
+	opengovernance "github.com/opengovern/og-util/pkg/opengovernance-es-sdk"
+	"github.com/stretchr/testify/require"
+)
+
+type dedupeHit struct {
+	Source struct {
+		EsID string `json:"es_id"`
+		Name string `json:"name"`
+	}
+}
+
+type dedupeResource struct {
+	ID   string `json:"es_id,omitempty"`
+	Name string `json:"name"`
+}
+
+func TestDeduper(t *testing.T) {
+	require := require.New(t)
+
+	d := opengovernance.NewDeduper(func(h dedupeHit) string { return h.Source.EsID }, opengovernance.DedupeOptions{})
+	hits := make([]dedupeHit, 5)
+	for i, id := range []string{"a", "b", "a", "c", "b"} {
+		hits[i].Source.EsID = id
+	}
+	hits = d.Filter(hits)
+	require.Len(hits, 3)
+	require.Equal([]string{"a", "b", "c"}, []string{hits[0].Source.EsID, hits[1].Source.EsID, hits[2].Source.EsID})
+	require.True(d.Seen(hits[2]))
+	require.Equal(int64(3), d.Dropped())
+
+	byEsID, err := opengovernance.NewEsIDDeduper[*dedupeResource](opengovernance.DedupeOptions{})
+	require.NoError(err)
+	require.False(byEsID.Seen(&dedupeResource{ID: "x", Name: "first"}))
+	require.True(byEsID.Seen(&dedupeResource{ID: "x", Name: "retry"}))
+
+	maps, err := opengovernance.NewEsIDDeduper[map[string]any](opengovernance.DedupeOptions{})
+	require.NoError(err)
+	require.False(maps.Seen(map[string]any{"es_id": "x"}))
+	require.True(maps.Seen(map[string]any{"es_id": "x"}))
+
+	_, err = opengovernance.NewEsIDDeduper[dedupeHit](opengovernance.DedupeOptions{})
+	require.Error(err)
+}
+
+func TestDeduperBloom(t *testing.T) {
+	require := require.New(t)
+
+	d := opengovernance.NewDeduper(func(id string) string { return id }, opengovernance.DedupeOptions{BloomCapacity: 1000})
+	falsePositives := 0
+	for i := 0; i < 10000; i++ {
+		id := fmt.Sprint("resource-", i)
+		if d.Seen(id) {
+			falsePositives++
+		}
+		// Duplicates within the window are always detected
+		require.True(d.Seen(id))
+		if i >= 500 {
+			require.True(d.Seen(fmt.Sprint("resource-", i-500)))
+		}
+	}
+	require.Less(falsePositives, 50)
+}
@@ -0,0 +1,149 @@
+package opengovernance
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Dialect describes where a provider's resource documents keep the fields that
+// provider-agnostic filters, such as resource collections, refer to. Integration plugins
+// register one per connector with RegisterDialect.
+type Dialect interface {
+	// AccountFields are the fields holding the account, e.g. metadata.SubscriptionID.
+	AccountFields() []string
+	// RegionFields are the fields holding the region or location.
+	RegionFields() []string
+}
+
+// ZoneDialect is implemented by dialects of providers with availability zones, whose
+// resources can be filtered by zone. Other dialects use the default zone field.
+type ZoneDialect interface {
+	Dialect
+	// ZoneFields are the fields holding the availability zone.
+	ZoneFields() []string
+}
+
+// FieldDialect is a Dialect with fixed field names.
+type FieldDialect struct {
+	Account []string
+	Region  []string
+	Zone    []string // The default zone field if empty
+}
+
+func (d FieldDialect) AccountFields() []string { return d.Account }
+func (d FieldDialect) RegionFields() []string  { return d.Region }
+
+func (d FieldDialect) ZoneFields() []string {
+	if len(d.Zone) == 0 {
+		return defaultZoneFields
+	}
+	return d.Zone
+}
+
+// defaultDialect is used for connectors without a registered dialect.
+var defaultDialect Dialect = FieldDialect{
+	Account: []string{"metadata.AccountID"},
+	Region:  []string{"metadata.Region", "metadata.Location"},
+}
+
+var defaultZoneFields = []string{"metadata.Zone"}
+
+// builtinDialects are the dialects of the connectors maintained with the platform.
+// Plugins may replace them with RegisterDialect.
+var builtinDialects = map[string]Dialect{
+	"aws": FieldDialect{
+		Account: []string{"metadata.AccountID"},
+		Region:  []string{"metadata.Region"},
+		Zone:    []string{"metadata.AvailabilityZone"},
+	},
+	"azure": FieldDialect{
+		Account: []string{"metadata.SubscriptionID"},
+		Region:  []string{"metadata.Location", "metadata.Region"},
+		Zone:    []string{"metadata.Zone"},
+	},
+	"gcp": FieldDialect{
+		Account: []string{"metadata.ProjectID"},
+		Region:  []string{"metadata.Region", "metadata.Location"},
+		Zone:    []string{"metadata.Zone"},
+	},
+}
+
+var dialects = struct {
+	sync.RWMutex
+	byConnector map[string]Dialect // Lowercased connector (source_type)
+}{byConnector: copyDialects(builtinDialects)}
+
+func copyDialects(in map[string]Dialect) map[string]Dialect {
+	out := make(map[string]Dialect, len(in))
+	for connector, dialect := range in {
+		out[connector] = dialect
+	}
+	return out
+}
+
+// zoneFields returns the zone fields of d, or the default ones if it has none.
+func zoneFields(d Dialect) []string {
+	if zd, ok := d.(ZoneDialect); ok {
+		return zd.ZoneFields()
+	}
+	return defaultZoneFields
+}
+
+// RegisterDialect sets the dialect of connector, the source_type of its resources
+// (case-insensitive), replacing a built-in one for aws, azure and gcp. A nil dialect
+// removes it.
+func RegisterDialect(connector string, dialect Dialect) {
+	dialects.Lock()
+	if dialect == nil {
+		delete(dialects.byConnector, strings.ToLower(connector))
+	} else {
+		dialects.byConnector[strings.ToLower(connector)] = dialect
+	}
+	dialects.Unlock()
+	// Compiled collections embed the field names
+	resetCompiledResourceCollections()
+}
+
+// dialectsFor returns the dialects of connectors, or of every connector if none are
+// given, always including defaultDialect for connectors without their own.
+func dialectsFor(connectors []string) []Dialect {
+	dialects.RLock()
+	defer dialects.RUnlock()
+	result := []Dialect{}
+	needDefault := len(connectors) == 0
+	if len(connectors) == 0 {
+		for _, d := range dialects.byConnector {
+			result = append(result, d)
+		}
+	}
+	for _, connector := range connectors {
+		if d, ok := dialects.byConnector[strings.ToLower(connector)]; ok {
+			result = append(result, d)
+		} else {
+			needDefault = true
+		}
+	}
+	if needDefault {
+		result = append(result, defaultDialect)
+	}
+	return result
+}
+
+// dialectFilter matches values in any of the fields the dialects of connectors name.
+func dialectFilter(connectors []string, fields func(Dialect) []string, values []string) BoolFilter {
+	var names []string
+	for _, d := range dialectsFor(connectors) {
+		names = append(names, fields(d)...)
+	}
+	sort.Strings(names) // Stable filters regardless of registration order
+	names = uniqueStrings(names)
+	if len(names) == 1 {
+		return NewTermsFilter(names[0], values)
+	}
+	filters := make([]BoolFilter, 0, len(names))
+	for _, name := range names {
+		filters = append(filters, NewTermsFilter(name, values))
+	}
+	return NewBoolShouldFilter(filters...)
+}
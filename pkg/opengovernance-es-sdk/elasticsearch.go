@@ -23,7 +23,6 @@ package opengovernance
 
 import (
 	"context"
-	"encoding/base64"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -35,7 +34,7 @@ import (
 	"github.com/elastic/go-elasticsearch/v7/esapi"
 	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
 	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
-	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/context_key"
+	"go.uber.org/zap/zapcore"
 
 	"github.com/opensearch-project/opensearch-go/v2"
 	"github.com/opensearch-project/opensearch-go/v2/opensearchapi"
@@ -101,6 +100,7 @@ func ESCloseSafe(resp *esapi.Response) {
 }
 
 // CheckError reads an opensearchapi.Response and tries to decode an error.
+// Overload responses are returned as *BackpressureError.
 func CheckError(resp *opensearchapi.Response) error {
 	if !resp.IsError() {
 		return nil
@@ -110,22 +110,24 @@ func CheckError(resp *opensearchapi.Response) error {
 		return fmt.Errorf("read error: %w", err)
 	}
 	var e ErrorResponse
-	if err := json.Unmarshal(data, &e); err != nil {
-		return fmt.Errorf("%s: %s", resp.String(), string(data))
-	}
-	if strings.TrimSpace(e.Info.Type) == "" && strings.TrimSpace(e.Info.Reason) == "" {
+	if err := json.Unmarshal(data, &e); err != nil || (strings.TrimSpace(e.Info.Type) == "" && strings.TrimSpace(e.Info.Reason) == "") {
+		if resp.StatusCode == http.StatusTooManyRequests {
+			return classifyError(resp.StatusCode, resp.Header, tooManyRequestsResponse(data))
+		}
 		return fmt.Errorf("%s: %s", resp.String(), string(data))
 	}
-	return e
+	return classifyError(resp.StatusCode, resp.Header, e)
 }
 
-// LogWarn logs a warning either via plugin.Logger() or fmt.Println if none.
+// tooManyRequestsResponse builds an ErrorResponse for a 429 whose body isn't a structured error.
+func tooManyRequestsResponse(data []byte) ErrorResponse {
+	return ErrorResponse{Info: ErrorInfo{Type: "too_many_requests", Reason: strings.TrimSpace(string(data))}}
+}
+
+// LogWarn logs a warning via plugin.Logger() or, if none, the default logger (see
+// SetDefaultLogger).
 func LogWarn(ctx context.Context, data string) {
-	if ctx.Value(context_key.Logger) == nil {
-		fmt.Println(data)
-	} else {
-		plugin.Logger(ctx).Warn(data)
-	}
+	logAt(ctx, nil, zapcore.WarnLevel, data)
 }
 
 // CheckErrorWithContext logs error details and returns an error if found.
@@ -140,13 +142,13 @@ func CheckErrorWithContext(resp *opensearchapi.Response, ctx context.Context) er
 	LogWarn(ctx, fmt.Sprintf("CheckErr data: %s", string(data)))
 
 	var e ErrorResponse
-	if err := json.Unmarshal(data, &e); err != nil {
-		return fmt.Errorf(string(data))
-	}
-	if strings.TrimSpace(e.Info.Type) == "" && strings.TrimSpace(e.Info.Reason) == "" {
+	if err := json.Unmarshal(data, &e); err != nil || (strings.TrimSpace(e.Info.Type) == "" && strings.TrimSpace(e.Info.Reason) == "") {
+		if resp.StatusCode == http.StatusTooManyRequests {
+			return classifyError(resp.StatusCode, resp.Header, tooManyRequestsResponse(data))
+		}
 		return fmt.Errorf(string(data))
 	}
-	return e
+	return classifyError(resp.StatusCode, resp.Header, e)
 }
 
 // ESCheckError does the same for the esapi.Response type.
@@ -159,13 +161,13 @@ func ESCheckError(resp *esapi.Response) error {
 		return fmt.Errorf("read error: %w", err)
 	}
 	var e ErrorResponse
-	if err := json.Unmarshal(data, &e); err != nil {
-		return fmt.Errorf(string(data))
-	}
-	if strings.TrimSpace(e.Info.Type) == "" && strings.TrimSpace(e.Info.Reason) == "" {
+	if err := json.Unmarshal(data, &e); err != nil || (strings.TrimSpace(e.Info.Type) == "" && strings.TrimSpace(e.Info.Reason) == "") {
+		if resp.StatusCode == http.StatusTooManyRequests {
+			return classifyError(resp.StatusCode, resp.Header, tooManyRequestsResponse(data))
+		}
 		return fmt.Errorf(string(data))
 	}
-	return e
+	return classifyError(resp.StatusCode, resp.Header, e)
 }
 
 // IsIndexNotFoundErr checks if error is index_not_found_exception
@@ -236,6 +238,20 @@ func BuildFilterWithDefaultFieldName(ctx context.Context, queryContext *plugin.Q
 					filters = append(filters, NewTermFilter(fieldName, val))
 				}
 			}
+			if oprStr == "<>" {
+				// != and NOT IN
+				if qual.GetValue().GetListValue() != nil {
+					vals := qual.GetValue().GetListValue().GetValues()
+					stringVals := make([]string, 0, len(vals))
+					for _, v := range vals {
+						stringVals = append(stringVals, qualValue(v))
+					}
+					filters = append(filters, NewBoolMustNotFilter(NewTermsFilter(fieldName, stringVals)))
+				} else {
+					val := qualValue(qual.GetValue())
+					filters = append(filters, NewBoolMustNotFilter(NewTermFilter(fieldName, val)))
+				}
+			}
 			if oprStr == ">" {
 				filters = append(filters, NewRangeFilter(fieldName,
 					qualValue(qual.GetValue()), "", "", ""))
@@ -252,6 +268,26 @@ func BuildFilterWithDefaultFieldName(ctx context.Context, queryContext *plugin.Q
 				filters = append(filters, NewRangeFilter(fieldName, "", "", "",
 					qualValue(qual.GetValue())))
 			}
+			if oprStr == "~~" || oprStr == "~~*" {
+				// LIKE, ILIKE
+				if like, ok := likeQualFilter(ctx, fieldName,
+					qualValue(qual.GetValue()), oprStr == "~~*"); ok {
+					filters = append(filters, like)
+				}
+			}
+			if oprStr == "!~~" || oprStr == "!~~*" {
+				// NOT LIKE, NOT ILIKE
+				if like, ok := likeQualFilter(ctx, fieldName,
+					qualValue(qual.GetValue()), oprStr == "!~~*"); ok {
+					filters = append(filters, NewBoolMustNotFilter(like))
+				}
+			}
+			if oprStr == "is not null" {
+				filters = append(filters, NewExistsFilter(fieldName))
+			}
+			if oprStr == "is null" {
+				filters = append(filters, NewBoolMustNotFilter(NewExistsFilter(fieldName)))
+			}
 		}
 	}
 
@@ -259,73 +295,18 @@ func BuildFilterWithDefaultFieldName(ctx context.Context, queryContext *plugin.Q
 		filters = append(filters, NewTermFilter("integration_id", *integrationID))
 	}
 
-	// If there's an encodedResourceGroupFilters => decode & handle
 	if encodedResourceGroupFilters != nil && len(*encodedResourceGroupFilters) > 0 {
-		resourceGroupFiltersJson, err := base64.StdEncoding.DecodeString(*encodedResourceGroupFilters)
+		var ct string
+		if clientType != nil {
+			ct = *clientType
+		}
+		resourceCollectionFilter, err := CompileResourceCollection(*encodedResourceGroupFilters, ct)
 		if err != nil {
-			plugin.Logger(ctx).Error("BuildFilter", "resourceGroupFiltersJson", "err", err)
+			// Fail closed: without the collection filter the query would be unscoped
+			plugin.Logger(ctx).Error("BuildFilter", "resourceCollectionFilters", "err", err)
+			filters = append(filters, matchNothingFilter)
 		} else {
-			var resourceGroupFilters []ResourceCollectionFilter
-			err = json.Unmarshal(resourceGroupFiltersJson, &resourceGroupFilters)
-			if err != nil {
-				plugin.Logger(ctx).Error("BuildFilter", "resourceGroupFiltersJson", "err", err)
-			} else {
-				esResourceGroupFilters := make([]BoolFilter, 0, len(resourceGroupFilters)+1)
-
-				if clientType != nil && *clientType == "compliance" {
-					taglessTypes := make([]string, 0, len(awsTaglessResourceTypes)+len(azureTaglessResourceTypes))
-					for _, awsTaglessResourceType := range awsTaglessResourceTypes {
-						taglessTypes = append(taglessTypes, strings.ToLower(awsTaglessResourceType))
-					}
-					for _, azureTaglessResourceType := range azureTaglessResourceTypes {
-						taglessTypes = append(taglessTypes, strings.ToLower(azureTaglessResourceType))
-					}
-					esResourceGroupFilters = append(esResourceGroupFilters,
-						NewBoolMustFilter(NewTermsFilter("metadata.ResourceType", taglessTypes)))
-				}
-				for _, rgf := range resourceGroupFilters {
-					andFilters := make([]BoolFilter, 0, 5)
-
-					if len(rgf.Connectors) > 0 {
-						andFilters = append(andFilters, NewTermsFilter("source_type", rgf.Connectors))
-					}
-					if len(rgf.AccountIDs) > 0 {
-						andFilters = append(andFilters, NewTermsFilter("metadata.AccountID", rgf.AccountIDs))
-					}
-					if len(rgf.ResourceTypes) > 0 {
-						andFilters = append(andFilters, NewTermsFilter("metadata.ResourceType", rgf.ResourceTypes))
-					}
-					if len(rgf.Regions) > 0 {
-						andFilters = append(andFilters,
-							NewBoolShouldFilter(
-								NewTermsFilter("metadata.Region", rgf.Regions),
-								NewTermsFilter("metadata.Location", rgf.Regions),
-							),
-						)
-					}
-					if len(rgf.Tags) > 0 {
-						for k, v := range rgf.Tags {
-							kLower := strings.ToLower(k)
-							vLower := strings.ToLower(v)
-							andFilters = append(andFilters,
-								NewNestedFilter("canonical_tags",
-									NewBoolMustFilter(
-										NewTermFilter("canonical_tags.key", kLower),
-										NewTermFilter("canonical_tags.value", vLower),
-									),
-								),
-							)
-						}
-					}
-					if len(andFilters) > 0 {
-						esResourceGroupFilters = append(esResourceGroupFilters,
-							NewBoolMustFilter(andFilters...))
-					}
-				}
-				if len(esResourceGroupFilters) > 0 {
-					filters = append(filters, NewBoolShouldFilter(esResourceGroupFilters...))
-				}
-			}
+			filters = append(filters, resourceCollectionFilter)
 		}
 	}
 
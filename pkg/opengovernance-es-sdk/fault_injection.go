@@ -0,0 +1,44 @@
+package opengovernance
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+
+	"github.com/opengovern/og-util/pkg/faultinject"
+)
+
+// faultInjectionEnv holds a JSON faultinject.Config used when ClientConfig.FaultInjection is nil.
+const faultInjectionEnv = "ELASTICSEARCH_FAULT_INJECTION"
+
+// faultInjectionConfig returns the fault injection config of c, falling back to the
+// environment. Fault injection is meant for test environments only.
+func faultInjectionConfig(c ClientConfig) (*faultinject.Config, error) {
+	if c.FaultInjection != nil {
+		return c.FaultInjection, nil
+	}
+	raw := os.Getenv(faultInjectionEnv)
+	if raw == "" {
+		return nil, nil
+	}
+	var cfg faultinject.Config
+	if err := json.Unmarshal([]byte(raw), &cfg); err != nil {
+		return nil, fmt.Errorf("parse %s: %w", faultInjectionEnv, err)
+	}
+	return &cfg, nil
+}
+
+// esOperation names a request after its API endpoint, e.g. "_search", "_bulk" or "_doc",
+// so fault injection rules can target single operations. Requests without an endpoint
+// segment, such as index creation, are named after their method.
+func esOperation(req *http.Request) string {
+	segments := strings.Split(strings.Trim(req.URL.Path, "/"), "/")
+	for _, segment := range segments {
+		if strings.HasPrefix(segment, "_") {
+			return segment
+		}
+	}
+	return req.Method
+}
@@ -0,0 +1,185 @@
+package opengovernance
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// FilterSQL renders filters, which are combined with AND like in BuildFilter's result, as
+// a human-readable SQL-like query against index, e.g.
+//
+//	SELECT * FROM aws_ec2_instance WHERE region IN ('us-east-1', 'eu-west-1') AND name ILIKE 'web'
+//
+// It is best-effort and meant for logs and error messages, not for execution: case
+// insensitive term matches are shown as ILIKE and filter types it does not know are
+// shown as their JSON.
+func FilterSQL(index string, filters []BoolFilter) string {
+	return fmt.Sprintf("SELECT * FROM %s WHERE %s", index, FilterExpression(filters...))
+}
+
+// QueryError is a failed search annotated with a readable rendering of its filters.
+type QueryError struct {
+	Query string // See FilterSQL
+	Err   error
+}
+
+func (e *QueryError) Error() string {
+	return fmt.Sprintf("%v (query: %s)", e.Err, e.Query)
+}
+
+func (e *QueryError) Unwrap() error {
+	return e.Err
+}
+
+// FilterExpression renders filters, combined with AND, as an SQL-like condition.
+func FilterExpression(filters ...BoolFilter) string {
+	return joinFilters(filters, " AND ", "TRUE")
+}
+
+func joinFilters(filters []BoolFilter, operator, empty string) string {
+	if len(filters) == 0 {
+		return empty
+	}
+	parts := make([]string, 0, len(filters))
+	for _, filter := range filters {
+		parts = append(parts, filterExpression(filter, len(filters) > 1))
+	}
+	return strings.Join(parts, operator)
+}
+
+// filterExpression renders one filter; compound expressions are parenthesized if nested.
+func filterExpression(filter BoolFilter, nested bool) string {
+	parenthesize := func(s string, compound bool) string {
+		if nested && compound {
+			return "(" + s + ")"
+		}
+		return s
+	}
+	switch f := filter.(type) {
+	case TermFilter:
+		if isTypedTermValue(f.value) {
+			return fmt.Sprintf("%s = %s", f.field, sqlLiteral(f.value, false))
+		}
+		return fmt.Sprintf("%s ILIKE %s", f.field, sqlLiteral(f.value, true))
+	case TermsFilter:
+		return fmt.Sprintf("%s IN (%s)", f.field, sqlList(f.values))
+	case TermsSetMatchAllFilter:
+		return fmt.Sprintf("%s CONTAINS ALL (%s)", f.field, sqlList(f.values))
+	case ExistsFilter:
+		return fmt.Sprintf("%s IS NOT NULL", f.field)
+	case PrefixFilter:
+		return fmt.Sprintf("%s %s %s", f.field, likeOperator(f.caseInsensitive), sqlLiteral(likeLiteral(f.prefix)+"%", true))
+	case WildcardFilter:
+		return fmt.Sprintf("%s %s %s", f.field, likeOperator(f.caseInsensitive), sqlLiteral(wildcardToLike(f.pattern), true))
+	case GeoDistanceFilter:
+		return fmt.Sprintf("DISTANCE(%s, POINT(%v, %v)) <= %s", f.field, f.center.Lat, f.center.Lon, sqlLiteral(f.distance, true))
+	case GeoBoundingBoxFilter:
+		return fmt.Sprintf("%s WITHIN BOX(POINT(%v, %v), POINT(%v, %v))", f.field,
+			f.topLeft.Lat, f.topLeft.Lon, f.bottomRight.Lat, f.bottomRight.Lon)
+	case IPRangeFilter:
+		relation := f.relation
+		if relation == "" {
+			relation = IPRangeIntersects
+		}
+		return fmt.Sprintf("%s %s %s", f.field, strings.ToUpper(string(relation)), sqlLiteral(f.cidr, true))
+	case RegexpFilter:
+		op := "~"
+		if f.caseInsensitive {
+			op = "~*"
+		}
+		return fmt.Sprintf("%s %s %s", f.field, op, sqlLiteral("^"+f.pattern+"$", true))
+	case MatchPhraseFilter:
+		return fmt.Sprintf("%s MATCH PHRASE %s", f.field, sqlLiteral(f.phrase, true))
+	case RangeFilter:
+		var conditions []string
+		for _, bound := range []struct{ operator, value string }{{">", f.gt}, {">=", f.gte}, {"<", f.lt}, {"<=", f.lte}} {
+			if bound.value != "" {
+				conditions = append(conditions, fmt.Sprintf("%s %s %s", f.field, bound.operator, sqlLiteral(bound.value, !isTypedTermValue(bound.value))))
+			}
+		}
+		if len(conditions) == 0 {
+			return "TRUE"
+		}
+		return parenthesize(strings.Join(conditions, " AND "), len(conditions) > 1)
+	case BoolShouldFilter:
+		return parenthesize(joinFilters(f.filters, " OR ", "FALSE"), len(f.filters) > 1)
+	case BoolMustFilter:
+		return parenthesize(joinFilters(f.filters, " AND ", "TRUE"), len(f.filters) > 1)
+	case BoolMustNotFilter:
+		if len(f.filters) == 0 {
+			return "TRUE"
+		}
+		return "NOT (" + joinFilters(f.filters, " OR ", "FALSE") + ")"
+	case NestedFilter:
+		return fmt.Sprintf("EXISTS (%s WHERE %s)", f.path, filterExpression(f.query, false))
+	default:
+		data, err := json.Marshal(filter)
+		if err != nil {
+			return fmt.Sprintf("<%T>", filter)
+		}
+		return "JSON(" + sqlLiteral(string(data), true) + ")"
+	}
+}
+
+// isTypedTermValue reports whether TermFilter matches value exactly as a boolean, date or
+// number, rather than as case-insensitive text.
+func isTypedTermValue(value string) bool {
+	lower := strings.ToLower(value)
+	if lower == "true" || lower == "false" {
+		return true
+	}
+	if ok, _ := attemptParseDate(value); ok {
+		return true
+	}
+	return isAllDigits(value) && (len(value) == 1 || value[0] != '0')
+}
+
+func sqlLiteral(value string, quote bool) string {
+	if !quote && (isAllDigits(value) || value == "true" || value == "false") {
+		return value
+	}
+	return "'" + strings.ReplaceAll(value, "'", "''") + "'"
+}
+
+func sqlList(values []string) string {
+	quoted := make([]string, 0, len(values))
+	for _, v := range values {
+		quoted = append(quoted, sqlLiteral(v, true))
+	}
+	return strings.Join(quoted, ", ")
+}
+
+func likeOperator(caseInsensitive bool) string {
+	if caseInsensitive {
+		return "ILIKE"
+	}
+	return "LIKE"
+}
+
+// likeLiteral escapes the LIKE wildcards in value.
+func likeLiteral(value string) string {
+	return strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`).Replace(value)
+}
+
+// wildcardToLike translates a wildcard query pattern into a LIKE pattern.
+func wildcardToLike(pattern string) string {
+	var b strings.Builder
+	escaped := false
+	for _, r := range pattern {
+		switch {
+		case escaped:
+			escaped = false
+			b.WriteString(likeLiteral(string(r)))
+		case r == '\\':
+			escaped = true
+		case r == '*':
+			b.WriteByte('%')
+		case r == '?':
+			b.WriteByte('_')
+		default:
+			b.WriteString(likeLiteral(string(r)))
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,59 @@
+package opengovernance_test
+
+import (
+	"testing"
+
+	opengovernance "github.com/opengovern/og-util/pkg/opengovernance-es-sdk"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFilterSQL(t *testing.T) {
+	require := require.New(t)
+
+	filters := []opengovernance.BoolFilter{
+		opengovernance.NewTermsFilter("region", []string{"us-east-1", "eu-west-1"}),
+		opengovernance.NewTermFilter("name", "o'brien"),
+		opengovernance.NewTermFilter("count", "42"),
+		opengovernance.NewBoolShouldFilter(
+			opengovernance.NewRangeFilter("described_at", "", "2024-01-01", "", "2024-12-31"),
+			opengovernance.NewBoolMustNotFilter(opengovernance.NewTermFilter("enabled", "false")),
+		),
+		opengovernance.NewNestedFilter("tags", opengovernance.NewTermFilter("tags.key", "env")),
+	}
+	require.Equal(
+		"SELECT * FROM aws_ec2_instance WHERE region IN ('us-east-1', 'eu-west-1') AND name ILIKE 'o''brien' AND count = 42"+
+			" AND ((described_at >= '2024-01-01' AND described_at <= '2024-12-31') OR NOT (enabled = false))"+
+			" AND EXISTS (tags WHERE tags.key ILIKE 'env')",
+		opengovernance.FilterSQL("aws_ec2_instance", filters))
+	require.Equal("TRUE", opengovernance.FilterExpression())
+}
+
+func TestFilterSQLMatchFilters(t *testing.T) {
+	require := require.New(t)
+
+	filters := []opengovernance.BoolFilter{
+		opengovernance.NewExistsFilter("arn"),
+		opengovernance.NewLikeFilter("name", "web-%", false),
+		opengovernance.NewLikeFilter("name", `%100\%_`, true),
+		opengovernance.NewMatchPhraseFilter("description", "public access"),
+	}
+	require.Equal(
+		`SELECT * FROM aws_ec2_instance WHERE arn IS NOT NULL AND name LIKE 'web-%' AND name ILIKE '%100\%_'`+
+			` AND description MATCH PHRASE 'public access'`,
+		opengovernance.FilterSQL("aws_ec2_instance", filters))
+}
+
+func TestFilterSQLTagFilters(t *testing.T) {
+	require := require.New(t)
+
+	filters := []opengovernance.BoolFilter{
+		opengovernance.TagEquals("Env", "Prod"),
+		opengovernance.TagExists("Owner"),
+		opengovernance.TagIn("Team", []string{"Core", "SRE"}),
+	}
+	require.Equal(
+		"SELECT * FROM aws_ec2_instance WHERE EXISTS (canonical_tags WHERE canonical_tags.key ILIKE 'env' AND canonical_tags.value ILIKE 'prod')"+
+			" AND EXISTS (canonical_tags WHERE canonical_tags.key ILIKE 'owner')"+
+			" AND EXISTS (canonical_tags WHERE canonical_tags.key ILIKE 'team' AND canonical_tags.value IN ('core', 'sre'))",
+		opengovernance.FilterSQL("aws_ec2_instance", filters))
+}
@@ -0,0 +1,208 @@
+// Package filtertest provides utilities for testing filtersQuals maps against BuildFilter
+// without running Steampipe: builders for plugin.QueryContext quals and a golden-file
+// comparison of the generated ES filter JSON.
+package filtertest
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	opengovernance "github.com/opengovern/og-util/pkg/opengovernance-es-sdk"
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/context_key"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// UpdateGoldenEnv is the environment variable that, when set to a non-empty value, makes
+// AssertGolden (re)write golden files instead of comparing against them.
+const UpdateGoldenEnv = "OG_UPDATE_GOLDEN"
+
+// Context returns a context carrying the logger BuildFilter expects.
+func Context() context.Context {
+	return context.WithValue(context.Background(), context_key.Logger, hclog.NewNullLogger())
+}
+
+// Value converts a Go value to a qual value. Supported types are string, the integer
+// types, float32/float64, bool, time.Time and []any (a list). It panics on any other
+// type, since a bad fixture is a bug in the test.
+func Value(value any) *proto.QualValue {
+	switch v := value.(type) {
+	case *proto.QualValue:
+		return v
+	case string:
+		return &proto.QualValue{Value: &proto.QualValue_StringValue{StringValue: v}}
+	case int:
+		return &proto.QualValue{Value: &proto.QualValue_Int64Value{Int64Value: int64(v)}}
+	case int32:
+		return &proto.QualValue{Value: &proto.QualValue_Int64Value{Int64Value: int64(v)}}
+	case int64:
+		return &proto.QualValue{Value: &proto.QualValue_Int64Value{Int64Value: v}}
+	case float32:
+		return &proto.QualValue{Value: &proto.QualValue_DoubleValue{DoubleValue: float64(v)}}
+	case float64:
+		return &proto.QualValue{Value: &proto.QualValue_DoubleValue{DoubleValue: v}}
+	case bool:
+		return &proto.QualValue{Value: &proto.QualValue_BoolValue{BoolValue: v}}
+	case time.Time:
+		return &proto.QualValue{Value: &proto.QualValue_TimestampValue{TimestampValue: timestamppb.New(v)}}
+	case []any:
+		values := make([]*proto.QualValue, 0, len(v))
+		for _, item := range v {
+			values = append(values, Value(item))
+		}
+		return &proto.QualValue{Value: &proto.QualValue_ListValue{ListValue: &proto.QualValueList{Values: values}}}
+	default:
+		panic(fmt.Sprintf("filtertest: unsupported qual value type %T", value))
+	}
+}
+
+// Qual builds a qual on column with an arbitrary operator.
+func Qual(column, operator string, value any) *proto.Qual {
+	return &proto.Qual{
+		FieldName: column,
+		Operator:  &proto.Qual_StringValue{StringValue: operator},
+		Value:     Value(value),
+	}
+}
+
+// EqualQual builds "column = value".
+func EqualQual(column string, value any) *proto.Qual {
+	return Qual(column, "=", value)
+}
+
+// RangeQual builds "column <operator> value" where operator is one of >, >=, < or <=.
+func RangeQual(column, operator string, value any) *proto.Qual {
+	switch operator {
+	case ">", ">=", "<", "<=":
+	default:
+		panic(fmt.Sprintf("filtertest: unsupported range operator %q", operator))
+	}
+	return Qual(column, operator, value)
+}
+
+// ListQual builds "column = ANY(values)", as Steampipe sends for IN lists.
+func ListQual(column string, values ...any) *proto.Qual {
+	return Qual(column, "=", values)
+}
+
+// NotEqualQual builds "column <> value".
+func NotEqualQual(column string, value any) *proto.Qual {
+	return Qual(column, "<>", value)
+}
+
+// NotInQual builds "column <> ALL(values)", as Steampipe sends for NOT IN lists.
+func NotInQual(column string, values ...any) *proto.Qual {
+	return Qual(column, "<>", values)
+}
+
+// LikeQual builds "column LIKE pattern", or "column ILIKE pattern" if caseInsensitive.
+func LikeQual(column, pattern string, caseInsensitive bool) *proto.Qual {
+	if caseInsensitive {
+		return Qual(column, "~~*", pattern)
+	}
+	return Qual(column, "~~", pattern)
+}
+
+// NullQual builds "column IS NULL", or "column IS NOT NULL" if notNull.
+func NullQual(column string, notNull bool) *proto.Qual {
+	operator := "is null"
+	if notNull {
+		operator = "is not null"
+	}
+	return &proto.Qual{
+		FieldName: column,
+		Operator:  &proto.Qual_StringValue{StringValue: operator},
+	}
+}
+
+// NewQueryContext groups quals by column into a plugin.QueryContext.
+func NewQueryContext(quals ...*proto.Qual) *plugin.QueryContext {
+	unsafeQuals := make(map[string]*proto.Quals)
+	for _, q := range quals {
+		if unsafeQuals[q.FieldName] == nil {
+			unsafeQuals[q.FieldName] = &proto.Quals{}
+		}
+		unsafeQuals[q.FieldName].Quals = append(unsafeQuals[q.FieldName].Quals, q)
+	}
+	return &plugin.QueryContext{UnsafeQuals: unsafeQuals}
+}
+
+// BuildFilterJSON runs BuildFilter for quals against filtersQuals and returns the
+// indented JSON of the resulting filters. Columns are iterated in map order by
+// BuildFilter, so quals on different columns may appear in any order; AssertGolden
+// compares semantically to account for that.
+func BuildFilterJSON(filtersQuals map[string]string, quals ...*proto.Qual) ([]byte, error) {
+	filters := opengovernance.BuildFilter(Context(), NewQueryContext(quals...), filtersQuals, nil, nil, nil)
+	if filters == nil {
+		filters = []opengovernance.BoolFilter{}
+	}
+	return json.MarshalIndent(filters, "", "  ")
+}
+
+// AssertGolden compares the JSON in actual with the golden file at path. Arrays are
+// compared as unordered sets at the top level. With OG_UPDATE_GOLDEN set the golden
+// file is written instead.
+func AssertGolden(t testing.TB, path string, actual []byte) {
+	t.Helper()
+
+	if os.Getenv(UpdateGoldenEnv) != "" {
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatalf("create golden dir: %v", err)
+		}
+		if err := os.WriteFile(path, append(actual, '\n'), 0644); err != nil {
+			t.Fatalf("write golden file: %v", err)
+		}
+		return
+	}
+
+	expected, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatalf("read golden file (set %s=1 to create it): %v", UpdateGoldenEnv, err)
+	}
+
+	var expectedValue, actualValue any
+	if err := json.Unmarshal(expected, &expectedValue); err != nil {
+		t.Fatalf("unmarshal golden file %s: %v", path, err)
+	}
+	if err := json.Unmarshal(actual, &actualValue); err != nil {
+		t.Fatalf("unmarshal actual JSON: %v", err)
+	}
+	if !jsonEqualUnordered(expectedValue, actualValue) {
+		t.Errorf("filter JSON does not match golden file %s (set %s=1 to update)\nexpected:\n%s\nactual:\n%s",
+			path, UpdateGoldenEnv, expected, actual)
+	}
+}
+
+// jsonEqualUnordered compares decoded JSON, treating top-level arrays as multisets.
+func jsonEqualUnordered(expected, actual any) bool {
+	expectedList, ok1 := expected.([]any)
+	actualList, ok2 := actual.([]any)
+	if !ok1 || !ok2 {
+		return reflect.DeepEqual(expected, actual)
+	}
+	if len(expectedList) != len(actualList) {
+		return false
+	}
+	used := make([]bool, len(actualList))
+	for _, e := range expectedList {
+		found := false
+		for i, a := range actualList {
+			if !used[i] && reflect.DeepEqual(e, a) {
+				used[i], found = true, true
+				break
+			}
+		}
+		if !found {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,53 @@
+package filtertest_test
+
+import (
+	"testing"
+
+	"github.com/opengovern/og-util/pkg/opengovernance-es-sdk/filtertest"
+	"github.com/stretchr/testify/require"
+)
+
+var filtersQuals = map[string]string{
+	"instance_id":   "description.Instance.InstanceId",
+	"instance_type": "description.Instance.InstanceType",
+	"cpu_count":     "description.Instance.CpuOptions.CoreCount",
+}
+
+func TestBuildFilterGolden(t *testing.T) {
+	require := require.New(t)
+
+	out, err := filtertest.BuildFilterJSON(filtersQuals,
+		filtertest.EqualQual("instance_id", "i-0123456789abcdef0"),
+		filtertest.ListQual("instance_type", "t3.micro", "m5.large"),
+		filtertest.RangeQual("cpu_count", ">=", 2),
+		filtertest.EqualQual("unmapped_column", "ignored"),
+	)
+	require.NoError(err)
+	filtertest.AssertGolden(t, "testdata/build_filter.golden.json", out)
+}
+
+func TestBuildFilterLikeAndNullGolden(t *testing.T) {
+	require := require.New(t)
+
+	out, err := filtertest.BuildFilterJSON(filtersQuals,
+		filtertest.LikeQual("instance_id", "i-0123%", false),
+		filtertest.LikeQual("instance_type", "T3._ic%", true),
+		filtertest.Qual("cpu_count", "!~~", "1%"),
+		filtertest.NullQual("instance_type", true),
+		filtertest.NullQual("cpu_count", false),
+	)
+	require.NoError(err)
+	filtertest.AssertGolden(t, "testdata/build_filter_like.golden.json", out)
+}
+
+func TestBuildFilterNegativeGolden(t *testing.T) {
+	require := require.New(t)
+
+	out, err := filtertest.BuildFilterJSON(filtersQuals,
+		filtertest.NotEqualQual("instance_id", "i-0123456789abcdef0"),
+		filtertest.NotInQual("instance_type", "t3.micro", "m5.large"),
+		filtertest.NotEqualQual("cpu_count", 4),
+	)
+	require.NoError(err)
+	filtertest.AssertGolden(t, "testdata/build_filter_negative.golden.json", out)
+}
@@ -0,0 +1,150 @@
+package opengovernance
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/netip"
+)
+
+// GeoPoint is a location of a geo_point field.
+type GeoPoint struct {
+	Lat float64 `json:"lat"`
+	Lon float64 `json:"lon"`
+}
+
+// GeoDistanceFilter matches documents whose geo_point field lies within distance of
+// center.
+type GeoDistanceFilter struct {
+	field    string
+	center   GeoPoint
+	distance string
+}
+
+// NewGeoDistanceFilter matches field within distance, e.g. "500km", of center.
+func NewGeoDistanceFilter(field string, center GeoPoint, distance string) BoolFilter {
+	return GeoDistanceFilter{
+		field:    field,
+		center:   center,
+		distance: distance,
+	}
+}
+
+func (t GeoDistanceFilter) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]any{
+		"geo_distance": map[string]any{
+			"distance": t.distance,
+			t.field:    t.center,
+		},
+	})
+}
+func (t GeoDistanceFilter) IsBoolFilter() {}
+
+// GeoBoundingBoxFilter matches documents whose geo_point field lies within a box.
+type GeoBoundingBoxFilter struct {
+	field       string
+	topLeft     GeoPoint
+	bottomRight GeoPoint
+}
+
+func NewGeoBoundingBoxFilter(field string, topLeft, bottomRight GeoPoint) BoolFilter {
+	return GeoBoundingBoxFilter{
+		field:       field,
+		topLeft:     topLeft,
+		bottomRight: bottomRight,
+	}
+}
+
+func (t GeoBoundingBoxFilter) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]any{
+		"geo_bounding_box": map[string]any{
+			t.field: map[string]GeoPoint{
+				"top_left":     t.topLeft,
+				"bottom_right": t.bottomRight,
+			},
+		},
+	})
+}
+func (t GeoBoundingBoxFilter) IsBoolFilter() {}
+
+// IPRangeRelation is how an ip_range field must relate to the range of an
+// IPRangeFilter. It is ignored for ip fields, which match addresses inside the range.
+type IPRangeRelation string
+
+const (
+	IPRangeIntersects IPRangeRelation = "intersects" // Overlaps the range (default)
+	IPRangeContains   IPRangeRelation = "contains"   // Contains the whole range, e.g. a VPC containing a subnet
+	IPRangeWithin     IPRangeRelation = "within"     // Lies inside the range, e.g. subnets of a VPC
+)
+
+// IPRangeFilter matches ip and ip_range fields against the addresses of a CIDR block.
+type IPRangeFilter struct {
+	field    string
+	cidr     string
+	relation IPRangeRelation
+}
+
+// NewIPRangeFilter matches field against cidr, e.g. "10.0.0.0/16"; a single address is
+// treated as a /32 or /128 block. Invalid blocks fail to marshal.
+func NewIPRangeFilter(field, cidr string, relation IPRangeRelation) BoolFilter {
+	return IPRangeFilter{
+		field:    field,
+		cidr:     cidr,
+		relation: relation,
+	}
+}
+
+func (t IPRangeFilter) MarshalJSON() ([]byte, error) {
+	first, last, err := cidrBounds(t.cidr)
+	if err != nil {
+		return nil, err
+	}
+	bounds := map[string]any{
+		"gte": first.String(),
+		"lte": last.String(),
+	}
+	if t.relation != "" {
+		bounds["relation"] = string(t.relation)
+	}
+	return json.Marshal(map[string]any{
+		"range": map[string]any{
+			t.field: bounds,
+		},
+	})
+}
+func (t IPRangeFilter) IsBoolFilter() {}
+
+// cidrBounds returns the first and last address of cidr.
+func cidrBounds(cidr string) (netip.Addr, netip.Addr, error) {
+	prefix, err := netip.ParsePrefix(cidr)
+	if err != nil {
+		addr, addrErr := netip.ParseAddr(cidr)
+		if addrErr != nil {
+			return netip.Addr{}, netip.Addr{}, fmt.Errorf("invalid CIDR block %q: %w", cidr, err)
+		}
+		return addr, addr, nil
+	}
+	prefix = prefix.Masked()
+	first := prefix.Addr()
+	last := first.AsSlice()
+	for bit := prefix.Bits(); bit < len(last)*8; bit++ {
+		last[bit/8] |= 0x80 >> (bit % 8)
+	}
+	lastAddr, _ := netip.AddrFromSlice(last)
+	return first, lastAddr, nil
+}
+
+// GeoPointMapping is the mapping of a geo_point field, for index templates.
+func GeoPointMapping() map[string]any {
+	return map[string]any{"type": "geo_point"}
+}
+
+// IPMapping is the mapping of a field holding IP addresses, for index templates.
+func IPMapping() map[string]any {
+	return map[string]any{"type": "ip"}
+}
+
+// IPRangeMapping is the mapping of a field holding CIDR blocks, such as a VPC's, for
+// index templates.
+func IPRangeMapping() map[string]any {
+	return map[string]any{"type": "ip_range"}
+}
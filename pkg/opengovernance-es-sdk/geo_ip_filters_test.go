@@ -0,0 +1,36 @@
+package opengovernance_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	opengovernance "github.com/opengovern/og-util/pkg/opengovernance-es-sdk"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGeoAndIPFilters(t *testing.T) {
+	require := require.New(t)
+
+	b, err := json.Marshal([]opengovernance.BoolFilter{
+		opengovernance.NewGeoDistanceFilter("location", opengovernance.GeoPoint{Lat: 50.1, Lon: 8.6}, "500km"),
+		opengovernance.NewGeoBoundingBoxFilter("location",
+			opengovernance.GeoPoint{Lat: 60, Lon: -10}, opengovernance.GeoPoint{Lat: 35, Lon: 30}),
+		opengovernance.NewIPRangeFilter("cidr_block", "10.1.2.3/16", opengovernance.IPRangeContains),
+		opengovernance.NewIPRangeFilter("private_ip", "2001:db8::/126", ""),
+		opengovernance.NewIPRangeFilter("public_ip", "52.1.2.3", ""),
+	})
+	require.NoError(err)
+	require.JSONEq(`[
+		{"geo_distance": {"distance": "500km", "location": {"lat": 50.1, "lon": 8.6}}},
+		{"geo_bounding_box": {"location": {"top_left": {"lat": 60, "lon": -10}, "bottom_right": {"lat": 35, "lon": 30}}}},
+		{"range": {"cidr_block": {"gte": "10.1.0.0", "lte": "10.1.255.255", "relation": "contains"}}},
+		{"range": {"private_ip": {"gte": "2001:db8::", "lte": "2001:db8::3"}}},
+		{"range": {"public_ip": {"gte": "52.1.2.3", "lte": "52.1.2.3"}}}
+	]`, string(b))
+
+	_, err = json.Marshal(opengovernance.NewIPRangeFilter("cidr_block", "10.0.0.0/33", ""))
+	require.Error(err)
+
+	require.Equal("cidr_block CONTAINS '10.1.0.0/16'",
+		opengovernance.FilterExpression(opengovernance.NewIPRangeFilter("cidr_block", "10.1.0.0/16", opengovernance.IPRangeContains)))
+}
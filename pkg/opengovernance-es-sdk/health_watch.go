@@ -0,0 +1,202 @@
+package opengovernance
+
+import (
+	"context"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	defaultHealthInterval         = 30 * time.Second
+	defaultHealthFailureThreshold = 3
+	defaultHealthTimeout          = 10 * time.Second
+)
+
+// HealthState is the state of the cluster as seen by a HealthWatch.
+type HealthState string
+
+const (
+	HealthUnknown   HealthState = "unknown"   // Not checked yet
+	HealthHealthy   HealthState = "healthy"   // The last check succeeded
+	HealthUnhealthy HealthState = "unhealthy" // The last check failed
+)
+
+// HealthEvent reports a change of HealthState.
+type HealthEvent struct {
+	State HealthState
+	Err   error // Why the check failed, for HealthUnhealthy
+	At    time.Time
+}
+
+// HealthWatch checks the cluster health in the background. See Client.StartHealthWatch.
+type HealthWatch struct {
+	client    Client
+	interval  time.Duration
+	timeout   time.Duration
+	threshold int
+	events    chan HealthEvent
+
+	mu       sync.Mutex
+	state    HealthState
+	failures int
+
+	stop     chan struct{}
+	stopOnce sync.Once
+	done     chan struct{}
+}
+
+// StartHealthWatch checks the cluster health every interval (30s if not positive) until
+// ctx is cancelled or Stop is called. Changes of state are sent on Events. After 3
+// consecutive failed checks the client's connections are dropped and re-established on
+// the next request, so that the client recovers from e.g. a cluster whose address moved
+// to another host, without being recreated by its users.
+func (c Client) StartHealthWatch(ctx context.Context, interval time.Duration) *HealthWatch {
+	if interval <= 0 {
+		interval = defaultHealthInterval
+	}
+	w := &HealthWatch{
+		client:    c,
+		interval:  interval,
+		timeout:   min(interval, defaultHealthTimeout),
+		threshold: defaultHealthFailureThreshold,
+		events:    make(chan HealthEvent, 1),
+		state:     HealthUnknown,
+		stop:      make(chan struct{}),
+		done:      make(chan struct{}),
+	}
+	go w.run(ctx)
+	return w
+}
+
+// Events returns the changes of state. Only the latest change is kept for a slow
+// receiver. It is closed when the watch stops.
+func (w *HealthWatch) Events() <-chan HealthEvent {
+	return w.events
+}
+
+// State returns the state of the last check.
+func (w *HealthWatch) State() HealthState {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.state
+}
+
+// Stop stops the watch and waits for a running check to finish.
+func (w *HealthWatch) Stop() {
+	w.stopOnce.Do(func() { close(w.stop) })
+	<-w.done
+}
+
+func (w *HealthWatch) run(ctx context.Context) {
+	defer close(w.done)
+	defer close(w.events)
+	ticker := time.NewTicker(w.interval)
+	defer ticker.Stop()
+	for {
+		w.check(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-w.stop:
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (w *HealthWatch) check(ctx context.Context) {
+	checkCtx, cancel := context.WithTimeout(ctx, w.timeout)
+	err := w.client.Healthcheck(checkCtx)
+	cancel()
+	if ctx.Err() != nil {
+		return
+	}
+
+	w.mu.Lock()
+	previous := w.state
+	reconnect := false
+	if err != nil {
+		w.state = HealthUnhealthy
+		w.failures++
+		reconnect = w.failures%w.threshold == 0
+	} else {
+		w.state = HealthHealthy
+		w.failures = 0
+	}
+	state, failures := w.state, w.failures
+	w.mu.Unlock()
+
+	if reconnect {
+		logAt(ctx, w.client.logger, zapcore.WarnLevel, "elasticsearch unhealthy, reconnecting",
+			zap.Error(err), zap.Int("failures", failures))
+		w.client.reconnect()
+	}
+	if state != previous {
+		w.publish(HealthEvent{State: state, Err: err, At: time.Now()})
+	}
+}
+
+// publish sends event, replacing an event the receiver has not taken yet.
+func (w *HealthWatch) publish(event HealthEvent) {
+	for {
+		select {
+		case w.events <- event:
+			return
+		default:
+		}
+		select {
+		case <-w.events:
+		default:
+		}
+	}
+}
+
+// reconnect drops the connections of the client's transport. It does nothing for
+// clients set with SetES, whose transport is not known.
+func (c Client) reconnect() {
+	if c.transport != nil {
+		c.transport.reset()
+	}
+}
+
+// reconnectingTransport is the client's HTTP transport, which HealthWatch replaces with a
+// fresh one, with new connections and DNS lookups, when the cluster stops responding.
+type reconnectingTransport struct {
+	config *TransportConfig
+
+	mu        sync.RWMutex
+	transport *http.Transport
+}
+
+func newReconnectingTransport(config *TransportConfig) (*reconnectingTransport, error) {
+	transport, err := newHTTPTransport(config)
+	if err != nil {
+		return nil, err
+	}
+	return &reconnectingTransport{config: config, transport: transport}, nil
+}
+
+func (t *reconnectingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	t.mu.RLock()
+	transport := t.transport
+	t.mu.RUnlock()
+	return transport.RoundTrip(req)
+}
+
+func (t *reconnectingTransport) reset() {
+	// The config was valid when the client was created
+	transport, err := newHTTPTransport(t.config)
+	if err != nil {
+		return
+	}
+	t.mu.Lock()
+	old := t.transport
+	t.transport = transport
+	t.mu.Unlock()
+	// Requests in flight finish on their connections
+	old.CloseIdleConnections()
+}
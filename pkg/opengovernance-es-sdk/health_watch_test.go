@@ -0,0 +1,102 @@
+package opengovernance_test
+
+import (
+	"context"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	opengovernance "github.com/opengovern/og-util/pkg/opengovernance-es-sdk"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHealthWatchReconnects(t *testing.T) {
+	r := require.New(t)
+
+	var red atomic.Bool
+	var connections atomic.Int32
+	server := httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if red.Load() {
+			w.Write([]byte(`{"status":"red"}`))
+			return
+		}
+		w.Write([]byte(`{"status":"green"}`))
+	}))
+	server.Config.ConnState = func(_ net.Conn, state http.ConnState) {
+		if state == http.StateNew {
+			connections.Add(1)
+		}
+	}
+	server.Start()
+	defer server.Close()
+
+	username, password := "", ""
+	client, err := opengovernance.NewClient(opengovernance.ClientConfig{
+		Addresses: []string{server.URL},
+		Username:  &username,
+		Password:  &password,
+	})
+	r.NoError(err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	watch := client.StartHealthWatch(ctx, 10*time.Millisecond)
+
+	next := func() opengovernance.HealthEvent {
+		select {
+		case event := <-watch.Events():
+			return event
+		case <-time.After(5 * time.Second):
+			t.Fatal("no health event")
+			return opengovernance.HealthEvent{}
+		}
+	}
+	r.Equal(opengovernance.HealthHealthy, next().State)
+	r.Equal(int32(1), connections.Load())
+
+	red.Store(true)
+	event := next()
+	r.Equal(opengovernance.HealthUnhealthy, event.State)
+	r.Error(event.Err)
+	r.Eventually(func() bool { return connections.Load() > 1 }, 5*time.Second, 10*time.Millisecond,
+		"the connection was not replaced after failed checks")
+
+	red.Store(false)
+	r.Equal(opengovernance.HealthHealthy, next().State)
+
+	watch.Stop()
+	_, open := <-watch.Events()
+	r.False(open)
+}
+
+func TestHealthWatchDefaultInterval(t *testing.T) {
+	r := require.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"status":"green"}`))
+	}))
+	defer server.Close()
+
+	username, password := "", ""
+	client, err := opengovernance.NewClient(opengovernance.ClientConfig{
+		Addresses: []string{server.URL},
+		Username:  &username,
+		Password:  &password,
+	})
+	r.NoError(err)
+
+	// A non-positive interval falls back to the default instead of panicking
+	watch := client.StartHealthWatch(context.Background(), 0)
+	select {
+	case event := <-watch.Events():
+		r.Equal(opengovernance.HealthHealthy, event.State)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no health event")
+	}
+	watch.Stop()
+}
@@ -0,0 +1,156 @@
+package opengovernance
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// HedgingPolicy configures request hedging for searches: if a search has not completed
+// after Delay, an identical second request is sent and whichever answers first wins, the
+// other is cancelled. The transport round-robins over the configured addresses, so the
+// hedge usually reaches a different node, cutting tail latency when one node is slow.
+//
+// Hedges are paid for from a budget that grows by BudgetRatio per search, so at most about
+// that fraction of searches is ever duplicated even when the whole cluster is slow.
+type HedgingPolicy struct {
+	Delay       time.Duration // Latency after which the hedge is sent, e.g. the p95 of searches; must be > 0
+	BudgetRatio float64       // Hedges allowed per search (default 0.05)
+	BudgetBurst int           // Maximum hedges that can be saved up (default 10)
+}
+
+// HedgingMetrics is a snapshot of a Client's hedging counters.
+type HedgingMetrics struct {
+	Requests        int64 // Searches sent through the hedger
+	Hedged          int64 // Searches for which a hedge was sent
+	HedgeWins       int64 // Hedges that answered first
+	BudgetExhausted int64 // Searches that were due a hedge but found the budget empty
+}
+
+type hedger struct {
+	delay time.Duration
+	ratio float64
+	burst float64
+
+	mu     sync.Mutex
+	tokens float64
+
+	requests        atomic.Int64
+	hedged          atomic.Int64
+	hedgeWins       atomic.Int64
+	budgetExhausted atomic.Int64
+}
+
+func newHedger(policy HedgingPolicy) *hedger {
+	if policy.Delay <= 0 {
+		return nil
+	}
+	h := &hedger{delay: policy.Delay, ratio: policy.BudgetRatio, burst: float64(policy.BudgetBurst)}
+	if h.ratio <= 0 {
+		h.ratio = 0.05
+	}
+	if h.burst <= 0 {
+		h.burst = 10
+	}
+	h.tokens = h.burst
+	return h
+}
+
+func (h *hedger) earn() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.tokens = min(h.tokens+h.ratio, h.burst)
+}
+
+func (h *hedger) spend() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.tokens < 1 {
+		return false
+	}
+	h.tokens--
+	return true
+}
+
+type hedgeResult struct {
+	body  []byte
+	err   error
+	hedge bool
+}
+
+// do runs send, hedging it if it is slow. send must be idempotent and honor ctx. The
+// first successful result is returned; if both requests fail, the first error is. A nil
+// hedger calls send directly.
+func (h *hedger) do(ctx context.Context, send func(ctx context.Context) ([]byte, error)) ([]byte, error) {
+	if h == nil {
+		return send(ctx)
+	}
+	h.requests.Add(1)
+	h.earn()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel() // Cancels the slower request
+	results := make(chan hedgeResult, 2)
+	run := func(hedge bool) {
+		body, err := send(ctx)
+		results <- hedgeResult{body: body, err: err, hedge: hedge}
+	}
+	go run(false)
+
+	timer := time.NewTimer(h.delay)
+	defer timer.Stop()
+	select {
+	case r := <-results:
+		return r.body, r.err
+	case <-timer.C:
+	}
+	if !h.spend() {
+		h.budgetExhausted.Add(1)
+		r := <-results
+		return r.body, r.err
+	}
+	h.hedged.Add(1)
+	go run(true)
+
+	first := <-results
+	if first.err == nil {
+		if first.hedge {
+			h.hedgeWins.Add(1)
+		}
+		return first.body, nil
+	}
+	second := <-results
+	if second.err == nil {
+		if second.hedge {
+			h.hedgeWins.Add(1)
+		}
+		return second.body, nil
+	}
+	return nil, first.err
+}
+
+func (h *hedger) metrics() HedgingMetrics {
+	if h == nil {
+		return HedgingMetrics{}
+	}
+	return HedgingMetrics{
+		Requests:        h.requests.Load(),
+		Hedged:          h.hedged.Load(),
+		HedgeWins:       h.hedgeWins.Load(),
+		BudgetExhausted: h.budgetExhausted.Load(),
+	}
+}
+
+// SetHedging enables hedging of this client's search requests (Search* and SearchQuery*).
+// A policy with a zero Delay disables it. A hedged search holds a single search limiter
+// slot. Copies of the Client made afterwards share the same budget.
+func (c *Client) SetHedging(policy HedgingPolicy) {
+	c.hedger = newHedger(policy)
+}
+
+// HedgingMetrics returns a snapshot of the hedging counters. All values are zero if
+// hedging is disabled.
+func (c Client) HedgingMetrics() HedgingMetrics {
+	return c.hedger.metrics()
+}
@@ -0,0 +1,84 @@
+package opengovernance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+)
+
+// Hit is a search hit whose _source decodes into T.
+type Hit[T any] struct {
+	Index  string   `json:"_index"`
+	ID     string   `json:"_id"`
+	Score  *float64 `json:"_score"`
+	Source T        `json:"_source"`
+	Sort   []any    `json:"sort"`
+
+	Fields map[string][]any `json:"fields,omitempty"` // Script fields and runtime fields requested as fields
+}
+
+// SearchAfter is the sort values of the last hit of a page, to continue after it.
+type SearchAfter []any
+
+// SearchResponse is a search response whose hits decode into T.
+type SearchResponse[T any] struct {
+	PitID    string `json:"pit_id"`
+	ScrollID string `json:"_scroll_id"`
+	Hits     struct {
+		Total SearchTotal `json:"total"`
+		Hits  []Hit[T]    `json:"hits"`
+	} `json:"hits"`
+	Aggregations AggregationResults `json:"aggregations"`
+}
+
+// SearchAfter returns the sort values of the last hit, or nil if there are no hits.
+func (r *SearchResponse[T]) SearchAfter() SearchAfter {
+	if len(r.Hits.Hits) == 0 {
+		return nil
+	}
+	return r.Hits.Hits[len(r.Hits.Hits)-1].Sort
+}
+
+// Sources returns the decoded _source of each hit, in order.
+func (r *SearchResponse[T]) Sources() []T {
+	sources := make([]T, 0, len(r.Hits.Hits))
+	for _, hit := range r.Hits.Hits {
+		sources = append(sources, hit.Source)
+	}
+	return sources
+}
+
+// DecodeSearchResponse decodes a search response body with typed hits.
+func DecodeSearchResponse[T any](body []byte) (*SearchResponse[T], error) {
+	var response SearchResponse[T]
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("unmarshal response: %w", err)
+	}
+	return &response, nil
+}
+
+// DecodeHits decodes the hits of a search response body and the search_after values to
+// request the next page with.
+func DecodeHits[T any](body []byte) ([]Hit[T], SearchAfter, error) {
+	response, err := DecodeSearchResponse[T](body)
+	if err != nil {
+		return nil, nil, err
+	}
+	return response.Hits.Hits, response.SearchAfter(), nil
+}
+
+// NextPage fetches the next page of p with typed hits and advances p past it, replacing
+// the Search and UpdateState pair of hand-written paginators:
+//
+//	for !p.Done() {
+//		hits, err := opengovernance.NextPage[Resource](ctx, p)
+//		...
+//	}
+func NextPage[T any](ctx context.Context, p *BaseESPaginator) ([]Hit[T], error) {
+	var response SearchResponse[T]
+	if err := p.Search(ctx, &response); err != nil {
+		return nil, err
+	}
+	p.UpdateState(int64(len(response.Hits.Hits)), response.SearchAfter(), response.PitID)
+	return response.Hits.Hits, nil
+}
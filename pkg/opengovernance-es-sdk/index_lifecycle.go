@@ -0,0 +1,228 @@
+package opengovernance
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/opensearch-project/opensearch-go/v2/opensearchapi"
+)
+
+// CreateISMPolicy creates the Index State Management policy policyID, or updates it if it
+// already exists. body is the policy document, {"policy": {...}}.
+func (c Client) CreateISMPolicy(ctx context.Context, policyID string, body string) error {
+	path := "/_plugins/_ism/policies/" + url.PathEscape(policyID)
+	res, err := c.perform(ctx, http.MethodPut, path, strings.NewReader(body))
+	defer CloseSafe(res)
+	if err != nil {
+		return err
+	}
+	if res.StatusCode != http.StatusConflict {
+		return CheckError(res)
+	}
+
+	// Updates must name the version of the policy they replace
+	seqNo, primaryTerm, err := c.ismPolicyVersion(ctx, policyID)
+	if err != nil {
+		return err
+	}
+	path += "?if_seq_no=" + strconv.FormatInt(seqNo, 10) + "&if_primary_term=" + strconv.FormatInt(primaryTerm, 10)
+	res, err = c.perform(ctx, http.MethodPut, path, strings.NewReader(body))
+	defer CloseSafe(res)
+	if err != nil {
+		return err
+	}
+	return CheckError(res)
+}
+
+func (c Client) ismPolicyVersion(ctx context.Context, policyID string) (seqNo, primaryTerm int64, err error) {
+	res, err := c.perform(ctx, http.MethodGet, "/_plugins/_ism/policies/"+url.PathEscape(policyID), nil)
+	defer CloseSafe(res)
+	if err != nil {
+		return 0, 0, err
+	} else if err := CheckError(res); err != nil {
+		return 0, 0, err
+	}
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		return 0, 0, fmt.Errorf("read response: %w", err)
+	}
+	var response struct {
+		SeqNo       int64 `json:"_seq_no"`
+		PrimaryTerm int64 `json:"_primary_term"`
+	}
+	if err := json.Unmarshal(b, &response); err != nil {
+		return 0, 0, fmt.Errorf("unmarshal response: %w", err)
+	}
+	return response.SeqNo, response.PrimaryTerm, nil
+}
+
+type ismAddResponse struct {
+	UpdatedIndices int  `json:"updated_indices"`
+	Failures       bool `json:"failures"`
+	FailedIndices  []struct {
+		IndexName string `json:"index_name"`
+		Reason    string `json:"reason"`
+	} `json:"failed_indices"`
+}
+
+// AttachPolicyToIndex manages index, which may be a pattern, with the ISM policy policyID.
+// Indices already managed by another policy are switched to policyID once they leave
+// their current state.
+func (c Client) AttachPolicyToIndex(ctx context.Context, index string, policyID string) error {
+	body, err := json.Marshal(map[string]string{"policy_id": policyID})
+	if err != nil {
+		return fmt.Errorf("marshal request: %w", err)
+	}
+	failed, err := c.ismIndexRequest(ctx, "/_plugins/_ism/add/"+url.PathEscape(index), body)
+	if err != nil {
+		return err
+	}
+
+	var errs []error
+	for name, reason := range failed {
+		if !strings.Contains(reason, "already has a policy") {
+			errs = append(errs, fmt.Errorf("attach policy %s to %s: %s", policyID, name, reason))
+			continue
+		}
+		changeFailed, err := c.ismIndexRequest(ctx, "/_plugins/_ism/change_policy/"+url.PathEscape(name), body)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("change policy of %s to %s: %w", name, policyID, err))
+			continue
+		}
+		for changedName, reason := range changeFailed {
+			errs = append(errs, fmt.Errorf("change policy of %s to %s: %s", changedName, policyID, reason))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ismIndexRequest sends an ISM add or change_policy request and returns the reason each
+// failed index failed.
+func (c Client) ismIndexRequest(ctx context.Context, path string, body []byte) (map[string]string, error) {
+	res, err := c.perform(ctx, http.MethodPost, path, bytes.NewReader(body))
+	defer CloseSafe(res)
+	if err != nil {
+		return nil, err
+	} else if err := CheckError(res); err != nil {
+		return nil, err
+	}
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	var response ismAddResponse
+	if err := json.Unmarshal(b, &response); err != nil {
+		return nil, fmt.Errorf("unmarshal response: %w", err)
+	}
+	failed := make(map[string]string, len(response.FailedIndices))
+	for _, f := range response.FailedIndices {
+		failed[f.IndexName] = f.Reason
+	}
+	return failed, nil
+}
+
+// perform sends a request to an API without a typed client, such as the ISM plugin's.
+func (c Client) perform(ctx context.Context, method, path string, body io.Reader) (*opensearchapi.Response, error) {
+	req, err := http.NewRequestWithContext(ctx, method, path, body)
+	if err != nil {
+		return nil, err
+	}
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+	res, err := c.es.Perform(req)
+	if err != nil {
+		return nil, err
+	}
+	return &opensearchapi.Response{StatusCode: res.StatusCode, Body: res.Body, Header: res.Header}, nil
+}
+
+// CreateAlias points alias at index. With writeIndex, index receives the alias' writes,
+// as rollover requires.
+func (c Client) CreateAlias(ctx context.Context, index string, alias string, writeIndex bool) error {
+	body, err := json.Marshal(map[string]any{"is_write_index": writeIndex})
+	if err != nil {
+		return fmt.Errorf("marshal request: %w", err)
+	}
+	res, err := c.es.Indices.PutAlias([]string{index}, alias,
+		c.es.Indices.PutAlias.WithContext(ctx),
+		c.es.Indices.PutAlias.WithBody(bytes.NewReader(body)),
+	)
+	defer CloseSafe(res)
+	if err != nil {
+		return err
+	}
+	return CheckError(res)
+}
+
+// RolloverConditions roll an alias over once any of them is met. Zero fields are
+// ignored; without any condition the alias rolls over unconditionally.
+type RolloverConditions struct {
+	MaxAge              string // e.g. "7d"
+	MaxDocs             int64
+	MaxSize             string // e.g. "50gb"
+	MaxPrimaryShardSize string
+}
+
+// RolloverResponse is the outcome of Rollover.
+type RolloverResponse struct {
+	OldIndex   string          `json:"old_index"`
+	NewIndex   string          `json:"new_index"`
+	RolledOver bool            `json:"rolled_over"`
+	DryRun     bool            `json:"dry_run"`
+	Conditions map[string]bool `json:"conditions"` // Whether each condition was met
+}
+
+// Rollover creates a new write index for alias if conditions are met. The new index is
+// named after the current one, whose name must end in a number (e.g. logs-000001).
+func (c Client) Rollover(ctx context.Context, alias string, conditions RolloverConditions) (*RolloverResponse, error) {
+	opts := []func(*opensearchapi.IndicesRolloverRequest){
+		c.es.Indices.Rollover.WithContext(ctx),
+	}
+	cond := map[string]any{}
+	if conditions.MaxAge != "" {
+		cond["max_age"] = conditions.MaxAge
+	}
+	if conditions.MaxDocs > 0 {
+		cond["max_docs"] = conditions.MaxDocs
+	}
+	if conditions.MaxSize != "" {
+		cond["max_size"] = conditions.MaxSize
+	}
+	if conditions.MaxPrimaryShardSize != "" {
+		cond["max_primary_shard_size"] = conditions.MaxPrimaryShardSize
+	}
+	if len(cond) > 0 {
+		body, err := json.Marshal(map[string]any{"conditions": cond})
+		if err != nil {
+			return nil, fmt.Errorf("marshal request: %w", err)
+		}
+		opts = append(opts, c.es.Indices.Rollover.WithBody(bytes.NewReader(body)))
+	}
+
+	res, err := c.es.Indices.Rollover(alias, opts...)
+	defer CloseSafe(res)
+	if err != nil {
+		return nil, err
+	} else if err := CheckError(res); err != nil {
+		return nil, err
+	}
+
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	var response RolloverResponse
+	if err := json.Unmarshal(b, &response); err != nil {
+		return nil, fmt.Errorf("unmarshal response: %w", err)
+	}
+	return &response, nil
+}
@@ -0,0 +1,233 @@
+package opengovernance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"strconv"
+)
+
+// ShardStats is the size of a single shard copy.
+type ShardStats struct {
+	Shard      int
+	Primary    bool
+	State      string
+	Node       string
+	DocCount   int64
+	StoreBytes int64
+}
+
+// IndexStats is the typed size summary of an index.
+type IndexStats struct {
+	Index                 string
+	DocCount              int64 // Primary documents
+	DeletedDocCount       int64 // Primary deleted documents not yet merged away
+	StoreSizeBytes        int64 // Including replicas
+	PrimaryStoreSizeBytes int64
+	SegmentCount          int64 // Including replicas
+	Shards                []ShardStats
+}
+
+type indicesStatsResponse struct {
+	Indices map[string]struct {
+		Primaries indexStatsSection `json:"primaries"`
+		Total     indexStatsSection `json:"total"`
+	} `json:"indices"`
+}
+
+type indexStatsSection struct {
+	Docs struct {
+		Count   int64 `json:"count"`
+		Deleted int64 `json:"deleted"`
+	} `json:"docs"`
+	Store struct {
+		SizeInBytes int64 `json:"size_in_bytes"`
+	} `json:"store"`
+	Segments struct {
+		Count int64 `json:"count"`
+	} `json:"segments"`
+}
+
+type catShard struct {
+	Index  string  `json:"index"`
+	Shard  string  `json:"shard"`
+	Prirep string  `json:"prirep"`
+	State  string  `json:"state"`
+	Docs   *string `json:"docs"`
+	Store  *string `json:"store"`
+	Node   *string `json:"node"`
+}
+
+// IndexStats returns doc counts, store sizes, segment counts and per-shard sizes for the
+// indices matching pattern, sorted by index name.
+func (c Client) IndexStats(ctx context.Context, pattern string) ([]IndexStats, error) {
+	res, err := c.es.Indices.Stats(
+		c.es.Indices.Stats.WithContext(ctx),
+		c.es.Indices.Stats.WithIndex(pattern),
+		c.es.Indices.Stats.WithMetric("docs", "store", "segments"),
+	)
+	defer CloseSafe(res)
+	if err != nil {
+		return nil, err
+	} else if err := CheckError(res); err != nil {
+		if IsIndexNotFoundErr(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	var statsResponse indicesStatsResponse
+	if err := json.Unmarshal(b, &statsResponse); err != nil {
+		return nil, fmt.Errorf("unmarshal response: %w", err)
+	}
+
+	shards, err := c.shardStats(ctx, pattern)
+	if err != nil {
+		return nil, err
+	}
+
+	stats := make([]IndexStats, 0, len(statsResponse.Indices))
+	for name, s := range statsResponse.Indices {
+		stats = append(stats, IndexStats{
+			Index:                 name,
+			DocCount:              s.Primaries.Docs.Count,
+			DeletedDocCount:       s.Primaries.Docs.Deleted,
+			StoreSizeBytes:        s.Total.Store.SizeInBytes,
+			PrimaryStoreSizeBytes: s.Primaries.Store.SizeInBytes,
+			SegmentCount:          s.Total.Segments.Count,
+			Shards:                shards[name],
+		})
+	}
+	sort.Slice(stats, func(i, j int) bool { return stats[i].Index < stats[j].Index })
+	return stats, nil
+}
+
+// shardStats returns the shard copies of the indices matching pattern, keyed by index.
+func (c Client) shardStats(ctx context.Context, pattern string) (map[string][]ShardStats, error) {
+	res, err := c.es.Cat.Shards(
+		c.es.Cat.Shards.WithContext(ctx),
+		c.es.Cat.Shards.WithIndex(pattern),
+		c.es.Cat.Shards.WithFormat("json"),
+		c.es.Cat.Shards.WithBytes("b"),
+	)
+	defer CloseSafe(res)
+	if err != nil {
+		return nil, err
+	} else if err := CheckError(res); err != nil {
+		if IsIndexNotFoundErr(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	var rows []catShard
+	if err := json.Unmarshal(b, &rows); err != nil {
+		return nil, fmt.Errorf("unmarshal response: %w", err)
+	}
+
+	shards := make(map[string][]ShardStats)
+	for _, row := range rows {
+		shard, err := strconv.Atoi(row.Shard)
+		if err != nil {
+			return nil, fmt.Errorf("invalid shard number %q for index %s", row.Shard, row.Index)
+		}
+		s := ShardStats{
+			Shard:      shard,
+			Primary:    row.Prirep == "p",
+			State:      row.State,
+			DocCount:   parseCatInt(row.Docs),
+			StoreBytes: parseCatInt(row.Store),
+		}
+		if row.Node != nil {
+			s.Node = *row.Node
+		}
+		shards[row.Index] = append(shards[row.Index], s)
+	}
+	for _, list := range shards {
+		sort.Slice(list, func(i, j int) bool {
+			if list[i].Shard != list[j].Shard {
+				return list[i].Shard < list[j].Shard
+			}
+			return list[i].Primary && !list[j].Primary
+		})
+	}
+	return shards, nil
+}
+
+// parseCatInt parses a numeric _cat column, which is null for unassigned shards.
+func parseCatInt(v *string) int64 {
+	if v == nil {
+		return 0
+	}
+	n, _ := strconv.ParseInt(*v, 10, 64)
+	return n
+}
+
+// CapacityThresholds are the limits CheckCapacity flags. A zero value disables that check.
+type CapacityThresholds struct {
+	MaxIndexSizeBytes int64 // Primary store size per index
+	MaxShardSizeBytes int64 // Store size per shard copy
+	MaxDocCount       int64 // Primary documents per index
+	MaxSegmentCount   int64 // Segments per index, including replicas
+}
+
+// CapacityWarning is a single threshold exceeded by an index or shard.
+type CapacityWarning struct {
+	Index string
+	Shard *int // Set for shard-level warnings
+	Check string
+	Value int64
+	Limit int64
+}
+
+func (w CapacityWarning) String() string {
+	if w.Shard != nil {
+		return fmt.Sprintf("%s shard %d: %s %d exceeds %d", w.Index, *w.Shard, w.Check, w.Value, w.Limit)
+	}
+	return fmt.Sprintf("%s: %s %d exceeds %d", w.Index, w.Check, w.Value, w.Limit)
+}
+
+// CheckCapacity returns a warning for every threshold exceeded by stats, in index order.
+// Shards are reported once per shard number, using the largest copy.
+func CheckCapacity(stats []IndexStats, thresholds CapacityThresholds) []CapacityWarning {
+	var warnings []CapacityWarning
+	exceeds := func(value, limit int64) bool { return limit > 0 && value > limit }
+
+	for _, s := range stats {
+		if exceeds(s.PrimaryStoreSizeBytes, thresholds.MaxIndexSizeBytes) {
+			warnings = append(warnings, CapacityWarning{Index: s.Index, Check: "primary_store_size_bytes", Value: s.PrimaryStoreSizeBytes, Limit: thresholds.MaxIndexSizeBytes})
+		}
+		if exceeds(s.DocCount, thresholds.MaxDocCount) {
+			warnings = append(warnings, CapacityWarning{Index: s.Index, Check: "doc_count", Value: s.DocCount, Limit: thresholds.MaxDocCount})
+		}
+		if exceeds(s.SegmentCount, thresholds.MaxSegmentCount) {
+			warnings = append(warnings, CapacityWarning{Index: s.Index, Check: "segment_count", Value: s.SegmentCount, Limit: thresholds.MaxSegmentCount})
+		}
+
+		largest := make(map[int]int64)
+		var order []int
+		for _, shard := range s.Shards {
+			if _, ok := largest[shard.Shard]; !ok {
+				order = append(order, shard.Shard)
+			}
+			if shard.StoreBytes > largest[shard.Shard] {
+				largest[shard.Shard] = shard.StoreBytes
+			}
+		}
+		for _, shard := range order {
+			if exceeds(largest[shard], thresholds.MaxShardSizeBytes) {
+				shard := shard
+				warnings = append(warnings, CapacityWarning{Index: s.Index, Shard: &shard, Check: "shard_store_size_bytes", Value: largest[shard], Limit: thresholds.MaxShardSizeBytes})
+			}
+		}
+	}
+	return warnings
+}
@@ -0,0 +1,80 @@
+package opengovernance
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Lifecycle fields maintained on resource documents. Timestamps are epoch milliseconds.
+const (
+	FieldFirstDescribedAt = "first_described_at"
+	FieldLastDescribedAt  = "last_described_at"
+	FieldIsStale          = "is_stale"
+)
+
+// LifecycleStrategies are the merge strategies that keep lifecycle fields correct when a
+// resource is described again: the earliest first_described_at and the latest
+// last_described_at win, and a fresh description clears is_stale.
+func LifecycleStrategies() map[string]MergeStrategy {
+	return map[string]MergeStrategy{
+		FieldFirstDescribedAt: MergeKeepMin,
+		FieldLastDescribedAt:  MergeKeepMax,
+		FieldIsStale:          MergeOverwrite,
+	}
+}
+
+// SetLifecycleFields stamps doc as described at describedAt. Used as-is for a new
+// document; merged with LifecycleStrategies when the document already exists.
+func SetLifecycleFields(doc map[string]any, describedAt time.Time) {
+	ms := describedAt.UnixMilli()
+	doc[FieldFirstDescribedAt] = ms
+	doc[FieldLastDescribedAt] = ms
+	doc[FieldIsStale] = false
+}
+
+// NewLifecycleUpsert returns an UpsertDocument for a resource described at describedAt
+// that maintains the lifecycle fields. doc is not modified. Strategies for other fields
+// may be added to the result before calling Upsert.
+func NewLifecycleUpsert(index, id string, doc map[string]any, describedAt time.Time) UpsertDocument {
+	stamped := make(map[string]any, len(doc)+3)
+	for k, v := range doc {
+		stamped[k] = v
+	}
+	SetLifecycleFields(stamped, describedAt)
+	return UpsertDocument{
+		Index:      index,
+		ID:         id,
+		Doc:        stamped,
+		Strategies: LifecycleStrategies(),
+	}
+}
+
+// NewStaleFilter matches documents not described within window of now.
+func NewStaleFilter(window time.Duration, now time.Time) BoolFilter {
+	return NewRangeFilter(FieldLastDescribedAt, "", "", strconv.FormatInt(now.Add(-window).UnixMilli(), 10), "")
+}
+
+// NewFreshFilter matches documents described within window of now.
+func NewFreshFilter(window time.Duration, now time.Time) BoolFilter {
+	return NewRangeFilter(FieldLastDescribedAt, "", strconv.FormatInt(now.Add(-window).UnixMilli(), 10), "", "")
+}
+
+// MarkStale sets is_stale on documents in indices that were last described more than
+// window before now and are not already marked. filters narrow the update, e.g. to one
+// integration. Version conflicts with concurrent ingestion are skipped rather than failing,
+// since a concurrent write means the document was just described.
+func (c Client) MarkStale(ctx context.Context, indices []string, window time.Duration, now time.Time, filters ...BoolFilter) (UpdateByQueryResponse, error) {
+	must := append([]BoolFilter{NewStaleFilter(window, now)}, filters...)
+	query := map[string]any{
+		"bool": map[string]any{
+			"filter": must,
+			"must_not": []BoolFilter{
+				NewTermFilter(FieldIsStale, "true"),
+			},
+		},
+	}
+	script := fmt.Sprintf("ctx._source.%s = true", FieldIsStale)
+	return c.UpdateByQuery(ctx, indices, query, script, nil, UpdateByQueryOptions{Conflicts: ConflictsProceed})
+}
@@ -0,0 +1,164 @@
+package opengovernance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// ErrSearchQueueTimeout is returned when a search waits longer than SearchLimits.QueueTimeout
+// for a concurrency slot.
+var ErrSearchQueueTimeout = errors.New("timed out waiting for a search concurrency slot")
+
+// SearchLimits bounds the number of concurrent search requests a Client sends, so that a
+// burst of callers (e.g. compliance evaluations) queues locally instead of exhausting
+// coordinating node memory.
+type SearchLimits struct {
+	MaxConcurrent int            // Maximum in-flight searches across all indices; <= 0 means unlimited
+	PerIndex      map[string]int // Maximum in-flight searches per index name; <= 0 means unlimited
+	QueueTimeout  time.Duration  // Maximum time to wait for a slot; 0 waits until the context is done
+}
+
+// SearchLimiterMetrics is a snapshot of a Client's search limiter counters.
+type SearchLimiterMetrics struct {
+	InFlight  int64         // Searches currently holding a slot
+	Queued    int64         // Searches currently waiting for a slot
+	Acquired  int64         // Total searches that obtained a slot
+	TimedOut  int64         // Total searches rejected with ErrSearchQueueTimeout
+	Cancelled int64         // Total searches whose context ended while queued
+	TotalWait time.Duration // Cumulative time spent waiting for slots
+}
+
+type searchLimiter struct {
+	global       chan struct{}
+	perIndex     map[string]chan struct{}
+	queueTimeout time.Duration
+
+	inFlight  atomic.Int64
+	queued    atomic.Int64
+	acquired  atomic.Int64
+	timedOut  atomic.Int64
+	cancelled atomic.Int64
+	totalWait atomic.Int64
+}
+
+func newSearchLimiter(limits SearchLimits) *searchLimiter {
+	l := &searchLimiter{
+		perIndex:     make(map[string]chan struct{}),
+		queueTimeout: limits.QueueTimeout,
+	}
+	if limits.MaxConcurrent > 0 {
+		l.global = make(chan struct{}, limits.MaxConcurrent)
+	}
+	for index, max := range limits.PerIndex {
+		if max > 0 {
+			l.perIndex[index] = make(chan struct{}, max)
+		}
+	}
+	return l
+}
+
+// acquire blocks until a slot is available for index (which may be a comma-separated
+// list) and returns a function that releases it. A nil limiter never blocks.
+func (l *searchLimiter) acquire(ctx context.Context, index string) (func(), error) {
+	if l == nil {
+		return func() {}, nil
+	}
+
+	// Acquire per-index slots in a stable order so that multi-index searches can't deadlock
+	// each other, and the global slot last so it isn't held while waiting on an index.
+	var sems []chan struct{}
+	var names []string
+	for _, name := range strings.Split(index, ",") {
+		name = strings.TrimSpace(name)
+		if _, ok := l.perIndex[name]; ok {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+	for i, name := range names {
+		if i > 0 && names[i-1] == name {
+			continue
+		}
+		sems = append(sems, l.perIndex[name])
+	}
+	if l.global != nil {
+		sems = append(sems, l.global)
+	}
+	if len(sems) == 0 {
+		l.acquired.Add(1)
+		l.inFlight.Add(1)
+		return func() { l.inFlight.Add(-1) }, nil
+	}
+
+	waitCtx := ctx
+	if l.queueTimeout > 0 {
+		var cancel context.CancelFunc
+		waitCtx, cancel = context.WithTimeout(ctx, l.queueTimeout)
+		defer cancel()
+	}
+
+	start := time.Now()
+	l.queued.Add(1)
+	held := make([]chan struct{}, 0, len(sems))
+	release := func() {
+		for _, sem := range held {
+			<-sem
+		}
+	}
+	for _, sem := range sems {
+		select {
+		case sem <- struct{}{}:
+			held = append(held, sem)
+		case <-waitCtx.Done():
+			release()
+			l.queued.Add(-1)
+			l.totalWait.Add(int64(time.Since(start)))
+			if ctx.Err() != nil {
+				l.cancelled.Add(1)
+				return nil, ctx.Err()
+			}
+			l.timedOut.Add(1)
+			return nil, fmt.Errorf("%w on index %s after %s", ErrSearchQueueTimeout, index, l.queueTimeout)
+		}
+	}
+	l.queued.Add(-1)
+	l.totalWait.Add(int64(time.Since(start)))
+	l.acquired.Add(1)
+	l.inFlight.Add(1)
+
+	return func() {
+		l.inFlight.Add(-1)
+		release()
+	}, nil
+}
+
+func (l *searchLimiter) metrics() SearchLimiterMetrics {
+	if l == nil {
+		return SearchLimiterMetrics{}
+	}
+	return SearchLimiterMetrics{
+		InFlight:  l.inFlight.Load(),
+		Queued:    l.queued.Load(),
+		Acquired:  l.acquired.Load(),
+		TimedOut:  l.timedOut.Load(),
+		Cancelled: l.cancelled.Load(),
+		TotalWait: time.Duration(l.totalWait.Load()),
+	}
+}
+
+// SetSearchLimits configures the concurrency limits applied to this client's search
+// operations. Copies of the Client made afterwards share the same limiter.
+func (c *Client) SetSearchLimits(limits SearchLimits) {
+	c.limiter = newSearchLimiter(limits)
+}
+
+// SearchMetrics returns a snapshot of the search limiter counters. All values are zero
+// if no limits have been set.
+func (c Client) SearchMetrics() SearchLimiterMetrics {
+	return c.limiter.metrics()
+}
@@ -0,0 +1,108 @@
+package opengovernance
+
+import (
+	"context"
+	"sync/atomic"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/context_key"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+var (
+	defaultLogger atomic.Pointer[zap.Logger]
+	logLevel      = zap.NewAtomicLevelAt(zapcore.InfoLevel)
+)
+
+// SetDefaultLogger sets the logger of clients and paginators without their own logger,
+// when the context carries none. Without one, messages go to zap.L(), a no-op unless
+// replaced with zap.ReplaceGlobals, so services' stdout stays clean by default.
+func SetDefaultLogger(logger *zap.Logger) {
+	defaultLogger.Store(logger)
+}
+
+type loggerContextKey struct{}
+
+// ContextWithLogger returns a copy of ctx carrying logger, which the SDK uses for calls
+// made with ctx unless the client or paginator has its own (see SetLogger). It reaches
+// code paths without a client, such as LogWarn and CheckErrorWithContext.
+func ContextWithLogger(ctx context.Context, logger *zap.Logger) context.Context {
+	return context.WithValue(ctx, loggerContextKey{}, logger)
+}
+
+// SetLogLevel sets the minimum level of messages the SDK logs (default info). Query
+// bodies of SearchWithLog and failed requests are logged at debug level.
+func SetLogLevel(level zapcore.Level) {
+	logLevel.SetLevel(level)
+}
+
+// SetLogger makes the client log to logger instead of the context's steampipe logger or
+// the default logger.
+func (c *Client) SetLogger(logger *zap.Logger) {
+	c.logger = logger
+}
+
+// WithLogger makes the paginator log to logger instead of the context's steampipe logger
+// or the default logger.
+func (p *BaseESPaginator) WithLogger(logger *zap.Logger) *BaseESPaginator {
+	p.logger = logger
+	return p
+}
+
+func (p *BaseESPaginator) log(ctx context.Context, level zapcore.Level, msg string, fields ...zap.Field) {
+	logAt(ctx, p.logger, level, msg, append(fields, zap.String("index", p.index))...)
+}
+
+// logAt logs msg to logger if set, else to the logger of ctx (see ContextWithLogger),
+// else to the steampipe plugin logger of ctx, else to the default logger.
+func logAt(ctx context.Context, logger *zap.Logger, level zapcore.Level, msg string, fields ...zap.Field) {
+	if !logLevel.Enabled(level) {
+		return
+	}
+	if logger == nil && ctx != nil {
+		logger, _ = ctx.Value(loggerContextKey{}).(*zap.Logger)
+	}
+	if logger == nil && ctx != nil && ctx.Value(context_key.Logger) != nil {
+		if pluginLogger, ok := ctx.Value(context_key.Logger).(hclog.Logger); ok {
+			pluginLogger.Log(hclogLevel(level), msg, hclogArgs(fields)...)
+			return
+		}
+	}
+	if logger == nil {
+		logger = defaultLogger.Load()
+	}
+	if logger == nil {
+		logger = zap.L()
+	}
+	if entry := logger.Check(level, msg); entry != nil {
+		entry.Write(fields...)
+	}
+}
+
+func hclogLevel(level zapcore.Level) hclog.Level {
+	switch {
+	case level <= zapcore.DebugLevel:
+		return hclog.Debug
+	case level == zapcore.InfoLevel:
+		return hclog.Info
+	case level == zapcore.WarnLevel:
+		return hclog.Warn
+	default:
+		return hclog.Error
+	}
+}
+
+// hclogArgs converts zap fields to hclog's alternating keys and values.
+func hclogArgs(fields []zap.Field) []any {
+	if len(fields) == 0 {
+		return nil
+	}
+	encoder := zapcore.NewMapObjectEncoder()
+	args := make([]any, 0, 2*len(fields))
+	for _, field := range fields {
+		field.AddTo(encoder)
+		args = append(args, field.Key, encoder.Fields[field.Key])
+	}
+	return args
+}
@@ -0,0 +1,205 @@
+package opengovernance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// Mapping is the typed mapping of an index.
+type Mapping struct {
+	Dynamic    any                      `json:"dynamic,omitempty"` // true, false, "strict" or "runtime"
+	Properties map[string]*MappingField `json:"properties,omitempty"`
+}
+
+// MappingField is a field of a mapping. Object and nested fields have Properties;
+// multi-fields, such as the keyword sub-field of a text field, are in Fields. The other
+// parameters of the field (analyzer, format, index, ...) are kept in Params.
+type MappingField struct {
+	Type       string                   `json:"type,omitempty"`
+	Properties map[string]*MappingField `json:"properties,omitempty"`
+	Fields     map[string]*MappingField `json:"fields,omitempty"`
+	Params     map[string]any           `json:"-"`
+}
+
+// FieldType returns the type of the field, "object" for object fields, which have no
+// explicit type.
+func (f *MappingField) FieldType() string {
+	if f.Type == "" && f.Properties != nil {
+		return "object"
+	}
+	return f.Type
+}
+
+func (f *MappingField) UnmarshalJSON(data []byte) error {
+	type field MappingField
+	var known field
+	if err := json.Unmarshal(data, &known); err != nil {
+		return err
+	}
+	var params map[string]any
+	if err := json.Unmarshal(data, &params); err != nil {
+		return err
+	}
+	delete(params, "type")
+	delete(params, "properties")
+	delete(params, "fields")
+	if len(params) > 0 {
+		known.Params = params
+	}
+	*f = MappingField(known)
+	return nil
+}
+
+func (f MappingField) MarshalJSON() ([]byte, error) {
+	out := make(map[string]any, len(f.Params)+3)
+	for k, v := range f.Params {
+		out[k] = v
+	}
+	if f.Type != "" {
+		out["type"] = f.Type
+	}
+	if f.Properties != nil {
+		out["properties"] = f.Properties
+	}
+	if f.Fields != nil {
+		out["fields"] = f.Fields
+	}
+	return json.Marshal(out)
+}
+
+// Fields returns every field of the mapping by dotted path, including the fields of
+// objects ("metadata.region") and multi-fields ("name.keyword").
+func (m *Mapping) Fields() map[string]*MappingField {
+	fields := make(map[string]*MappingField)
+	flattenMappingFields("", m.Properties, fields)
+	return fields
+}
+
+func flattenMappingFields(prefix string, properties map[string]*MappingField, out map[string]*MappingField) {
+	for name, field := range properties {
+		if field == nil {
+			continue
+		}
+		path := prefix + name
+		out[path] = field
+		flattenMappingFields(path+".", field.Properties, out)
+		flattenMappingFields(path+".", field.Fields, out)
+	}
+}
+
+type getMappingResponse map[string]struct {
+	Mappings Mapping `json:"mappings"`
+}
+
+// GetMapping returns the live mapping of index, which may be an alias or a pattern
+// matching a single index.
+func (c Client) GetMapping(ctx context.Context, index string) (*Mapping, error) {
+	res, err := c.es.Indices.GetMapping(
+		c.es.Indices.GetMapping.WithContext(ctx),
+		c.es.Indices.GetMapping.WithIndex(index),
+	)
+	defer CloseSafe(res)
+	if err != nil {
+		return nil, err
+	} else if err := CheckError(res); err != nil {
+		return nil, err
+	}
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	var response getMappingResponse
+	if err := json.Unmarshal(b, &response); err != nil {
+		return nil, fmt.Errorf("unmarshal response: %w", err)
+	}
+	if len(response) != 1 {
+		indices := make([]string, 0, len(response))
+		for name := range response {
+			indices = append(indices, name)
+		}
+		sort.Strings(indices)
+		return nil, fmt.Errorf("%s matches %d indices, expected one: %v", index, len(response), indices)
+	}
+	for _, index := range response {
+		mapping := index.Mappings
+		return &mapping, nil
+	}
+	return nil, nil
+}
+
+// MappingFieldChange is a field whose type differs between two mappings.
+type MappingFieldChange struct {
+	Field string
+	From  string // Live type
+	To    string // Desired type
+}
+
+// MappingDiff is the drift of a live mapping from the desired one. Fields are dotted
+// paths, sorted.
+type MappingDiff struct {
+	Added       []string             // In the desired mapping only
+	Removed     []string             // In the live mapping only, e.g. added by dynamic mapping
+	TypeChanged []MappingFieldChange // In both with different types; requires a reindex
+}
+
+// HasDrift reports whether the mappings differ.
+func (d MappingDiff) HasDrift() bool {
+	return len(d.Added) > 0 || len(d.Removed) > 0 || len(d.TypeChanged) > 0
+}
+
+// CompareMapping diffs the desired mapping, given as the JSON of a mapping or of an index
+// definition with "mappings" (e.g. an index template body), against the live mapping
+// returned by GetMapping. Only fields and their types are compared, not their other
+// parameters.
+func CompareMapping(desired []byte, live *Mapping) (MappingDiff, error) {
+	var wrapper struct {
+		Mappings *Mapping `json:"mappings"`
+		Template *struct {
+			Mappings *Mapping `json:"mappings"`
+		} `json:"template"`
+	}
+	if err := json.Unmarshal(desired, &wrapper); err != nil {
+		return MappingDiff{}, fmt.Errorf("unmarshal desired mapping: %w", err)
+	}
+	want := wrapper.Mappings
+	if want == nil && wrapper.Template != nil {
+		want = wrapper.Template.Mappings
+	}
+	if want == nil {
+		want = &Mapping{}
+		if err := json.Unmarshal(desired, want); err != nil {
+			return MappingDiff{}, fmt.Errorf("unmarshal desired mapping: %w", err)
+		}
+	}
+	if live == nil {
+		live = &Mapping{}
+	}
+	return diffMappings(want, live), nil
+}
+
+func diffMappings(desired, live *Mapping) MappingDiff {
+	want, have := desired.Fields(), live.Fields()
+	var diff MappingDiff
+	for path, field := range want {
+		liveField, ok := have[path]
+		if !ok {
+			diff.Added = append(diff.Added, path)
+			continue
+		}
+		if field.FieldType() != liveField.FieldType() {
+			diff.TypeChanged = append(diff.TypeChanged, MappingFieldChange{Field: path, From: liveField.FieldType(), To: field.FieldType()})
+		}
+	}
+	for path := range have {
+		if _, ok := want[path]; !ok {
+			diff.Removed = append(diff.Removed, path)
+		}
+	}
+	sort.Strings(diff.Added)
+	sort.Strings(diff.Removed)
+	sort.Slice(diff.TypeChanged, func(i, j int) bool { return diff.TypeChanged[i].Field < diff.TypeChanged[j].Field })
+	return diff
+}
@@ -0,0 +1,63 @@
+package opengovernance_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	opengovernance "github.com/opengovern/og-util/pkg/opengovernance-es-sdk"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompareMapping(t *testing.T) {
+	r := require.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		r.Equal("/inventory/_mapping", req.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"inventory-000002":{"mappings":{"dynamic":"true","properties":{
+			"name":{"type":"text","analyzer":"standard","fields":{"keyword":{"type":"keyword","ignore_above":256}}},
+			"created_at":{"type":"keyword"},
+			"metadata":{"properties":{"region":{"type":"keyword"},"zone":{"type":"keyword"}}},
+			"tags":{"type":"object","enabled":false}
+		}}}}`))
+	}))
+	defer server.Close()
+
+	username, password := "", ""
+	client, err := opengovernance.NewClient(opengovernance.ClientConfig{
+		Addresses: []string{server.URL},
+		Username:  &username,
+		Password:  &password,
+	})
+	r.NoError(err)
+
+	live, err := client.GetMapping(context.Background(), "inventory")
+	r.NoError(err)
+	fields := live.Fields()
+	r.Equal("keyword", fields["name.keyword"].FieldType())
+	r.Equal("object", fields["metadata"].FieldType())
+	r.Equal("standard", fields["name"].Params["analyzer"])
+	r.Equal(false, fields["tags"].Params["enabled"])
+
+	desired := []byte(`{"settings":{"number_of_shards":1},"mappings":{"properties":{
+		"name":{"type":"text","fields":{"keyword":{"type":"keyword"}}},
+		"created_at":{"type":"date"},
+		"metadata":{"properties":{"region":{"type":"keyword"},"account_id":{"type":"keyword"}}},
+		"tags":{"type":"object"}
+	}}}`)
+	diff, err := opengovernance.CompareMapping(desired, live)
+	r.NoError(err)
+	r.True(diff.HasDrift())
+	r.Equal([]string{"metadata.account_id"}, diff.Added)
+	r.Equal([]string{"metadata.zone"}, diff.Removed)
+	r.Equal([]opengovernance.MappingFieldChange{{Field: "created_at", From: "keyword", To: "date"}}, diff.TypeChanged)
+
+	// A bare mapping without differences
+	diff, err = opengovernance.CompareMapping([]byte(`{"properties":{"a":{"type":"keyword"}}}`), &opengovernance.Mapping{
+		Properties: map[string]*opengovernance.MappingField{"a": {Type: "keyword"}},
+	})
+	r.NoError(err)
+	r.False(diff.HasDrift())
+}
@@ -0,0 +1,191 @@
+package opengovernance
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"path"
+	"strings"
+
+	"github.com/opengovern/og-util/pkg/api"
+	"go.uber.org/zap"
+)
+
+// DefaultMaskValue replaces masked fields unless a rule sets its own replacement.
+const DefaultMaskValue = "****"
+
+type roleContextKey struct{}
+
+// WithRole returns a context carrying the role of the caller a query is run for.
+func WithRole(ctx context.Context, role api.Role) context.Context {
+	return context.WithValue(ctx, roleContextKey{}, role)
+}
+
+// RoleFromContext returns the role set by WithRole.
+func RoleFromContext(ctx context.Context) (api.Role, bool) {
+	role, ok := ctx.Value(roleContextKey{}).(api.Role)
+	return role, ok && role != ""
+}
+
+// MaskRule hides a _source field from callers below MinRole.
+type MaskRule struct {
+	Field       string   // Dotted path within _source, e.g. "description.AccountId"; arrays are traversed
+	MinRole     api.Role // Lowest role that sees the real value
+	Indices     []string // Index names or path.Match patterns the rule applies to; all if empty
+	Replacement any      // Value written instead; DefaultMaskValue if nil
+}
+
+// FieldMaskerOptions configures NewFieldMasker.
+type FieldMaskerOptions struct {
+	Rules []MaskRule
+	// Logger receives an audit entry for each response in which fields were masked.
+	// Audit logging is disabled if nil.
+	Logger *zap.Logger
+}
+
+// FieldMasker masks _source fields in search and get responses according to the role in
+// the request context. Requests without a role are masked as the least privileged role.
+type FieldMasker struct {
+	rules  []MaskRule
+	logger *zap.Logger
+}
+
+func NewFieldMasker(opts FieldMaskerOptions) (*FieldMasker, error) {
+	rules := make([]MaskRule, 0, len(opts.Rules))
+	for _, rule := range opts.Rules {
+		if rule.Field == "" {
+			return nil, fmt.Errorf("mask rule requires a field")
+		}
+		role := api.GetRole(string(rule.MinRole))
+		if role == "" {
+			return nil, fmt.Errorf("mask rule for %s has unknown role %q", rule.Field, rule.MinRole)
+		}
+		// AtLeast compares exact values: a rule for "Admin" would mask nothing
+		rule.MinRole = role
+		for _, pattern := range rule.Indices {
+			if _, err := path.Match(pattern, ""); err != nil {
+				return nil, fmt.Errorf("mask rule for %s has invalid index pattern %q: %w", rule.Field, pattern, err)
+			}
+		}
+		rules = append(rules, rule)
+	}
+	return &FieldMasker{
+		rules:  rules,
+		logger: opts.Logger,
+	}, nil
+}
+
+// Hook returns the masker as a ResponseHook for Client.AddResponseHook and
+// BaseESPaginator.SetResponseHooks.
+func (m *FieldMasker) Hook() ResponseHook {
+	return m.Mask
+}
+
+// Mask applies the rules matching index and the caller's role to every _source in body,
+// which may be a search, get or multi-get response.
+func (m *FieldMasker) Mask(ctx context.Context, index string, body []byte) ([]byte, error) {
+	role, _ := RoleFromContext(ctx)
+	var rules []MaskRule
+	for _, rule := range m.rules {
+		if !role.AtLeast(rule.MinRole) && rule.appliesTo(index) {
+			rules = append(rules, rule)
+		}
+	}
+	if len(rules) == 0 {
+		return body, nil
+	}
+
+	dec := json.NewDecoder(bytes.NewReader(body))
+	dec.UseNumber()
+	var doc map[string]any
+	if err := dec.Decode(&doc); err != nil {
+		return nil, fmt.Errorf("decode response for masking: %w", err)
+	}
+
+	masked := make(map[string]int)
+	maskSource := func(source any) {
+		for _, rule := range rules {
+			masked[rule.Field] += maskPath(source, strings.Split(rule.Field, "."), rule.replacement())
+		}
+	}
+	if source, ok := doc["_source"]; ok {
+		maskSource(source)
+	}
+	maskList := func(list any) {
+		items, _ := list.([]any)
+		for _, item := range items {
+			if h, ok := item.(map[string]any); ok {
+				maskSource(h["_source"])
+			}
+		}
+	}
+	if hits, ok := doc["hits"].(map[string]any); ok {
+		maskList(hits["hits"])
+	}
+	maskList(doc["docs"])
+
+	var fields []string
+	for _, rule := range rules {
+		if masked[rule.Field] > 0 {
+			fields = append(fields, rule.Field)
+		}
+	}
+	if len(fields) == 0 {
+		return body, nil
+	}
+	if m.logger != nil {
+		m.logger.Info("masked fields in query response",
+			zap.String("role", string(role)),
+			zap.String("index", index),
+			zap.Strings("fields", fields),
+		)
+	}
+	return json.Marshal(doc)
+}
+
+func (r MaskRule) appliesTo(index string) bool {
+	if len(r.Indices) == 0 {
+		return true
+	}
+	for _, name := range strings.Split(index, ",") {
+		for _, pattern := range r.Indices {
+			if ok, _ := path.Match(pattern, strings.TrimSpace(name)); ok {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+func (r MaskRule) replacement() any {
+	if r.Replacement == nil {
+		return DefaultMaskValue
+	}
+	return r.Replacement
+}
+
+// maskPath replaces the value at keys under v, traversing arrays, and returns how many
+// values were replaced. Missing and null values are left alone.
+func maskPath(v any, keys []string, replacement any) int {
+	switch node := v.(type) {
+	case []any:
+		n := 0
+		for _, item := range node {
+			n += maskPath(item, keys, replacement)
+		}
+		return n
+	case map[string]any:
+		child, ok := node[keys[0]]
+		if !ok || child == nil {
+			return 0
+		}
+		if len(keys) == 1 {
+			node[keys[0]] = replacement
+			return 1
+		}
+		return maskPath(child, keys[1:], replacement)
+	default:
+		return 0
+	}
+}
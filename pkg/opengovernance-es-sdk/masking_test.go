@@ -0,0 +1,106 @@
+package opengovernance_test
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/opengovern/og-util/pkg/api"
+	opengovernance "github.com/opengovern/og-util/pkg/opengovernance-es-sdk"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFieldMasker(t *testing.T) {
+	r := require.New(t)
+
+	masker, err := opengovernance.NewFieldMasker(opengovernance.FieldMaskerOptions{
+		Rules: []opengovernance.MaskRule{
+			{Field: "a", MinRole: "Admin"}, // Roles are case-insensitive
+			{Field: "description.AccountId", MinRole: api.EditorRole, Indices: []string{"aws_*"}},
+		},
+	})
+	r.NoError(err)
+
+	get := []byte(`{"_source":{"a":"secret","b":"public"}}`)
+	for _, ctx := range []context.Context{
+		opengovernance.WithRole(context.Background(), api.ViewerRole),
+		opengovernance.WithRole(context.Background(), api.EditorRole),
+		context.Background(), // No role is the least privileged
+	} {
+		body, err := masker.Mask(ctx, "inventory", get)
+		r.NoError(err)
+		r.JSONEq(`{"_source":{"a":"****","b":"public"}}`, string(body))
+	}
+	body, err := masker.Mask(opengovernance.WithRole(context.Background(), api.AdminRole), "inventory", get)
+	r.NoError(err)
+	r.JSONEq(string(get), string(body))
+
+	search := []byte(`{"hits":{"hits":[
+		{"_source":{"description":[{"AccountId":"123"},{"AccountId":null}]}},
+		{"_source":{"description":{"AccountId":"456"}}}
+	]}}`)
+	viewer := opengovernance.WithRole(context.Background(), api.ViewerRole)
+	body, err = masker.Mask(viewer, "aws_ec2_instance", search)
+	r.NoError(err)
+	r.JSONEq(`{"hits":{"hits":[
+		{"_source":{"description":[{"AccountId":"****"},{"AccountId":null}]}},
+		{"_source":{"description":{"AccountId":"****"}}}
+	]}}`, string(body))
+	body, err = masker.Mask(viewer, "azure_vm", search)
+	r.NoError(err)
+	r.JSONEq(string(search), string(body))
+
+	_, err = opengovernance.NewFieldMasker(opengovernance.FieldMaskerOptions{
+		Rules: []opengovernance.MaskRule{{Field: "a", MinRole: "owner"}},
+	})
+	r.ErrorContains(err, `unknown role "owner"`)
+	_, err = opengovernance.NewFieldMasker(opengovernance.FieldMaskerOptions{
+		Rules: []opengovernance.MaskRule{{Field: "a", MinRole: api.ViewerRole, Indices: []string{"["}}},
+	})
+	r.ErrorContains(err, "invalid index pattern")
+}
+
+func TestFieldMaskerMGet(t *testing.T) {
+	r := require.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		r.Equal("/aws_ec2_instance/_mget", req.URL.Path)
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprint(w, `{"docs":[
+			{"_index":"aws_ec2_instance","_id":"a","found":true,"_source":{"description":{"AccountId":"123"}}},
+			{"_index":"aws_ec2_instance","_id":"b","found":false}
+		]}`)
+	}))
+	defer server.Close()
+
+	username, password := "", ""
+	client, err := opengovernance.NewClient(opengovernance.ClientConfig{
+		Addresses: []string{server.URL},
+		Username:  &username,
+		Password:  &password,
+	})
+	r.NoError(err)
+	masker, err := opengovernance.NewFieldMasker(opengovernance.FieldMaskerOptions{
+		Rules: []opengovernance.MaskRule{{Field: "description.AccountId", MinRole: api.EditorRole}},
+	})
+	r.NoError(err)
+	client.AddResponseHook(masker.Hook())
+
+	type resource struct {
+		Description struct {
+			AccountId string `json:"AccountId"`
+		} `json:"description"`
+	}
+	var docs map[string]resource
+	missing, err := client.MGet(opengovernance.WithRole(context.Background(), api.ViewerRole), "aws_ec2_instance", []string{"a", "b"}, &docs)
+	r.NoError(err)
+	r.Equal([]string{"b"}, missing)
+	r.Equal("****", docs["a"].Description.AccountId)
+
+	docs = nil
+	_, err = client.MGet(opengovernance.WithRole(context.Background(), api.EditorRole), "aws_ec2_instance", []string{"a", "b"}, &docs)
+	r.NoError(err)
+	r.Equal("123", docs["a"].Description.AccountId)
+}
@@ -0,0 +1,251 @@
+package opengovernance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+	"sync"
+)
+
+const defaultMaxPatternLength = 256
+
+// ErrPatternRejected is returned for wildcard and regexp patterns outside the limits set
+// with SetPatternLimits.
+var ErrPatternRejected = errors.New("pattern rejected")
+
+// PatternLimits guard wildcard and regexp queries, whose cost grows with the pattern and
+// which scan every term of the field when they start with a wildcard.
+type PatternLimits struct {
+	MaxLength            int  // Longest pattern accepted (default 256)
+	AllowLeadingWildcard bool // Accept patterns starting with *, ? or, for regexps, .
+}
+
+var patternLimits = struct {
+	sync.RWMutex
+	limits PatternLimits
+}{limits: PatternLimits{MaxLength: defaultMaxPatternLength}}
+
+// SetPatternLimits sets the limits of wildcard and regexp filters. MaxLength <= 0 restores
+// the default.
+func SetPatternLimits(limits PatternLimits) {
+	if limits.MaxLength <= 0 {
+		limits.MaxLength = defaultMaxPatternLength
+	}
+	patternLimits.Lock()
+	defer patternLimits.Unlock()
+	patternLimits.limits = limits
+}
+
+// CheckWildcardPattern returns an ErrPatternRejected error if pattern is outside the
+// pattern limits, so that APIs can reject user input before searching.
+func CheckWildcardPattern(pattern string) error {
+	return checkPattern("wildcard", pattern, strings.HasPrefix(pattern, "*") || strings.HasPrefix(pattern, "?"))
+}
+
+// CheckRegexpPattern is CheckWildcardPattern for regexp patterns.
+func CheckRegexpPattern(pattern string) error {
+	return checkPattern("regexp", pattern, strings.HasPrefix(pattern, "."))
+}
+
+func checkPattern(kind, pattern string, leadingWildcard bool) error {
+	patternLimits.RLock()
+	limits := patternLimits.limits
+	patternLimits.RUnlock()
+	if len(pattern) > limits.MaxLength {
+		return fmt.Errorf("%w: %s pattern longer than %d characters", ErrPatternRejected, kind, limits.MaxLength)
+	}
+	if leadingWildcard && !limits.AllowLeadingWildcard {
+		return fmt.Errorf("%w: %s pattern %q starts with a wildcard", ErrPatternRejected, kind, pattern)
+	}
+	return nil
+}
+
+// ExistsFilter matches documents with a non-null value in field.
+type ExistsFilter struct {
+	field string
+}
+
+func NewExistsFilter(field string) BoolFilter {
+	return ExistsFilter{field: field}
+}
+
+func (t ExistsFilter) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]any{
+		"exists": map[string]string{
+			"field": t.field,
+		},
+	})
+}
+func (t ExistsFilter) IsBoolFilter() {}
+
+// PrefixFilter matches documents whose field starts with prefix.
+type PrefixFilter struct {
+	field           string
+	prefix          string
+	caseInsensitive bool
+}
+
+func NewPrefixFilter(field, prefix string, caseInsensitive bool) BoolFilter {
+	return PrefixFilter{
+		field:           field,
+		prefix:          prefix,
+		caseInsensitive: caseInsensitive,
+	}
+}
+
+func (t PrefixFilter) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]any{
+		"prefix": map[string]any{
+			t.field: map[string]any{
+				"value":            t.prefix,
+				"case_insensitive": t.caseInsensitive,
+			},
+		},
+	})
+}
+func (t PrefixFilter) IsBoolFilter() {}
+
+// WildcardFilter matches documents whose field matches pattern, where * matches any
+// sequence of characters, ? any single character and \ escapes either. Patterns outside
+// the limits set with SetPatternLimits fail to marshal.
+type WildcardFilter struct {
+	field           string
+	pattern         string
+	caseInsensitive bool
+}
+
+func NewWildcardFilter(field, pattern string, caseInsensitive bool) BoolFilter {
+	return WildcardFilter{
+		field:           field,
+		pattern:         pattern,
+		caseInsensitive: caseInsensitive,
+	}
+}
+
+func (t WildcardFilter) MarshalJSON() ([]byte, error) {
+	if err := CheckWildcardPattern(t.pattern); err != nil {
+		return nil, err
+	}
+	return json.Marshal(map[string]any{
+		"wildcard": map[string]any{
+			t.field: map[string]any{
+				"value":            t.pattern,
+				"case_insensitive": t.caseInsensitive,
+			},
+		},
+	})
+}
+func (t WildcardFilter) IsBoolFilter() {}
+
+// RegexpFilter matches documents whose field matches the Lucene regular expression
+// pattern, which is anchored at both ends. Patterns outside the limits set with
+// SetPatternLimits fail to marshal.
+type RegexpFilter struct {
+	field           string
+	pattern         string
+	caseInsensitive bool
+}
+
+func NewRegexpFilter(field, pattern string, caseInsensitive bool) BoolFilter {
+	return RegexpFilter{
+		field:           field,
+		pattern:         pattern,
+		caseInsensitive: caseInsensitive,
+	}
+}
+
+func (t RegexpFilter) MarshalJSON() ([]byte, error) {
+	if err := CheckRegexpPattern(t.pattern); err != nil {
+		return nil, err
+	}
+	return json.Marshal(map[string]any{
+		"regexp": map[string]any{
+			t.field: map[string]any{
+				"value":            t.pattern,
+				"case_insensitive": t.caseInsensitive,
+			},
+		},
+	})
+}
+func (t RegexpFilter) IsBoolFilter() {}
+
+// MatchPhraseFilter matches documents whose analyzed text field contains phrase, e.g. a
+// sequence of words in a description.
+type MatchPhraseFilter struct {
+	field  string
+	phrase string
+}
+
+func NewMatchPhraseFilter(field, phrase string) BoolFilter {
+	return MatchPhraseFilter{
+		field:  field,
+		phrase: phrase,
+	}
+}
+
+func (t MatchPhraseFilter) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]any{
+		"match_phrase": map[string]string{
+			t.field: t.phrase,
+		},
+	})
+}
+func (t MatchPhraseFilter) IsBoolFilter() {}
+
+// NewLikeFilter translates the SQL LIKE pattern, where % matches any sequence of
+// characters, _ any single character and \ escapes either, into a PrefixFilter if its
+// only wildcard is a trailing %, and a WildcardFilter otherwise.
+func NewLikeFilter(field, pattern string, caseInsensitive bool) BoolFilter {
+	var wildcard, literal strings.Builder
+	wildcards := 0
+	trailingPercent, escaped := false, false
+	for _, r := range pattern {
+		trailingPercent = false
+		if escaped {
+			escaped = false
+			writeWildcardLiteral(&wildcard, r)
+			literal.WriteRune(r)
+			continue
+		}
+		switch r {
+		case '\\':
+			escaped = true
+		case '%':
+			wildcard.WriteByte('*')
+			wildcards++
+			trailingPercent = true
+		case '_':
+			wildcard.WriteByte('?')
+			wildcards++
+		default:
+			writeWildcardLiteral(&wildcard, r)
+			literal.WriteRune(r)
+		}
+	}
+	if wildcards == 1 && trailingPercent {
+		return NewPrefixFilter(field, literal.String(), caseInsensitive)
+	}
+	return NewWildcardFilter(field, wildcard.String(), caseInsensitive)
+}
+
+// likeQualFilter translates a LIKE qual, or returns false if its pattern is outside the
+// pattern limits and is left to Steampipe to apply.
+func likeQualFilter(ctx context.Context, field, pattern string, caseInsensitive bool) (BoolFilter, bool) {
+	filter := NewLikeFilter(field, pattern, caseInsensitive)
+	if wildcard, ok := filter.(WildcardFilter); ok {
+		if err := CheckWildcardPattern(wildcard.pattern); err != nil {
+			LogWarn(ctx, fmt.Sprintf("BuildFilter: not filtering %s: %v", field, err))
+			return nil, false
+		}
+	}
+	return filter, true
+}
+
+func writeWildcardLiteral(b *strings.Builder, r rune) {
+	if r == '*' || r == '?' || r == '\\' {
+		b.WriteByte('\\')
+	}
+	b.WriteRune(r)
+}
@@ -0,0 +1,31 @@
+package opengovernance_test
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	opengovernance "github.com/opengovern/og-util/pkg/opengovernance-es-sdk"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPatternLimits(t *testing.T) {
+	require := require.New(t)
+	t.Cleanup(func() { opengovernance.SetPatternLimits(opengovernance.PatternLimits{}) })
+
+	b, err := json.Marshal(opengovernance.NewLikeFilter("name", "web-_1%", true))
+	require.NoError(err)
+	require.JSONEq(`{"wildcard": {"name": {"value": "web-?1*", "case_insensitive": true}}}`, string(b))
+
+	_, err = json.Marshal(opengovernance.NewWildcardFilter("name", "*web", false))
+	require.ErrorIs(err, opengovernance.ErrPatternRejected)
+	_, err = json.Marshal(opengovernance.NewRegexpFilter("name", ".*web", false))
+	require.ErrorIs(err, opengovernance.ErrPatternRejected)
+	require.ErrorIs(opengovernance.CheckWildcardPattern(strings.Repeat("a", 257)), opengovernance.ErrPatternRejected)
+
+	opengovernance.SetPatternLimits(opengovernance.PatternLimits{MaxLength: 8, AllowLeadingWildcard: true})
+	b, err = json.Marshal(opengovernance.NewRegexpFilter("name", ".*web", false))
+	require.NoError(err)
+	require.JSONEq(`{"regexp": {"name": {"value": ".*web", "case_insensitive": false}}}`, string(b))
+	require.ErrorIs(opengovernance.CheckRegexpPattern("web-[0-9]+"), opengovernance.ErrPatternRejected)
+}
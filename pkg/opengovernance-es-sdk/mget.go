@@ -0,0 +1,234 @@
+package opengovernance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+
+	"github.com/opensearch-project/opensearch-go/v2/opensearchapi"
+	"github.com/opensearch-project/opensearch-go/v2/opensearchutil"
+)
+
+const (
+	defaultMGetConcurrency = 8
+	defaultMGetBatchSize   = 1000
+)
+
+// MGetOptions configures MGetMulti.
+type MGetOptions struct {
+	Concurrency    int      // Maximum concurrent mget requests (default 8)
+	BatchSize      int      // Maximum ids per mget request (default 1000)
+	SourceIncludes []string // _source fields to fetch; all if empty
+}
+
+// MGetResult holds the merged documents of MGetMulti.
+type MGetResult[T any] struct {
+	Documents map[string]map[string]T // Index -> id -> decoded _source of found documents
+	Missing   map[string][]string     // Index -> ids that were not found, sorted
+}
+
+// Get returns the document with id in index, if it was found.
+func (r *MGetResult[T]) Get(index, id string) (T, bool) {
+	doc, ok := r.Documents[index][id]
+	return doc, ok
+}
+
+type mgetResponse struct {
+	Docs []struct {
+		Index  string          `json:"_index"`
+		ID     string          `json:"_id"`
+		Found  bool            `json:"found"`
+		Source json.RawMessage `json:"_source"`
+		Error  *ErrorInfo      `json:"error"`
+	} `json:"docs"`
+}
+
+type mgetBatch struct {
+	index string
+	ids   []string
+}
+
+// MGetMulti fetches documents by id from many indices, e.g. the resource-type indices a
+// compliance evaluation needs, running the mget requests concurrently and decoding each
+// _source into T. Requests are subject to the client's search limits and response hooks.
+// Missing indices yield missing documents unless strict index checking is enabled.
+//
+// Failures are collected per index: the returned result holds every document that could
+// be fetched and the error joins the failures, each naming its index.
+func MGetMulti[T any](ctx context.Context, c Client, ids map[string][]string, opts MGetOptions) (*MGetResult[T], error) {
+	if opts.Concurrency <= 0 {
+		opts.Concurrency = defaultMGetConcurrency
+	}
+	if opts.BatchSize <= 0 {
+		opts.BatchSize = defaultMGetBatchSize
+	}
+
+	var batches []mgetBatch
+	for index, indexIDs := range ids {
+		indexIDs = uniqueStrings(indexIDs)
+		for start := 0; start < len(indexIDs); start += opts.BatchSize {
+			batches = append(batches, mgetBatch{index: index, ids: indexIDs[start:min(start+opts.BatchSize, len(indexIDs))]})
+		}
+	}
+
+	result := &MGetResult[T]{
+		Documents: make(map[string]map[string]T),
+		Missing:   make(map[string][]string),
+	}
+	var (
+		mu   sync.Mutex
+		errs []error
+		wg   sync.WaitGroup
+	)
+	sem := make(chan struct{}, opts.Concurrency)
+	for _, batch := range batches {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+			wg.Wait()
+			return result, ctx.Err()
+		}
+		wg.Add(1)
+		go func(batch mgetBatch) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			docs, missing, err := mgetBatchDocuments[T](ctx, c, batch, opts.SourceIncludes)
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("mget index %s: %w", batch.index, err))
+				return
+			}
+			if len(docs) > 0 && result.Documents[batch.index] == nil {
+				result.Documents[batch.index] = make(map[string]T, len(docs))
+			}
+			for id, doc := range docs {
+				result.Documents[batch.index][id] = doc
+			}
+			if len(missing) > 0 {
+				result.Missing[batch.index] = append(result.Missing[batch.index], missing...)
+			}
+		}(batch)
+	}
+	wg.Wait()
+
+	for _, missing := range result.Missing {
+		sort.Strings(missing)
+	}
+	return result, errors.Join(errs...)
+}
+
+// MGet fetches the documents with ids from index with the _mget API, in batches of up to
+// 1000 ids, instead of one GetByID round trip per document. The _source of the found
+// documents is decoded into response as an object keyed by id, e.g. a *map[string]T.
+// The ids that were not found are returned sorted; a missing index is reported as all
+// of them missing unless strict index checking is enabled.
+func (c Client) MGet(ctx context.Context, index string, ids []string, response any) ([]string, error) {
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	result, err := MGetMulti[json.RawMessage](ctx, c, map[string][]string{index: ids}, MGetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	docs := result.Documents[index]
+	if docs == nil {
+		docs = map[string]json.RawMessage{}
+	}
+	b, err := json.Marshal(docs)
+	if err != nil {
+		return nil, fmt.Errorf("marshal documents: %w", err)
+	}
+	if err := json.Unmarshal(b, response); err != nil {
+		return nil, fmt.Errorf("unmarshal response: %w", err)
+	}
+	return result.Missing[index], nil
+}
+
+func mgetBatchDocuments[T any](ctx context.Context, c Client, batch mgetBatch, sourceIncludes []string) (_ map[string]T, _ []string, err error) {
+	release, err := c.limiter.acquire(ctx, batch.index)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer release()
+	ctx, done := requestTimeout(ctx, "search", c.searchTimeout)
+	defer func() { err = done(err) }()
+
+	opts := []func(*opensearchapi.MgetRequest){
+		c.es.Mget.WithContext(ctx),
+		c.es.Mget.WithIndex(batch.index),
+	}
+	if len(sourceIncludes) > 0 {
+		opts = append(opts, c.es.Mget.WithSourceIncludes(sourceIncludes...))
+	}
+
+	res, err := c.es.Mget(opensearchutil.NewJSONReader(map[string]any{"ids": batch.ids}), opts...)
+	defer CloseSafe(res)
+	if err != nil {
+		return nil, nil, err
+	} else if err := CheckError(res); err != nil {
+		if IsIndexNotFoundErr(err) {
+			if err := c.indexNotFound(batch.index, err); err != nil {
+				return nil, nil, err
+			}
+			return nil, batch.ids, nil
+		}
+		return nil, nil, err
+	}
+
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, nil, fmt.Errorf("read response: %w", err)
+	}
+	b, err = applyResponseHooks(ctx, c.responseHooks, batch.index, b)
+	if err != nil {
+		return nil, nil, err
+	}
+	var response mgetResponse
+	if err := json.Unmarshal(b, &response); err != nil {
+		return nil, nil, fmt.Errorf("unmarshal response: %w", err)
+	}
+
+	docs := make(map[string]T, len(response.Docs))
+	var missing []string
+	for _, doc := range response.Docs {
+		if doc.Error != nil && doc.Error.Type == "index_not_found_exception" {
+			// mget reports a missing index per document rather than for the request
+			if err := c.indexNotFound(batch.index, ErrorResponse{Info: *doc.Error}); err != nil {
+				return nil, nil, err
+			}
+			missing = append(missing, doc.ID)
+			continue
+		}
+		if doc.Error != nil {
+			return nil, nil, fmt.Errorf("document %s: %s: %s", doc.ID, doc.Error.Type, doc.Error.Reason)
+		}
+		if !doc.Found {
+			missing = append(missing, doc.ID)
+			continue
+		}
+		var source T
+		if err := json.Unmarshal(doc.Source, &source); err != nil {
+			return nil, nil, fmt.Errorf("unmarshal document %s: %w", doc.ID, err)
+		}
+		docs[doc.ID] = source
+	}
+	return docs, missing, nil
+}
+
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		if !seen[v] {
+			seen[v] = true
+			out = append(out, v)
+		}
+	}
+	return out
+}
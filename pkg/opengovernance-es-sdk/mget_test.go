@@ -0,0 +1,64 @@
+package opengovernance_test
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	opengovernance "github.com/opengovern/og-util/pkg/opengovernance-es-sdk"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMGet(t *testing.T) {
+	r := require.New(t)
+
+	stored := map[string]string{"a": `{"name":"alpha"}`, "c": `{"name":"gamma"}`}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		r.Equal("/inventory/_mget", req.URL.Path)
+		reader := io.Reader(req.Body)
+		if req.Header.Get("Content-Encoding") == "gzip" {
+			gz, err := gzip.NewReader(req.Body)
+			r.NoError(err)
+			reader = gz
+		}
+		var body struct {
+			IDs []string `json:"ids"`
+		}
+		r.NoError(json.NewDecoder(reader).Decode(&body))
+		var docs []string
+		for _, id := range body.IDs {
+			if source, ok := stored[id]; ok {
+				docs = append(docs, fmt.Sprintf(`{"_index":"inventory","_id":%q,"found":true,"_source":%s}`, id, source))
+			} else {
+				docs = append(docs, fmt.Sprintf(`{"_index":"inventory","_id":%q,"found":false}`, id))
+			}
+		}
+		w.Header().Set("Content-Type", "application/json")
+		fmt.Fprintf(w, `{"docs":[%s]}`, strings.Join(docs, ","))
+	}))
+	defer server.Close()
+
+	username, password := "", ""
+	client, err := opengovernance.NewClient(opengovernance.ClientConfig{
+		Addresses: []string{server.URL},
+		Username:  &username,
+		Password:  &password,
+	})
+	r.NoError(err)
+
+	var docs map[string]struct {
+		Name string `json:"name"`
+	}
+	missing, err := client.MGet(context.Background(), "inventory", []string{"d", "a", "b", "c", "a"}, &docs)
+	r.NoError(err)
+	r.Equal([]string{"b", "d"}, missing)
+	r.Len(docs, 2)
+	r.Equal("alpha", docs["a"].Name)
+	r.Equal("gamma", docs["c"].Name)
+}
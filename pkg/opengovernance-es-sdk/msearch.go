@@ -0,0 +1,126 @@
+package opengovernance
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/opensearch-project/opensearch-go/v2/opensearchapi"
+)
+
+// MSearchRequest is one search of a multi-search.
+type MSearchRequest struct {
+	Index    string
+	Query    any // Search body, e.g. a SearchRequest; marshalled to JSON
+	Response any // Decoded from the search's response, like Search's response argument
+}
+
+type msearchResponse struct {
+	Responses []json.RawMessage `json:"responses"`
+}
+
+// MSearch sends requests as one _msearch request, e.g. for per-resource-type counts that
+// would otherwise take dozens of serial searches, and decodes each search's response into
+// its Response. The request counts as one search against the client's global search
+// limit.
+//
+// Failures are reported per search: every search that succeeded is decoded and the
+// returned error joins the failures, each naming its position and index. Missing
+// indices leave Response untouched unless strict index checking is enabled.
+func (c Client) MSearch(ctx context.Context, requests []MSearchRequest) (err error) {
+	if len(requests) == 0 {
+		return nil
+	}
+	var body bytes.Buffer
+	indices := make([]string, 0, len(requests))
+	for i, r := range requests {
+		header := map[string]any{"index": r.Index}
+		if c.strictIndices {
+			header["allow_no_indices"] = false
+		}
+		headerJSON, err := json.Marshal(header)
+		if err != nil {
+			return fmt.Errorf("marshal search %d header: %w", i, err)
+		}
+		query, err := json.Marshal(r.Query)
+		if err != nil {
+			return fmt.Errorf("marshal search %d query: %w", i, err)
+		}
+		body.Write(headerJSON)
+		body.WriteByte('\n')
+		body.Write(query)
+		body.WriteByte('\n')
+		indices = append(indices, r.Index)
+	}
+	sort.Strings(indices)
+
+	// Per-index limits only apply to single-index searches; an msearch takes a global slot
+	release, err := c.limiter.acquire(ctx, strings.Join(uniqueStrings(indices), ","))
+	if err != nil {
+		return err
+	}
+	defer release()
+	ctx, done := requestTimeout(ctx, "search", c.searchTimeout)
+	defer func() { err = done(err) }()
+
+	opts := []func(*opensearchapi.MsearchRequest){
+		c.es.Msearch.WithContext(ctx),
+	}
+	res, err := c.es.Msearch(&body, opts...)
+	defer CloseSafe(res)
+	if err != nil {
+		return err
+	} else if err := CheckError(res); err != nil {
+		return err
+	}
+
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+	var response msearchResponse
+	if err := json.Unmarshal(b, &response); err != nil {
+		return fmt.Errorf("unmarshal response: %w", err)
+	}
+	if len(response.Responses) != len(requests) {
+		return fmt.Errorf("msearch returned %d responses for %d searches", len(response.Responses), len(requests))
+	}
+
+	var errs []error
+	for i, r := range requests {
+		if err := c.decodeMSearchResponse(ctx, r, response.Responses[i]); err != nil {
+			errs = append(errs, fmt.Errorf("search %d on %s: %w", i, r.Index, err))
+		}
+	}
+	return errors.Join(errs...)
+}
+
+func (c Client) decodeMSearchResponse(ctx context.Context, r MSearchRequest, raw json.RawMessage) error {
+	var item struct {
+		Status int        `json:"status"`
+		Error  *ErrorInfo `json:"error"`
+	}
+	if err := json.Unmarshal(raw, &item); err != nil {
+		return fmt.Errorf("unmarshal response: %w", err)
+	}
+	if item.Error != nil {
+		err := classifyError(item.Status, nil, ErrorResponse{Info: *item.Error})
+		if IsIndexNotFoundErr(err) {
+			return c.indexNotFound(r.Index, err)
+		}
+		return err
+	}
+	b, err := applyResponseHooks(ctx, c.responseHooks, r.Index, raw)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(b, r.Response); err != nil {
+		return fmt.Errorf("unmarshal response: %w", err)
+	}
+	return nil
+}
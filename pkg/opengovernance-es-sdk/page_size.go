@@ -0,0 +1,106 @@
+package opengovernance
+
+import "sync"
+
+const (
+	defaultPageSize    = 10000
+	defaultMaxPageSize = 10000 // index.max_result_window default
+	minShrunkPageSize  = 10
+	maxPageShrinks     = 5
+)
+
+var pageSizeConfig = struct {
+	sync.RWMutex
+	defaultSize int64
+	maxSize     int64
+}{defaultSize: defaultPageSize, maxSize: defaultMaxPageSize}
+
+// SetPageSizeDefaults sets the page size of new paginators and the maximum page size any
+// paginator may use. Values <= 0 restore the defaults of 10000. The default page size
+// is capped at the maximum.
+func SetPageSizeDefaults(defaultSize, maxSize int64) {
+	if maxSize <= 0 {
+		maxSize = defaultMaxPageSize
+	}
+	if defaultSize <= 0 {
+		defaultSize = defaultPageSize
+	}
+	pageSizeConfig.Lock()
+	defer pageSizeConfig.Unlock()
+	pageSizeConfig.maxSize = maxSize
+	pageSizeConfig.defaultSize = min(defaultSize, maxSize)
+}
+
+// DefaultPageSize returns the page size of new paginators.
+func DefaultPageSize() int64 {
+	pageSizeConfig.RLock()
+	defer pageSizeConfig.RUnlock()
+	return pageSizeConfig.defaultSize
+}
+
+// MaxPageSize returns the maximum page size of paginators.
+func MaxPageSize() int64 {
+	pageSizeConfig.RLock()
+	defer pageSizeConfig.RUnlock()
+	return pageSizeConfig.maxSize
+}
+
+// WithPageSize sets the number of documents fetched per page, capped at MaxPageSize and
+// at the paginator's limit.
+func (p *BaseESPaginator) WithPageSize(size int64) *BaseESPaginator {
+	if size <= 0 {
+		return p
+	}
+	p.pageSize = min(size, MaxPageSize())
+	if p.limit > 0 && p.limit < p.pageSize {
+		p.pageSize = p.limit
+	}
+	return p
+}
+
+// WithMaxBytesPerPage bounds the response size of a page, to keep wide documents within
+// coordinating node limits. After each page the page size is lowered to fit maxBytes at
+// the average document size seen so far; it is never raised again. Zero disables it.
+func (p *BaseESPaginator) WithMaxBytesPerPage(maxBytes int64) *BaseESPaginator {
+	p.maxBytesPerPage = maxBytes
+	return p
+}
+
+// WithShrinkOnBackpressure controls whether a search rejected with HTTP 429, a tripped
+// circuit breaker or another backpressure error is retried with half the page size, after the usual cool-down.
+// It is enabled by default.
+func (p *BaseESPaginator) WithShrinkOnBackpressure(enabled bool) *BaseESPaginator {
+	p.noShrink = !enabled
+	return p
+}
+
+// PageSize returns the current page size.
+func (p *BaseESPaginator) PageSize() int64 {
+	return p.pageSize
+}
+
+// shrinkPage halves the page size after a search rejected by err and reports whether the
+// search should be retried.
+func (p *BaseESPaginator) shrinkPage(err error, attempt int) bool {
+	if p.noShrink || p.scrollID != "" || attempt >= maxPageShrinks || p.pageSize <= minShrunkPageSize {
+		return false
+	}
+	if !IsBackpressureErr(err) {
+		return false
+	}
+	p.pageSize = max(p.pageSize/2, minShrunkPageSize)
+	return true
+}
+
+// fitPageToBytes lowers the page size so the next page stays within maxBytesPerPage.
+// The page size of an open scroll is fixed.
+func (p *BaseESPaginator) fitPageToBytes(numHits int64) {
+	if p.maxBytesPerPage <= 0 || p.scrollID != "" || p.lastPageBytes <= 0 || numHits <= 0 {
+		return
+	}
+	perDoc := max(p.lastPageBytes/numHits, 1)
+	fit := max(p.maxBytesPerPage/perDoc, 1)
+	if fit < p.pageSize {
+		p.pageSize = fit
+	}
+}
@@ -15,8 +15,8 @@ import (
 	"github.com/opensearch-project/opensearch-go/v2"
 	"github.com/opensearch-project/opensearch-go/v2/opensearchapi"
 	"github.com/opensearch-project/opensearch-go/v2/opensearchutil"
-	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
-	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/context_key"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 type BaseESPaginator struct {
@@ -24,6 +24,7 @@ type BaseESPaginator struct {
 
 	index    string         // Query index
 	query    map[string]any // Query filters
+	filters  []BoolFilter   // Filters the query was built from, for error messages
 	pageSize int64          // Query page size
 	pitID    string         // Query point in time id (Only set if max is greater than size)
 
@@ -34,21 +35,33 @@ type BaseESPaginator struct {
 
 	searchAfter []any
 	done        bool
+
+	sourceIncludes []string       // _source fields to fetch; all if empty
+	responseHooks  []ResponseHook // Run on each page before it is decoded
+
+	maxBytesPerPage int64 // Target response size of a page; unlimited if zero
+	lastPageBytes   int64 // Response size of the last page
+	noShrink        bool  // Don't shrink pages on backpressure
+
+	mode           PaginationMode // PaginationModePIT if empty
+	scrollFallback bool           // PaginationModeAuto switched to scroll
+	scrollID       string         // Current scroll context, in scroll mode
+	restoredPit    bool           // The PIT was restored by RestoreCursor and may have expired
+
+	logger *zap.Logger // See WithLogger
+
+	slice     *SearchSlice // Set on the paginators returned by Slices
+	sharedPit bool         // The PIT belongs to the paginator this one was sliced from
+
+	pitKeepAlive  time.Duration        // keep_alive requested for the PIT or scroll; 1m if zero
+	searchTimeout time.Duration        // See WithSearchTimeout
+	pitTimeout    time.Duration        // See WithPITTimeout
+	keeper        *pitKeeper           // nil unless StartKeepAlive was called
+	autoKeepAlive *PITKeepAliveOptions // See WithAutoKeepAlive
 }
 
 func NewPaginatorWithSort(client *opensearch.Client, index string, filters []BoolFilter, limit *int64, sort []map[string]any) (*BaseESPaginator, error) {
-	var query map[string]any
-	if len(filters) > 0 {
-		query = map[string]any{
-			"bool": map[string]any{
-				"filter": filters,
-			},
-		}
-	} else {
-		query = map[string]any{
-			"match_all": map[string]any{},
-		}
-	}
+	query := filterQuery(filters)
 
 	// We need a tiebreaker for the sort to work properly, so we add _id if it's not present
 	foundId := false
@@ -81,7 +94,8 @@ func NewPaginatorWithSort(client *opensearch.Client, index string, filters []Boo
 		client:   client,
 		index:    index,
 		query:    query,
-		pageSize: 10000,
+		filters:  filters,
+		pageSize: DefaultPageSize(),
 		limit:    max,
 		sort:     sort,
 		queried:  0,
@@ -96,52 +110,63 @@ func (p *BaseESPaginator) Done() bool {
 	return p.done
 }
 
+// UpdatePageSize sets the page size, capped at the maximum page size. See WithPageSize.
 func (p *BaseESPaginator) UpdatePageSize(i int64) {
-	p.pageSize = i
+	p.WithPageSize(i)
 }
 
+// Deallocate deletes the paginator's point in time or scroll context, if any. PIT
+// failures are returned as a *PITCleanupError. It is a no-op if the keep-alive refresher
+// already deleted the PIT.
 func (p *BaseESPaginator) Deallocate(ctx context.Context) error {
-	if p.pitID != "" {
-		pitRaw, _, err := p.client.PointInTime.Delete(
-			p.client.PointInTime.Delete.WithPitID(p.pitID),
-		)
+	if err := p.clearScroll(ctx); err != nil {
+		return err
+	}
+	if p.pitID != "" && !p.sharedPit {
+		err := p.keeper.deletePit(p.pitID, func(pitID string) error {
+			deleteCtx, done := requestTimeout(ctx, "pit", p.pitTimeout)
+			return done(deletePit(deleteCtx, p.client, p.logger, pitID))
+		})
 		if err != nil {
-			LogWarn(ctx, fmt.Sprintf("Deallocate.Err err=%v pitRaw=%v", err, pitRaw))
-			return err
-		} else if errIf := CheckErrorWithContext(pitRaw, ctx); errIf != nil {
-			LogWarn(ctx, fmt.Sprintf("Deallocate.CheckErr err=%v errIf=%v pitRaw=%s", err, errIf, pitRaw.String()))
+			return &PITCleanupError{PitID: p.pitID, Err: err}
+		}
+		p.setPitID("")
+	}
+	return nil
+}
 
-			if pitRaw.StatusCode != http.StatusMethodNotAllowed {
-				return errIf
-			}
+func deletePit(ctx context.Context, client *opensearch.Client, logger *zap.Logger, pitID string) error {
+	pitRaw, _, err := client.PointInTime.Delete(
+		client.PointInTime.Delete.WithPitID(pitID),
+		client.PointInTime.Delete.WithContext(ctx),
+	)
+	if err != nil {
+		logAt(ctx, logger, zapcore.WarnLevel, "delete point in time failed", zap.Error(err))
+		return err
+	} else if errIf := CheckErrorWithContext(pitRaw, ctx); errIf != nil {
+		logAt(ctx, logger, zapcore.WarnLevel, "delete point in time failed", zap.Error(errIf), zap.Int("status", pitRaw.StatusCode))
 
-			// try elasticsearch api instead
-			req := esapi.ClosePointInTimeRequest{
-				Body: strings.NewReader(fmt.Sprintf(`{"id": "%s"}`, p.pitID)),
+		if pitRaw.StatusCode != http.StatusMethodNotAllowed {
+			return errIf
+		}
+
+		// try elasticsearch api instead
+		req := esapi.ClosePointInTimeRequest{
+			Body: strings.NewReader(fmt.Sprintf(`{"id": "%s"}`, pitID)),
+		}
+		res, err2 := req.Do(ctx, client.Transport)
+		defer ESCloseSafe(res)
+		if err2 != nil {
+			if errIf != nil {
+				return errIf
 			}
-			res, err2 := req.Do(ctx, p.client.Transport)
-			defer ESCloseSafe(res)
-			if err2 != nil {
-				if errIf != nil {
-					return errIf
-				}
-				return err
-			} else if err2 := ESCheckError(res); err2 != nil {
-				if errIf != nil {
-					return errIf
-				}
-				return err
+			return err
+		} else if err2 := ESCheckError(res); err2 != nil {
+			if errIf != nil {
+				return errIf
 			}
+			return err
 		}
-		//
-		//if err != nil {
-		//	LogWarn(ctx, fmt.Sprintf("failed to delete PIT %v", err))
-		//	return err
-		//} else if errIf := CheckError(pitRaw); errIf != nil {
-		//	LogWarn(ctx, fmt.Sprintf("failed to delete PIT %v", errIf))
-		//	return errIf
-		//}
-		p.pitID = ""
 	}
 	return nil
 }
@@ -155,24 +180,54 @@ func (p *BaseESPaginator) SearchWithLog(ctx context.Context, response any, doLog
 	if p.done {
 		return errors.New("no more page to query")
 	}
+	for attempt := 0; ; attempt++ {
+		err := p.searchPage(ctx, response, doLog)
+		if err != nil && p.reopenExpiredPit(ctx, err) {
+			continue
+		}
+		if err == nil || !p.shrinkPage(err, attempt) {
+			return err
+		}
+		p.log(ctx, zapcore.WarnLevel, "search was rejected, retrying with a smaller page",
+			zap.Int64("page_size", p.pageSize), zap.Error(err))
+		if waitErr := WaitBackpressure(ctx, err, attempt); waitErr != nil {
+			return err
+		}
+	}
+}
 
+func (p *BaseESPaginator) searchPage(ctx context.Context, response any, doLog bool) (err error) {
+	if p.scrolling() {
+		return p.searchScroll(ctx, response)
+	}
 	if err := p.CreatePit(ctx); err != nil {
 		if IsIndexNotFoundErr(err) {
 			return nil
 		}
+		if p.mode == PaginationModeAuto {
+			p.log(ctx, zapcore.WarnLevel, "point in time unavailable, falling back to scroll", zap.Error(err))
+			p.scrollFallback = true
+			return p.searchScroll(ctx, response)
+		}
 		return err
 	}
+	p.startAutoKeepAlive(ctx)
+	ctx, done := requestTimeout(ctx, "search", p.searchTimeout)
+	defer func() { err = done(err) }()
 
+	size := p.nextPageSize()
 	sa := SearchRequest{
-		Size:  &p.pageSize,
-		Query: p.query,
-		Sort:  p.sort,
+		Size:   &size,
+		Query:  p.query,
+		Sort:   p.sort,
+		Source: p.sourceIncludes,
+		Slice:  p.slice,
 	}
 
 	if p.limit > p.pageSize && p.pitID != "" {
 		sa.PIT = &PointInTime{
 			ID:        p.pitID,
-			KeepAlive: "1m",
+			KeepAlive: formatKeepAlive(p.keepAlive()),
 		}
 	}
 
@@ -191,7 +246,7 @@ func (p *BaseESPaginator) SearchWithLog(ctx context.Context, response any, doLog
 
 	if doLog {
 		m, _ := json.Marshal(sa)
-		LogWarn(ctx, fmt.Sprintf("SearchWithLog: %s", string(m)))
+		p.log(ctx, zapcore.DebugLevel, "SearchWithLog", zap.String("query", string(m)))
 	}
 
 	res, err := p.client.Search(opts...)
@@ -202,14 +257,10 @@ func (p *BaseESPaginator) SearchWithLog(ctx context.Context, response any, doLog
 			b, _ = io.ReadAll(res.Body)
 		}
 		if doLog {
-			if ctx.Value(context_key.Logger) == nil {
-				fmt.Println(fmt.Sprintf("failure while querying es: %v\n%s\n", err, string(b)))
-			} else {
-				plugin.Logger(ctx).Trace(fmt.Sprintf("failure while querying es: %v\n%s\n", err, string(b)))
-			}
+			p.log(ctx, zapcore.DebugLevel, "failure while querying es", zap.Error(err), zap.String("response", string(b)))
 		}
 
-		return err
+		return p.queryError(err)
 	} else if err := CheckError(res); err != nil {
 		if IsIndexNotFoundErr(err) {
 			return nil
@@ -219,34 +270,28 @@ func (p *BaseESPaginator) SearchWithLog(ctx context.Context, response any, doLog
 			b, _ = io.ReadAll(res.Body)
 		}
 		if doLog {
-			if ctx.Value(context_key.Logger) == nil {
-				fmt.Println(fmt.Sprintf("failure while querying es: %v\n%s\n", err, string(b)))
-			} else {
-				plugin.Logger(ctx).Trace(fmt.Sprintf("failure while querying es: %v\n%s\n", err, string(b)))
-			}
+			p.log(ctx, zapcore.DebugLevel, "failure while querying es", zap.Error(err), zap.String("response", string(b)))
 		}
-		return err
+		return p.queryError(err)
 	}
 
 	b, err := io.ReadAll(res.Body)
 	if err != nil {
 		if doLog {
-			if ctx.Value(context_key.Logger) == nil {
-				fmt.Println(fmt.Sprintf("read response: %v", err))
-			} else {
-				plugin.Logger(ctx).Warn(fmt.Sprintf("read response: %v", err))
-			}
+			p.log(ctx, zapcore.WarnLevel, "read response", zap.Error(err))
 		}
 		return fmt.Errorf("read response: %w", err)
 	}
+	p.lastPageBytes = int64(len(b))
+
+	b, err = applyResponseHooks(ctx, p.responseHooks, p.index, b)
+	if err != nil {
+		return err
+	}
 
 	if err := json.Unmarshal(b, response); err != nil {
 		if doLog {
-			if ctx.Value(context_key.Logger) == nil {
-				fmt.Println(fmt.Sprintf("unmarshal response: %v", err))
-			} else {
-				plugin.Logger(ctx).Warn(fmt.Sprintf("unmarshal response: %v", err))
-			}
+			p.log(ctx, zapcore.WarnLevel, "unmarshal response", zap.Error(err))
 		}
 		return fmt.Errorf("unmarshal response: %w", err)
 	}
@@ -254,6 +299,11 @@ func (p *BaseESPaginator) SearchWithLog(ctx context.Context, response any, doLog
 	return nil
 }
 
+// queryError annotates err with the paginator's filters rendered by FilterSQL.
+func (p *BaseESPaginator) queryError(err error) error {
+	return &QueryError{Query: FilterSQL(p.index, p.filters), Err: err}
+}
+
 func (p *BaseESPaginator) CreatePit(ctx context.Context) (err error) {
 	return p.CreatePitWithRetry(ctx, 0)
 }
@@ -265,6 +315,12 @@ func (p *BaseESPaginator) CreatePitWithRetry(ctx context.Context, retry int) (er
 	} else if p.pitID != "" {
 		return nil
 	}
+	if retry == 0 {
+		// The timeout covers the retries on backpressure
+		var done func(error) error
+		ctx, done = requestTimeout(ctx, "pit", p.pitTimeout)
+		defer func() { err = done(err) }()
+	}
 
 	defer func() {
 		if err == nil {
@@ -272,7 +328,7 @@ func (p *BaseESPaginator) CreatePitWithRetry(ctx context.Context, retry int) (er
 		}
 
 		// check if the index exists
-		res, resErr := p.client.Indices.Exists([]string{p.index})
+		res, resErr := p.client.Indices.Exists([]string{p.index}, p.client.Indices.Exists.WithContext(ctx))
 		defer CloseSafe(res)
 		if resErr != nil {
 			return
@@ -285,25 +341,27 @@ func (p *BaseESPaginator) CreatePitWithRetry(ctx context.Context, retry int) (er
 
 	pitRaw, pitRes, err := p.client.PointInTime.Create(
 		p.client.PointInTime.Create.WithIndex(p.index),
-		p.client.PointInTime.Create.WithKeepAlive(1*time.Minute),
+		p.client.PointInTime.Create.WithKeepAlive(p.keepAlive()),
 		p.client.PointInTime.Create.WithContext(ctx),
 	)
 
 	defer CloseSafe(pitRaw)
 	if err != nil && !strings.Contains(err.Error(), "illegal_argument_exception") {
-		LogWarn(ctx, fmt.Sprintf("PointInTime.Err err=%v pitRaw=%v", err, pitRaw))
+		p.log(ctx, zapcore.WarnLevel, "create point in time failed", zap.Error(err))
 		return err
 	} else if errIf := CheckErrorWithContext(pitRaw, ctx); errIf != nil || (err != nil && strings.Contains(err.Error(), "illegal_argument_exception")) {
-		LogWarn(ctx, fmt.Sprintf("PointInTime.CheckErr err=%v errIf=%v pitRaw=%s", err, errIf, pitRaw.String()))
-		if pitRaw.StatusCode == http.StatusTooManyRequests && retry < 10 {
-			time.Sleep(time.Duration(retry+1) * time.Second)
+		p.log(ctx, zapcore.WarnLevel, "create point in time failed", zap.NamedError("transport_error", err), zap.Error(errIf), zap.Int("status", pitRaw.StatusCode))
+		if (pitRaw.StatusCode == http.StatusTooManyRequests || IsBackpressureErr(errIf)) && retry < 10 {
+			if waitErr := WaitBackpressure(ctx, errIf, retry); waitErr != nil {
+				return waitErr
+			}
 			return p.CreatePitWithRetry(ctx, retry+1)
 		}
 
 		// try elasticsearch api instead
 		req := esapi.OpenPointInTimeRequest{
 			Index:     []string{p.index},
-			KeepAlive: "1m",
+			KeepAlive: formatKeepAlive(p.keepAlive()),
 		}
 		res, err2 := req.Do(ctx, p.client.Transport)
 		defer ESCloseSafe(res)
@@ -329,18 +387,18 @@ func (p *BaseESPaginator) CreatePitWithRetry(ctx context.Context, retry int) (er
 			if err2 = json.Unmarshal(data, &pit); err2 != nil {
 				return fmt.Errorf("unmarshal response: %w", err2)
 			}
-			p.pitID = pit.ID
+			p.setPitID(pit.ID)
 			return nil
 		}
 	}
 
-	p.pitID = pitRes.PitID
+	p.setPitID(pitRes.PitID)
 	return nil
 }
 
 func (p *BaseESPaginator) UpdateState(numHits int64, searchAfter []any, pitID string) {
 	p.queried += numHits
-	if p.queried > p.limit {
+	if p.queried >= p.limit {
 		// Have found enough documents
 		p.done = true
 	} else if numHits == 0 || numHits < p.pageSize {
@@ -349,7 +407,11 @@ func (p *BaseESPaginator) UpdateState(numHits int64, searchAfter []any, pitID st
 	}
 
 	if numHits > 0 {
+		p.restoredPit = false
 		p.searchAfter = searchAfter
-		p.pitID = pitID
+		p.setPitID(pitID)
+		p.fitPageToBytes(numHits)
 	}
+	p.clearScrollWhenDone()
+	p.releasePitWhenDone()
 }
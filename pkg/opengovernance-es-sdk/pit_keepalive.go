@@ -0,0 +1,271 @@
+package opengovernance
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/opensearch-project/opensearch-go/v2"
+	"github.com/opensearch-project/opensearch-go/v2/opensearchutil"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	defaultPitKeepAlive     = time.Minute
+	defaultPitDeleteTimeout = 30 * time.Second
+)
+
+// PITCleanupError is returned when a paginator's point in time could not be deleted.
+// The PIT stays open on the cluster until its keep-alive expires.
+type PITCleanupError struct {
+	PitID string
+	Err   error
+}
+
+func (e *PITCleanupError) Error() string {
+	return fmt.Sprintf("delete point in time: %v", e.Err)
+}
+
+func (e *PITCleanupError) Unwrap() error {
+	return e.Err
+}
+
+// PITKeepAliveOptions configures BaseESPaginator.StartKeepAlive.
+type PITKeepAliveOptions struct {
+	KeepAlive     time.Duration // keep_alive requested for the PIT (default 1m)
+	Interval      time.Duration // Time between refreshes (default KeepAlive/2)
+	DeleteTimeout time.Duration // Timeout of the delete issued on cancellation (default 30s)
+}
+
+// pitKeeper extends the keep-alive of a paginator's PIT from a background goroutine.
+type pitKeeper struct {
+	client        *opensearch.Client
+	logger        *zap.Logger
+	keepAlive     time.Duration
+	deleteTimeout time.Duration
+
+	mu      sync.Mutex
+	pitID   string
+	deleted bool
+
+	stop chan struct{}
+	done chan struct{}
+}
+
+// StartKeepAlive starts a goroutine that extends the paginator's point in time every
+// Interval while the consumer processes pages slowly, so long exports don't fail on an
+// expired PIT. When ctx is cancelled the PIT is deleted right away, even if the caller
+// never reaches Deallocate. Call StopKeepAlive, or Close, when done with the paginator.
+func (p *BaseESPaginator) StartKeepAlive(ctx context.Context, opts PITKeepAliveOptions) {
+	if p.keeper != nil {
+		return
+	}
+	if opts.KeepAlive <= 0 {
+		opts.KeepAlive = defaultPitKeepAlive
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = opts.KeepAlive / 2
+	}
+	if opts.DeleteTimeout <= 0 {
+		opts.DeleteTimeout = defaultPitDeleteTimeout
+	}
+	p.pitKeepAlive = opts.KeepAlive
+	p.keeper = &pitKeeper{
+		client:        p.client,
+		logger:        p.logger,
+		keepAlive:     opts.KeepAlive,
+		deleteTimeout: opts.DeleteTimeout,
+		pitID:         p.pitID,
+		stop:          make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+	go p.keeper.run(ctx, opts.Interval)
+}
+
+// WithAutoKeepAlive makes the paginator manage its point in time's lifecycle: keep-alive
+// refreshes start with the first search, using that search's context as in
+// StartKeepAlive, and the PIT is deleted as soon as the last page was read. Deallocate
+// is still needed if the scan is abandoned early.
+func (p *BaseESPaginator) WithAutoKeepAlive(opts PITKeepAliveOptions) *BaseESPaginator {
+	p.autoKeepAlive = &opts
+	if opts.KeepAlive > 0 {
+		p.pitKeepAlive = opts.KeepAlive
+	}
+	return p
+}
+
+func (p *BaseESPaginator) startAutoKeepAlive(ctx context.Context) {
+	if p.autoKeepAlive == nil || p.keeper != nil || p.pitID == "" {
+		return
+	}
+	p.StartKeepAlive(ctx, *p.autoKeepAlive)
+}
+
+// releasePitWhenDone deletes an automatically managed PIT once the last page was read,
+// so it doesn't linger until its keep-alive expires.
+func (p *BaseESPaginator) releasePitWhenDone() {
+	if !p.done || p.autoKeepAlive == nil || p.pitID == "" {
+		return
+	}
+	timeout := p.autoKeepAlive.DeleteTimeout
+	if timeout <= 0 {
+		timeout = defaultPitDeleteTimeout
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := p.Close(ctx); err != nil {
+		p.log(ctx, zapcore.WarnLevel, "delete point in time failed", zap.Error(err))
+	}
+}
+
+// StopKeepAlive stops the keep-alive goroutine and waits for it to exit, after which
+// StartKeepAlive may start a new one. The PIT is not deleted; see Close.
+func (p *BaseESPaginator) StopKeepAlive() {
+	if p.keeper == nil {
+		return
+	}
+	p.keeper.mu.Lock()
+	select {
+	case <-p.keeper.stop:
+	default:
+		close(p.keeper.stop)
+	}
+	p.keeper.mu.Unlock()
+	<-p.keeper.done
+	if p.keeper.released() {
+		p.pitID = "" // Deleted on cancellation, nothing left for Deallocate
+	}
+	p.keeper = nil
+}
+
+// Close stops the keep-alive goroutine and deletes the PIT. Deletion failures are
+// returned as a *PITCleanupError.
+func (p *BaseESPaginator) Close(ctx context.Context) error {
+	p.StopKeepAlive()
+	return p.Deallocate(ctx)
+}
+
+func (p *BaseESPaginator) keepAlive() time.Duration {
+	if p.pitKeepAlive <= 0 {
+		return defaultPitKeepAlive
+	}
+	return p.pitKeepAlive
+}
+
+func (p *BaseESPaginator) setPitID(pitID string) {
+	p.pitID = pitID
+	p.keeper.setPitID(pitID)
+}
+
+// formatKeepAlive formats d as an ES time unit, rounded up to whole seconds.
+func formatKeepAlive(d time.Duration) string {
+	return fmt.Sprintf("%ds", int64((d+time.Second-1)/time.Second))
+}
+
+func (k *pitKeeper) setPitID(pitID string) {
+	if k == nil {
+		return
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if pitID != k.pitID {
+		k.deleted = false
+	}
+	k.pitID = pitID
+}
+
+// deletePit deletes pitID with del unless the keeper already released it. The keeper's
+// lock is held across the check and the delete so that Deallocate and a release on
+// cancellation never both delete the PIT. A nil keeper just deletes.
+func (k *pitKeeper) deletePit(pitID string, del func(pitID string) error) error {
+	if k == nil {
+		return del(pitID)
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	if k.deleted && k.pitID == pitID {
+		return nil
+	}
+	if err := del(pitID); err != nil {
+		return err
+	}
+	if k.pitID == pitID {
+		k.deleted = true
+	}
+	return nil
+}
+
+// released reports whether the keeper deleted the PIT after its context was cancelled.
+func (k *pitKeeper) released() bool {
+	if k == nil {
+		return false
+	}
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return k.deleted
+}
+
+func (k *pitKeeper) currentPitID() string {
+	k.mu.Lock()
+	defer k.mu.Unlock()
+	return k.pitID
+}
+
+func (k *pitKeeper) run(ctx context.Context, interval time.Duration) {
+	defer close(k.done)
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-k.stop:
+			return
+		case <-ctx.Done():
+			k.release(ctx)
+			return
+		case <-ticker.C:
+			if pitID := k.currentPitID(); pitID != "" {
+				if err := k.refresh(ctx, pitID); err != nil && ctx.Err() == nil {
+					logAt(ctx, k.logger, zapcore.WarnLevel, "point in time keep-alive refresh failed", zap.Error(err))
+				}
+			}
+		}
+	}
+}
+
+// refresh extends the PIT's keep-alive with an empty search against it.
+func (k *pitKeeper) refresh(ctx context.Context, pitID string) error {
+	size := int64(0)
+	req := SearchRequest{
+		Size: &size,
+		PIT:  &PointInTime{ID: pitID, KeepAlive: formatKeepAlive(k.keepAlive)},
+	}
+	res, err := k.client.Search(
+		k.client.Search.WithContext(ctx),
+		k.client.Search.WithBody(opensearchutil.NewJSONReader(req)),
+		k.client.Search.WithTrackTotalHits(false),
+	)
+	defer CloseSafe(res)
+	if err != nil {
+		return err
+	}
+	return CheckError(res)
+}
+
+// release deletes the PIT after ctx is cancelled, using a fresh context bounded by the
+// delete timeout.
+func (k *pitKeeper) release(ctx context.Context) {
+	pitID := k.currentPitID()
+	if pitID == "" {
+		return
+	}
+	deleteCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), k.deleteTimeout)
+	defer cancel()
+	err := k.deletePit(pitID, func(pitID string) error {
+		return deletePit(deleteCtx, k.client, k.logger, pitID)
+	})
+	if err != nil {
+		logAt(ctx, k.logger, zapcore.WarnLevel, "delete point in time after cancellation failed", zap.Error(err))
+	}
+}
@@ -0,0 +1,172 @@
+package opengovernance_test
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	opengovernance "github.com/opengovern/og-util/pkg/opengovernance-es-sdk"
+	"github.com/stretchr/testify/require"
+)
+
+// pitServer hands out a new PIT per create and answers deletes of unknown or already
+// deleted PITs with 404, like the cluster, so double deletes surface as errors.
+type pitServer struct {
+	mu        sync.Mutex
+	created   int
+	open      map[string]bool
+	deletes   map[string]int
+	refreshes atomic.Int32
+	keepAlive atomic.Value
+}
+
+func newPitServer(t *testing.T) (*pitServer, *httptest.Server) {
+	s := &pitServer{open: make(map[string]bool), deletes: make(map[string]int)}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		reader := io.Reader(req.Body)
+		if req.Header.Get("Content-Encoding") == "gzip" {
+			gz, err := gzip.NewReader(req.Body)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			reader = gz
+		}
+		w.Header().Set("Content-Type", "application/json")
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		switch {
+		case strings.HasSuffix(req.URL.Path, "/_search/point_in_time") && req.Method == http.MethodPost:
+			s.created++
+			pitID := fmt.Sprintf("pit-%d", s.created)
+			s.open[pitID] = true
+			fmt.Fprintf(w, `{"pit_id":%q}`, pitID)
+		case strings.HasSuffix(req.URL.Path, "/_search/point_in_time") && req.Method == http.MethodDelete:
+			var body struct {
+				PitID []string `json:"pit_id"`
+			}
+			if err := json.NewDecoder(reader).Decode(&body); err != nil || len(body.PitID) != 1 {
+				t.Errorf("unexpected delete body: %v", err)
+				return
+			}
+			pitID := body.PitID[0]
+			s.deletes[pitID]++
+			if !s.open[pitID] {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"error":{"type":"not_found","reason":"pit not found"},"status":404}`))
+				return
+			}
+			delete(s.open, pitID)
+			fmt.Fprintf(w, `{"pits":[{"pit_id":%q,"successful":true}]}`, pitID)
+		case req.URL.Path == "/_search":
+			var body struct {
+				PIT struct {
+					KeepAlive string `json:"keep_alive"`
+				} `json:"pit"`
+			}
+			if err := json.NewDecoder(reader).Decode(&body); err != nil {
+				t.Error(err)
+			}
+			s.refreshes.Add(1)
+			s.keepAlive.Store(body.PIT.KeepAlive)
+			w.Write([]byte(`{"hits":{"hits":[]}}`))
+		default:
+			t.Errorf("unexpected request %s %s", req.Method, req.URL.Path)
+		}
+	}))
+	return s, server
+}
+
+func (s *pitServer) deleteCount(pitID string) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.deletes[pitID]
+}
+
+func newPitPaginator(t *testing.T, server *httptest.Server) *opengovernance.BaseESPaginator {
+	username, password := "", ""
+	client, err := opengovernance.NewClient(opengovernance.ClientConfig{
+		Addresses: []string{server.URL},
+		Username:  &username,
+		Password:  &password,
+	})
+	require.NoError(t, err)
+	limit := int64(100000)
+	p, err := opengovernance.NewPaginator(client.ES(), "inventory", nil, &limit)
+	require.NoError(t, err)
+	require.NoError(t, p.CreatePit(context.Background()))
+	return p
+}
+
+func TestPITKeepAliveRefresh(t *testing.T) {
+	r := require.New(t)
+	s, server := newPitServer(t)
+	defer server.Close()
+
+	p := newPitPaginator(t, server)
+	opts := opengovernance.PITKeepAliveOptions{KeepAlive: 90 * time.Second, Interval: 5 * time.Millisecond}
+	p.StartKeepAlive(context.Background(), opts)
+	r.Eventually(func() bool { return s.refreshes.Load() >= 2 }, time.Second, time.Millisecond)
+	r.Equal("90s", s.keepAlive.Load())
+
+	// The keep-alive can be restarted after a stop
+	p.StopKeepAlive()
+	refreshes := s.refreshes.Load()
+	p.StartKeepAlive(context.Background(), opts)
+	r.Eventually(func() bool { return s.refreshes.Load() > refreshes }, time.Second, time.Millisecond)
+
+	r.NoError(p.Close(context.Background()))
+	r.Equal(1, s.deleteCount("pit-1"))
+}
+
+func TestPITKeepAliveReleaseOnCancel(t *testing.T) {
+	r := require.New(t)
+	s, server := newPitServer(t)
+	defer server.Close()
+
+	p := newPitPaginator(t, server)
+	ctx, cancel := context.WithCancel(context.Background())
+	p.StartKeepAlive(ctx, opengovernance.PITKeepAliveOptions{Interval: time.Hour})
+	cancel()
+	r.Eventually(func() bool { return s.deleteCount("pit-1") == 1 }, time.Second, time.Millisecond)
+
+	// Already deleted by the keeper: neither Deallocate nor Close delete it again
+	r.NoError(p.Deallocate(context.Background()))
+	r.NoError(p.Close(context.Background()))
+	r.Equal(1, s.deleteCount("pit-1"))
+
+	// Nor after the keeper was stopped
+	p = newPitPaginator(t, server)
+	ctx, cancel = context.WithCancel(context.Background())
+	p.StartKeepAlive(ctx, opengovernance.PITKeepAliveOptions{Interval: time.Hour})
+	cancel()
+	r.Eventually(func() bool { return s.deleteCount("pit-2") == 1 }, time.Second, time.Millisecond)
+	p.StopKeepAlive()
+	r.NoError(p.Deallocate(context.Background()))
+	r.Equal(1, s.deleteCount("pit-2"))
+}
+
+func TestPITDeallocateRacingCancel(t *testing.T) {
+	r := require.New(t)
+	s, server := newPitServer(t)
+	defer server.Close()
+
+	for i := 1; i <= 20; i++ {
+		p := newPitPaginator(t, server)
+		ctx, cancel := context.WithCancel(context.Background())
+		p.StartKeepAlive(ctx, opengovernance.PITKeepAliveOptions{Interval: time.Hour})
+		go cancel()
+		r.NoError(p.Deallocate(context.Background()))
+		p.StopKeepAlive()
+		r.Equal(1, s.deleteCount(fmt.Sprintf("pit-%d", i)))
+	}
+}
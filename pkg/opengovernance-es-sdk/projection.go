@@ -0,0 +1,74 @@
+package opengovernance
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+)
+
+// FieldMapping maps Steampipe column names to the _source paths needed to populate them.
+// A column may depend on several paths (e.g. a title built from name and id).
+type FieldMapping map[string][]string
+
+var (
+	fieldMappingsMu sync.RWMutex
+	fieldMappings   = map[string]FieldMapping{}
+)
+
+// RegisterFieldMapping registers the column to _source mapping for index, replacing any
+// previous registration. Integrations typically call it from their table definitions.
+func RegisterFieldMapping(index string, mapping FieldMapping) {
+	fieldMappingsMu.Lock()
+	defer fieldMappingsMu.Unlock()
+	fieldMappings[index] = mapping
+}
+
+// LookupFieldMapping returns the mapping registered for index.
+func LookupFieldMapping(index string) (FieldMapping, bool) {
+	fieldMappingsMu.RLock()
+	defer fieldMappingsMu.RUnlock()
+	mapping, ok := fieldMappings[index]
+	return mapping, ok
+}
+
+// SourceIncludes returns the sorted, de-duplicated _source paths needed for columns of
+// index. It returns nil, meaning the full document must be fetched, if no mapping is
+// registered, no columns are requested, or any requested column is unmapped.
+func SourceIncludes(index string, columns []string) []string {
+	mapping, ok := LookupFieldMapping(index)
+	if !ok || len(columns) == 0 {
+		return nil
+	}
+	seen := make(map[string]bool)
+	var includes []string
+	for _, column := range columns {
+		paths, ok := mapping[column]
+		if !ok || len(paths) == 0 {
+			return nil
+		}
+		for _, path := range paths {
+			if !seen[path] {
+				seen[path] = true
+				includes = append(includes, path)
+			}
+		}
+	}
+	sort.Strings(includes)
+	return includes
+}
+
+// SetSourceIncludes restricts the _source fields returned by the paginator's searches.
+// A nil or empty slice fetches full documents.
+func (p *BaseESPaginator) SetSourceIncludes(includes []string) {
+	p.sourceIncludes = includes
+}
+
+// ApplyColumnProjection limits the paginator to the _source fields needed for the
+// columns requested in queryContext, using the FieldMapping registered for its index.
+func (p *BaseESPaginator) ApplyColumnProjection(queryContext *plugin.QueryContext) {
+	if queryContext == nil {
+		return
+	}
+	p.SetSourceIncludes(SourceIncludes(p.index, queryContext.Columns))
+}
@@ -0,0 +1,211 @@
+package opengovernance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// QueryBuilder assembles a search body section by section and validates it before it is
+// sent, instead of hand-written JSON strings:
+//
+//	q := opengovernance.NewQueryBuilder().
+//		Filters(opengovernance.NewTermFilter("metadata.Region", region)).
+//		Sort("_id", "asc").
+//		Size(100)
+//	err := client.SearchWithQueryBuilder(ctx, index, q, &response)
+//
+// Setters record the first invalid input, which Build reports.
+type QueryBuilder struct {
+	query       any
+	filters     []BoolFilter
+	size        *int64
+	sort        []map[string]any
+	source      []string
+	searchAfter []any
+	aggs        map[string]Aggregation
+	runtime     map[string]RuntimeField
+	scripts     map[string]ScriptField
+	pit         *PointInTime // Set by Snapshot.Search
+	err         error
+}
+
+func NewQueryBuilder() *QueryBuilder {
+	return &QueryBuilder{}
+}
+
+func (b *QueryBuilder) fail(err error) *QueryBuilder {
+	if b.err == nil {
+		b.err = err
+	}
+	return b
+}
+
+// Query sets the query clause, e.g. a BoolFilter or a map. It is combined with Filters
+// as a bool query that must match it.
+func (b *QueryBuilder) Query(query any) *QueryBuilder {
+	b.query = query
+	return b
+}
+
+// QueryJSON sets the query clause from raw JSON, which must be valid.
+func (b *QueryBuilder) QueryJSON(query string) *QueryBuilder {
+	if !json.Valid([]byte(query)) {
+		return b.fail(errors.New("query builder: query is not valid JSON"))
+	}
+	b.query = json.RawMessage(query)
+	return b
+}
+
+// Filters adds filters the hits must match, without affecting their score.
+func (b *QueryBuilder) Filters(filters ...BoolFilter) *QueryBuilder {
+	b.filters = append(b.filters, filters...)
+	return b
+}
+
+// Size sets the number of hits returned, at most MaxPageSize.
+func (b *QueryBuilder) Size(size int64) *QueryBuilder {
+	if size < 0 || size > MaxPageSize() {
+		return b.fail(fmt.Errorf("query builder: size %d is not between 0 and %d", size, MaxPageSize()))
+	}
+	b.size = &size
+	return b
+}
+
+// Sort adds a sort on field, in order "asc" or "desc".
+func (b *QueryBuilder) Sort(field string, order string) *QueryBuilder {
+	if field == "" {
+		return b.fail(errors.New("query builder: sort field is empty"))
+	}
+	if order != "asc" && order != "desc" {
+		return b.fail(fmt.Errorf("query builder: invalid sort order %q for %s", order, field))
+	}
+	b.sort = append(b.sort, map[string]any{field: order})
+	return b
+}
+
+// Source limits the _source fields returned.
+func (b *QueryBuilder) Source(fields ...string) *QueryBuilder {
+	b.source = append(b.source, fields...)
+	return b
+}
+
+// SearchAfter continues after the hit with these sort values. It requires a sort with as
+// many fields.
+func (b *QueryBuilder) SearchAfter(values ...any) *QueryBuilder {
+	b.searchAfter = values
+	return b
+}
+
+// Aggregation adds the aggregation name.
+func (b *QueryBuilder) Aggregation(name string, agg Aggregation) *QueryBuilder {
+	if name == "" {
+		return b.fail(errors.New("query builder: aggregation name is empty"))
+	}
+	if b.aggs == nil {
+		b.aggs = make(map[string]Aggregation)
+	}
+	b.aggs[name] = agg
+	return b
+}
+
+// RuntimeField defines name as a field of type fieldType (keyword, long, double, date,
+// boolean, ip or geo_point) computed by script at search time, e.g. days until a
+// certificate expires, so that it can be filtered, sorted and aggregated on without
+// reindexing. The cluster must support runtime_mappings.
+func (b *QueryBuilder) RuntimeField(name string, fieldType string, script Script) *QueryBuilder {
+	if name == "" {
+		return b.fail(errors.New("query builder: runtime field name is empty"))
+	}
+	if !runtimeFieldTypes[fieldType] {
+		return b.fail(fmt.Errorf("query builder: invalid runtime field type %q for %s", fieldType, name))
+	}
+	if err := script.Validate(); err != nil {
+		return b.fail(fmt.Errorf("query builder: runtime field %s: %w", name, err))
+	}
+	if b.runtime == nil {
+		b.runtime = make(map[string]RuntimeField)
+	}
+	b.runtime[name] = RuntimeField{Type: fieldType, Script: script}
+	return b
+}
+
+// ScriptField returns the value script computes for each hit in the hit's Fields under
+// name. Requesting script fields omits _source from hits unless Source is set.
+func (b *QueryBuilder) ScriptField(name string, script Script) *QueryBuilder {
+	if name == "" {
+		return b.fail(errors.New("query builder: script field name is empty"))
+	}
+	if err := script.Validate(); err != nil {
+		return b.fail(fmt.Errorf("query builder: script field %s: %w", name, err))
+	}
+	if b.scripts == nil {
+		b.scripts = make(map[string]ScriptField)
+	}
+	b.scripts[name] = ScriptField{Script: script}
+	return b
+}
+
+// Build validates the sections and returns the search body.
+func (b *QueryBuilder) Build() (string, error) {
+	if b.err != nil {
+		return "", b.err
+	}
+	if len(b.searchAfter) > 0 && len(b.searchAfter) != len(b.sort) {
+		return "", fmt.Errorf("query builder: search_after has %d values for %d sort fields", len(b.searchAfter), len(b.sort))
+	}
+
+	request := SearchRequest{
+		Size:        b.size,
+		Sort:        b.sort,
+		SearchAfter: b.searchAfter,
+		Source:      b.source,
+		Aggs:        b.aggs,
+		PIT:         b.pit,
+
+		RuntimeMappings: b.runtime,
+		ScriptFields:    b.scripts,
+	}
+	switch {
+	case len(b.filters) > 0 && b.query != nil:
+		request.Query = map[string]any{"bool": map[string]any{"must": b.query, "filter": b.filters}}
+	case len(b.filters) > 0:
+		request.Query = map[string]any{"bool": map[string]any{"filter": b.filters}}
+	default:
+		request.Query = b.query
+	}
+
+	query, err := json.Marshal(request)
+	if err != nil {
+		return "", fmt.Errorf("query builder: %w", err)
+	}
+	return string(query), nil
+}
+
+// String returns the search body for logging, or the reason it is invalid.
+func (b *QueryBuilder) String() string {
+	query, err := b.Build()
+	if err != nil {
+		return "invalid query: " + err.Error()
+	}
+	return query
+}
+
+// SearchWithQueryBuilder builds query and runs the search.
+func (c Client) SearchWithQueryBuilder(ctx context.Context, index string, query *QueryBuilder, response any) error {
+	body, err := query.Build()
+	if err != nil {
+		return err
+	}
+	return c.search(ctx, index, body, nil, response, false)
+}
+
+// SearchWithQueryBuilder builds query and runs the search against the tenant's index.
+func (t TenantClient) SearchWithQueryBuilder(ctx context.Context, index string, query *QueryBuilder, response any) error {
+	index, err := t.ResolveIndex(ctx, index)
+	if err != nil {
+		return err
+	}
+	return t.client.SearchWithQueryBuilder(ctx, index, query, response)
+}
@@ -0,0 +1,44 @@
+package opengovernance_test
+
+import (
+	"testing"
+
+	opengovernance "github.com/opengovern/og-util/pkg/opengovernance-es-sdk"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryBuilder(t *testing.T) {
+	require := require.New(t)
+
+	query, err := opengovernance.NewQueryBuilder().
+		QueryJSON(`{"match": {"description": "public"}}`).
+		Filters(opengovernance.NewTermsFilter("metadata.Region", []string{"us-east-1"})).
+		Size(10).
+		Sort("_id", "asc").
+		SearchAfter("i-1").
+		Source("metadata").
+		Build()
+	require.NoError(err)
+	require.JSONEq(`{
+		"size": 10,
+		"query": {"bool": {
+			"must": {"match": {"description": "public"}},
+			"filter": [{"terms": {"metadata.Region": ["us-east-1"]}}]
+		}},
+		"sort": [{"_id": "asc"}],
+		"search_after": ["i-1"],
+		"_source": ["metadata"]
+	}`, query)
+
+	_, err = opengovernance.NewQueryBuilder().QueryJSON(`{"match": `).Build()
+	require.ErrorContains(err, "not valid JSON")
+	_, err = opengovernance.NewQueryBuilder().Size(-1).Build()
+	require.ErrorContains(err, "size -1")
+	_, err = opengovernance.NewQueryBuilder().Sort("name", "up").Build()
+	require.ErrorContains(err, "invalid sort order")
+	_, err = opengovernance.NewQueryBuilder().SearchAfter("x").Build()
+	require.ErrorContains(err, "search_after has 1 values for 0 sort fields")
+
+	require.Equal(`{"query":{"bool":{"filter":[{"exists":{"field":"arn"}}]}}}`,
+		opengovernance.NewQueryBuilder().Filters(opengovernance.NewExistsFilter("arn")).String())
+}
@@ -0,0 +1,81 @@
+package opengovernance
+
+import (
+	"context"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"go.uber.org/zap/zapcore"
+)
+
+// QueryLimit returns the SQL LIMIT Steampipe pushed down in queryContext, or nil if the
+// query is unbounded. It can be passed directly as the limit of NewPaginator.
+func QueryLimit(queryContext *plugin.QueryContext) *int64 {
+	if queryContext == nil || queryContext.Limit == nil || *queryContext.Limit < 0 {
+		return nil
+	}
+	limit := *queryContext.Limit
+	return &limit
+}
+
+// ApplyQueryLimit caps the paginator's limit at the query's SQL LIMIT and shrinks the
+// page size to match, so that a `LIMIT 10` query issues a single 10-document search
+// instead of fetching a full page.
+func (p *BaseESPaginator) ApplyQueryLimit(queryContext *plugin.QueryContext) {
+	limit := QueryLimit(queryContext)
+	if limit == nil {
+		return
+	}
+	p.capLimit(*limit)
+}
+
+// ShouldStop reports whether the scan should end before the next page because Steampipe
+// cancelled it or already has all the rows it needs, and marks the paginator as done if
+// so. Otherwise it caps the remaining fetch at d.RowsRemaining. Call it before each
+// Search in the list hydrate loop.
+func (p *BaseESPaginator) ShouldStop(ctx context.Context, d *plugin.QueryData) bool {
+	if p.done {
+		return true
+	}
+	if ctx.Err() != nil {
+		p.done = true
+		return true
+	}
+	if d == nil {
+		return false
+	}
+	remaining := d.RowsRemaining(ctx)
+	if remaining <= 0 {
+		p.log(ctx, zapcore.DebugLevel, "paginator stopping early: no more rows required")
+		p.done = true
+		return true
+	}
+	if p.queried+remaining > p.queried { // guard against overflow for unbounded queries
+		p.capLimit(p.queried + remaining)
+	}
+	return false
+}
+
+// capLimit lowers the limit (never raises it) and keeps the page size within the
+// documents still to fetch.
+func (p *BaseESPaginator) capLimit(limit int64) {
+	if limit < 0 {
+		return
+	}
+	if limit < p.limit {
+		p.limit = limit
+	}
+	if p.queried >= p.limit {
+		p.done = true
+		return
+	}
+	p.pageSize = p.nextPageSize()
+}
+
+// nextPageSize is the size of the next page: the page size, or fewer if the limit is
+// closer, so that the last page does not overshoot it.
+func (p *BaseESPaginator) nextPageSize() int64 {
+	if remaining := p.limit - p.queried; remaining > 0 && remaining < p.pageSize {
+		return remaining
+	}
+	return p.pageSize
+}
@@ -0,0 +1,92 @@
+package opengovernance_test
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	opengovernance "github.com/opengovern/og-util/pkg/opengovernance-es-sdk"
+	"github.com/stretchr/testify/require"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+)
+
+func TestApplyQueryLimitMultiPage(t *testing.T) {
+	r := require.New(t)
+
+	var sizes []int
+	next := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		reader := io.Reader(req.Body)
+		if req.Header.Get("Content-Encoding") == "gzip" {
+			gz, err := gzip.NewReader(req.Body)
+			r.NoError(err)
+			reader = gz
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(req.URL.Path, "/_search/point_in_time") && req.Method == http.MethodPost:
+			w.Write([]byte(`{"pit_id":"pit-1"}`))
+		case strings.HasSuffix(req.URL.Path, "/_search/point_in_time") && req.Method == http.MethodDelete:
+			w.Write([]byte(`{"pits":[{"pit_id":"pit-1","successful":true}]}`))
+		case req.URL.Path == "/_search":
+			var body struct {
+				Size int `json:"size"`
+			}
+			r.NoError(json.NewDecoder(reader).Decode(&body))
+			sizes = append(sizes, body.Size)
+			// The index holds more documents than the limit
+			hits := make([]string, body.Size)
+			for i := range hits {
+				hits[i] = fmt.Sprintf(`{"_id":"%d","sort":[%d]}`, next, next)
+				next++
+			}
+			fmt.Fprintf(w, `{"pit_id":"pit-1","hits":{"hits":[%s]}}`, strings.Join(hits, ","))
+		default:
+			t.Errorf("unexpected request %s %s", req.Method, req.URL.Path)
+		}
+	}))
+	defer server.Close()
+
+	username, password := "", ""
+	client, err := opengovernance.NewClient(opengovernance.ClientConfig{
+		Addresses: []string{server.URL},
+		Username:  &username,
+		Password:  &password,
+	})
+	r.NoError(err)
+	p, err := opengovernance.NewPaginator(client.ES(), "inventory", nil, nil)
+	r.NoError(err)
+	p.WithPageSize(10)
+	limit := int64(25)
+	p.ApplyQueryLimit(&plugin.QueryContext{Limit: &limit})
+
+	ctx := context.Background()
+	total := 0
+	for !p.Done() {
+		var response struct {
+			PitID string `json:"pit_id"`
+			Hits  struct {
+				Hits []struct {
+					Sort []any `json:"sort"`
+				} `json:"hits"`
+			} `json:"hits"`
+		}
+		r.NoError(p.Search(ctx, &response))
+		hits := response.Hits.Hits
+		total += len(hits)
+		var searchAfter []any
+		if len(hits) > 0 {
+			searchAfter = hits[len(hits)-1].Sort
+		}
+		p.UpdateState(int64(len(hits)), searchAfter, response.PitID)
+	}
+	r.Equal(25, total)
+	r.Equal([]int{10, 10, 5}, sizes)
+	r.NoError(p.Deallocate(ctx))
+}
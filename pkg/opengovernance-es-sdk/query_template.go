@@ -0,0 +1,140 @@
+package opengovernance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// QueryParams binds values to the placeholders of a query template.
+type QueryParams map[string]any
+
+// BuildQuery renders a JSON query template, replacing each {{name}} placeholder with the
+// JSON encoding of params[name]. Placeholders stand for whole JSON values and must not
+// appear inside string literals, so a bound value can never change the structure of the
+// query:
+//
+//	BuildQuery(`{"query": {"term": {"account_id": {{account}}}}}`, QueryParams{"account": id})
+//
+// Every placeholder must be bound and every param must be used.
+func BuildQuery(template string, params QueryParams) (string, error) {
+	var (
+		out      strings.Builder
+		used     = make(map[string]bool)
+		inString bool
+		escaped  bool
+	)
+	for i := 0; i < len(template); i++ {
+		ch := template[i]
+		if inString {
+			switch {
+			case escaped:
+				escaped = false
+			case ch == '\\':
+				escaped = true
+			case ch == '"':
+				inString = false
+			case strings.HasPrefix(template[i:], "{{"):
+				return "", fmt.Errorf("query template: placeholder at offset %d is inside a string literal; bind the whole value instead", i)
+			}
+			out.WriteByte(ch)
+			continue
+		}
+		if ch == '"' {
+			inString = true
+			out.WriteByte(ch)
+			continue
+		}
+		if !strings.HasPrefix(template[i:], "{{") {
+			out.WriteByte(ch)
+			continue
+		}
+
+		end := strings.Index(template[i+2:], "}}")
+		if end < 0 {
+			return "", fmt.Errorf("query template: unterminated placeholder at offset %d", i)
+		}
+		name := strings.TrimSpace(template[i+2 : i+2+end])
+		if !isPlaceholderName(name) {
+			return "", fmt.Errorf("query template: invalid placeholder name %q", name)
+		}
+		value, ok := params[name]
+		if !ok {
+			return "", fmt.Errorf("query template: no value bound for {{%s}}", name)
+		}
+		encoded, err := json.Marshal(value)
+		if err != nil {
+			return "", fmt.Errorf("query template: encode {{%s}}: %w", name, err)
+		}
+		out.Write(encoded)
+		used[name] = true
+		i += end + 3
+	}
+	if inString {
+		return "", errors.New("query template: unterminated string literal")
+	}
+
+	var unused []string
+	for name := range params {
+		if !used[name] {
+			unused = append(unused, name)
+		}
+	}
+	if len(unused) > 0 {
+		sort.Strings(unused)
+		return "", fmt.Errorf("query template: unused params %v", unused)
+	}
+
+	query := out.String()
+	if !json.Valid([]byte(query)) {
+		return "", errors.New("query template: rendered query is not valid JSON")
+	}
+	return query, nil
+}
+
+func isPlaceholderName(name string) bool {
+	if name == "" {
+		return false
+	}
+	for i, r := range name {
+		switch {
+		case r == '_', r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z':
+		case i > 0 && (r >= '0' && r <= '9' || r == '.'):
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// SearchQuery renders template with params (see BuildQuery) and runs the search.
+func (c Client) SearchQuery(ctx context.Context, index string, template string, params QueryParams, response any) error {
+	return c.SearchQueryWithTrackTotalHits(ctx, index, template, params, nil, response, false)
+}
+
+// SearchQueryWithTrackTotalHits renders template with params (see BuildQuery) and runs
+// the search with the given filter path and track_total_hits.
+func (c Client) SearchQueryWithTrackTotalHits(ctx context.Context, index string, template string, params QueryParams, filterPath []string, response any, trackTotalHits any) error {
+	query, err := BuildQuery(template, params)
+	if err != nil {
+		return err
+	}
+	return c.search(ctx, index, query, filterPath, response, trackTotalHits)
+}
+
+// SearchQuery renders template with params (see BuildQuery) and runs the search against
+// the tenant's index.
+func (t TenantClient) SearchQuery(ctx context.Context, index string, template string, params QueryParams, response any) error {
+	return t.SearchQueryWithTrackTotalHits(ctx, index, template, params, nil, response, false)
+}
+
+func (t TenantClient) SearchQueryWithTrackTotalHits(ctx context.Context, index string, template string, params QueryParams, filterPath []string, response any, trackTotalHits any) error {
+	index, err := t.ResolveIndex(ctx, index)
+	if err != nil {
+		return err
+	}
+	return t.client.SearchQueryWithTrackTotalHits(ctx, index, template, params, filterPath, response, trackTotalHits)
+}
@@ -0,0 +1,43 @@
+package opengovernance_test
+
+import (
+	"testing"
+
+	opengovernance "github.com/opengovern/og-util/pkg/opengovernance-es-sdk"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildQuery(t *testing.T) {
+	require := require.New(t)
+
+	query, err := opengovernance.BuildQuery(
+		`{"size": {{size}}, "query": {"bool": {"filter": [{"term": {"account_id": {{ account }}}}, {"terms": {"region": {{regions}}}}]}}}`,
+		opengovernance.QueryParams{
+			"size":    10,
+			"account": `123"}},{"match_all":{}}]}}}`,
+			"regions": []string{"us-east-1", "eu-west-1"},
+		})
+	require.NoError(err)
+	require.JSONEq(`{"size": 10, "query": {"bool": {"filter": [
+		{"term": {"account_id": "123\"}},{\"match_all\":{}}]}}}"}},
+		{"terms": {"region": ["us-east-1", "eu-west-1"]}}
+	]}}}`, query)
+}
+
+func TestBuildQueryRejectsUnsafeTemplates(t *testing.T) {
+	require := require.New(t)
+
+	for name, tc := range map[string]struct {
+		template string
+		params   opengovernance.QueryParams
+	}{
+		"placeholder in string": {`{"term": {"id": "prefix-{{id}}"}}`, opengovernance.QueryParams{"id": "x"}},
+		"missing param":         {`{"term": {"id": {{id}}}}`, nil},
+		"unused param":          {`{"match_all": {}}`, opengovernance.QueryParams{"id": "x"}},
+		"unterminated":          {`{"term": {"id": {{id}}`, opengovernance.QueryParams{"id": "x"}},
+		"invalid name":          {`{"term": {"id": {{a b}}}}`, opengovernance.QueryParams{"a b": "x"}},
+	} {
+		_, err := opengovernance.BuildQuery(tc.template, tc.params)
+		require.Error(err, name)
+	}
+}
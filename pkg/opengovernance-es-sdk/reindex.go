@@ -0,0 +1,216 @@
+package opengovernance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/opensearch-project/opensearch-go/v2/opensearchapi"
+	"github.com/opensearch-project/opensearch-go/v2/opensearchutil"
+)
+
+// DefaultReindexPollInterval is how often Reindex polls the status of its task.
+const DefaultReindexPollInterval = 5 * time.Second
+
+// ReindexOptions configures a reindex.
+type ReindexOptions struct {
+	Query             any             // Documents of the source copied (e.g. a map or a BoolFilter query); all if nil
+	Conflicts         ConflictsPolicy // ConflictsProceed to count version conflicts instead of failing
+	OnlyCreate        bool            // Copy only documents missing from dest; existing ones are version conflicts
+	Refresh           bool            // Refresh dest once done
+	MaxDocs           int             // Copy at most this many documents; all if zero
+	Slices            int             // Parallel slices; 1 if zero, "auto" if negative
+	RequestsPerSecond int             // Throttle of the batches; unthrottled if zero
+	Async             bool            // Return the task ID without waiting for the reindex to complete
+
+	PollInterval time.Duration         // Between status polls; DefaultReindexPollInterval if zero
+	OnProgress   func(ReindexProgress) // Called after every poll
+}
+
+// ReindexProgress is the status of a running reindex.
+type ReindexProgress struct {
+	Total            int `json:"total"` // Documents to copy, known once the first batch is read
+	Created          int `json:"created"`
+	Updated          int `json:"updated"`
+	Deleted          int `json:"deleted"`
+	Batches          int `json:"batches"`
+	VersionConflicts int `json:"version_conflicts"`
+	Noops            int `json:"noops"`
+}
+
+// Copied returns the number of documents written to the destination so far.
+func (p ReindexProgress) Copied() int {
+	return p.Created + p.Updated
+}
+
+// ReindexResponse ...
+type ReindexResponse struct {
+	Task             string `json:"task"` // ID of the reindex task, e.g. to wait for it with WaitForReindex
+	Took             int    `json:"took"`
+	TimedOut         bool   `json:"timed_out"`
+	Total            int    `json:"total"`
+	Created          int    `json:"created"`
+	Updated          int    `json:"updated"`
+	Deleted          int    `json:"deleted"`
+	Batches          int    `json:"batches"`
+	VersionConflicts int    `json:"version_conflicts"`
+	Noops            int    `json:"noops"`
+	Retries          struct {
+		Bulk   int `json:"bulk"`
+		Search int `json:"search"`
+	} `json:"retries"`
+	ThrottledMillis      int     `json:"throttled_millis"`
+	RequestsPerSecond    float64 `json:"requests_per_second"`
+	ThrottledUntilMillis int     `json:"throttled_until_millis"`
+	Failures             []any   `json:"failures"`
+}
+
+type reindexTaskStatus struct {
+	Completed bool `json:"completed"`
+	Task      struct {
+		Status ReindexProgress `json:"status"`
+	} `json:"task"`
+	Response *ReindexResponse `json:"response"`
+	Error    *struct {
+		Type   string `json:"type"`
+		Reason string `json:"reason"`
+	} `json:"error"`
+}
+
+// Reindex copies the documents of source matching opts.Query into dest with _reindex,
+// e.g. to migrate an index to a new mapping. The reindex runs as a task that is polled
+// every opts.PollInterval until it completes, reporting its progress to opts.OnProgress.
+// Documents that failed to copy are in the response's Failures and make Reindex return
+// an error along with the response.
+//
+// If ctx is done first, the task keeps running: the returned response holds its ID to
+// wait for it again with WaitForReindex.
+func (c Client) Reindex(ctx context.Context, source, dest string, opts ReindexOptions) (ReindexResponse, error) {
+	if source == "" || dest == "" {
+		return ReindexResponse{}, errors.New("reindex source and dest are required")
+	} else if source == dest {
+		return ReindexResponse{}, fmt.Errorf("cannot reindex %s into itself", source)
+	}
+	sourceBody := map[string]any{"index": source}
+	if opts.Query != nil {
+		sourceBody["query"] = opts.Query
+	}
+	destBody := map[string]any{"index": dest}
+	if opts.OnlyCreate {
+		destBody["op_type"] = "create"
+	}
+	body := map[string]any{
+		"source": sourceBody,
+		"dest":   destBody,
+	}
+	switch opts.Conflicts {
+	case "":
+	case ConflictsAbort, ConflictsProceed:
+		body["conflicts"] = string(opts.Conflicts)
+	default:
+		return ReindexResponse{}, fmt.Errorf("invalid conflicts policy: %s", opts.Conflicts)
+	}
+
+	reqOpts := []func(*opensearchapi.ReindexRequest){
+		c.es.Reindex.WithContext(ctx),
+		c.es.Reindex.WithWaitForCompletion(false),
+	}
+	if opts.Refresh {
+		reqOpts = append(reqOpts, c.es.Reindex.WithRefresh(true))
+	}
+	if opts.MaxDocs > 0 {
+		reqOpts = append(reqOpts, c.es.Reindex.WithMaxDocs(opts.MaxDocs))
+	}
+	if opts.Slices < 0 {
+		reqOpts = append(reqOpts, c.es.Reindex.WithSlices("auto"))
+	} else if opts.Slices > 0 {
+		reqOpts = append(reqOpts, c.es.Reindex.WithSlices(opts.Slices))
+	}
+	if opts.RequestsPerSecond > 0 {
+		reqOpts = append(reqOpts, c.es.Reindex.WithRequestsPerSecond(opts.RequestsPerSecond))
+	}
+
+	resp, err := c.es.Reindex(opensearchutil.NewJSONReader(body), reqOpts...)
+	defer CloseSafe(resp)
+	if err != nil {
+		return ReindexResponse{}, err
+	} else if err := CheckError(resp); err != nil {
+		return ReindexResponse{}, err
+	}
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return ReindexResponse{}, fmt.Errorf("read response: %w", err)
+	}
+	var started struct {
+		Task string `json:"task"`
+	}
+	if err := json.Unmarshal(b, &started); err != nil {
+		return ReindexResponse{}, fmt.Errorf("unmarshal response: %w", err)
+	} else if started.Task == "" {
+		return ReindexResponse{}, fmt.Errorf("reindex of %s into %s returned no task", source, dest)
+	}
+	if opts.Async {
+		return ReindexResponse{Task: started.Task}, nil
+	}
+	return c.WaitForReindex(ctx, started.Task, opts.PollInterval, opts.OnProgress)
+}
+
+// WaitForReindex polls the reindex task every pollInterval (DefaultReindexPollInterval if
+// zero) until it completes, reporting its progress to onProgress, which may be nil. See
+// Reindex for the errors returned.
+func (c Client) WaitForReindex(ctx context.Context, task string, pollInterval time.Duration, onProgress func(ReindexProgress)) (ReindexResponse, error) {
+	if pollInterval <= 0 {
+		pollInterval = DefaultReindexPollInterval
+	}
+	for {
+		status, err := c.getReindexTask(ctx, task)
+		if err != nil {
+			return ReindexResponse{Task: task}, err
+		}
+		if onProgress != nil {
+			onProgress(status.Task.Status)
+		}
+		if status.Completed {
+			if status.Error != nil {
+				return ReindexResponse{Task: task}, fmt.Errorf("reindex task %s failed: %s: %s", task, status.Error.Type, status.Error.Reason)
+			}
+			var response ReindexResponse
+			if status.Response != nil {
+				response = *status.Response
+			}
+			response.Task = task
+			if len(response.Failures) > 0 {
+				return response, fmt.Errorf("reindex task %s: %d failures, first: %v", task, len(response.Failures), response.Failures[0])
+			}
+			return response, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return ReindexResponse{Task: task}, fmt.Errorf("wait for reindex task %s: %w", task, ctx.Err())
+		case <-time.After(pollInterval):
+		}
+	}
+}
+
+func (c Client) getReindexTask(ctx context.Context, task string) (reindexTaskStatus, error) {
+	resp, err := c.es.Tasks.Get(task, c.es.Tasks.Get.WithContext(ctx))
+	defer CloseSafe(resp)
+	if err != nil {
+		return reindexTaskStatus{}, err
+	} else if err := CheckError(resp); err != nil {
+		return reindexTaskStatus{}, err
+	}
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return reindexTaskStatus{}, fmt.Errorf("read response: %w", err)
+	}
+	var status reindexTaskStatus
+	if err := json.Unmarshal(b, &status); err != nil {
+		return reindexTaskStatus{}, fmt.Errorf("unmarshal response: %w", err)
+	}
+	return status, nil
+}
@@ -0,0 +1,117 @@
+package opengovernance_test
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	opengovernance "github.com/opengovern/og-util/pkg/opengovernance-es-sdk"
+	"github.com/stretchr/testify/require"
+)
+
+func TestReindex(t *testing.T) {
+	r := require.New(t)
+
+	polls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch req.URL.Path {
+		case "/_reindex":
+			r.Equal("false", req.URL.Query().Get("wait_for_completion"))
+			r.Equal("auto", req.URL.Query().Get("slices"))
+			r.Equal("500", req.URL.Query().Get("requests_per_second"))
+			reader := io.Reader(req.Body)
+			if req.Header.Get("Content-Encoding") == "gzip" {
+				gz, err := gzip.NewReader(req.Body)
+				r.NoError(err)
+				reader = gz
+			}
+			var body map[string]any
+			r.NoError(json.NewDecoder(reader).Decode(&body))
+			r.Equal(map[string]any{"index": "inventory_v1"}, body["source"])
+			r.Equal(map[string]any{"index": "inventory_v2", "op_type": "create"}, body["dest"])
+			r.Equal("proceed", body["conflicts"])
+			fmt.Fprint(w, `{"task":"node-1:42"}`)
+		case "/_tasks/node-1:42":
+			polls++
+			if polls < 3 {
+				fmt.Fprintf(w, `{"completed":false,"task":{"status":{"total":100,"created":%d,"updated":0,"batches":%d}}}`, polls*40, polls)
+				return
+			}
+			fmt.Fprint(w, `{"completed":true,"task":{"status":{"total":100,"created":90,"updated":0,"version_conflicts":10,"batches":3}},
+				"response":{"took":120,"total":100,"created":90,"version_conflicts":10,"batches":3,"failures":[]}}`)
+		default:
+			t.Errorf("unexpected request %s", req.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	username, password := "", ""
+	client, err := opengovernance.NewClient(opengovernance.ClientConfig{
+		Addresses: []string{server.URL},
+		Username:  &username,
+		Password:  &password,
+	})
+	r.NoError(err)
+
+	var copied []int
+	resp, err := client.Reindex(context.Background(), "inventory_v1", "inventory_v2", opengovernance.ReindexOptions{
+		Conflicts:         opengovernance.ConflictsProceed,
+		OnlyCreate:        true,
+		Slices:            -1,
+		RequestsPerSecond: 500,
+		PollInterval:      time.Millisecond,
+		OnProgress: func(p opengovernance.ReindexProgress) {
+			r.Equal(100, p.Total)
+			copied = append(copied, p.Copied())
+		},
+	})
+	r.NoError(err)
+	r.Equal([]int{40, 80, 90}, copied)
+	r.Equal("node-1:42", resp.Task)
+	r.Equal(90, resp.Created)
+	r.Equal(10, resp.VersionConflicts)
+
+	_, err = client.Reindex(context.Background(), "inventory_v1", "inventory_v1", opengovernance.ReindexOptions{})
+	r.Error(err)
+}
+
+func TestReindexFailures(t *testing.T) {
+	r := require.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch req.URL.Path {
+		case "/_reindex":
+			fmt.Fprint(w, `{"task":"node-1:7"}`)
+		case "/_tasks/node-1:7":
+			fmt.Fprint(w, `{"completed":true,"task":{"status":{"total":2,"created":1}},
+				"response":{"total":2,"created":1,"failures":[{"id":"b","cause":{"type":"mapper_parsing_exception"}}]}}`)
+		}
+	}))
+	defer server.Close()
+
+	username, password := "", ""
+	client, err := opengovernance.NewClient(opengovernance.ClientConfig{
+		Addresses: []string{server.URL},
+		Username:  &username,
+		Password:  &password,
+	})
+	r.NoError(err)
+
+	resp, err := client.Reindex(context.Background(), "a", "b", opengovernance.ReindexOptions{PollInterval: time.Millisecond})
+	r.ErrorContains(err, "1 failures")
+	r.Equal(1, resp.Created)
+	r.Len(resp.Failures, 1)
+
+	resp, err = client.Reindex(context.Background(), "a", "b", opengovernance.ReindexOptions{Async: true})
+	r.NoError(err)
+	r.Equal("node-1:7", resp.Task)
+}
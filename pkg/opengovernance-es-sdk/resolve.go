@@ -0,0 +1,136 @@
+package opengovernance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+)
+
+// IndexNotMatchedError is returned in strict index mode when an index pattern matches
+// no index, alias or data stream. It wraps the underlying index_not_found_exception, so
+// IsIndexNotFoundErr still reports true.
+type IndexNotMatchedError struct {
+	Pattern string
+	cause   error
+}
+
+func (e *IndexNotMatchedError) Error() string {
+	return fmt.Sprintf("index pattern %q matched no indices", e.Pattern)
+}
+
+func (e *IndexNotMatchedError) Unwrap() error {
+	return e.cause
+}
+
+// IsIndexNotMatchedErr checks if error is an IndexNotMatchedError
+func IsIndexNotMatchedErr(err error) bool {
+	var e *IndexNotMatchedError
+	return errors.As(err, &e)
+}
+
+// SetStrictIndices controls how Search, Count and GetByID treat patterns that match
+// nothing. By default they return empty results; in strict mode wildcards must match at
+// least one index and a miss returns an IndexNotMatchedError. Copies of the Client made
+// afterwards keep the setting.
+func (c *Client) SetStrictIndices(strict bool) {
+	c.strictIndices = strict
+}
+
+// indexNotFound handles an index_not_found_exception from a query helper: it is
+// swallowed unless strict index mode is on.
+func (c Client) indexNotFound(pattern string, err error) error {
+	if !c.strictIndices {
+		return nil
+	}
+	return &IndexNotMatchedError{Pattern: pattern, cause: err}
+}
+
+// ResolvedIndex is a concrete index matched by ResolveIndices.
+type ResolvedIndex struct {
+	Name       string   `json:"name"`
+	Aliases    []string `json:"aliases"`
+	Attributes []string `json:"attributes"` // e.g. open, closed, hidden
+	DataStream string   `json:"data_stream"`
+}
+
+// ResolvedAlias is an alias matched by ResolveIndices.
+type ResolvedAlias struct {
+	Name    string   `json:"name"`
+	Indices []string `json:"indices"`
+}
+
+// ResolvedDataStream is a data stream matched by ResolveIndices.
+type ResolvedDataStream struct {
+	Name           string   `json:"name"`
+	BackingIndices []string `json:"backing_indices"`
+	TimestampField string   `json:"timestamp_field"`
+}
+
+// ResolvedIndices is the result of ResolveIndices.
+type ResolvedIndices struct {
+	Indices     []ResolvedIndex      `json:"indices"`
+	Aliases     []ResolvedAlias      `json:"aliases"`
+	DataStreams []ResolvedDataStream `json:"data_streams"`
+}
+
+// Names returns the sorted names of the concrete indices.
+func (r ResolvedIndices) Names() []string {
+	names := make([]string, 0, len(r.Indices))
+	for _, index := range r.Indices {
+		names = append(names, index.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Empty reports whether nothing was matched.
+func (r ResolvedIndices) Empty() bool {
+	return len(r.Indices) == 0 && len(r.Aliases) == 0 && len(r.DataStreams) == 0
+}
+
+// ResolveIndices expands patterns (names, aliases, wildcards and "-" exclusions) to the
+// open indices, aliases and data streams they match, using the resolve index API.
+// Concrete names that do not exist are ignored.
+func (c Client) ResolveIndices(ctx context.Context, patterns []string) (ResolvedIndices, error) {
+	res, err := c.es.Indices.ResolveIndex(patterns,
+		c.es.Indices.ResolveIndex.WithContext(ctx),
+		c.es.Indices.ResolveIndex.WithExpandWildcards("open"),
+	)
+	defer CloseSafe(res)
+	if err != nil {
+		return ResolvedIndices{}, err
+	} else if err := CheckError(res); err != nil {
+		if IsIndexNotFoundErr(err) {
+			return ResolvedIndices{}, nil
+		}
+		return ResolvedIndices{}, err
+	}
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		return ResolvedIndices{}, fmt.Errorf("read response: %w", err)
+	}
+	var response ResolvedIndices
+	if err := json.Unmarshal(b, &response); err != nil {
+		return ResolvedIndices{}, fmt.Errorf("unmarshal response: %w", err)
+	}
+	return response, nil
+}
+
+// RequireIndices resolves each pattern separately and returns an IndexNotMatchedError for
+// the first one that matches nothing. Use it as a pre-check before running queries whose
+// empty results would otherwise be ambiguous.
+func (c Client) RequireIndices(ctx context.Context, patterns ...string) error {
+	for _, pattern := range patterns {
+		resolved, err := c.ResolveIndices(ctx, []string{pattern})
+		if err != nil {
+			return err
+		}
+		if resolved.Empty() {
+			return &IndexNotMatchedError{Pattern: pattern}
+		}
+	}
+	return nil
+}
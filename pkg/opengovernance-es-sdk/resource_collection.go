@@ -0,0 +1,109 @@
+package opengovernance
+
+import (
+	"sort"
+	"sync"
+
+	"github.com/opengovern/og-util/pkg/resourcecollection"
+)
+
+// ResourceCollectionFilter is kept for callers of the SDK; see resourcecollection.Filter.
+type ResourceCollectionFilter = resourcecollection.Filter
+
+// matchNothingFilter replaces resource collection filters that fail to compile, so that
+// a query meant to be scoped to a collection returns nothing rather than everything.
+var matchNothingFilter = NewBoolMustNotFilter(matchAllFilter{})
+
+type matchAllFilter struct{}
+
+func (matchAllFilter) MarshalJSON() ([]byte, error) {
+	return []byte(`{"match_all":{}}`), nil
+}
+func (matchAllFilter) IsBoolFilter() {}
+
+// maxCompiledResourceCollections bounds the cache; plugins see a handful of collections.
+const maxCompiledResourceCollections = 256
+
+var compiledResourceCollections = struct {
+	sync.Mutex
+	filters    map[string]BoolFilter
+	generation uint64 // Incremented when the cache is reset
+}{filters: make(map[string]BoolFilter)}
+
+// CompileResourceCollection decodes and validates encoded resource collection filters
+// (see resourcecollection.Decode) and compiles them to one filter matching the resources
+// of any collection filter. For the "compliance" client type, tagless resource types are
+// matched as well, since tag criteria cannot apply to them. Account, region and zone
+// criteria use the fields of the filter's connectors' dialects (see RegisterDialect).
+// Compiled filters are cached by encoded string and client type.
+func CompileResourceCollection(encoded string, clientType string) (BoolFilter, error) {
+	key := clientType + "\x00" + encoded
+	compiledResourceCollections.Lock()
+	filter, ok := compiledResourceCollections.filters[key]
+	generation := compiledResourceCollections.generation
+	compiledResourceCollections.Unlock()
+	if ok {
+		return filter, nil
+	}
+
+	filters, err := resourcecollection.Decode(encoded)
+	if err != nil {
+		return nil, err
+	}
+	filter = compileResourceCollection(filters, clientType)
+
+	compiledResourceCollections.Lock()
+	defer compiledResourceCollections.Unlock()
+	if generation != compiledResourceCollections.generation {
+		return filter, nil // Compiled against a replaced registry; don't cache it
+	}
+	if len(compiledResourceCollections.filters) >= maxCompiledResourceCollections {
+		compiledResourceCollections.filters = make(map[string]BoolFilter)
+	}
+	compiledResourceCollections.filters[key] = filter
+	return filter, nil
+}
+
+func resetCompiledResourceCollections() {
+	compiledResourceCollections.Lock()
+	defer compiledResourceCollections.Unlock()
+	compiledResourceCollections.filters = make(map[string]BoolFilter)
+	compiledResourceCollections.generation++
+}
+
+func compileResourceCollection(filters []resourcecollection.Filter, clientType string) BoolFilter {
+	esFilters := make([]BoolFilter, 0, len(filters)+1)
+	if clientType == "compliance" {
+		if taglessTypes := TaglessResourceTypes(); len(taglessTypes) > 0 {
+			esFilters = append(esFilters, NewBoolMustFilter(NewTermsFilter("metadata.ResourceType", taglessTypes)))
+		}
+	}
+	for _, f := range filters {
+		andFilters := make([]BoolFilter, 0, 5+len(f.Tags))
+		if len(f.Connectors) > 0 {
+			andFilters = append(andFilters, NewTermsFilter("source_type", f.Connectors))
+		}
+		if len(f.AccountIDs) > 0 {
+			andFilters = append(andFilters, dialectFilter(f.Connectors, Dialect.AccountFields, f.AccountIDs))
+		}
+		if len(f.ResourceTypes) > 0 {
+			andFilters = append(andFilters, NewTermsFilter("metadata.ResourceType", f.ResourceTypes))
+		}
+		if len(f.Regions) > 0 {
+			andFilters = append(andFilters, dialectFilter(f.Connectors, Dialect.RegionFields, f.Regions))
+		}
+		if len(f.Zones) > 0 {
+			andFilters = append(andFilters, dialectFilter(f.Connectors, zoneFields, f.Zones))
+		}
+		tagKeys := make([]string, 0, len(f.Tags))
+		for k := range f.Tags {
+			tagKeys = append(tagKeys, k)
+		}
+		sort.Strings(tagKeys) // Stable filters for identical collections
+		for _, k := range tagKeys {
+			andFilters = append(andFilters, TagEquals(k, f.Tags[k]))
+		}
+		esFilters = append(esFilters, NewBoolMustFilter(andFilters...))
+	}
+	return NewBoolShouldFilter(esFilters...)
+}
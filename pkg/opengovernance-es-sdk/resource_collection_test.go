@@ -0,0 +1,35 @@
+package opengovernance_test
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"testing"
+
+	opengovernance "github.com/opengovern/og-util/pkg/opengovernance-es-sdk"
+	"github.com/opengovern/og-util/pkg/opengovernance-es-sdk/filtertest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBuildFilterResourceCollection(t *testing.T) {
+	r := require.New(t)
+
+	build := func(collection string) string {
+		encoded := base64.StdEncoding.EncodeToString([]byte(collection))
+		filters := opengovernance.BuildFilter(filtertest.Context(), filtertest.NewQueryContext(), nil, nil, &encoded, nil)
+		b, err := json.Marshal(filters)
+		r.NoError(err)
+		return string(b)
+	}
+
+	r.JSONEq(`[{"bool": {"should": [{"bool": {"must": [{"terms": {"source_type": ["AWS"]}}]}}]}}]`,
+		build(`[{"connectors":["AWS"]}]`))
+
+	// Collections that fail validation match nothing instead of every resource
+	for _, collection := range []string{
+		`[{"connectors":["AWS"],"extra":1}]`,
+		`[{}]`,
+		`not json`,
+	} {
+		r.JSONEq(`[{"bool": {"must_not": [{"match_all": {}}]}}]`, build(collection), collection)
+	}
+}
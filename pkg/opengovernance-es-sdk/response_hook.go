@@ -0,0 +1,39 @@
+package opengovernance
+
+import (
+	"context"
+	"fmt"
+)
+
+// ResponseHook post-processes the raw JSON body of a search or get response before it is
+// decoded into the caller's response value. index is the index pattern that was queried.
+type ResponseHook func(ctx context.Context, index string, body []byte) ([]byte, error)
+
+// AddResponseHook registers hook to run, in registration order, on every response of
+// Search*, SearchQuery*, GetByID, MGet and MGetMulti. Copies of the Client made afterwards keep the hooks.
+// Paginators are created from the raw ES client and need them set with
+// BaseESPaginator.SetResponseHooks(c.ResponseHooks()...).
+func (c *Client) AddResponseHook(hook ResponseHook) {
+	c.responseHooks = append(c.responseHooks, hook)
+}
+
+// ResponseHooks returns the registered response hooks.
+func (c Client) ResponseHooks() []ResponseHook {
+	return append([]ResponseHook(nil), c.responseHooks...)
+}
+
+// SetResponseHooks replaces the hooks run on each page before it is decoded.
+func (p *BaseESPaginator) SetResponseHooks(hooks ...ResponseHook) {
+	p.responseHooks = hooks
+}
+
+func applyResponseHooks(ctx context.Context, hooks []ResponseHook, index string, body []byte) ([]byte, error) {
+	for _, hook := range hooks {
+		var err error
+		body, err = hook(ctx, index, body)
+		if err != nil {
+			return nil, fmt.Errorf("response hook: %w", err)
+		}
+	}
+	return body, nil
+}
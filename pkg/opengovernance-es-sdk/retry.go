@@ -0,0 +1,134 @@
+package opengovernance
+
+import (
+	"context"
+	"errors"
+	"math/rand"
+	"net/http"
+	"slices"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// RetryPolicy configures retries of failed searches (Search* and SearchQuery*). Network
+// errors and responses with a retryable status are retried with exponential backoff; a
+// cluster's Retry-After on 429 is honored up to MaxBackoff.
+type RetryPolicy struct {
+	MaxAttempts          int           // Attempts including the first; retries are disabled if < 2
+	InitialBackoff       time.Duration // Delay before the first retry, doubled on each retry (default 200ms)
+	MaxBackoff           time.Duration // Maximum delay between attempts (default 10s)
+	Jitter               float64       // Fraction of each delay that is randomized, in [0, 1] (default 0.2)
+	RetryableStatusCodes []int         // Default 429, 502, 503 and 504
+}
+
+var defaultRetryableStatusCodes = []int{
+	http.StatusTooManyRequests,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+type retrier struct {
+	policy RetryPolicy
+}
+
+func newRetrier(policy RetryPolicy) *retrier {
+	if policy.MaxAttempts < 2 {
+		return nil
+	}
+	if policy.InitialBackoff <= 0 {
+		policy.InitialBackoff = 200 * time.Millisecond
+	}
+	if policy.MaxBackoff <= 0 {
+		policy.MaxBackoff = 10 * time.Second
+	}
+	if policy.Jitter <= 0 || policy.Jitter > 1 {
+		policy.Jitter = 0.2
+	}
+	if len(policy.RetryableStatusCodes) == 0 {
+		policy.RetryableStatusCodes = defaultRetryableStatusCodes
+	}
+	return &retrier{policy: policy}
+}
+
+// SetRetryPolicy enables retries of this client's failed searches, replacing any previous
+// policy. A policy with MaxAttempts < 2 disables them.
+func (c *Client) SetRetryPolicy(policy RetryPolicy) {
+	c.retrier = newRetrier(policy)
+}
+
+// do calls send until it succeeds, fails with a non-retryable error or the attempts are
+// used up, returning the last error. A nil retrier calls send once.
+func (r *retrier) do(ctx context.Context, logger *zap.Logger, index string, send func() ([]byte, error)) ([]byte, error) {
+	if r == nil {
+		return send()
+	}
+	for attempt := 1; ; attempt++ {
+		b, err := send()
+		if err == nil || attempt >= r.policy.MaxAttempts || !r.retryable(ctx, err) {
+			return b, err
+		}
+		delay := r.backoff(err, attempt)
+		logAt(ctx, logger, zapcore.DebugLevel, "retrying search",
+			zap.String("index", index), zap.Int("attempt", attempt), zap.Duration("delay", delay), zap.Error(err))
+		timer := time.NewTimer(delay)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, err
+		case <-timer.C:
+		}
+	}
+}
+
+func (r *retrier) retryable(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	if IsTimeoutErr(err) {
+		return true // Only the attempt timed out, see SetSearchTimeout
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var statusErr *responseStatusError
+	if !errors.As(err, &statusErr) {
+		return true // Transport failure: connection refused or reset, timeout, etc.
+	}
+	return slices.Contains(r.policy.RetryableStatusCodes, statusErr.statusCode)
+}
+
+// backoff returns the delay before the retry following attempt (1-based).
+func (r *retrier) backoff(err error, attempt int) time.Duration {
+	delay := r.policy.InitialBackoff
+	for i := 1; i < attempt && delay < r.policy.MaxBackoff; i++ {
+		delay *= 2
+	}
+	delay = min(delay, r.policy.MaxBackoff)
+	jitter := time.Duration(r.policy.Jitter * float64(delay))
+	if jitter > 0 {
+		delay = delay - jitter + time.Duration(rand.Int63n(int64(2*jitter)))
+	}
+	var backpressure *BackpressureError
+	if errors.As(err, &backpressure) && backpressure.RetryAfter > delay {
+		delay = min(backpressure.RetryAfter, r.policy.MaxBackoff)
+	}
+	return delay
+}
+
+// responseStatusError attaches the HTTP status of a failed response to its error without
+// changing its message.
+type responseStatusError struct {
+	statusCode int
+	err        error
+}
+
+func (e *responseStatusError) Error() string {
+	return e.err.Error()
+}
+
+func (e *responseStatusError) Unwrap() error {
+	return e.err
+}
@@ -0,0 +1,165 @@
+package opengovernance
+
+import (
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+)
+
+// Script is a painless script. Values are bound through Params, never formatted into
+// Source, so they cannot change what the script does:
+//
+//	NewScript("(doc['description.NotAfter'].value.toInstant().toEpochMilli() - params.now) / 86400000L",
+//		map[string]any{"now": time.Now().UnixMilli()})
+type Script struct {
+	Source string
+	Params map[string]any
+}
+
+func NewScript(source string, params map[string]any) Script {
+	return Script{Source: source, Params: params}
+}
+
+var scriptParamPattern = regexp.MustCompile(`params\.([A-Za-z_][A-Za-z0-9_]*)|params\[\s*['"]([^'"]+)['"]\s*\]`)
+
+// Validate checks that every param the source refers to is bound and every bound param
+// is used.
+func (s Script) Validate() error {
+	if s.Source == "" {
+		return fmt.Errorf("script: source is empty")
+	}
+	used := make(map[string]bool)
+	for _, match := range scriptParamPattern.FindAllStringSubmatch(s.Source, -1) {
+		name := match[1]
+		if name == "" {
+			name = match[2]
+		}
+		if name == "_source" || name == "_agg" || name == "_aggs" {
+			// Provided by ES to scripted metric scripts
+			continue
+		}
+		if _, ok := s.Params[name]; !ok {
+			return fmt.Errorf("script: no value bound for params.%s", name)
+		}
+		used[name] = true
+	}
+	var unused []string
+	for name := range s.Params {
+		if !used[name] {
+			unused = append(unused, name)
+		}
+	}
+	if len(unused) > 0 {
+		sort.Strings(unused)
+		return fmt.Errorf("script: unused params %v", unused)
+	}
+	return nil
+}
+
+func (s Script) MarshalJSON() ([]byte, error) {
+	if err := s.Validate(); err != nil {
+		return nil, err
+	}
+	script := map[string]any{
+		"lang":   "painless",
+		"source": s.Source,
+	}
+	if len(s.Params) > 0 {
+		script["params"] = s.Params
+	}
+	return json.Marshal(script)
+}
+
+// RuntimeField is a field computed by a script at search time, which the query, sort and
+// aggregations can use like a mapped field. See QueryBuilder.RuntimeField.
+type RuntimeField struct {
+	Type   string `json:"type"`
+	Script Script `json:"script"`
+}
+
+// ScriptField is a value computed by a script for each hit. See QueryBuilder.ScriptField.
+type ScriptField struct {
+	Script Script `json:"script"`
+}
+
+// Runtime field types accepted by QueryBuilder.RuntimeField.
+var runtimeFieldTypes = map[string]bool{
+	"boolean":   true,
+	"date":      true,
+	"double":    true,
+	"geo_point": true,
+	"ip":        true,
+	"keyword":   true,
+	"long":      true,
+}
+
+// ScriptedMetricAgg computes a metric with scripts run on each shard and combined, for
+// values no built-in aggregation provides. Decode its result with
+// AggregationResults.ScriptedMetric.
+type ScriptedMetricAgg struct {
+	initScript    string
+	mapScript     string
+	combineScript string
+	reduceScript  string
+	params        map[string]any
+}
+
+// NewScriptedMetricAgg returns a scripted metric running the painless mapScript per
+// document into state, combineScript per shard and reduceScript over the shards' results
+// in states.
+func NewScriptedMetricAgg(mapScript, combineScript, reduceScript string) ScriptedMetricAgg {
+	return ScriptedMetricAgg{
+		mapScript:     mapScript,
+		combineScript: combineScript,
+		reduceScript:  reduceScript,
+	}
+}
+
+// InitScript sets the script that initializes state before the first document.
+func (a ScriptedMetricAgg) InitScript(script string) ScriptedMetricAgg {
+	a.initScript = script
+	return a
+}
+
+// Params binds the params of the init, map and combine scripts.
+func (a ScriptedMetricAgg) Params(params map[string]any) ScriptedMetricAgg {
+	a.params = params
+	return a
+}
+
+func (a ScriptedMetricAgg) MarshalJSON() ([]byte, error) {
+	// Params are shared by the scripts, so they are validated together
+	shared := Script{Source: a.initScript + "\n" + a.mapScript + "\n" + a.combineScript, Params: a.params}
+	if err := shared.Validate(); err != nil {
+		return nil, err
+	}
+	body := map[string]any{
+		"map_script":     a.mapScript,
+		"combine_script": a.combineScript,
+		"reduce_script":  a.reduceScript,
+	}
+	if a.initScript != "" {
+		body["init_script"] = a.initScript
+	}
+	if len(a.params) > 0 {
+		body["params"] = a.params
+	}
+	return json.Marshal(map[string]any{"scripted_metric": body})
+}
+
+func (a ScriptedMetricAgg) IsAggregation() {}
+
+// ScriptedMetric decodes the value of the scripted metric aggregation name into v.
+func (r AggregationResults) ScriptedMetric(name string, v any) error {
+	var result struct {
+		Value json.RawMessage `json:"value"`
+	}
+	if err := r.decode(name, &result); err != nil {
+		return err
+	}
+	if err := json.Unmarshal(result.Value, v); err != nil {
+		return fmt.Errorf("aggregation %s: %w", name, err)
+	}
+	return nil
+}
@@ -0,0 +1,60 @@
+package opengovernance_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	opengovernance "github.com/opengovern/og-util/pkg/opengovernance-es-sdk"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQueryBuilderScripts(t *testing.T) {
+	require := require.New(t)
+
+	daysLeft := opengovernance.NewScript(
+		"emit((doc['description.NotAfter'].value.toInstant().toEpochMilli() - params.now) / 86400000L)",
+		map[string]any{"now": 1700000000000})
+	query, err := opengovernance.NewQueryBuilder().
+		RuntimeField("days_until_expiry", "long", daysLeft).
+		ScriptField("name_upper", opengovernance.NewScript("doc['name'].value.toUpperCase()", nil)).
+		Filters(opengovernance.NewRangeFilter("days_until_expiry", "", "", "30", "")).
+		Build()
+	require.NoError(err)
+	require.JSONEq(`{
+		"query": {"bool": {"filter": [{"range": {"days_until_expiry": {"lt": "30"}}}]}},
+		"runtime_mappings": {"days_until_expiry": {"type": "long", "script": {
+			"lang": "painless",
+			"source": "emit((doc['description.NotAfter'].value.toInstant().toEpochMilli() - params.now) / 86400000L)",
+			"params": {"now": 1700000000000}
+		}}},
+		"script_fields": {"name_upper": {"script": {"lang": "painless", "source": "doc['name'].value.toUpperCase()"}}}
+	}`, query)
+
+	_, err = opengovernance.NewQueryBuilder().
+		ScriptField("x", opengovernance.NewScript("params.a + params['b']", map[string]any{"a": 1})).Build()
+	require.ErrorContains(err, "no value bound for params.b")
+	_, err = opengovernance.NewQueryBuilder().
+		ScriptField("x", opengovernance.NewScript("1", map[string]any{"a": 1})).Build()
+	require.ErrorContains(err, "unused params [a]")
+	_, err = opengovernance.NewQueryBuilder().RuntimeField("x", "text", daysLeft).Build()
+	require.ErrorContains(err, `invalid runtime field type "text"`)
+
+	agg := opengovernance.NewScriptedMetricAgg(
+		"state.total += doc['size'].value * params.factor", "return state.total", "double t = 0; for (s in states) { t += s } return t").
+		InitScript("state.total = 0").
+		Params(map[string]any{"factor": 2})
+	b, err := json.Marshal(agg)
+	require.NoError(err)
+	require.JSONEq(`{"scripted_metric": {
+		"init_script": "state.total = 0",
+		"map_script": "state.total += doc['size'].value * params.factor",
+		"combine_script": "return state.total",
+		"reduce_script": "double t = 0; for (s in states) { t += s } return t",
+		"params": {"factor": 2}
+	}}`, string(b))
+
+	var total float64
+	results := opengovernance.AggregationResults{"total": json.RawMessage(`{"value": 84.5}`)}
+	require.NoError(results.ScriptedMetric("total", &total))
+	require.Equal(84.5, total)
+}
@@ -0,0 +1,146 @@
+package opengovernance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/opensearch-project/opensearch-go/v2/opensearchapi"
+	"github.com/opensearch-project/opensearch-go/v2/opensearchutil"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// PaginationMode selects how BaseESPaginator pages through more than one page of results.
+type PaginationMode string
+
+const (
+	// PaginationModePIT uses a point in time with search_after (the default).
+	PaginationModePIT PaginationMode = "pit"
+	// PaginationModeScroll uses the classic scroll API, available on every OpenSearch and
+	// Elasticsearch 7.x version.
+	PaginationModeScroll PaginationMode = "scroll"
+	// PaginationModeAuto uses a point in time and falls back to scroll if the cluster
+	// cannot open one, e.g. Elasticsearch before 7.10.
+	PaginationModeAuto PaginationMode = "auto"
+)
+
+const scrollCleanupTimeout = 10 * time.Second
+
+// WithPaginationMode sets how the paginator pages through results. It must be called
+// before the first Search.
+func (p *BaseESPaginator) WithPaginationMode(mode PaginationMode) *BaseESPaginator {
+	p.mode = mode
+	return p
+}
+
+// scrolling reports whether the paginator pages with the scroll API.
+func (p *BaseESPaginator) scrolling() bool {
+	if p.scrollID != "" {
+		return true
+	}
+	return p.limit > p.pageSize && (p.mode == PaginationModeScroll || p.scrollFallback)
+}
+
+type scrollResponse struct {
+	ScrollID string `json:"_scroll_id"`
+}
+
+// searchScroll fetches the next page with the scroll API, opening the scroll context on
+// the first call. The page is decoded into response like a search_after page.
+func (p *BaseESPaginator) searchScroll(ctx context.Context, response any) (err error) {
+	ctx, done := requestTimeout(ctx, "search", p.searchTimeout)
+	defer func() { err = done(err) }()
+
+	keepAlive := p.keepAlive()
+	var res *opensearchapi.Response
+	if p.scrollID == "" {
+		sa := SearchRequest{
+			Size:   &p.pageSize,
+			Query:  p.query,
+			Sort:   p.sort,
+			Source: p.sourceIncludes,
+			Slice:  p.slice,
+		}
+		res, err = p.client.Search(
+			p.client.Search.WithContext(ctx),
+			p.client.Search.WithIndex(p.index),
+			p.client.Search.WithBody(opensearchutil.NewJSONReader(sa)),
+			p.client.Search.WithScroll(keepAlive),
+			p.client.Search.WithTrackTotalHits(false),
+		)
+	} else {
+		res, err = p.client.Scroll(
+			p.client.Scroll.WithContext(ctx),
+			p.client.Scroll.WithBody(opensearchutil.NewJSONReader(map[string]string{
+				"scroll":    formatKeepAlive(keepAlive),
+				"scroll_id": p.scrollID,
+			})),
+		)
+	}
+	defer CloseSafe(res)
+	if err != nil {
+		return p.queryError(err)
+	} else if err := CheckError(res); err != nil {
+		if IsIndexNotFoundErr(err) {
+			return nil
+		}
+		return p.queryError(err)
+	}
+
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+	p.lastPageBytes = int64(len(b))
+	var scroll scrollResponse
+	if err := json.Unmarshal(b, &scroll); err != nil {
+		return fmt.Errorf("unmarshal response: %w", err)
+	}
+	p.scrollID = scroll.ScrollID
+
+	b, err = applyResponseHooks(ctx, p.responseHooks, p.index, b)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(b, response); err != nil {
+		return fmt.Errorf("unmarshal response: %w", err)
+	}
+	return nil
+}
+
+// clearScroll releases the scroll context, if any.
+func (p *BaseESPaginator) clearScroll(ctx context.Context) error {
+	if p.scrollID == "" {
+		return nil
+	}
+	res, err := p.client.ClearScroll(
+		p.client.ClearScroll.WithContext(ctx),
+		p.client.ClearScroll.WithBody(opensearchutil.NewJSONReader(map[string][]string{
+			"scroll_id": {p.scrollID},
+		})),
+	)
+	defer CloseSafe(res)
+	if err != nil {
+		return err
+	} else if err := CheckError(res); err != nil && res.StatusCode != 404 { // 404: already expired
+		return err
+	}
+	p.scrollID = ""
+	return nil
+}
+
+// clearScrollWhenDone releases the scroll context as soon as the last page was read, so
+// it doesn't linger until its keep-alive expires if the caller never calls Deallocate.
+func (p *BaseESPaginator) clearScrollWhenDone() {
+	if !p.done || p.scrollID == "" {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), scrollCleanupTimeout)
+	defer cancel()
+	if err := p.clearScroll(ctx); err != nil {
+		p.log(ctx, zapcore.WarnLevel, "clear scroll failed", zap.Error(err))
+	}
+}
@@ -1,6 +1,7 @@
 package opengovernance
 
 import (
+	"bytes"
 	"context"
 	"encoding/json"
 	"fmt"
@@ -8,6 +9,8 @@ import (
 	"strings"
 
 	"github.com/opensearch-project/opensearch-go/v2/opensearchapi"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
 )
 
 type PointInTime struct {
@@ -21,6 +24,18 @@ type SearchRequest struct {
 	PIT         *PointInTime             `json:"pit,omitempty"`
 	Sort        []map[string]interface{} `json:"sort,omitempty"`
 	SearchAfter []interface{}            `json:"search_after,omitempty"`
+	Source      []string                 `json:"_source,omitempty"`
+	Aggs        map[string]Aggregation   `json:"aggs,omitempty"`
+	Slice       *SearchSlice             `json:"slice,omitempty"`
+
+	RuntimeMappings map[string]RuntimeField `json:"runtime_mappings,omitempty"`
+	ScriptFields    map[string]ScriptField  `json:"script_fields,omitempty"`
+}
+
+// SearchSlice selects one of Max disjoint slices of a PIT or scroll search.
+type SearchSlice struct {
+	ID  int `json:"id"`
+	Max int `json:"max"`
 }
 
 type SearchTotal struct {
@@ -28,12 +43,16 @@ type SearchTotal struct {
 	Relation string `json:"relation"`
 }
 
+// Deprecated: raw query strings assembled with fmt.Sprintf are open to JSON injection.
+// Use SearchQuery.
 func (c Client) Search(ctx context.Context, index string, query string, response any) error {
-	return c.SearchWithTrackTotalHits(ctx, index, query, nil, response, false)
+	return c.search(ctx, index, query, nil, response, false)
 }
 
+// Deprecated: raw query strings assembled with fmt.Sprintf are open to JSON injection.
+// Use SearchQueryWithTrackTotalHits.
 func (c Client) SearchWithFilterPath(ctx context.Context, index string, query string, filterPath []string, response any) error {
-	return c.SearchWithTrackTotalHits(ctx, index, query, filterPath, response, false)
+	return c.search(ctx, index, query, filterPath, response, false)
 }
 
 type CountResponse struct {
@@ -41,10 +60,57 @@ type CountResponse struct {
 }
 
 func (c Client) Count(ctx context.Context, index string) (int64, error) {
+	return c.count(ctx, index, nil)
+}
+
+// CountWithQuery counts the documents of index matching all filters.
+func (c Client) CountWithQuery(ctx context.Context, index string, filters []BoolFilter) (int64, error) {
+	body, err := json.Marshal(map[string]any{
+		"query": filterQuery(filters),
+	})
+	if err != nil {
+		return 0, fmt.Errorf("marshal query: %w", err)
+	}
+	return c.count(ctx, index, body)
+}
+
+// Cardinality approximates the number of distinct values of field in the documents of
+// index matching all filters. Counts below 3000 are expected to be exact.
+func (c Client) Cardinality(ctx context.Context, index string, field string, filters []BoolFilter) (int64, error) {
+	query := NewQueryBuilder().
+		Filters(filters...).
+		Size(0).
+		Aggregation("cardinality", NewCardinalityAgg(field))
+	var response AggregationResponse
+	if err := c.SearchWithQueryBuilder(ctx, index, query, &response); err != nil {
+		return 0, err
+	}
+	return response.Aggregations.Cardinality("cardinality")
+}
+
+// filterQuery returns a bool query matching all filters, or all documents if there are
+// none.
+func filterQuery(filters []BoolFilter) map[string]any {
+	if len(filters) == 0 {
+		return map[string]any{"match_all": map[string]any{}}
+	}
+	return map[string]any{"bool": map[string]any{"filter": filters}}
+}
+
+func (c Client) count(ctx context.Context, index string, body []byte) (_ int64, err error) {
+	ctx, done := requestTimeout(ctx, "search", c.searchTimeout)
+	defer func() { err = done(err) }()
+
 	opts := []func(count *opensearchapi.CountRequest){
 		c.es.Count.WithContext(ctx),
 		c.es.Count.WithIndex(index),
 	}
+	if body != nil {
+		opts = append(opts, c.es.Count.WithBody(bytes.NewReader(body)))
+	}
+	if c.strictIndices {
+		opts = append(opts, c.es.Count.WithAllowNoIndices(false))
+	}
 
 	res, err := c.es.Count(opts...)
 	defer CloseSafe(res)
@@ -52,7 +118,7 @@ func (c Client) Count(ctx context.Context, index string) (int64, error) {
 		return 0, err
 	} else if err := CheckError(res); err != nil {
 		if IsIndexNotFoundErr(err) {
-			return 0, nil
+			return 0, c.indexNotFound(index, err)
 		}
 		return 0, err
 	}
@@ -76,8 +142,51 @@ func removeControlChars(s string) string {
 	return s
 }
 
+// Deprecated: raw query strings assembled with fmt.Sprintf are open to JSON injection.
+// Use SearchQueryWithTrackTotalHits.
 func (c Client) SearchWithTrackTotalHits(ctx context.Context, index string, query string, filterPath []string, response any, trackTotalHits any) error {
+	return c.search(ctx, index, query, filterPath, response, trackTotalHits)
+}
+
+func (c Client) search(ctx context.Context, index string, query string, filterPath []string, response any, trackTotalHits any) error {
+	release, err := c.limiter.acquire(ctx, index)
+	if err != nil {
+		return err
+	}
+	defer release()
+
 	query = removeControlChars(query)
+	key := staleCacheKey(index, query, filterPath, trackTotalHits)
+	b, err := instrumentSearch(ctx, c.instrumentation, index, query, func() ([]byte, error) {
+		return c.staleCache.search(ctx, c.logger, key, func() ([]byte, error) {
+			return c.retrier.do(ctx, c.logger, index, func() ([]byte, error) {
+				return c.hedger.do(ctx, func(ctx context.Context) ([]byte, error) {
+					return c.sendSearch(ctx, index, query, filterPath, trackTotalHits)
+				})
+			})
+		})
+	})
+	if err != nil {
+		return err
+	} else if b == nil { // Missing index swallowed by sendSearch, response left as is
+		return nil
+	}
+	b, err = applyResponseHooks(ctx, c.responseHooks, index, b)
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(b, response); err != nil {
+		return fmt.Errorf("unmarshal response: %w", err)
+	}
+	return nil
+}
+
+// sendSearch sends one search request and returns the raw response body.
+func (c Client) sendSearch(ctx context.Context, index string, query string, filterPath []string, trackTotalHits any) (_ []byte, err error) {
+	ctx, done := requestTimeout(ctx, "search", c.searchTimeout)
+	defer func() { err = done(err) }()
+
 	opts := []func(*opensearchapi.SearchRequest){
 		c.es.Search.WithContext(ctx),
 		c.es.Search.WithBody(strings.NewReader(query)),
@@ -85,6 +194,9 @@ func (c Client) SearchWithTrackTotalHits(ctx context.Context, index string, quer
 		c.es.Search.WithIndex(index),
 		c.es.Search.WithFilterPath(filterPath...),
 	}
+	if c.strictIndices {
+		opts = append(opts, c.es.Search.WithAllowNoIndices(false))
+	}
 
 	res, err := c.es.Search(opts...)
 	defer CloseSafe(res)
@@ -93,33 +205,35 @@ func (c Client) SearchWithTrackTotalHits(ctx context.Context, index string, quer
 		if res != nil {
 			b, _ = io.ReadAll(res.Body)
 		}
-		fmt.Printf("failure while querying es: %v\n%s\n", err, string(b))
-		return err
+		if ctx.Err() == nil { // The slower request of a hedged pair is cancelled
+			logAt(ctx, c.logger, zapcore.WarnLevel, "failure while querying es",
+				zap.Error(err), zap.String("index", index), zap.String("response", string(b)))
+		}
+		return nil, err
 	} else if err := CheckError(res); err != nil {
 		if IsIndexNotFoundErr(err) {
-			return nil
+			return nil, c.indexNotFound(index, err)
 		}
 		var b []byte
 		if res != nil {
 			b, _ = io.ReadAll(res.Body)
 		}
-		fmt.Printf("failure while querying es: %v\n%s\n", err, string(b))
-		return err
+		logAt(ctx, c.logger, zapcore.WarnLevel, "failure while querying es",
+			zap.Error(err), zap.String("index", index), zap.String("response", string(b)))
+		return nil, &responseStatusError{statusCode: res.StatusCode, err: err}
 	}
 
 	b, err := io.ReadAll(res.Body)
-
 	if err != nil {
-		return fmt.Errorf("read response: %w", err)
+		return nil, fmt.Errorf("read response: %w", err)
 	}
-
-	if err := json.Unmarshal(b, response); err != nil {
-		return fmt.Errorf("unmarshal response: %w", err)
-	}
-	return nil
+	return b, nil
 }
 
-func (c Client) GetByID(ctx context.Context, index string, id string, response any) error {
+func (c Client) GetByID(ctx context.Context, index string, id string, response any) (err error) {
+	ctx, done := requestTimeout(ctx, "search", c.searchTimeout)
+	defer func() { err = done(err) }()
+
 	opts := []func(request *opensearchapi.GetRequest){
 		c.es.Get.WithContext(ctx),
 	}
@@ -130,7 +244,7 @@ func (c Client) GetByID(ctx context.Context, index string, id string, response a
 		return err
 	} else if err := CheckError(res); err != nil {
 		if IsIndexNotFoundErr(err) {
-			return nil
+			return c.indexNotFound(index, err)
 		}
 		return err
 	}
@@ -139,6 +253,10 @@ func (c Client) GetByID(ctx context.Context, index string, id string, response a
 	if err != nil {
 		return fmt.Errorf("read response: %w", err)
 	}
+	b, err = applyResponseHooks(ctx, c.responseHooks, index, b)
+	if err != nil {
+		return err
+	}
 
 	if err := json.Unmarshal(b, response); err != nil {
 		return fmt.Errorf("unmarshal response: %w", err)
@@ -0,0 +1,222 @@
+package opengovernance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+// SearchEvent describes a finished search.
+type SearchEvent struct {
+	Index    string
+	Query    string
+	Took     time.Duration // Time the cluster spent, from the response's took
+	Duration time.Duration // Time the client waited, including retries and queueing
+	Hits     int64         // Total hits, or the hits returned if the total is not tracked
+	Bytes    int           // Size of the response body
+	Err      error
+}
+
+// SearchInstrumentation observes the searches of a client, e.g. to find which table scans
+// load the cluster. OnSearchEnd is called once per search, after retries.
+type SearchInstrumentation interface {
+	OnSearchStart(ctx context.Context, index string)
+	OnSearchEnd(ctx context.Context, event SearchEvent)
+}
+
+// AddSearchInstrumentation registers instrumentation for the searches of Search*,
+// SearchWithQueryBuilder and Snapshot. Copies of the Client made afterwards keep it.
+func (c *Client) AddSearchInstrumentation(instrumentation SearchInstrumentation) {
+	c.instrumentation = append(c.instrumentation, instrumentation)
+}
+
+// instrumentSearch runs send between the instrumentation's start and end calls.
+func instrumentSearch(ctx context.Context, instrumentation []SearchInstrumentation, index, query string, send func() ([]byte, error)) ([]byte, error) {
+	if len(instrumentation) == 0 {
+		return send()
+	}
+	for _, i := range instrumentation {
+		i.OnSearchStart(ctx, index)
+	}
+	start := time.Now()
+	b, err := send()
+	event := SearchEvent{
+		Index:    index,
+		Query:    query,
+		Duration: time.Since(start),
+		Bytes:    len(b),
+		Err:      err,
+	}
+	if err == nil {
+		event.Took, event.Hits = searchStats(b)
+	}
+	for _, i := range instrumentation {
+		i.OnSearchEnd(ctx, event)
+	}
+	return b, err
+}
+
+// searchStats reads took and the hit count of a search response.
+func searchStats(b []byte) (time.Duration, int64) {
+	var response struct {
+		Took int64 `json:"took"`
+		Hits struct {
+			Total json.RawMessage   `json:"total"`
+			Hits  []json.RawMessage `json:"hits"`
+		} `json:"hits"`
+	}
+	if json.Unmarshal(b, &response) != nil {
+		return 0, 0
+	}
+	took := time.Duration(response.Took) * time.Millisecond
+	var total struct {
+		Value int64 `json:"value"`
+	}
+	if json.Unmarshal(response.Hits.Total, &total) == nil && total.Value > 0 {
+		return took, total.Value
+	}
+	var legacyTotal int64 // A number before ES 7
+	if json.Unmarshal(response.Hits.Total, &legacyTotal) == nil && legacyTotal > 0 {
+		return took, legacyTotal
+	}
+	return took, int64(len(response.Hits.Hits))
+}
+
+// SlowLog logs searches slower than a threshold with their query.
+type SlowLog struct {
+	logger    *zap.Logger
+	threshold time.Duration
+}
+
+// NewSlowLog logs searches taking longer than threshold at warn level to logger, or to
+// the logger of the search's context if nil.
+func NewSlowLog(logger *zap.Logger, threshold time.Duration) *SlowLog {
+	return &SlowLog{logger: logger, threshold: threshold}
+}
+
+func (l *SlowLog) OnSearchStart(context.Context, string) {}
+
+func (l *SlowLog) OnSearchEnd(ctx context.Context, event SearchEvent) {
+	if event.Duration < l.threshold {
+		return
+	}
+	logAt(ctx, l.logger, zapcore.WarnLevel, "slow search",
+		zap.String("index", event.Index),
+		zap.Duration("duration", event.Duration),
+		zap.Duration("took", event.Took),
+		zap.Int64("hits", event.Hits),
+		zap.Int("bytes", event.Bytes),
+		zap.Error(event.Err),
+		zap.String("query", event.Query))
+}
+
+// SearchMetrics is a Prometheus collector of search latency, hits and response sizes per
+// index. Register it and add it to the clients to measure:
+//
+//	metrics := opengovernance.NewSearchMetrics("opengovernance")
+//	prometheus.MustRegister(metrics)
+//	client.AddSearchInstrumentation(metrics)
+type SearchMetrics struct {
+	inFlight *prometheus.GaugeVec
+	duration *prometheus.HistogramVec
+	took     *prometheus.HistogramVec
+	hits     *prometheus.CounterVec
+	bytes    *prometheus.CounterVec
+}
+
+func NewSearchMetrics(namespace string) *SearchMetrics {
+	buckets := []float64{
+		0.01, // 10ms
+		0.05,
+		0.1, // 100ms
+		0.25,
+		0.5,
+		1, // 1s
+		2.5,
+		5,
+		10, // 10s
+		30, // 30s
+	}
+	return &SearchMetrics{
+		inFlight: prometheus.NewGaugeVec(prometheus.GaugeOpts{
+			Namespace: namespace,
+			Subsystem: "es",
+			Name:      "searches_in_flight",
+			Help:      "Number of searches waiting for a response",
+		}, []string{"index"}),
+		duration: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "es",
+			Name:      "search_duration_seconds",
+			Help:      "Time the client waited for searches, including retries",
+			Buckets:   buckets,
+		}, []string{"index", "status"}),
+		took: prometheus.NewHistogramVec(prometheus.HistogramOpts{
+			Namespace: namespace,
+			Subsystem: "es",
+			Name:      "search_took_seconds",
+			Help:      "Time the cluster spent on successful searches",
+			Buckets:   buckets,
+		}, []string{"index"}),
+		hits: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "es",
+			Name:      "search_hits_total",
+			Help:      "Number of hits matched by successful searches",
+		}, []string{"index"}),
+		bytes: prometheus.NewCounterVec(prometheus.CounterOpts{
+			Namespace: namespace,
+			Subsystem: "es",
+			Name:      "search_response_bytes_total",
+			Help:      "Size of search responses",
+		}, []string{"index"}),
+	}
+}
+
+func (m *SearchMetrics) OnSearchStart(_ context.Context, index string) {
+	m.inFlight.WithLabelValues(index).Inc()
+}
+
+func (m *SearchMetrics) OnSearchEnd(_ context.Context, event SearchEvent) {
+	m.inFlight.WithLabelValues(event.Index).Dec()
+	m.duration.WithLabelValues(event.Index, searchStatus(event.Err)).Observe(event.Duration.Seconds())
+	m.bytes.WithLabelValues(event.Index).Add(float64(event.Bytes))
+	if event.Err == nil {
+		m.took.WithLabelValues(event.Index).Observe(event.Took.Seconds())
+		m.hits.WithLabelValues(event.Index).Add(float64(event.Hits))
+	}
+}
+
+func searchStatus(err error) string {
+	switch {
+	case err == nil:
+		return "ok"
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return "cancelled"
+	case IsIndexNotMatchedErr(err):
+		return "index_not_matched"
+	default:
+		return "error"
+	}
+}
+
+func (m *SearchMetrics) Describe(ch chan<- *prometheus.Desc) {
+	m.inFlight.Describe(ch)
+	m.duration.Describe(ch)
+	m.took.Describe(ch)
+	m.hits.Describe(ch)
+	m.bytes.Describe(ch)
+}
+
+func (m *SearchMetrics) Collect(ch chan<- prometheus.Metric) {
+	m.inFlight.Collect(ch)
+	m.duration.Collect(ch)
+	m.took.Collect(ch)
+	m.hits.Collect(ch)
+	m.bytes.Collect(ch)
+}
@@ -0,0 +1,77 @@
+package opengovernance_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+
+	opengovernance "github.com/opengovern/og-util/pkg/opengovernance-es-sdk"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+type recordedSearches struct {
+	mu      sync.Mutex
+	started []string
+	ended   []opengovernance.SearchEvent
+}
+
+func (r *recordedSearches) OnSearchStart(_ context.Context, index string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.started = append(r.started, index)
+}
+
+func (r *recordedSearches) OnSearchEnd(_ context.Context, event opengovernance.SearchEvent) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.ended = append(r.ended, event)
+}
+
+func TestSearchInstrumentation(t *testing.T) {
+	r := require.New(t)
+
+	const body = `{"took":42,"hits":{"total":{"value":7,"relation":"eq"},"hits":[{"_id":"a"}]}}`
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(body))
+	}))
+	defer server.Close()
+
+	username, password := "", ""
+	client, err := opengovernance.NewClient(opengovernance.ClientConfig{
+		Addresses: []string{server.URL},
+		Username:  &username,
+		Password:  &password,
+	})
+	r.NoError(err)
+
+	recorded := &recordedSearches{}
+	metrics := opengovernance.NewSearchMetrics("test")
+	client.AddSearchInstrumentation(recorded)
+	client.AddSearchInstrumentation(metrics)
+
+	var response map[string]any
+	r.NoError(client.SearchWithQueryBuilder(context.Background(), "inventory",
+		opengovernance.NewQueryBuilder().Size(1), &response))
+
+	r.Equal([]string{"inventory"}, recorded.started)
+	r.Len(recorded.ended, 1)
+	event := recorded.ended[0]
+	r.Equal("inventory", event.Index)
+	r.JSONEq(`{"size":1}`, event.Query)
+	r.Equal(int64(42), event.Took.Milliseconds())
+	r.Equal(int64(7), event.Hits)
+	r.Equal(len(body), event.Bytes)
+	r.NoError(event.Err)
+
+	r.Equal(5, testutil.CollectAndCount(metrics))
+	r.NoError(testutil.CollectAndCompare(metrics, strings.NewReader(`
+# HELP test_es_search_hits_total Number of hits matched by successful searches
+# TYPE test_es_search_hits_total counter
+test_es_search_hits_total{index="inventory"} 7
+`), "test_es_search_hits_total"))
+}
@@ -0,0 +1,105 @@
+package opengovernance
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/opensearch-project/opensearch-go/v2/opensearchapi"
+	"github.com/opensearch-project/opensearch-go/v2/opensearchutil"
+)
+
+// PutSearchTemplate stores a mustache search template under name, creating or replacing
+// it, so that canned queries are sent once and then run by name with
+// ExecuteSearchTemplate:
+//
+//	PutSearchTemplate(ctx, "resources_by_account", `{"query": {"term": {"account_id": "{{account}}"}}}`)
+//
+// Unlike BuildQuery templates, the template is rendered by the cluster with mustache
+// semantics: {{name}} is replaced by the text of the param and {{#toJson}}name{{/toJson}}
+// by its JSON encoding.
+func (c Client) PutSearchTemplate(ctx context.Context, name string, template string) error {
+	if name == "" {
+		return errors.New("search template name is required")
+	} else if template == "" {
+		return fmt.Errorf("search template %s is empty", name)
+	}
+	body := map[string]any{
+		"script": map[string]any{
+			"lang":   "mustache",
+			"source": template,
+		},
+	}
+	res, err := c.es.PutScript(name, opensearchutil.NewJSONReader(body), c.es.PutScript.WithContext(ctx))
+	defer CloseSafe(res)
+	if err != nil {
+		return err
+	} else if err := CheckError(res); err != nil {
+		return fmt.Errorf("put search template %s: %w", name, err)
+	}
+	return nil
+}
+
+// ExecuteSearchTemplate runs the search template stored as name by PutSearchTemplate
+// against index, rendered with params, and unmarshals the search response into response.
+func (c Client) ExecuteSearchTemplate(ctx context.Context, index string, name string, params QueryParams, response any) (err error) {
+	if name == "" {
+		return errors.New("search template name is required")
+	}
+	release, err := c.limiter.acquire(ctx, index)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	ctx, done := requestTimeout(ctx, "search", c.searchTimeout)
+	defer func() { err = done(err) }()
+
+	body, err := json.Marshal(map[string]any{"id": name, "params": params})
+	if err != nil {
+		return fmt.Errorf("search template %s: encode params: %w", name, err)
+	}
+	opts := []func(*opensearchapi.SearchTemplateRequest){
+		c.es.SearchTemplate.WithContext(ctx),
+		c.es.SearchTemplate.WithIndex(index),
+	}
+	if c.strictIndices {
+		opts = append(opts, c.es.SearchTemplate.WithAllowNoIndices(false))
+	}
+
+	res, err := c.es.SearchTemplate(bytes.NewReader(body), opts...)
+	defer CloseSafe(res)
+	if err != nil {
+		return err
+	} else if err := CheckError(res); err != nil {
+		if IsIndexNotFoundErr(err) {
+			return c.indexNotFound(index, err)
+		}
+		return fmt.Errorf("search template %s: %w", name, err)
+	}
+
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		return fmt.Errorf("read response: %w", err)
+	}
+	b, err = applyResponseHooks(ctx, c.responseHooks, index, b)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(b, response); err != nil {
+		return fmt.Errorf("unmarshal response: %w", err)
+	}
+	return nil
+}
+
+// ExecuteSearchTemplate runs the stored search template against the tenant's index.
+func (t TenantClient) ExecuteSearchTemplate(ctx context.Context, index string, name string, params QueryParams, response any) error {
+	index, err := t.ResolveIndex(ctx, index)
+	if err != nil {
+		return err
+	}
+	return t.client.ExecuteSearchTemplate(ctx, index, name, params, response)
+}
@@ -0,0 +1,90 @@
+package opengovernance_test
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	opengovernance "github.com/opengovern/og-util/pkg/opengovernance-es-sdk"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchTemplate(t *testing.T) {
+	r := require.New(t)
+
+	templates := make(map[string]string)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		reader := io.Reader(req.Body)
+		if req.Header.Get("Content-Encoding") == "gzip" {
+			gz, err := gzip.NewReader(req.Body)
+			r.NoError(err)
+			reader = gz
+		}
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case req.Method == http.MethodPut && req.URL.Path == "/_scripts/resources_by_account":
+			var body struct {
+				Script struct {
+					Lang   string `json:"lang"`
+					Source string `json:"source"`
+				} `json:"script"`
+			}
+			r.NoError(json.NewDecoder(reader).Decode(&body))
+			r.Equal("mustache", body.Script.Lang)
+			templates["resources_by_account"] = body.Script.Source
+			w.Write([]byte(`{"acknowledged":true}`))
+		case req.URL.Path == "/inventory/_search/template":
+			var body struct {
+				ID     string         `json:"id"`
+				Params map[string]any `json:"params"`
+			}
+			r.NoError(json.NewDecoder(reader).Decode(&body))
+			if _, ok := templates[body.ID]; !ok {
+				w.WriteHeader(http.StatusNotFound)
+				w.Write([]byte(`{"error":{"type":"resource_not_found_exception","reason":"unable to find script [` + body.ID + `]"},"status":404}`))
+				return
+			}
+			r.Equal(map[string]any{"account": "123"}, body.Params)
+			w.Write([]byte(`{"hits":{"total":{"value":1,"relation":"eq"},"hits":[{"_id":"a","_source":{"account_id":"123"}}]}}`))
+		default:
+			t.Errorf("unexpected request %s %s", req.Method, req.URL.Path)
+			w.WriteHeader(http.StatusBadRequest)
+		}
+	}))
+	defer server.Close()
+
+	username, password := "", ""
+	client, err := opengovernance.NewClient(opengovernance.ClientConfig{
+		Addresses: []string{server.URL},
+		Username:  &username,
+		Password:  &password,
+	})
+	r.NoError(err)
+
+	ctx := context.Background()
+	template := `{"query": {"term": {"account_id": "{{account}}"}}}`
+	r.NoError(client.PutSearchTemplate(ctx, "resources_by_account", template))
+	r.Equal(template, templates["resources_by_account"])
+
+	var response struct {
+		Hits struct {
+			Hits []struct {
+				ID     string `json:"_id"`
+				Source struct {
+					AccountID string `json:"account_id"`
+				} `json:"_source"`
+			} `json:"hits"`
+		} `json:"hits"`
+	}
+	r.NoError(client.ExecuteSearchTemplate(ctx, "inventory", "resources_by_account", opengovernance.QueryParams{"account": "123"}, &response))
+	r.Len(response.Hits.Hits, 1)
+	r.Equal("123", response.Hits.Hits[0].Source.AccountID)
+
+	err = client.ExecuteSearchTemplate(ctx, "inventory", "missing", nil, &response)
+	r.ErrorContains(err, "search template missing")
+	r.ErrorContains(client.PutSearchTemplate(ctx, "", template), "name is required")
+}
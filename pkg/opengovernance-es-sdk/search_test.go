@@ -0,0 +1,48 @@
+package opengovernance_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	opengovernance "github.com/opengovern/og-util/pkg/opengovernance-es-sdk"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchMissingIndex(t *testing.T) {
+	r := require.New(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		w.WriteHeader(http.StatusNotFound)
+		w.Write([]byte(`{"error":{"root_cause":[{"type":"index_not_found_exception","reason":"no such index [missing]"}],"type":"index_not_found_exception","reason":"no such index [missing]"},"status":404}`))
+	}))
+	defer server.Close()
+
+	username, password := "", ""
+	client, err := opengovernance.NewClient(opengovernance.ClientConfig{
+		Addresses: []string{server.URL},
+		Username:  &username,
+		Password:  &password,
+	})
+	r.NoError(err)
+	client.SetStaleCache(opengovernance.StaleCacheOptions{})
+
+	// Swallowed outside strict index mode, leaving the response untouched
+	ctx, stale := opengovernance.WithStaleFallback(context.Background())
+	var response map[string]any
+	r.NoError(client.Search(ctx, "missing", `{"query":{"match_all":{}}}`, &response))
+	r.Nil(response)
+	r.NoError(client.SearchWithTrackTotalHits(ctx, "missing", `{}`, []string{"hits.total"}, &response, true))
+	r.Nil(response)
+	isStale, _, _ := stale.Stale()
+	r.False(isStale)
+
+	client.SetStrictIndices(true)
+	err = client.Search(context.Background(), "missing", `{}`, &response)
+	var notMatched *opengovernance.IndexNotMatchedError
+	r.True(errors.As(err, &notMatched), "unexpected error: %v", err)
+	r.Equal("missing", notMatched.Pattern)
+}
@@ -0,0 +1,353 @@
+package opengovernance
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const defaultScanSlices = 4
+
+// Slices splits the paginator's scan into n disjoint slices that can be paged
+// concurrently, e.g. to export millions of documents in a fraction of the time. It must
+// be called before the first Search. In PIT mode the point in time is opened here and
+// shared by the slices, so keep it alive and delete it through p (see WithAutoKeepAlive
+// and Close); closing a slice only clears its own scroll context. The limit of p applies
+// to each slice; ScanSlices enforces it over all of them.
+func (p *BaseESPaginator) Slices(ctx context.Context, n int) ([]*BaseESPaginator, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("invalid slice count: %d", n)
+	}
+	if p.queried > 0 || p.searchAfter != nil || p.scrollID != "" {
+		return nil, errors.New("paginator was already searched")
+	}
+	if n == 1 || p.limit <= p.pageSize {
+		// A single page needs no PIT or scroll to slice
+		return []*BaseESPaginator{p}, nil
+	}
+
+	if !p.scrolling() {
+		if err := p.CreatePit(ctx); err != nil {
+			if p.mode != PaginationModeAuto {
+				return nil, err
+			}
+			p.log(ctx, zapcore.WarnLevel, "point in time unavailable, falling back to scroll", zap.Error(err))
+			p.scrollFallback = true
+		} else if p.pitID == "" {
+			// The index doesn't exist
+			return []*BaseESPaginator{p}, nil
+		}
+		p.startAutoKeepAlive(ctx)
+	}
+
+	slices := make([]*BaseESPaginator, n)
+	for i := range slices {
+		slice := *p
+		slice.slice = &SearchSlice{ID: i, Max: n}
+		slice.sharedPit = p.pitID != ""
+		slice.keeper = nil
+		slice.autoKeepAlive = nil
+		slices[i] = &slice
+	}
+	return slices, nil
+}
+
+// SliceScanOptions configures ScanSlices.
+type SliceScanOptions struct {
+	Slices  int  // Number of slices (default 4)
+	Workers int  // Slices paged concurrently (default Slices); all of them when Ordered
+	Ordered bool // Merge hits in the paginator's sort order instead of sending pages as they arrive
+}
+
+// SlicePage is a page of hits sent by ScanSlices.
+type SlicePage[T any] struct {
+	Slice int      // Slice the hits come from, or -1 for merged pages
+	Hits  []Hit[T] // At most one page size of hits
+	Err   error    // Set on the last value if the scan failed
+}
+
+// ScanSlices pages through p with opts.Slices slices paged by a pool of workers and sends
+// the hits on the returned channel, which is closed when the scan ends. Without
+// Ordered, pages are sent as they arrive; with it, the slices' hits are merged in the
+// paginator's sort order. p's limit applies to the whole scan. p and its slices are
+// closed when the scan ends. Receive until the channel is closed, or cancel ctx.
+func ScanSlices[T any](ctx context.Context, p *BaseESPaginator, opts SliceScanOptions) <-chan SlicePage[T] {
+	if opts.Slices <= 0 {
+		opts.Slices = defaultScanSlices
+	}
+	if opts.Workers <= 0 || opts.Workers > opts.Slices || opts.Ordered {
+		opts.Workers = opts.Slices
+	}
+	out := make(chan SlicePage[T], opts.Workers)
+	go func() {
+		defer close(out)
+		err := scanSlices(ctx, p, opts, out)
+		closeCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), scrollCleanupTimeout)
+		defer cancel()
+		if closeErr := p.Close(closeCtx); closeErr != nil {
+			p.log(ctx, zapcore.WarnLevel, "close sliced paginator failed", zap.Error(closeErr))
+		}
+		if err != nil {
+			select {
+			case out <- SlicePage[T]{Slice: -1, Err: err}:
+			case <-ctx.Done():
+			}
+		}
+	}()
+	return out
+}
+
+func scanSlices[T any](ctx context.Context, p *BaseESPaginator, opts SliceScanOptions, out chan<- SlicePage[T]) error {
+	slices, err := p.Slices(ctx, opts.Slices)
+	if err != nil {
+		return err
+	}
+	scanCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	defer func() {
+		closeCtx, closeCancel := context.WithTimeout(context.WithoutCancel(ctx), scrollCleanupTimeout)
+		defer closeCancel()
+		for _, slice := range slices {
+			if slice == p {
+				continue
+			}
+			if err := slice.Deallocate(closeCtx); err != nil {
+				p.log(ctx, zapcore.WarnLevel, "close slice failed", zap.Error(err))
+			}
+		}
+	}()
+
+	if opts.Ordered {
+		return mergeSlices(scanCtx, p, slices, out)
+	}
+
+	limit := newSliceLimit(p.limit)
+	work := make(chan int, len(slices))
+	for i := range slices {
+		work <- i
+	}
+	close(work)
+	errs := make([]error, len(slices))
+	var wg sync.WaitGroup
+	for w := 0; w < opts.Workers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range work {
+				err := pageSlice(scanCtx, slices[i], func(hits []Hit[T]) bool {
+					hits, more := take(limit, hits)
+					if len(hits) > 0 {
+						select {
+						case out <- SlicePage[T]{Slice: slices[i].sliceID(), Hits: hits}:
+						case <-scanCtx.Done():
+							return false
+						}
+					}
+					if !more {
+						cancel()
+					}
+					return more
+				})
+				// Failures caused by stopping the other slices are not reported
+				if err != nil && scanCtx.Err() == nil {
+					errs[i] = fmt.Errorf("slice %d: %w", i, err)
+					cancel()
+				}
+			}
+		}()
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		return err
+	}
+	return ctx.Err()
+}
+
+// pageSlice pages through slice, passing each page to emit until it returns false.
+func pageSlice[T any](ctx context.Context, slice *BaseESPaginator, emit func([]Hit[T]) bool) error {
+	for !slice.Done() {
+		hits, err := NextPage[T](ctx, slice)
+		if err != nil {
+			return err
+		}
+		if len(hits) == 0 {
+			return nil
+		}
+		if !emit(hits) {
+			return nil
+		}
+	}
+	return nil
+}
+
+func (p *BaseESPaginator) sliceID() int {
+	if p.slice == nil {
+		return 0
+	}
+	return p.slice.ID
+}
+
+// sliceLimit counts the hits sent by all slices against the paginator's limit.
+type sliceLimit struct {
+	mu      sync.Mutex
+	limit   int64
+	emitted int64
+}
+
+func newSliceLimit(limit int64) *sliceLimit {
+	return &sliceLimit{limit: limit}
+}
+
+// take returns the part of hits within the limit, and whether more hits may follow.
+func take[T any](l *sliceLimit, hits []Hit[T]) ([]Hit[T], bool) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if remaining := l.limit - l.emitted; int64(len(hits)) > remaining {
+		hits = hits[:remaining]
+	}
+	l.emitted += int64(len(hits))
+	return hits, l.emitted < l.limit
+}
+
+// mergeSlices pages all slices concurrently and sends their hits merged in the
+// paginator's sort order, in pages of the paginator's page size.
+func mergeSlices[T any](ctx context.Context, p *BaseESPaginator, slices []*BaseESPaginator, out chan<- SlicePage[T]) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	feeds := make([]chan []Hit[T], len(slices))
+	errs := make([]error, len(slices))
+	var wg sync.WaitGroup
+	defer wg.Wait()
+	defer cancel() // Stop the feeds before waiting for them
+	for i, slice := range slices {
+		feeds[i] = make(chan []Hit[T], 1)
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			defer close(feeds[i])
+			errs[i] = pageSlice(ctx, slice, func(hits []Hit[T]) bool {
+				select {
+				case feeds[i] <- hits:
+					return true
+				case <-ctx.Done():
+					return false
+				}
+			})
+		}()
+	}
+
+	descending := sortDescending(p.sort)
+	heads := make([][]Hit[T], len(slices))
+	exhausted := make([]bool, len(slices))
+	var batch []Hit[T]
+	send := func() bool {
+		if len(batch) == 0 {
+			return true
+		}
+		select {
+		case out <- SlicePage[T]{Slice: -1, Hits: batch}:
+			batch = nil
+			return true
+		case <-ctx.Done():
+			return false
+		}
+	}
+
+	for emitted := int64(0); emitted < p.limit; emitted++ {
+		next := -1
+		for i := range slices {
+			if len(heads[i]) == 0 && !exhausted[i] {
+				select {
+				case page, ok := <-feeds[i]:
+					if !ok {
+						// errs[i] is written before the feed is closed
+						if errs[i] != nil {
+							return fmt.Errorf("slice %d: %w", i, errs[i])
+						}
+						exhausted[i] = true
+						continue
+					}
+					heads[i] = page
+				case <-ctx.Done():
+					return ctx.Err()
+				}
+			}
+			if len(heads[i]) > 0 && (next < 0 || compareSortValues(heads[i][0].Sort, heads[next][0].Sort, descending) < 0) {
+				next = i
+			}
+		}
+		if next < 0 {
+			break
+		}
+		batch = append(batch, heads[next][0])
+		heads[next] = heads[next][1:]
+		if int64(len(batch)) >= p.pageSize && !send() {
+			return ctx.Err()
+		}
+	}
+	if !send() {
+		return ctx.Err()
+	}
+	return nil
+}
+
+// sortDescending returns whether each sort field of sort is in descending order.
+func sortDescending(sort []map[string]any) []bool {
+	var descending []bool
+	for _, field := range sort {
+		for _, order := range field {
+			if options, ok := order.(map[string]any); ok {
+				order = options["order"]
+			}
+			descending = append(descending, order == "desc")
+		}
+	}
+	return descending
+}
+
+// compareSortValues compares the sort values of two hits. Missing values sort last in
+// either order, as they do in ES.
+func compareSortValues(a, b []any, descending []bool) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] == nil || b[i] == nil {
+			switch {
+			case a[i] == nil && b[i] == nil:
+				continue
+			case a[i] == nil:
+				return 1
+			default:
+				return -1
+			}
+		}
+		var c int
+		switch x := a[i].(type) {
+		case float64:
+			if y, ok := b[i].(float64); ok {
+				c = cmp.Compare(x, y)
+				break
+			}
+			c = cmp.Compare(fmt.Sprint(a[i]), fmt.Sprint(b[i]))
+		case string:
+			if y, ok := b[i].(string); ok {
+				c = cmp.Compare(x, y)
+				break
+			}
+			c = cmp.Compare(fmt.Sprint(a[i]), fmt.Sprint(b[i]))
+		default:
+			c = cmp.Compare(fmt.Sprint(a[i]), fmt.Sprint(b[i]))
+		}
+		if i < len(descending) && descending[i] {
+			c = -c
+		}
+		if c != 0 {
+			return c
+		}
+	}
+	return cmp.Compare(len(a), len(b))
+}
@@ -0,0 +1,146 @@
+package opengovernance_test
+
+import (
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sort"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	opengovernance "github.com/opengovern/og-util/pkg/opengovernance-es-sdk"
+	"github.com/stretchr/testify/require"
+)
+
+type sliceDoc struct {
+	N int `json:"n"`
+}
+
+// newSliceServer serves a PIT over docs 0..count-1 sorted by n, where a slice holds the
+// docs whose n modulo the slice count is the slice id.
+func newSliceServer(t *testing.T, count int, pitDeletes *atomic.Int32) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		switch {
+		case strings.HasSuffix(req.URL.Path, "/_search/point_in_time") && req.Method == http.MethodPost:
+			w.Write([]byte(`{"pit_id":"pit-1"}`))
+		case strings.HasSuffix(req.URL.Path, "/_search/point_in_time") && req.Method == http.MethodDelete:
+			pitDeletes.Add(1)
+			w.Write([]byte(`{"pits":[{"pit_id":"pit-1","successful":true}]}`))
+		case req.URL.Path == "/_search":
+			var body struct {
+				Size        int   `json:"size"`
+				SearchAfter []any `json:"search_after"`
+				PIT         struct {
+					ID string `json:"id"`
+				} `json:"pit"`
+				Slice struct {
+					ID  int `json:"id"`
+					Max int `json:"max"`
+				} `json:"slice"`
+			}
+			reader := io.Reader(req.Body)
+			if req.Header.Get("Content-Encoding") == "gzip" {
+				gz, err := gzip.NewReader(req.Body)
+				if err != nil {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				reader = gz
+			}
+			if err := json.NewDecoder(reader).Decode(&body); err != nil || body.PIT.ID != "pit-1" || body.Slice.Max == 0 {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"error":{"type":"bad_request","reason":"expected a sliced PIT search"}}`))
+				return
+			}
+			after := -1
+			if len(body.SearchAfter) > 0 {
+				after = int(body.SearchAfter[0].(float64))
+			}
+			var hits []string
+			for n := after + 1; n < count && len(hits) < body.Size; n++ {
+				if n%body.Slice.Max == body.Slice.ID {
+					hits = append(hits, fmt.Sprintf(`{"_id":"%d","_source":{"n":%d},"sort":[%d,"%d"]}`, n, n, n, n))
+				}
+			}
+			fmt.Fprintf(w, `{"pit_id":"pit-1","hits":{"hits":[%s]}}`, strings.Join(hits, ","))
+		default:
+			t.Errorf("unexpected request %s %s", req.Method, req.URL.Path)
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+}
+
+func newSlicedPaginator(t *testing.T, server *httptest.Server, limit *int64) *opengovernance.BaseESPaginator {
+	username, password := "", ""
+	client, err := opengovernance.NewClient(opengovernance.ClientConfig{
+		Addresses: []string{server.URL},
+		Username:  &username,
+		Password:  &password,
+	})
+	require.NoError(t, err)
+	p, err := opengovernance.NewPaginatorWithSort(client.ES(), "inventory", nil, limit, []map[string]any{{"n": "asc"}})
+	require.NoError(t, err)
+	p.WithPageSize(7)
+	return p
+}
+
+func TestScanSlices(t *testing.T) {
+	r := require.New(t)
+
+	var pitDeletes atomic.Int32
+	server := newSliceServer(t, 100, &pitDeletes)
+	defer server.Close()
+
+	var unordered []int
+	slicesSeen := map[int]bool{}
+	p := newSlicedPaginator(t, server, nil)
+	for page := range opengovernance.ScanSlices[sliceDoc](context.Background(), p, opengovernance.SliceScanOptions{Slices: 3, Workers: 2}) {
+		r.NoError(page.Err)
+		r.LessOrEqual(len(page.Hits), 7)
+		slicesSeen[page.Slice] = true
+		for _, hit := range page.Hits {
+			unordered = append(unordered, hit.Source.N)
+		}
+	}
+	r.Len(unordered, 100)
+	sort.Ints(unordered)
+	for i, n := range unordered {
+		r.Equal(i, n)
+	}
+	r.Equal(map[int]bool{0: true, 1: true, 2: true}, slicesSeen)
+	r.Equal(int32(1), pitDeletes.Load())
+
+	var ordered []int
+	p = newSlicedPaginator(t, server, nil)
+	for page := range opengovernance.ScanSlices[sliceDoc](context.Background(), p, opengovernance.SliceScanOptions{Slices: 4, Ordered: true}) {
+		r.NoError(page.Err)
+		r.Equal(-1, page.Slice)
+		for _, hit := range page.Hits {
+			ordered = append(ordered, hit.Source.N)
+		}
+	}
+	r.Len(ordered, 100)
+	r.True(sort.IntsAreSorted(ordered), "hits are not merged in sort order: %v", ordered)
+
+	limit := int64(30)
+	for _, opts := range []opengovernance.SliceScanOptions{{Slices: 3}, {Slices: 3, Ordered: true}} {
+		var limited []int
+		p = newSlicedPaginator(t, server, &limit)
+		for page := range opengovernance.ScanSlices[sliceDoc](context.Background(), p, opts) {
+			r.NoError(page.Err)
+			for _, hit := range page.Hits {
+				limited = append(limited, hit.Source.N)
+			}
+		}
+		r.Len(limited, 30)
+		if opts.Ordered {
+			r.Equal(29, limited[29])
+		}
+	}
+}
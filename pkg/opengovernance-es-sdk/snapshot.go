@@ -0,0 +1,188 @@
+package opengovernance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+	"sync"
+)
+
+// Snapshot runs related searches against one point in time of an index, so that e.g. the
+// panels of a dashboard agree with each other even while documents are being written.
+// Its PIT is kept alive in the background until Close.
+type Snapshot struct {
+	client Client
+	index  string
+	keeper *pitKeeper
+}
+
+// SnapshotQuery is one search of a snapshot, decoded into Response like Search's
+// response argument, e.g. a *SearchResponse[T].
+type SnapshotQuery struct {
+	Name     string
+	Query    *QueryBuilder
+	Response any
+}
+
+// OpenSnapshot opens a point in time of index, kept alive as configured by opts until
+// the snapshot is closed or ctx is cancelled.
+func (c Client) OpenSnapshot(ctx context.Context, index string, opts PITKeepAliveOptions) (*Snapshot, error) {
+	if opts.KeepAlive <= 0 {
+		opts.KeepAlive = defaultPitKeepAlive
+	}
+	if opts.Interval <= 0 {
+		opts.Interval = opts.KeepAlive / 2
+	}
+	if opts.DeleteTimeout <= 0 {
+		opts.DeleteTimeout = defaultPitDeleteTimeout
+	}
+
+	createCtx, done := requestTimeout(ctx, "pit", c.pitTimeout)
+	pitRaw, pitRes, err := c.es.PointInTime.Create(
+		c.es.PointInTime.Create.WithIndex(index),
+		c.es.PointInTime.Create.WithKeepAlive(opts.KeepAlive),
+		c.es.PointInTime.Create.WithContext(createCtx),
+	)
+	err = done(err)
+	defer CloseSafe(pitRaw)
+	if err != nil {
+		return nil, fmt.Errorf("create point in time: %w", err)
+	} else if err := CheckError(pitRaw); err != nil {
+		if IsIndexNotFoundErr(err) {
+			return nil, c.indexNotFound(index, err)
+		}
+		return nil, fmt.Errorf("create point in time: %w", err)
+	}
+
+	keeper := &pitKeeper{
+		client:        c.es,
+		logger:        c.logger,
+		keepAlive:     opts.KeepAlive,
+		deleteTimeout: opts.DeleteTimeout,
+		pitID:         pitRes.PitID,
+		stop:          make(chan struct{}),
+		done:          make(chan struct{}),
+	}
+	go keeper.run(ctx, opts.Interval)
+	return &Snapshot{client: c, index: index, keeper: keeper}, nil
+}
+
+// Search runs query against the snapshot and decodes the response into response.
+func (s *Snapshot) Search(ctx context.Context, query *QueryBuilder, response any) error {
+	pitID := s.keeper.currentPitID()
+	if pitID == "" || s.keeper.released() {
+		return errors.New("snapshot is closed")
+	}
+	pinned := *query
+	pinned.pit = &PointInTime{ID: pitID, KeepAlive: formatKeepAlive(s.keeper.keepAlive)}
+	body, err := pinned.Build()
+	if err != nil {
+		return err
+	}
+
+	release, err := s.client.limiter.acquire(ctx, s.index)
+	if err != nil {
+		return err
+	}
+	defer release()
+	b, err := instrumentSearch(ctx, s.client.instrumentation, s.index, body, func() ([]byte, error) {
+		return s.client.retrier.do(ctx, s.client.logger, s.index, func() ([]byte, error) {
+			return s.send(ctx, body)
+		})
+	})
+	if err != nil {
+		return err
+	}
+	var pit struct {
+		PitID string `json:"pit_id"`
+	}
+	if json.Unmarshal(b, &pit) == nil && pit.PitID != "" {
+		// The cluster may return a new id for the same point in time
+		s.keeper.setPitID(pit.PitID)
+	}
+	b, err = applyResponseHooks(ctx, s.client.responseHooks, s.index, b)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(b, response); err != nil {
+		return fmt.Errorf("unmarshal response: %w", err)
+	}
+	return nil
+}
+
+// send sends a PIT search, which names the index through the PIT instead of the path.
+func (s *Snapshot) send(ctx context.Context, body string) (_ []byte, err error) {
+	ctx, done := requestTimeout(ctx, "search", s.client.searchTimeout)
+	defer func() { err = done(err) }()
+
+	res, err := s.client.es.Search(
+		s.client.es.Search.WithContext(ctx),
+		s.client.es.Search.WithBody(strings.NewReader(body)),
+	)
+	defer CloseSafe(res)
+	if err != nil {
+		return nil, err
+	} else if err := CheckError(res); err != nil {
+		return nil, &responseStatusError{statusCode: res.StatusCode, err: err}
+	}
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	return b, nil
+}
+
+// Run runs queries concurrently against the snapshot. Every query that succeeded is
+// decoded; the returned error joins the failures, each naming its query.
+func (s *Snapshot) Run(ctx context.Context, queries ...SnapshotQuery) error {
+	errs := make([]error, len(queries))
+	var wg sync.WaitGroup
+	for i, q := range queries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if err := s.Search(ctx, q.Query, q.Response); err != nil {
+				errs[i] = fmt.Errorf("query %s: %w", q.Name, err)
+			}
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// Close stops the keep-alive and deletes the point in time. Deletion failures are
+// returned as a *PITCleanupError.
+func (s *Snapshot) Close(ctx context.Context) error {
+	s.keeper.mu.Lock()
+	select {
+	case <-s.keeper.stop:
+	default:
+		close(s.keeper.stop)
+	}
+	s.keeper.mu.Unlock()
+	<-s.keeper.done
+
+	pitID := s.keeper.currentPitID()
+	if pitID == "" || s.keeper.released() {
+		return nil
+	}
+	if err := deletePit(ctx, s.client.es, s.client.logger, pitID); err != nil {
+		return &PITCleanupError{PitID: pitID, Err: err}
+	}
+	s.keeper.setPitID("")
+	return nil
+}
+
+// RunSnapshot opens a snapshot of index, runs queries against it and closes it.
+func RunSnapshot(ctx context.Context, c Client, index string, queries ...SnapshotQuery) error {
+	snapshot, err := c.OpenSnapshot(ctx, index, PITKeepAliveOptions{})
+	if err != nil {
+		return err
+	}
+	runErr := snapshot.Run(ctx, queries...)
+	closeErr := snapshot.Close(context.WithoutCancel(ctx))
+	return errors.Join(runErr, closeErr)
+}
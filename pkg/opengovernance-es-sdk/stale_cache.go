@@ -0,0 +1,177 @@
+package opengovernance
+
+import (
+	"container/list"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	defaultStaleCacheMaxEntries = 1000
+	defaultStaleCacheMaxBytes   = 64 << 20 // 64 MiB
+	defaultStaleCacheMaxAge     = time.Hour
+)
+
+// StaleCacheOptions configures the degraded mode enabled with SetStaleCache.
+type StaleCacheOptions struct {
+	MaxEntries int           // Cached responses kept, least recently used evicted first (default 1000)
+	MaxBytes   int           // Total size of cached responses (default 64 MiB)
+	MaxAge     time.Duration // Older responses are not served (default 1h)
+}
+
+// StaleResult reports whether a search made with a WithStaleFallback context was served
+// from the stale cache.
+type StaleResult struct {
+	mu    sync.Mutex
+	stale bool
+	age   time.Duration
+	err   error
+}
+
+// Stale reports whether the last search was answered from the cache and, if so, the age
+// of the cached response and the error that prevented a live answer.
+func (r *StaleResult) Stale() (stale bool, age time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return r.stale, r.age, r.err
+}
+
+func (r *StaleResult) set(stale bool, age time.Duration, err error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.stale, r.age, r.err = stale, age, err
+}
+
+type staleResultKey struct{}
+
+// WithStaleFallback designates the searches made with the returned context, e.g. those of
+// a dashboard, as ones that may be answered with the last cached response when the
+// cluster cannot be reached. The returned StaleResult tells whether that happened. It has
+// no effect on clients without SetStaleCache.
+func WithStaleFallback(ctx context.Context) (context.Context, *StaleResult) {
+	result := &StaleResult{}
+	return context.WithValue(ctx, staleResultKey{}, result), result
+}
+
+// SetStaleCache enables degraded mode: successful responses of searches designated with
+// WithStaleFallback are cached, and served, flagged as stale, when a later identical
+// search fails with a connectivity error (transport failure or 502, 503, 504).
+func (c *Client) SetStaleCache(opts StaleCacheOptions) {
+	if opts.MaxEntries <= 0 {
+		opts.MaxEntries = defaultStaleCacheMaxEntries
+	}
+	if opts.MaxBytes <= 0 {
+		opts.MaxBytes = defaultStaleCacheMaxBytes
+	}
+	if opts.MaxAge <= 0 {
+		opts.MaxAge = defaultStaleCacheMaxAge
+	}
+	c.staleCache = &staleCache{opts: opts, entries: make(map[string]*list.Element), lru: list.New()}
+}
+
+type staleCache struct {
+	opts StaleCacheOptions
+
+	mu      sync.Mutex
+	entries map[string]*list.Element // Values are *staleEntry
+	lru     *list.List               // Most recently used first
+	size    int
+}
+
+type staleEntry struct {
+	key      string
+	body     []byte // Raw response, before response hooks
+	storedAt time.Time
+}
+
+func staleCacheKey(index, query string, filterPath []string, trackTotalHits any) string {
+	return fmt.Sprintf("%s\x00%s\x00%q\x00%v", index, query, filterPath, trackTotalHits)
+}
+
+// search wraps a search sent by send with the stale cache, if ctx is designated.
+func (s *staleCache) search(ctx context.Context, logger *zap.Logger, key string, send func() ([]byte, error)) ([]byte, error) {
+	result, _ := ctx.Value(staleResultKey{}).(*StaleResult)
+	if s == nil || result == nil {
+		return send()
+	}
+	b, err := send()
+	if err == nil {
+		if b != nil { // Nil for a swallowed missing index, nothing to serve later
+			s.put(key, b)
+		}
+		result.set(false, 0, nil)
+		return b, nil
+	}
+	if !isConnectivityError(ctx, err) {
+		return nil, err
+	}
+	cached, age, ok := s.get(key)
+	if !ok {
+		return nil, err
+	}
+	logAt(ctx, logger, zapcore.WarnLevel, "serving stale search response",
+		zap.Duration("age", age), zap.Error(err))
+	result.set(true, age, err)
+	return cached, nil
+}
+
+func isConnectivityError(ctx context.Context, err error) bool {
+	if ctx.Err() != nil || errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return false
+	}
+	var statusErr *responseStatusError
+	if !errors.As(err, &statusErr) {
+		return true
+	}
+	switch statusErr.statusCode {
+	case http.StatusBadGateway, http.StatusServiceUnavailable, http.StatusGatewayTimeout:
+		return true
+	}
+	return false
+}
+
+func (s *staleCache) put(key string, body []byte) {
+	if len(body) > s.opts.MaxBytes {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if element, ok := s.entries[key]; ok {
+		s.remove(element)
+	}
+	s.entries[key] = s.lru.PushFront(&staleEntry{key: key, body: body, storedAt: time.Now()})
+	s.size += len(body)
+	for len(s.entries) > s.opts.MaxEntries || s.size > s.opts.MaxBytes {
+		s.remove(s.lru.Back())
+	}
+}
+
+func (s *staleCache) get(key string) ([]byte, time.Duration, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	element, ok := s.entries[key]
+	if !ok {
+		return nil, 0, false
+	}
+	entry := element.Value.(*staleEntry)
+	age := time.Since(entry.storedAt)
+	if age > s.opts.MaxAge {
+		s.remove(element)
+		return nil, 0, false
+	}
+	s.lru.MoveToFront(element)
+	return entry.body, age, true
+}
+
+func (s *staleCache) remove(element *list.Element) {
+	entry := s.lru.Remove(element).(*staleEntry)
+	delete(s.entries, entry.key)
+	s.size -= len(entry.body)
+}
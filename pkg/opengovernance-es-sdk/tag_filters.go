@@ -0,0 +1,36 @@
+package opengovernance
+
+import "strings"
+
+// Canonical tags are stored as nested {key, value} documents, lowercased.
+const (
+	canonicalTagsPath  = "canonical_tags"
+	canonicalTagsKey   = canonicalTagsPath + ".key"
+	canonicalTagsValue = canonicalTagsPath + ".value"
+)
+
+// TagEquals matches resources tagged key=value. Key and value are case-insensitive.
+func TagEquals(key, value string) BoolFilter {
+	return NewNestedFilter(canonicalTagsPath, NewBoolMustFilter(
+		NewTermFilter(canonicalTagsKey, strings.ToLower(key)),
+		NewTermFilter(canonicalTagsValue, strings.ToLower(value)),
+	))
+}
+
+// TagExists matches resources with tag key, whatever its value. Key is case-insensitive.
+func TagExists(key string) BoolFilter {
+	return NewNestedFilter(canonicalTagsPath, NewTermFilter(canonicalTagsKey, strings.ToLower(key)))
+}
+
+// TagIn matches resources whose tag key has any of values. Key and values are
+// case-insensitive.
+func TagIn(key string, values []string) BoolFilter {
+	lowered := make([]string, 0, len(values))
+	for _, v := range values {
+		lowered = append(lowered, strings.ToLower(v))
+	}
+	return NewNestedFilter(canonicalTagsPath, NewBoolMustFilter(
+		NewTermFilter(canonicalTagsKey, strings.ToLower(key)),
+		NewTermsFilter(canonicalTagsValue, lowered),
+	))
+}
@@ -1,5 +1,15 @@
 package opengovernance
 
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Default tagless resource types, registered for the aws_cloud_account and
+// azure_subscription integration types until plugins provide their own.
 var awsTaglessResourceTypes = []string{
 	"AWS::Account::AlternateContact",
 	"AWS::ApiGateway::Authorizer",
@@ -62,3 +72,83 @@ var azureTaglessResourceTypes = []string{
 	"Microsoft.Security/settings",
 	"Microsoft.Storage/storageaccounts/blobservices/containers",
 }
+
+// taglessResourceTypes is the registry of resource types that cannot carry tags, by
+// integration type. Resource collections with tag criteria would never match them, so
+// compliance queries match them regardless (see CompileResourceCollection).
+var taglessResourceTypes = struct {
+	sync.RWMutex
+	byIntegrationType map[string][]string // Lowercased resource types
+}{byIntegrationType: map[string][]string{
+	"aws_cloud_account":  lowerStrings(awsTaglessResourceTypes),
+	"azure_subscription": lowerStrings(azureTaglessResourceTypes),
+}}
+
+// RegisterTaglessResourceTypes replaces the tagless resource types of integrationType;
+// an empty list removes them.
+func RegisterTaglessResourceTypes(integrationType string, resourceTypes []string) {
+	taglessResourceTypes.Lock()
+	if len(resourceTypes) == 0 {
+		delete(taglessResourceTypes.byIntegrationType, integrationType)
+	} else {
+		taglessResourceTypes.byIntegrationType[integrationType] = lowerStrings(resourceTypes)
+	}
+	taglessResourceTypes.Unlock()
+	// Compiled compliance collections embed the tagless types
+	resetCompiledResourceCollections()
+}
+
+// LoadTaglessResourceTypeCatalog registers the tagless resource types of a plugin's
+// resource type catalog, a JSON list of interfaces.ResourceTypeConfiguration. Each
+// integration type with at least one entry stating "tagless" has its tagless resource
+// types replaced by the catalog's; others keep their current ones, so catalogs that
+// predate the field don't clear the defaults.
+func LoadTaglessResourceTypeCatalog(catalog []byte) error {
+	var entries []struct {
+		Name            string `json:"name"`
+		IntegrationType string `json:"integration_type"`
+		Tagless         *bool  `json:"tagless"`
+	}
+	if err := json.Unmarshal(catalog, &entries); err != nil {
+		return fmt.Errorf("unmarshal resource type catalog: %w", err)
+	}
+	byIntegrationType := make(map[string][]string)
+	for _, entry := range entries {
+		if entry.Tagless == nil {
+			continue
+		}
+		if entry.IntegrationType == "" || entry.Name == "" {
+			return fmt.Errorf("resource type catalog entry %q has no name or integration type", entry.Name)
+		}
+		types := byIntegrationType[entry.IntegrationType]
+		if *entry.Tagless {
+			types = append(types, entry.Name)
+		}
+		byIntegrationType[entry.IntegrationType] = types
+	}
+	for integrationType, types := range byIntegrationType {
+		RegisterTaglessResourceTypes(integrationType, types)
+	}
+	return nil
+}
+
+// TaglessResourceTypes returns the registered tagless resource types of all integration
+// types, lowercased and sorted.
+func TaglessResourceTypes() []string {
+	taglessResourceTypes.RLock()
+	defer taglessResourceTypes.RUnlock()
+	var types []string
+	for _, t := range taglessResourceTypes.byIntegrationType {
+		types = append(types, t...)
+	}
+	sort.Strings(types)
+	return uniqueStrings(types)
+}
+
+func lowerStrings(values []string) []string {
+	out := make([]string, 0, len(values))
+	for _, v := range values {
+		out = append(out, strings.ToLower(v))
+	}
+	return out
+}
@@ -0,0 +1,190 @@
+package opengovernance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// TenantIndexSeparator separates the tenant identifier from the index name. Tenant ids
+// cannot contain it, so the prefix of one tenant is never the prefix of another.
+const TenantIndexSeparator = "_"
+
+// ErrTenantNotInContext is returned by TenantClient operations when the context carries
+// no tenant, unless AllowNoTenant is set.
+var ErrTenantNotInContext = errors.New("no tenant id in context")
+
+type tenantContextKey struct{}
+
+// WithTenant returns a copy of ctx carrying tenantID for TenantClient operations.
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	return context.WithValue(ctx, tenantContextKey{}, tenantID)
+}
+
+// TenantFromContext returns the tenant set by WithTenant, if any.
+func TenantFromContext(ctx context.Context) (string, bool) {
+	tenantID, ok := ctx.Value(tenantContextKey{}).(string)
+	return tenantID, ok && tenantID != ""
+}
+
+// TenantIndex prefixes every index in index (a comma-separated list, possibly with
+// wildcards or "-" exclusions) with tenantID. The prefix is always added, even to names
+// that look prefixed already, so a name can never reach the indices of another tenant.
+func TenantIndex(tenantID, index string) (string, error) {
+	if err := validateTenantID(tenantID); err != nil {
+		return "", err
+	}
+	prefix := strings.ToLower(tenantID) + TenantIndexSeparator
+	parts := strings.Split(index, ",")
+	for i, part := range parts {
+		part = strings.TrimSpace(part)
+		exclude := strings.HasPrefix(part, "-")
+		name := strings.TrimPrefix(part, "-")
+		if name == "" {
+			return "", fmt.Errorf("empty index name in %q", index)
+		}
+		name = prefix + name
+		if exclude {
+			name = "-" + name
+		}
+		parts[i] = name
+	}
+	return strings.Join(parts, ","), nil
+}
+
+// validateTenantID rejects tenant ids that would produce invalid index names.
+func validateTenantID(tenantID string) error {
+	if tenantID == "" {
+		return errors.New("tenant id cannot be empty")
+	}
+	if strings.ContainsAny(tenantID, `\/*?"<>| ,#:`) {
+		return fmt.Errorf("tenant id %q contains characters not allowed in index names", tenantID)
+	}
+	if strings.Contains(tenantID, TenantIndexSeparator) {
+		return fmt.Errorf("tenant id %q cannot contain %q", tenantID, TenantIndexSeparator)
+	}
+	if strings.ContainsAny(tenantID[:1], "-+") {
+		return fmt.Errorf("tenant id %q cannot start with '-' or '+'", tenantID)
+	}
+	return nil
+}
+
+// TenantClientOptions configures a TenantClient.
+type TenantClientOptions struct {
+	// AllowNoTenant lets operations whose context has no tenant use the unprefixed index,
+	// i.e. see the indices of every tenant. By default they fail with
+	// ErrTenantNotInContext; only set it for clients that serve no tenant at all.
+	AllowNoTenant bool
+}
+
+// TenantClient wraps a Client and prefixes index names with the tenant from the context
+// of each call (see WithTenant), giving soft multi-tenancy on a shared cluster.
+type TenantClient struct {
+	client  Client
+	options TenantClientOptions
+}
+
+func NewTenantClient(client Client, options TenantClientOptions) TenantClient {
+	return TenantClient{client: client, options: options}
+}
+
+// Client returns the underlying, non tenant-scoped client.
+func (t TenantClient) Client() Client {
+	return t.client
+}
+
+// ResolveIndex returns the index name an operation with ctx would target.
+func (t TenantClient) ResolveIndex(ctx context.Context, index string) (string, error) {
+	tenantID, ok := TenantFromContext(ctx)
+	if !ok {
+		if t.options.AllowNoTenant {
+			return index, nil
+		}
+		return "", ErrTenantNotInContext
+	}
+	return TenantIndex(tenantID, index)
+}
+
+// Deprecated: use SearchQuery.
+func (t TenantClient) Search(ctx context.Context, index string, query string, response any) error {
+	return t.SearchWithTrackTotalHits(ctx, index, query, nil, response, false)
+}
+
+// Deprecated: use SearchQueryWithTrackTotalHits.
+func (t TenantClient) SearchWithFilterPath(ctx context.Context, index string, query string, filterPath []string, response any) error {
+	return t.SearchWithTrackTotalHits(ctx, index, query, filterPath, response, false)
+}
+
+// Deprecated: use SearchQueryWithTrackTotalHits.
+func (t TenantClient) SearchWithTrackTotalHits(ctx context.Context, index string, query string, filterPath []string, response any, trackTotalHits any) error {
+	index, err := t.ResolveIndex(ctx, index)
+	if err != nil {
+		return err
+	}
+	return t.client.search(ctx, index, query, filterPath, response, trackTotalHits)
+}
+
+func (t TenantClient) Count(ctx context.Context, index string) (int64, error) {
+	index, err := t.ResolveIndex(ctx, index)
+	if err != nil {
+		return 0, err
+	}
+	return t.client.Count(ctx, index)
+}
+
+func (t TenantClient) CountWithQuery(ctx context.Context, index string, filters []BoolFilter) (int64, error) {
+	index, err := t.ResolveIndex(ctx, index)
+	if err != nil {
+		return 0, err
+	}
+	return t.client.CountWithQuery(ctx, index, filters)
+}
+
+func (t TenantClient) Cardinality(ctx context.Context, index string, field string, filters []BoolFilter) (int64, error) {
+	index, err := t.ResolveIndex(ctx, index)
+	if err != nil {
+		return 0, err
+	}
+	return t.client.Cardinality(ctx, index, field, filters)
+}
+
+func (t TenantClient) GetByID(ctx context.Context, index string, id string, response any) error {
+	index, err := t.ResolveIndex(ctx, index)
+	if err != nil {
+		return err
+	}
+	return t.client.GetByID(ctx, index, id, response)
+}
+
+func (t TenantClient) MGet(ctx context.Context, index string, ids []string, response any) ([]string, error) {
+	index, err := t.ResolveIndex(ctx, index)
+	if err != nil {
+		return nil, err
+	}
+	return t.client.MGet(ctx, index, ids, response)
+}
+
+func (t TenantClient) CreateIndexIfNotExist(ctx context.Context, logger *zap.Logger, index string) error {
+	index, err := t.ResolveIndex(ctx, index)
+	if err != nil {
+		return err
+	}
+	return t.client.CreateIndexIfNotExist(ctx, logger, index)
+}
+
+func (t TenantClient) SearchTiered(ctx context.Context, cfg TierConfig, from, to time.Time, filters []BoolFilter, size int64) (*TieredSearchResult, error) {
+	hotIndex, err := t.ResolveIndex(ctx, cfg.HotIndex)
+	if err != nil {
+		return nil, err
+	}
+	coldIndex, err := t.ResolveIndex(ctx, cfg.ColdIndex)
+	if err != nil {
+		return nil, err
+	}
+	cfg.HotIndex, cfg.ColdIndex = hotIndex, coldIndex
+	return t.client.SearchTiered(ctx, cfg, from, to, filters, size)
+}
@@ -0,0 +1,69 @@
+package opengovernance_test
+
+import (
+	"context"
+	"path"
+	"testing"
+
+	opengovernance "github.com/opengovern/og-util/pkg/opengovernance-es-sdk"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTenantIndex(t *testing.T) {
+	r := require.New(t)
+
+	index, err := opengovernance.TenantIndex("acme", "inventory,-inventory_old")
+	r.NoError(err)
+	r.Equal("acme_inventory,-acme_inventory_old", index)
+
+	// A name that looks like it belongs to another tenant is still prefixed.
+	index, err = opengovernance.TenantIndex("acme", "other_inventory")
+	r.NoError(err)
+	r.Equal("acme_other_inventory", index)
+	index, err = opengovernance.TenantIndex("acme", "acme_inventory")
+	r.NoError(err)
+	r.Equal("acme_acme_inventory", index)
+
+	_, err = opengovernance.TenantIndex("acme_corp", "inventory")
+	r.ErrorContains(err, "cannot contain")
+	_, err = opengovernance.TenantIndex("", "inventory")
+	r.Error(err)
+	_, err = opengovernance.TenantIndex("acme", "inventory,")
+	r.Error(err)
+}
+
+func TestTenantIndexWildcardStaysInTenant(t *testing.T) {
+	r := require.New(t)
+
+	pattern, err := opengovernance.TenantIndex("acme", "*")
+	r.NoError(err)
+	for _, tenantID := range []string{"acmecorp", "acme-corp", "acme.corp"} {
+		index, err := opengovernance.TenantIndex(tenantID, "inventory")
+		r.NoError(err)
+		matched, err := path.Match(pattern, index)
+		r.NoError(err)
+		r.False(matched, "%s matches %s", pattern, index)
+	}
+	own, err := opengovernance.TenantIndex("acme", "corp_inventory")
+	r.NoError(err)
+	matched, err := path.Match(pattern, own)
+	r.NoError(err)
+	r.True(matched)
+}
+
+func TestTenantClientResolveIndex(t *testing.T) {
+	r := require.New(t)
+	ctx := context.Background()
+
+	client := opengovernance.NewTenantClient(opengovernance.Client{}, opengovernance.TenantClientOptions{})
+	_, err := client.ResolveIndex(ctx, "inventory")
+	r.ErrorIs(err, opengovernance.ErrTenantNotInContext)
+	index, err := client.ResolveIndex(opengovernance.WithTenant(ctx, "acme"), "inventory")
+	r.NoError(err)
+	r.Equal("acme_inventory", index)
+
+	client = opengovernance.NewTenantClient(opengovernance.Client{}, opengovernance.TenantClientOptions{AllowNoTenant: true})
+	index, err = client.ResolveIndex(ctx, "inventory")
+	r.NoError(err)
+	r.Equal("inventory", index)
+}
@@ -0,0 +1,179 @@
+package opengovernance
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	TierHot  = "hot"
+	TierCold = "cold"
+)
+
+// TierConfig describes a time-series data set split between a hot index holding recent
+// documents and a cold index holding older ones.
+type TierConfig struct {
+	HotIndex       string
+	ColdIndex      string
+	TimestampField string        // Date or epoch-millis field used for the tier split
+	HotRetention   time.Duration // Documents newer than now-HotRetention live in HotIndex
+	HotTimeout     time.Duration // Timeout for the hot portion; 0 means no extra timeout
+	ColdTimeout    time.Duration // Timeout for the cold portion; 0 means no extra timeout
+}
+
+// TierQuery is the portion of a time-bounded query that targets a single tier.
+// From is inclusive and To is exclusive.
+type TierQuery struct {
+	Tier    string
+	Index   string
+	From    time.Time
+	To      time.Time
+	Timeout time.Duration
+}
+
+// SplitByTier splits the [from, to) interval at the hot/cold boundary (now - HotRetention)
+// and returns the portions that are non-empty, hot first.
+func SplitByTier(cfg TierConfig, from, to, now time.Time) ([]TierQuery, error) {
+	if cfg.HotIndex == "" || cfg.ColdIndex == "" {
+		return nil, errors.New("tier config requires both hot and cold index")
+	}
+	if cfg.TimestampField == "" {
+		return nil, errors.New("tier config requires a timestamp field")
+	}
+	if cfg.HotRetention <= 0 {
+		return nil, fmt.Errorf("invalid hot retention: %s", cfg.HotRetention)
+	}
+	if !from.Before(to) {
+		return nil, fmt.Errorf("invalid time range: from %s is not before to %s", from, to)
+	}
+
+	boundary := now.Add(-cfg.HotRetention)
+	var queries []TierQuery
+	if to.After(boundary) {
+		hotFrom := from
+		if hotFrom.Before(boundary) {
+			hotFrom = boundary
+		}
+		queries = append(queries, TierQuery{Tier: TierHot, Index: cfg.HotIndex, From: hotFrom, To: to, Timeout: cfg.HotTimeout})
+	}
+	if from.Before(boundary) {
+		coldTo := to
+		if coldTo.After(boundary) {
+			coldTo = boundary
+		}
+		queries = append(queries, TierQuery{Tier: TierCold, Index: cfg.ColdIndex, From: from, To: coldTo, Timeout: cfg.ColdTimeout})
+	}
+	return queries, nil
+}
+
+// TieredHit is a single document returned by SearchTiered.
+type TieredHit struct {
+	Index  string          `json:"_index"`
+	ID     string          `json:"_id"`
+	Source json.RawMessage `json:"_source"`
+	Sort   []any           `json:"sort"`
+}
+
+// TieredSearchResult holds the merged hits of a tiered search. When the cold portion
+// fails or times out the hot hits are still returned, with ColdErr set and Partial true.
+type TieredSearchResult struct {
+	Hits    []TieredHit
+	Partial bool
+	ColdErr error
+}
+
+type tieredSearchResponse struct {
+	Hits struct {
+		Hits []TieredHit `json:"hits"`
+	} `json:"hits"`
+}
+
+// SearchTiered runs filters against both tiers covering [from, to), sorted by the
+// timestamp field descending, and merges the results (hot hits first, since they are
+// newer) up to size documents. Both portions run concurrently with their configured
+// timeouts. A hot tier failure fails the whole search; a cold tier failure yields a
+// partial result.
+func (c Client) SearchTiered(ctx context.Context, cfg TierConfig, from, to time.Time, filters []BoolFilter, size int64) (*TieredSearchResult, error) {
+	queries, err := SplitByTier(cfg, from, to, time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	hits := make([][]TieredHit, len(queries))
+	errs := make([]error, len(queries))
+	var wg sync.WaitGroup
+	for i, q := range queries {
+		wg.Add(1)
+		go func(i int, q TierQuery) {
+			defer wg.Done()
+			hits[i], errs[i] = c.searchTier(ctx, cfg.TimestampField, q, filters, size)
+		}(i, q)
+	}
+	wg.Wait()
+
+	result := &TieredSearchResult{}
+	for i, q := range queries {
+		if errs[i] != nil {
+			if q.Tier == TierHot {
+				return nil, fmt.Errorf("hot tier search on %s: %w", q.Index, errs[i])
+			}
+			result.ColdErr = errs[i]
+			result.Partial = true
+			logAt(ctx, c.logger, zapcore.WarnLevel, "cold tier search failed, returning partial result",
+				zap.String("index", q.Index), zap.Error(errs[i]))
+			continue
+		}
+		result.Hits = append(result.Hits, hits[i]...)
+	}
+	if size > 0 && int64(len(result.Hits)) > size {
+		result.Hits = result.Hits[:size]
+	}
+	return result, nil
+}
+
+func (c Client) searchTier(ctx context.Context, timestampField string, q TierQuery, filters []BoolFilter, size int64) ([]TieredHit, error) {
+	if q.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, q.Timeout)
+		defer cancel()
+	}
+
+	tierFilters := make([]BoolFilter, 0, len(filters)+1)
+	tierFilters = append(tierFilters, filters...)
+	tierFilters = append(tierFilters, NewRangeFilter(timestampField,
+		"", strconv.FormatInt(q.From.UnixMilli(), 10),
+		strconv.FormatInt(q.To.UnixMilli(), 10), ""))
+
+	request := map[string]any{
+		"query": map[string]any{
+			"bool": map[string]any{
+				"filter": tierFilters,
+			},
+		},
+		"sort": []map[string]any{
+			{timestampField: "desc"},
+			{"_id": "desc"},
+		},
+	}
+	if size > 0 {
+		request["size"] = size
+	}
+	query, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+
+	var response tieredSearchResponse
+	if err := c.search(ctx, q.Index, string(query), nil, &response, false); err != nil {
+		return nil, err
+	}
+	return response.Hits.Hits, nil
+}
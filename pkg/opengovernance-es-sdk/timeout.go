@@ -0,0 +1,76 @@
+package opengovernance
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// TimeoutError is returned when a request exceeds a timeout set with SetSearchTimeout,
+// SetPITTimeout or their paginator counterparts, as opposed to the caller's context
+// expiring. It unwraps to context.DeadlineExceeded.
+type TimeoutError struct {
+	Op      string // "search" or "pit"
+	Timeout time.Duration
+	cause   error
+}
+
+func (e *TimeoutError) Error() string {
+	return fmt.Sprintf("%s request timed out after %s: %v", e.Op, e.Timeout, e.cause)
+}
+
+func (e *TimeoutError) Unwrap() []error {
+	return []error{e.cause, context.DeadlineExceeded}
+}
+
+// IsTimeoutErr checks if error is a TimeoutError
+func IsTimeoutErr(err error) bool {
+	var e *TimeoutError
+	return errors.As(err, &e)
+}
+
+// SetSearchTimeout bounds each read request (search, count, get, mget and msearch) to
+// timeout, whatever the deadline of the caller's context. A request that times out fails
+// with a *TimeoutError; with a retry policy it is retried like other transient failures.
+// Zero disables the timeout. Copies of the Client made afterwards keep the setting.
+func (c *Client) SetSearchTimeout(timeout time.Duration) {
+	c.searchTimeout = timeout
+}
+
+// SetPITTimeout bounds each point in time request made by the client, such as opening a
+// Snapshot, to timeout. Zero disables the timeout.
+func (c *Client) SetPITTimeout(timeout time.Duration) {
+	c.pitTimeout = timeout
+}
+
+// WithSearchTimeout bounds each page request of the paginator to timeout, whatever the
+// deadline of the context passed to Search. Zero disables the timeout.
+func (p *BaseESPaginator) WithSearchTimeout(timeout time.Duration) *BaseESPaginator {
+	p.searchTimeout = timeout
+	return p
+}
+
+// WithPITTimeout bounds the requests opening and deleting the paginator's point in time
+// to timeout. Zero disables the timeout.
+func (p *BaseESPaginator) WithPITTimeout(timeout time.Duration) *BaseESPaginator {
+	p.pitTimeout = timeout
+	return p
+}
+
+// requestTimeout derives the context of one request bounded by timeout, if positive.
+// Pass the request's error to its done function, which releases the context and turns a
+// timeout into a *TimeoutError.
+func requestTimeout(ctx context.Context, op string, timeout time.Duration) (context.Context, func(err error) error) {
+	if timeout <= 0 {
+		return ctx, func(err error) error { return err }
+	}
+	reqCtx, cancel := context.WithTimeout(ctx, timeout)
+	return reqCtx, func(err error) error {
+		defer cancel()
+		if err != nil && ctx.Err() == nil && errors.Is(reqCtx.Err(), context.DeadlineExceeded) {
+			return &TimeoutError{Op: op, Timeout: timeout, cause: err}
+		}
+		return err
+	}
+}
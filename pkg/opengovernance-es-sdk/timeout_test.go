@@ -0,0 +1,63 @@
+package opengovernance_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	opengovernance "github.com/opengovern/og-util/pkg/opengovernance-es-sdk"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSearchTimeout(t *testing.T) {
+	r := require.New(t)
+
+	stop := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		select {
+		case <-req.Context().Done():
+		case <-stop:
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{"hits":{"hits":[]}}`))
+	}))
+	defer server.Close()
+	defer close(stop)
+
+	username, password := "", ""
+	client, err := opengovernance.NewClient(opengovernance.ClientConfig{
+		Addresses: []string{server.URL},
+		Username:  &username,
+		Password:  &password,
+	})
+	r.NoError(err)
+	client.SetSearchTimeout(50 * time.Millisecond)
+
+	var response opengovernance.AggregationResponse
+	start := time.Now()
+	err = client.SearchWithQueryBuilder(context.Background(), "inventory", opengovernance.NewQueryBuilder(), &response)
+	r.Less(time.Since(start), time.Second)
+	r.True(opengovernance.IsTimeoutErr(err), "unexpected error: %v", err)
+	r.ErrorIs(err, context.DeadlineExceeded)
+
+	// The parent context expiring is not a request timeout
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err = client.Count(ctx, "inventory")
+	r.Error(err)
+	r.False(opengovernance.IsTimeoutErr(err))
+
+	// A single page needs no PIT
+	limit := int64(10)
+	p, err := opengovernance.NewPaginator(client.ES(), "inventory", nil, &limit)
+	r.NoError(err)
+	p.WithPageSize(10).WithSearchTimeout(50 * time.Millisecond)
+	var page struct{}
+	err = p.Search(context.Background(), &page)
+	var timeoutErr *opengovernance.TimeoutError
+	r.True(errors.As(err, &timeoutErr), "unexpected error: %v", err)
+	r.Equal("search", timeoutErr.Op)
+}
@@ -0,0 +1,115 @@
+package opengovernance
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+
+	"github.com/opensearch-project/opensearch-go/v2/opensearchapi"
+	"github.com/opensearch-project/opensearch-go/v2/opensearchutil"
+)
+
+// ConflictsPolicy is what an update by query does when a document changed since it was
+// read.
+type ConflictsPolicy string
+
+const (
+	ConflictsAbort   ConflictsPolicy = "abort"   // Stop the update and fail (default)
+	ConflictsProceed ConflictsPolicy = "proceed" // Skip the document and count it in VersionConflicts
+)
+
+// UpdateByQueryOptions configures an update by query.
+type UpdateByQueryOptions struct {
+	Conflicts         ConflictsPolicy
+	Async             bool // Return the task ID without waiting for the update to complete
+	Refresh           bool // Refresh the updated indices once done
+	MaxDocs           int  // Update at most this many documents; all if zero
+	Slices            int  // Parallel slices per index; 1 if zero, "auto" if negative
+	RequestsPerSecond int  // Throttle of the batches; unthrottled if zero
+}
+
+// UpdateByQueryResponse ...
+type UpdateByQueryResponse struct {
+	Task             string `json:"task"` // Set instead of the counts for Async updates
+	Took             int    `json:"took"`
+	TimedOut         bool   `json:"timed_out"`
+	Total            int    `json:"total"`
+	Updated          int    `json:"updated"`
+	Deleted          int    `json:"deleted"`
+	Batches          int    `json:"batches"`
+	VersionConflicts int    `json:"version_conflicts"`
+	Noops            int    `json:"noops"`
+	Retries          struct {
+		Bulk   int `json:"bulk"`
+		Search int `json:"search"`
+	} `json:"retries"`
+	ThrottledMillis      int     `json:"throttled_millis"`
+	RequestsPerSecond    float64 `json:"requests_per_second"`
+	ThrottledUntilMillis int     `json:"throttled_until_millis"`
+	Failures             []any   `json:"failures"`
+}
+
+// UpdateByQuery runs the painless script with params on every document of indices
+// matching query (e.g. a map or a BoolFilter query; all documents if nil). As with
+// DeleteByQuery, missing indices are not an error.
+func (c Client) UpdateByQuery(ctx context.Context, indices []string, query any, script string, params map[string]any, opts UpdateByQueryOptions) (UpdateByQueryResponse, error) {
+	s := NewScript(script, params)
+	if err := s.Validate(); err != nil {
+		return UpdateByQueryResponse{}, err
+	}
+	if query == nil {
+		query = map[string]any{"match_all": map[string]any{}}
+	}
+	body := map[string]any{
+		"query":  query,
+		"script": s,
+	}
+
+	reqOpts := []func(*opensearchapi.UpdateByQueryRequest){
+		c.es.UpdateByQuery.WithContext(ctx),
+		c.es.UpdateByQuery.WithBody(opensearchutil.NewJSONReader(body)),
+		c.es.UpdateByQuery.WithWaitForCompletion(!opts.Async),
+	}
+	switch opts.Conflicts {
+	case "":
+	case ConflictsAbort, ConflictsProceed:
+		reqOpts = append(reqOpts, c.es.UpdateByQuery.WithConflicts(string(opts.Conflicts)))
+	default:
+		return UpdateByQueryResponse{}, fmt.Errorf("invalid conflicts policy: %s", opts.Conflicts)
+	}
+	if opts.Refresh {
+		reqOpts = append(reqOpts, c.es.UpdateByQuery.WithRefresh(true))
+	}
+	if opts.MaxDocs > 0 {
+		reqOpts = append(reqOpts, c.es.UpdateByQuery.WithMaxDocs(opts.MaxDocs))
+	}
+	if opts.Slices < 0 {
+		reqOpts = append(reqOpts, c.es.UpdateByQuery.WithSlices("auto"))
+	} else if opts.Slices > 0 {
+		reqOpts = append(reqOpts, c.es.UpdateByQuery.WithSlices(opts.Slices))
+	}
+	if opts.RequestsPerSecond > 0 {
+		reqOpts = append(reqOpts, c.es.UpdateByQuery.WithRequestsPerSecond(opts.RequestsPerSecond))
+	}
+
+	resp, err := c.es.UpdateByQuery(indices, reqOpts...)
+	defer CloseSafe(resp)
+	if err != nil {
+		return UpdateByQueryResponse{}, err
+	} else if cerr := CheckError(resp); cerr != nil {
+		if IsIndexNotFoundErr(cerr) {
+			return UpdateByQueryResponse{}, nil
+		}
+		return UpdateByQueryResponse{}, cerr
+	}
+	b, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return UpdateByQueryResponse{}, fmt.Errorf("read response: %w", err)
+	}
+	var response UpdateByQueryResponse
+	if err := json.Unmarshal(b, &response); err != nil {
+		return UpdateByQueryResponse{}, fmt.Errorf("unmarshal response: %w", err)
+	}
+	return response, nil
+}
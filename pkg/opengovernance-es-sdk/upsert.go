@@ -0,0 +1,214 @@
+package opengovernance
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+
+	"github.com/opensearch-project/opensearch-go/v2/opensearchapi"
+)
+
+// MergeStrategy controls how a field in an upserted document is combined with the
+// value already stored, when several describers enrich the same resource document.
+type MergeStrategy string
+
+const (
+	MergeOverwrite MergeStrategy = "overwrite"  // Replace the stored value (default)
+	MergeMap       MergeStrategy = "merge_map"  // Union object keys, new values win (e.g. tags)
+	MergeList      MergeStrategy = "merge_list" // Append list items not already present
+	MergeKeepMin   MergeStrategy = "keep_min"   // Keep the smaller value (e.g. earliest first_seen)
+	MergeKeepMax   MergeStrategy = "keep_max"   // Keep the larger value (e.g. latest last_seen)
+)
+
+// upsertMergeScript applies params.strategies to each top-level field of params.doc.
+// Numbers compare numerically; anything else compares as strings, which orders ISO 8601
+// UTC timestamps correctly. On insert (empty _source) the document is stored as-is.
+const upsertMergeScript = `
+if (ctx._source.isEmpty()) { ctx._source.putAll(params.doc); return; }
+for (entry in params.doc.entrySet()) {
+  String k = entry.getKey();
+  def v = entry.getValue();
+  def cur = ctx._source[k];
+  String s = params.strategies.containsKey(k) ? params.strategies[k] : 'overwrite';
+  if (cur == null || s == 'overwrite') {
+    ctx._source[k] = v;
+  } else if (v == null) {
+    continue;
+  } else if (s == 'merge_map') {
+    if (cur instanceof Map && v instanceof Map) { cur.putAll(v); } else { ctx._source[k] = v; }
+  } else if (s == 'merge_list') {
+    if (cur instanceof List && v instanceof List) {
+      for (item in v) { if (!cur.contains(item)) { cur.add(item); } }
+    } else { ctx._source[k] = v; }
+  } else if (s == 'keep_min' || s == 'keep_max') {
+    int cmp;
+    if (v instanceof Number && cur instanceof Number) {
+      cmp = Double.compare(((Number) v).doubleValue(), ((Number) cur).doubleValue());
+    } else {
+      cmp = v.toString().compareTo(cur.toString());
+    }
+    if ((s == 'keep_min' && cmp < 0) || (s == 'keep_max' && cmp > 0)) { ctx._source[k] = v; }
+  }
+}`
+
+// UpsertDocument is a partial document to merge into Index/ID, creating it if missing.
+// Fields not listed in Strategies use MergeOverwrite.
+type UpsertDocument struct {
+	Index      string
+	ID         string
+	Doc        map[string]any
+	Strategies map[string]MergeStrategy
+}
+
+// UpsertOptions configures a bulk upsert.
+type UpsertOptions struct {
+	Refresh         string // "", "true", "false" or "wait_for"
+	RetryOnConflict int    // Retries for concurrent updates of the same document; defaults to 3
+	UpdateOnly      bool   // Fail missing documents with status 404 instead of creating them
+}
+
+// UpsertItemError is the failure of a single document in a bulk upsert.
+type UpsertItemError struct {
+	Index  string
+	ID     string
+	Status int
+	Err    ErrorInfo
+}
+
+func (e UpsertItemError) Error() string {
+	return fmt.Sprintf("upsert %s/%s failed with status %d: %s: %s", e.Index, e.ID, e.Status, e.Err.Type, e.Err.Reason)
+}
+
+// UpsertResponse summarizes a bulk upsert. Failed lists per-document errors; the call
+// itself only returns an error when the bulk request as a whole fails.
+type UpsertResponse struct {
+	Succeeded int
+	Failed    []UpsertItemError
+}
+
+type bulkUpdateResponse struct {
+	Errors bool `json:"errors"`
+	Items  []map[string]struct {
+		Index  string     `json:"_index"`
+		ID     string     `json:"_id"`
+		Status int        `json:"status"`
+		Error  *ErrorInfo `json:"error,omitempty"`
+	} `json:"items"`
+}
+
+// Upsert merges docs into their target documents with a single bulk request. Documents
+// without strategies use a plain doc_as_upsert partial update; documents with strategies
+// use a scripted upsert that applies them field by field.
+func (c Client) Upsert(ctx context.Context, docs []UpsertDocument, opts UpsertOptions) (*UpsertResponse, error) {
+	if len(docs) == 0 {
+		return &UpsertResponse{}, nil
+	}
+	retryOnConflict := opts.RetryOnConflict
+	if retryOnConflict <= 0 {
+		retryOnConflict = 3
+	}
+
+	body, err := buildUpsertBody(docs, retryOnConflict, opts.UpdateOnly)
+	if err != nil {
+		return nil, err
+	}
+
+	reqOpts := []func(*opensearchapi.BulkRequest){
+		c.es.Bulk.WithContext(ctx),
+	}
+	if opts.Refresh != "" {
+		reqOpts = append(reqOpts, c.es.Bulk.WithRefresh(opts.Refresh))
+	}
+	res, err := c.es.Bulk(bytes.NewReader(body), reqOpts...)
+	defer CloseSafe(res)
+	if err != nil {
+		return nil, err
+	} else if err := CheckError(res); err != nil {
+		return nil, err
+	}
+
+	b, err := io.ReadAll(res.Body)
+	if err != nil {
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	var response bulkUpdateResponse
+	if err := json.Unmarshal(b, &response); err != nil {
+		return nil, fmt.Errorf("unmarshal response: %w", err)
+	}
+
+	result := &UpsertResponse{}
+	for _, item := range response.Items {
+		for _, action := range item {
+			if action.Error != nil {
+				result.Failed = append(result.Failed, UpsertItemError{
+					Index:  action.Index,
+					ID:     action.ID,
+					Status: action.Status,
+					Err:    *action.Error,
+				})
+			} else {
+				result.Succeeded++
+			}
+		}
+	}
+	return result, nil
+}
+
+func buildUpsertBody(docs []UpsertDocument, retryOnConflict int, updateOnly bool) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := json.NewEncoder(&buf)
+	for _, doc := range docs {
+		if doc.Index == "" || doc.ID == "" {
+			return nil, errors.New("upsert document requires index and id")
+		}
+		for field, strategy := range doc.Strategies {
+			switch strategy {
+			case MergeOverwrite, MergeMap, MergeList, MergeKeepMin, MergeKeepMax:
+			default:
+				return nil, fmt.Errorf("upsert %s/%s: unknown merge strategy %q for field %s", doc.Index, doc.ID, strategy, field)
+			}
+		}
+
+		action := map[string]any{
+			"update": map[string]any{
+				"_index":            doc.Index,
+				"_id":               doc.ID,
+				"retry_on_conflict": retryOnConflict,
+			},
+		}
+		var update map[string]any
+		if len(doc.Strategies) == 0 {
+			update = map[string]any{
+				"doc":           doc.Doc,
+				"doc_as_upsert": !updateOnly,
+			}
+		} else {
+			update = map[string]any{
+				"scripted_upsert": true,
+				"script": map[string]any{
+					"lang":   "painless",
+					"source": upsertMergeScript,
+					"params": map[string]any{
+						"doc":        doc.Doc,
+						"strategies": doc.Strategies,
+					},
+				},
+				"upsert": map[string]any{},
+			}
+			if updateOnly {
+				delete(update, "scripted_upsert")
+				delete(update, "upsert")
+			}
+		}
+		if err := enc.Encode(action); err != nil {
+			return nil, fmt.Errorf("marshal upsert action: %w", err)
+		}
+		if err := enc.Encode(update); err != nil {
+			return nil, fmt.Errorf("marshal upsert %s/%s: %w", doc.Index, doc.ID, err)
+		}
+	}
+	return buf.Bytes(), nil
+}
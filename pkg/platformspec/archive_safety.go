@@ -0,0 +1,310 @@
+// archive_safety.go
+package platformspec
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"log"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+)
+
+// ErrUnsafeArchivePath is returned for archive entries that would be written outside the
+// extraction directory: absolute paths, paths with '..' components, and links pointing
+// outside it.
+var ErrUnsafeArchivePath = errors.New("archive entry escapes the destination directory")
+
+// archiveEntry is the part of a zip or tar header needed to extract or vet an entry.
+type archiveEntry struct {
+	name     string
+	mode     fs.FileMode // Type bits and permissions
+	linkname string      // Target of symlinks and hard links
+	hardLink bool
+	open     func() (io.ReadCloser, error)
+}
+
+// checkArchiveEntryName returns the cleaned, slash-separated relative path of an entry
+// name, or ErrUnsafeArchivePath if it is absolute or escapes the root with '..'.
+func checkArchiveEntryName(name string) (string, error) {
+	if name == "" || strings.ContainsRune(name, 0) {
+		return "", fmt.Errorf("%w: invalid entry name %q", ErrUnsafeArchivePath, name)
+	}
+	slashed := strings.ReplaceAll(name, `\`, "/")
+	if strings.HasPrefix(slashed, "/") || filepath.IsAbs(name) || filepath.VolumeName(name) != "" || (len(slashed) > 1 && slashed[1] == ':') {
+		return "", fmt.Errorf("%w: absolute entry name '%s'", ErrUnsafeArchivePath, name)
+	}
+	cleaned := path.Clean(slashed)
+	if cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return "", fmt.Errorf("%w: entry name '%s'", ErrUnsafeArchivePath, name)
+	}
+	return cleaned, nil
+}
+
+// checkArchiveLink returns ErrUnsafeArchivePath if the link entry at cleanedName points
+// outside the archive root. Symlink targets are relative to the link's directory, hard
+// link targets to the root.
+//
+// Symlink targets may only have '..' components at their start. After any other
+// component, which may itself be a symlink, '..' is resolved from that link's target
+// rather than lexically, so a chain such as 'x/y/s -> ../..' and
+// 't -> x/y/s/../../etc/passwd' would escape although each target looks inside the root.
+func checkArchiveLink(cleanedName string, entry archiveEntry) error {
+	target := strings.ReplaceAll(entry.linkname, `\`, "/")
+	if target == "" || strings.HasPrefix(target, "/") || filepath.IsAbs(entry.linkname) || (len(target) > 1 && target[1] == ':') {
+		return fmt.Errorf("%w: link '%s' points to '%s'", ErrUnsafeArchivePath, entry.name, entry.linkname)
+	}
+	if !entry.hardLink {
+		if hasInnerDotDot(target) {
+			return fmt.Errorf("%w: link '%s' points to '%s', which has '..' after another component", ErrUnsafeArchivePath, entry.name, entry.linkname)
+		}
+		target = path.Join(path.Dir(cleanedName), target)
+	}
+	if cleaned := path.Clean(target); cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+		return fmt.Errorf("%w: link '%s' points to '%s'", ErrUnsafeArchivePath, entry.name, entry.linkname)
+	}
+	return nil
+}
+
+// hasInnerDotDot reports whether a slash-separated path has a '..' component after a
+// component other than '..' or '.'.
+func hasInnerDotDot(p string) bool {
+	descended := false
+	for _, part := range strings.Split(p, "/") {
+		switch part {
+		case "", ".":
+		case "..":
+			if descended {
+				return true
+			}
+		default:
+			descended = true
+		}
+	}
+	return false
+}
+
+// SafeJoin joins an archive entry name to destDir, returning ErrUnsafeArchivePath if the
+// result would not be inside destDir.
+func SafeJoin(destDir, name string) (string, error) {
+	cleaned, err := checkArchiveEntryName(name)
+	if err != nil {
+		return "", err
+	}
+	target := filepath.Join(destDir, filepath.FromSlash(cleaned))
+	rel, err := filepath.Rel(destDir, target)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", fmt.Errorf("%w: entry name '%s'", ErrUnsafeArchivePath, name)
+	}
+	return target, nil
+}
+
+// walkArchive calls fn for every entry of a zip, tar.gz or tar.bz2 archive, in order.
+func walkArchive(archiveData []byte, archiveType string, fn func(archiveEntry) error) error {
+	byteReader := bytes.NewReader(archiveData)
+	switch archiveType {
+	case "zip":
+		zipReader, err := zip.NewReader(byteReader, int64(len(archiveData)))
+		if err != nil {
+			return fmt.Errorf("failed to create zip reader: %w", err)
+		}
+		for _, f := range zipReader.File {
+			entry := archiveEntry{name: f.Name, mode: f.Mode(), open: f.Open}
+			if entry.mode&fs.ModeSymlink != 0 {
+				rc, err := f.Open()
+				if err != nil {
+					return fmt.Errorf("failed to open link '%s': %w", f.Name, err)
+				}
+				target, err := io.ReadAll(io.LimitReader(rc, 4096))
+				rc.Close()
+				if err != nil {
+					return fmt.Errorf("failed to read link '%s': %w", f.Name, err)
+				}
+				entry.linkname = string(target)
+			}
+			if err := fn(entry); err != nil {
+				return err
+			}
+		}
+		return nil
+
+	case "tar.gz", "tar.bz2":
+		var r io.Reader
+		if archiveType == "tar.gz" {
+			gzipReader, err := gzip.NewReader(byteReader)
+			if err != nil {
+				return fmt.Errorf("failed to create gzip reader: %w", err)
+			}
+			defer gzipReader.Close()
+			r = gzipReader
+		} else {
+			r = bzip2.NewReader(byteReader)
+		}
+		tarReader := tar.NewReader(r)
+		for {
+			header, err := tarReader.Next()
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return fmt.Errorf("failed to read next tar header: %w", err)
+			}
+			entry := archiveEntry{
+				name:     header.Name,
+				mode:     header.FileInfo().Mode(),
+				linkname: header.Linkname,
+				hardLink: header.Typeflag == tar.TypeLink,
+				open:     func() (io.ReadCloser, error) { return io.NopCloser(tarReader), nil },
+			}
+			switch header.Typeflag {
+			case tar.TypeXGlobalHeader, tar.TypeXHeader, tar.TypeGNULongName, tar.TypeGNULongLink:
+				continue // Metadata, not entries
+			}
+			if err := fn(entry); err != nil {
+				return err
+			}
+		}
+
+	default:
+		return fmt.Errorf("internal error: unexpected archive type '%s'", archiveType)
+	}
+}
+
+// checkArchiveEntriesSafe returns ErrUnsafeArchivePath if any entry of the archive would
+// escape an extraction directory, so that archives crafted for path traversal (zip-slip)
+// are rejected even when the entries the spec refers to are harmless.
+func checkArchiveEntriesSafe(archiveData []byte, archiveType string) error {
+	return walkArchive(archiveData, archiveType, func(entry archiveEntry) error {
+		cleaned, err := checkArchiveEntryName(entry.name)
+		if err != nil {
+			return err
+		}
+		if entry.mode&fs.ModeSymlink != 0 || entry.hardLink {
+			return checkArchiveLink(cleaned, entry)
+		}
+		return nil
+	})
+}
+
+// ExtractArchive extracts a zip, tar.gz or tar.bz2 archive (detected from archiveURI)
+// into destDir and returns the paths of the extracted files. Entries that would escape
+// destDir, including links pointing outside it, fail the extraction with
+// ErrUnsafeArchivePath; devices, FIFOs and other special files are skipped. Permissions
+// are normalized to 0755 for directories and executables and 0644 for other files, and
+// the extracted size is limited to MaxDownloadSizeBytes.
+func ExtractArchive(archiveData []byte, archiveURI, destDir string) ([]string, error) {
+	archiveType, err := archiveTypeForURI(archiveURI)
+	if err != nil {
+		return nil, err
+	}
+	// Vet every entry first so that a malicious archive leaves nothing behind
+	if err := checkArchiveEntriesSafe(archiveData, archiveType); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(destDir, 0o755); err != nil {
+		return nil, fmt.Errorf("create destination directory: %w", err)
+	}
+
+	var extracted []string
+	var total int64
+	err = walkArchive(archiveData, archiveType, func(entry archiveEntry) error {
+		target, err := SafeJoin(destDir, entry.name)
+		if err != nil {
+			return err
+		}
+		// A link extracted earlier must not redirect the writes of later entries
+		if err := checkNoSymlinkParents(destDir, target); err != nil {
+			return err
+		}
+		switch {
+		case entry.mode.IsDir():
+			return os.MkdirAll(target, 0o755)
+
+		case entry.mode&fs.ModeSymlink != 0:
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return err
+			}
+			if err := os.Symlink(filepath.FromSlash(strings.ReplaceAll(entry.linkname, `\`, "/")), target); err != nil {
+				return fmt.Errorf("create link '%s': %w", entry.name, err)
+			}
+			extracted = append(extracted, target)
+			return nil
+
+		case entry.hardLink:
+			source, err := SafeJoin(destDir, entry.linkname)
+			if err != nil {
+				return err
+			}
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return err
+			}
+			if err := os.Link(source, target); err != nil {
+				return fmt.Errorf("create link '%s': %w", entry.name, err)
+			}
+			extracted = append(extracted, target)
+			return nil
+
+		case entry.mode.IsRegular():
+			if err := os.MkdirAll(filepath.Dir(target), 0o755); err != nil {
+				return err
+			}
+			rc, err := entry.open()
+			if err != nil {
+				return fmt.Errorf("open '%s': %w", entry.name, err)
+			}
+			defer rc.Close()
+			mode := os.FileMode(0o644)
+			if entry.mode.Perm()&0o111 != 0 {
+				mode = 0o755
+			}
+			limited := &io.LimitedReader{R: rc, N: MaxDownloadSizeBytes - total + 1}
+			if err := writeFileAtomic(target, limited, mode); err != nil {
+				return err
+			}
+			total = MaxDownloadSizeBytes + 1 - limited.N
+			if total > MaxDownloadSizeBytes {
+				_ = os.Remove(target)
+				return fmt.Errorf("extracted size exceeds the %d bytes limit", MaxDownloadSizeBytes)
+			}
+			extracted = append(extracted, target)
+			return nil
+
+		default:
+			log.Printf("Skipping special file '%s' (mode %v) in archive '%s'.", entry.name, entry.mode, archiveURI)
+			return nil
+		}
+	})
+	if err != nil {
+		return extracted, err
+	}
+	return extracted, nil
+}
+
+// checkNoSymlinkParents returns ErrUnsafeArchivePath if a directory between destDir and
+// target is a symlink.
+func checkNoSymlinkParents(destDir, target string) error {
+	rel, err := filepath.Rel(destDir, filepath.Dir(target))
+	if err != nil || rel == "." {
+		return nil
+	}
+	current := destDir
+	for _, part := range strings.Split(rel, string(filepath.Separator)) {
+		current = filepath.Join(current, part)
+		info, err := os.Lstat(current)
+		if err != nil {
+			return nil // Not created yet
+		}
+		if info.Mode()&fs.ModeSymlink != 0 {
+			return fmt.Errorf("%w: '%s' is inside link '%s'", ErrUnsafeArchivePath, target, current)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,102 @@
+package platformspec_test
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/opengovern/og-util/pkg/platformspec"
+	"github.com/stretchr/testify/require"
+)
+
+type archiveFile struct {
+	name     string
+	content  string
+	linkname string
+	typeflag byte
+}
+
+func tarGz(t *testing.T, files ...archiveFile) []byte {
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	for _, f := range files {
+		header := &tar.Header{Name: f.name, Linkname: f.linkname, Typeflag: f.typeflag, Mode: 0o644, Size: int64(len(f.content))}
+		switch f.typeflag {
+		case tar.TypeDir:
+			header.Mode = 0o755
+		case tar.TypeSymlink, tar.TypeLink:
+			header.Size = 0
+		}
+		require.NoError(t, tw.WriteHeader(header))
+		if header.Size > 0 {
+			_, err := tw.Write([]byte(f.content))
+			require.NoError(t, err)
+		}
+	}
+	require.NoError(t, tw.Close())
+	require.NoError(t, gz.Close())
+	return buf.Bytes()
+}
+
+func TestExtractArchive(t *testing.T) {
+	r := require.New(t)
+
+	dest := filepath.Join(t.TempDir(), "dest")
+	extracted, err := platformspec.ExtractArchive(tarGz(t,
+		archiveFile{name: "bin/", typeflag: tar.TypeDir},
+		archiveFile{name: "bin/tool", content: "binary", typeflag: tar.TypeReg},
+		archiveFile{name: "lib/tool", linkname: "../bin/tool", typeflag: tar.TypeSymlink},
+		archiveFile{name: "tool", linkname: "bin/tool", typeflag: tar.TypeLink},
+	), "https://example.com/tool.tar.gz", dest)
+	r.NoError(err)
+	r.Len(extracted, 3)
+	for _, name := range []string{"bin/tool", "lib/tool", "tool"} {
+		content, err := os.ReadFile(filepath.Join(dest, name))
+		r.NoError(err)
+		r.Equal("binary", string(content))
+	}
+}
+
+func TestExtractArchiveRejectsUnsafeEntries(t *testing.T) {
+	for name, files := range map[string][]archiveFile{
+		"dot-dot entry":    {{name: "../evil", content: "x", typeflag: tar.TypeReg}},
+		"inner dot-dot":    {{name: "a/../../evil", content: "x", typeflag: tar.TypeReg}},
+		"absolute entry":   {{name: "/etc/evil", content: "x", typeflag: tar.TypeReg}},
+		"absolute symlink": {{name: "s", linkname: "/etc/passwd", typeflag: tar.TypeSymlink}},
+		"escaping symlink": {{name: "a/s", linkname: "../../etc/passwd", typeflag: tar.TypeSymlink}},
+		"escaping hard link": {
+			{name: "h", linkname: "../etc/passwd", typeflag: tar.TypeLink},
+		},
+		"chained symlinks": {
+			{name: "x/y/s", linkname: "../..", typeflag: tar.TypeSymlink},
+			{name: "t", linkname: "x/y/s/../../etc/passwd", typeflag: tar.TypeSymlink},
+		},
+		"write through symlink": {
+			{name: "s", linkname: ".", typeflag: tar.TypeSymlink},
+			{name: "s/evil", content: "x", typeflag: tar.TypeReg},
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			dest := filepath.Join(t.TempDir(), "dest")
+			_, err := platformspec.ExtractArchive(tarGz(t, files...), "https://example.com/a.tar.gz", dest)
+			require.ErrorIs(t, err, platformspec.ErrUnsafeArchivePath)
+			_, statErr := os.Stat(filepath.Join(filepath.Dir(dest), "evil"))
+			require.True(t, os.IsNotExist(statErr))
+		})
+	}
+
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	w, err := zw.Create("../../evil")
+	require.NoError(t, err)
+	_, err = w.Write([]byte("x"))
+	require.NoError(t, err)
+	require.NoError(t, zw.Close())
+	_, err = platformspec.ExtractArchive(buf.Bytes(), "https://example.com/a.zip", filepath.Join(t.TempDir(), "dest"))
+	require.ErrorIs(t, err, platformspec.ErrUnsafeArchivePath)
+}
@@ -20,10 +20,6 @@ import (
 	"strconv"
 	"strings"
 	"time"
-
-	"oras.land/oras-go/v2/registry"
-	"oras.land/oras-go/v2/registry/remote"
-	"oras.land/oras-go/v2/registry/remote/errcode"
 )
 
 // --- Configuration Constants (Duplicated here for clarity, consider centralizing) ---
@@ -35,9 +31,9 @@ const (
 	MaxDownloadSizeBytes   = 1 * 1024 * 1024 * 1024 // 1 GiB
 )
 
-// validateImageManifestExists checks if an image manifest exists in the remote registry using ORAS libraries.
-// It performs retries with exponential backoff for transient network or server errors.
-func (v *defaultValidator) validateImageManifestExists(imageURI string) error {
+// checkImageDigestURI checks that an image URI is pinned by digest, as required for the
+// existence check.
+func checkImageDigestURI(imageURI string) error {
 	if !isNonEmpty(imageURI) {
 		return errors.New("image URI cannot be empty for existence check")
 	}
@@ -45,78 +41,7 @@ func (v *defaultValidator) validateImageManifestExists(imageURI string) error {
 	if !imageDigestRegex.MatchString(imageURI) {
 		return fmt.Errorf("image URI ('%s') must be in digest format (e.g., repo/image@sha256:...) for existence check", imageURI)
 	}
-
-	log.Printf("--- Checking Image Manifest Existence (using ORAS): %s ---", imageURI)
-	var lastErr error
-	backoff := InitialBackoffDuration
-
-	for attempt := 0; attempt <= MaxRegistryRetries; attempt++ {
-		if attempt > 0 {
-			jitter := time.Duration(rand.Int63n(int64(backoff) / 2)) // Add jitter
-			waitTime := backoff + jitter
-			log.Printf("Image resolve attempt %d for '%s' failed. Retrying in %v...", attempt, imageURI, waitTime)
-			time.Sleep(waitTime)
-			backoff *= 2 // Exponential backoff
-		}
-
-		log.Printf("Image resolve attempt %d/%d for %s...", attempt+1, MaxRegistryRetries+1, imageURI)
-		ctx, cancel := context.WithTimeout(context.Background(), OverallRequestTimeout) // Apply overall timeout
-
-		var err error // Declare err here for the scope
-
-		// 1. Parse the image reference
-		var ref registry.Reference
-		ref, err = registry.ParseReference(imageURI)
-		if err != nil {
-			cancel() // Release context resources
-			return fmt.Errorf("failed to parse image reference '%s': %w", imageURI, err)
-		}
-
-		// 2. Create a remote repository client
-		var repo registry.Repository
-		// *** FIX: Use RepositoryWithRegistry() to include the hostname ***
-		// FIX: Combine Host() and Repository() for the full name
-		repoNameWithRegistry := fmt.Sprintf("%s/%s", ref.Host(), ref.Repository)
-		log.Printf("[Debug] Creating remote repository client for: %s", repoNameWithRegistry) // Add debug log
-		repo, err = remote.NewRepository(repoNameWithRegistry)
-		if err != nil {
-			lastErr = fmt.Errorf("attempt %d: failed to create ORAS repository client for '%s': %w", attempt+1, repoNameWithRegistry, err)
-			cancel()
-			continue // Retry might not help, but let's follow the loop structure
-		}
-
-		// 3. Resolve the manifest by digest
-		log.Printf("Attempting to resolve digest '%s' in repository '%s'...", ref.Reference, repoNameWithRegistry) // Log full name
-		_, err = repo.Resolve(ctx, ref.Reference)                                                                  // ref.Reference contains the digest
-		cancel()                                                                                                   // Release context resources after the operation
-
-		// 4. Handle results
-		if err == nil {
-			log.Printf("Successfully resolved image manifest for '%s'.", imageURI)
-			return nil // Success! Manifest exists.
-		}
-
-		// --- Error Handling ---
-		lastErr = fmt.Errorf("attempt %d: failed to resolve image manifest for '%s': %w", attempt+1, imageURI, err)
-		log.Printf("ORAS resolve error details: %v", err)
-
-		var errResp *errcode.ErrorResponse
-		if errors.As(err, &errResp) {
-			log.Printf("Registry returned HTTP status %d: %s", errResp.StatusCode, errResp.Error())
-			if errResp.StatusCode >= 400 && errResp.StatusCode < 500 {
-				log.Printf("Attempt %d: Received client error %d. Aborting retries.", attempt+1, errResp.StatusCode)
-				return lastErr // Return the specific error, don't retry
-			}
-		} else if errors.Is(err, context.DeadlineExceeded) {
-			log.Printf("Attempt %d: Operation timed out.", attempt+1)
-		} else if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
-			log.Printf("Attempt %d: Network timeout detected.", attempt+1)
-		} else {
-			log.Printf("Attempt %d: Encountered non-HTTP or unknown error type. Retrying allowed.", attempt+1)
-		}
-	} // End retry loop
-
-	return fmt.Errorf("failed to resolve image manifest '%s' after %d attempts: %w", imageURI, MaxRegistryRetries+1, lastErr)
+	return nil
 }
 
 // validateSingleDownloadableComponent downloads, verifies checksum, and checks path (if applicable) for one component.
@@ -127,6 +52,13 @@ func (v *defaultValidator) validateSingleDownloadableComponent(component Compone
 		return nil, fmt.Errorf("%s validation failed: component URI is missing", componentName)
 	}
 	log.Printf("Component URI: %s", component.URI)
+
+	// The artifact stays in memory until it is verified
+	endWait := v.stats.startPhase(PhaseMemoryWait)
+	release := v.archiveSlots.acquire(componentName)
+	endWait()
+	defer release()
+
 	log.Printf("Checksum provided: %s", component.Checksum)            // Log if checksum is expected
 	log.Printf("PathInArchive specified: %s", component.PathInArchive) // Log if path check is needed
 
@@ -157,6 +89,14 @@ func (v *defaultValidator) validateSingleDownloadableComponent(component Compone
 		log.Printf("Component %s validated (no path-in-archive specified).", componentName)
 	}
 
+	// 4. Validate declared files (if any)
+	if len(component.Files) > 0 {
+		log.Printf("Verifying %d declared files within downloaded archive for %s...", len(component.Files), componentName)
+		if err := v.validateArchiveFiles(downloadedData, component.Files, component.URI); err != nil {
+			return nil, fmt.Errorf("%s file verification failed for URI '%s': %w", componentName, component.URI, err)
+		}
+	}
+
 	log.Printf("--- Downloadable Component Validation Successful: %s ---", componentName)
 	return downloadedData, nil
 }
@@ -164,13 +104,21 @@ func (v *defaultValidator) validateSingleDownloadableComponent(component Compone
 // downloadWithRetry attempts to download a file from a URL with exponential backoff, jitter, size limits, and status checks.
 // It now also explicitly checks if the downloaded content is empty (0 bytes).
 func (v *defaultValidator) downloadWithRetry(url string) ([]byte, error) {
+	defer v.stats.startPhase(PhaseDownload)()
+	maxBytes := v.maxArchiveBytes()
 	var lastErr error
 	backoff := InitialBackoffDuration
+	var retryAfter time.Duration // Set when the host asked to wait
 
 	for attempt := 0; attempt <= MaxDownloadRetries; attempt++ {
 		if attempt > 0 {
 			jitter := time.Duration(rand.Int63n(int64(backoff) / 2))
 			waitTime := backoff + jitter
+			if retryAfter > 0 {
+				waitTime = retryAfter
+				recordRateLimitWait(waitTime)
+				retryAfter = 0
+			}
 			log.Printf("Download attempt %d for '%s' failed. Retrying in %v...", attempt, url, waitTime)
 			time.Sleep(waitTime)
 			backoff *= 2 // Exponential backoff
@@ -188,8 +136,7 @@ func (v *defaultValidator) downloadWithRetry(url string) ([]byte, error) {
 		// Consider adding User-Agent?
 		// req.Header.Set("User-Agent", "platformspec-validator/1.0")
 
-		// httpClient is assumed to be initialized in validator.go init()
-		resp, err := httpClient.Do(req)
+		resp, err := v.client().Do(req)
 		if err != nil {
 			lastErr = fmt.Errorf("attempt %d: HTTP request failed for '%s': %w", attempt+1, url, err)
 			if errors.Is(err, context.DeadlineExceeded) {
@@ -216,6 +163,15 @@ func (v *defaultValidator) downloadWithRetry(url string) ([]byte, error) {
 				log.Printf("Attempt %d: Received client error %d. Aborting retries for '%s'.", attempt+1, resp.StatusCode, url)
 				return nil, lastErr
 			}
+			if resp.StatusCode == http.StatusTooManyRequests {
+				recordRateLimitHit(req.URL.Host, false)
+				if wait, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+					if wait > MaxRetryAfterWait {
+						return nil, fmt.Errorf("rate limit for '%s' resets in %v, longer than the maximum wait of %v: %w", url, wait.Round(time.Second), MaxRetryAfterWait, lastErr)
+					}
+					retryAfter = wait
+				}
+			}
 			log.Printf("Attempt %d: Received status %d. Allowing retry for '%s'.", attempt+1, resp.StatusCode, url)
 			continue
 		}
@@ -226,10 +182,10 @@ func (v *defaultValidator) downloadWithRetry(url string) ([]byte, error) {
 		if contentLengthHeader != "" {
 			if parsedSize, parseErr := strconv.ParseInt(contentLengthHeader, 10, 64); parseErr == nil && parsedSize >= 0 {
 				expectedSize = parsedSize
-				if expectedSize > MaxDownloadSizeBytes {
+				if expectedSize > maxBytes {
 					resp.Body.Close()
 					cancel()
-					return nil, fmt.Errorf("attempt %d: declared content length %d bytes exceeds maximum allowed %d bytes for '%s'", attempt+1, expectedSize, MaxDownloadSizeBytes, url)
+					return nil, fmt.Errorf("attempt %d: declared content length %d bytes exceeds maximum allowed %d bytes for '%s'", attempt+1, expectedSize, maxBytes, url)
 				}
 				log.Printf("Attempt %d: Content-Length header indicates %d bytes for '%s'.", attempt+1, expectedSize, url)
 			} else {
@@ -239,7 +195,7 @@ func (v *defaultValidator) downloadWithRetry(url string) ([]byte, error) {
 			log.Printf("Attempt %d: Warning - Content-Length header missing for '%s'. Proceeding with download limit.", attempt+1, url)
 		}
 
-		limitedReader := io.LimitedReader{R: resp.Body, N: MaxDownloadSizeBytes + 1}
+		limitedReader := io.LimitedReader{R: resp.Body, N: maxBytes + 1}
 		bodyBytes, err := io.ReadAll(&limitedReader)
 		readErr := err
 		closeErr := resp.Body.Close()
@@ -254,7 +210,7 @@ func (v *defaultValidator) downloadWithRetry(url string) ([]byte, error) {
 		}
 		if limitedReader.N == 0 {
 			// File exceeded limit
-			return nil, fmt.Errorf("attempt %d: downloaded file from '%s' exceeds maximum allowed size of %d bytes", attempt+1, url, MaxDownloadSizeBytes)
+			return nil, fmt.Errorf("attempt %d: downloaded file from '%s' exceeds maximum allowed size of %d bytes", attempt+1, url, maxBytes)
 		}
 
 		// *** ADDED CHECK: Ensure downloaded file is not empty (0 KB) ***
@@ -284,28 +240,24 @@ func (v *defaultValidator) downloadWithRetry(url string) ([]byte, error) {
 
 // verifyChecksum compares the SHA256 hash of data against an expected checksum string (e.g., "sha256:abc...").
 func (v *defaultValidator) verifyChecksum(data []byte, expectedChecksum string) error {
+	defer v.stats.startPhase(PhaseArchive)()
 	if !isNonEmpty(expectedChecksum) {
 		log.Println("Checksum verification skipped: No checksum provided in the specification.")
 		return nil
 	}
+	return verifyChecksumReader(bytes.NewReader(data), expectedChecksum)
+}
 
-	parts := strings.SplitN(expectedChecksum, ":", 2)
-	if len(parts) != 2 || !isNonEmpty(parts[0]) || !isNonEmpty(parts[1]) {
-		return fmt.Errorf("invalid checksum format '%s', expected format 'algorithm:hash' (e.g., 'sha256:...')", expectedChecksum)
-	}
-
-	algo, expectedHash := strings.ToLower(parts[0]), strings.ToLower(parts[1])
-
-	if algo != "sha256" {
-		return fmt.Errorf("unsupported checksum algorithm '%s', only 'sha256' is supported", algo)
-	}
-
-	if len(expectedHash) != 64 || !isHex(expectedHash) {
-		return fmt.Errorf("invalid expected sha256 hash format '%s', must be 64 hexadecimal characters", expectedHash)
+// verifyChecksumReader compares the SHA256 hash of everything read from r against an
+// expected checksum string (e.g., "sha256:abc...").
+func verifyChecksumReader(r io.Reader, expectedChecksum string) error {
+	expectedHash, err := parseChecksum(expectedChecksum)
+	if err != nil {
+		return err
 	}
 
 	hasher := sha256.New()
-	if _, err := io.Copy(hasher, bytes.NewReader(data)); err != nil {
+	if _, err := io.Copy(hasher, r); err != nil {
 		return fmt.Errorf("failed to calculate sha256 hash: %w", err)
 	}
 	actualHash := hex.EncodeToString(hasher.Sum(nil))
@@ -318,6 +270,26 @@ func (v *defaultValidator) verifyChecksum(data []byte, expectedChecksum string)
 	return nil
 }
 
+// parseChecksum validates a checksum string (e.g., "sha256:abc...") and returns its
+// lowercase hex hash.
+func parseChecksum(checksum string) (string, error) {
+	parts := strings.SplitN(checksum, ":", 2)
+	if len(parts) != 2 || !isNonEmpty(parts[0]) || !isNonEmpty(parts[1]) {
+		return "", fmt.Errorf("invalid checksum format '%s', expected format 'algorithm:hash' (e.g., 'sha256:...')", checksum)
+	}
+
+	algo, hash := strings.ToLower(parts[0]), strings.ToLower(parts[1])
+
+	if algo != "sha256" {
+		return "", fmt.Errorf("unsupported checksum algorithm '%s', only 'sha256' is supported", algo)
+	}
+
+	if len(hash) != 64 || !isHex(hash) {
+		return "", fmt.Errorf("invalid expected sha256 hash format '%s', must be 64 hexadecimal characters", hash)
+	}
+	return hash, nil
+}
+
 // isHex checks if a string contains only hexadecimal characters.
 func isHex(s string) bool {
 	for _, r := range s {
@@ -331,6 +303,7 @@ func isHex(s string) bool {
 // validateArchivePathExists checks if a specific file path exists within various archive formats (zip, tar.gz, tar.bz2).
 // It reads the archive from the provided byte slice.
 func (v *defaultValidator) validateArchivePathExists(archiveData []byte, pathInArchive string, archiveURI string) error {
+	defer v.stats.startPhase(PhaseArchive)()
 	if len(archiveData) == 0 {
 		// This check is slightly redundant now given the check in downloadWithRetry, but harmless.
 		return errors.New("cannot check path in empty archive data")
@@ -342,22 +315,20 @@ func (v *defaultValidator) validateArchivePathExists(archiveData []byte, pathInA
 	if !isNonEmpty(cleanedPath) || cleanedPath == "." {
 		return fmt.Errorf("invalid path-in-archive specified: '%s'", pathInArchive)
 	}
+	if _, err := checkArchiveEntryName(strings.Trim(pathInArchive, "/")); err != nil {
+		return fmt.Errorf("invalid path-in-archive specified: %w", err)
+	}
 
 	log.Printf("Attempting to detect archive type for URI: %s", archiveURI)
-	archiveType := ""
-	lowerURI := strings.ToLower(archiveURI)
-	if strings.HasSuffix(lowerURI, ".tar.gz") || strings.HasSuffix(lowerURI, ".tgz") {
-		archiveType = "tar.gz"
-	} else if strings.HasSuffix(lowerURI, ".tar.bz2") || strings.HasSuffix(lowerURI, ".tbz2") {
-		archiveType = "tar.bz2"
-	} else if strings.HasSuffix(lowerURI, ".zip") {
-		archiveType = "zip"
-	} else {
-		return fmt.Errorf("unsupported or unrecognized archive extension for URI '%s'. Supported: .zip, .tar.gz, .tgz, .tar.bz2, .tbz2", archiveURI)
+	archiveType, err := archiveTypeForURI(archiveURI)
+	if err != nil {
+		return err
+	}
+	if err := checkArchiveEntriesSafe(archiveData, archiveType); err != nil {
+		return fmt.Errorf("archive '%s' is unsafe to extract: %w", archiveURI, err)
 	}
 	log.Printf("Detected archive type: %s. Searching for path: '%s'", archiveType, cleanedPath)
 
-	var err error
 	found := false
 	byteReader := bytes.NewReader(archiveData) // Use a reader for archive libraries
 
@@ -374,6 +345,9 @@ func (v *defaultValidator) validateArchivePathExists(archiveData []byte, pathInA
 				if file.FileInfo().IsDir() {
 					return fmt.Errorf("path '%s' in zip archive '%s' is a directory, not a file", cleanedPath, archiveURI)
 				}
+				if !file.Mode().IsRegular() {
+					return fmt.Errorf("path '%s' in zip archive '%s' is not a regular file (mode: %v)", cleanedPath, archiveURI, file.Mode())
+				}
 				rc, openErr := file.Open()
 				if openErr != nil {
 					return fmt.Errorf("found path '%s' in zip '%s', but failed to open it: %w", cleanedPath, archiveURI, openErr)
@@ -422,6 +396,19 @@ func (v *defaultValidator) validateArchivePathExists(archiveData []byte, pathInA
 	return nil
 }
 
+// archiveTypeForURI detects the archive type ("zip", "tar.gz" or "tar.bz2") from the extension of the URI.
+func archiveTypeForURI(archiveURI string) (string, error) {
+	lowerURI := strings.ToLower(archiveURI)
+	if strings.HasSuffix(lowerURI, ".tar.gz") || strings.HasSuffix(lowerURI, ".tgz") {
+		return "tar.gz", nil
+	} else if strings.HasSuffix(lowerURI, ".tar.bz2") || strings.HasSuffix(lowerURI, ".tbz2") {
+		return "tar.bz2", nil
+	} else if strings.HasSuffix(lowerURI, ".zip") {
+		return "zip", nil
+	}
+	return "", fmt.Errorf("unsupported or unrecognized archive extension for URI '%s'. Supported: .zip, .tar.gz, .tgz, .tar.bz2, .tbz2", archiveURI)
+}
+
 // checkTarArchive iterates through a tar reader to find and validate a specific file path.
 func (v *defaultValidator) checkTarArchive(tarReader *tar.Reader, cleanedPath string, archiveURI string, archiveType string) (bool, error) {
 	filesChecked := 0
@@ -0,0 +1,330 @@
+// builder.go
+package platformspec
+
+import (
+	"fmt"
+	"slices"
+
+	"github.com/opengovern/og-util/pkg/integration"
+	"gopkg.in/yaml.v3"
+)
+
+// PluginSpecBuilder builds a plugin specification programmatically, e.g. in release
+// automation, with the validation rules of ProcessSpecification:
+//
+//	spec, err := NewPluginSpecBuilder().
+//		WithName("aws").
+//		WithVersion("1.2.0").
+//		WithSupportedPlatformVersions(">=1.0.0").
+//		WithMetadata(metadata).
+//		WithDiscoveryTask(NewTaskSpecBuilder().WithID("aws-describer")...Spec()).
+//		Build()
+//
+// The With methods never fail; every error is returned by Build.
+type PluginSpecBuilder struct {
+	spec      PluginSpecification
+	validator Validator
+}
+
+// NewPluginSpecBuilder returns a builder of a v1 plugin specification.
+func NewPluginSpecBuilder() *PluginSpecBuilder {
+	return &PluginSpecBuilder{spec: PluginSpecification{APIVersion: APIVersionV1, Type: SpecTypePlugin}}
+}
+
+func (b *PluginSpecBuilder) WithName(name string) *PluginSpecBuilder {
+	b.spec.Name = name
+	return b
+}
+
+func (b *PluginSpecBuilder) WithVersion(version string) *PluginSpecBuilder {
+	b.spec.Version = version
+	return b
+}
+
+func (b *PluginSpecBuilder) WithIntegrationType(integrationType integration.Type) *PluginSpecBuilder {
+	b.spec.IntegrationType = integrationType
+	return b
+}
+
+// WithSupportedPlatformVersions sets the semver constraints of the platform versions
+// the plugin supports, e.g. ">=1.0.0".
+func (b *PluginSpecBuilder) WithSupportedPlatformVersions(constraints ...string) *PluginSpecBuilder {
+	b.spec.SupportedPlatformVersions = slices.Clone(constraints)
+	return b
+}
+
+func (b *PluginSpecBuilder) WithMetadata(metadata Metadata) *PluginSpecBuilder {
+	b.spec.Metadata = metadata
+	return b
+}
+
+// WithDiscoveryTask embeds the discovery task, e.g. as built by TaskSpecBuilder.Spec.
+// It replaces a task set with WithDiscoveryTaskID.
+func (b *PluginSpecBuilder) WithDiscoveryTask(task *TaskSpecification) *PluginSpecBuilder {
+	b.spec.Components.Discovery = DiscoveryComponent{TaskSpec: task.DeepCopy()}
+	return b
+}
+
+// WithDiscoveryTaskID refers to a discovery task published on its own. It replaces a
+// task set with WithDiscoveryTask.
+func (b *PluginSpecBuilder) WithDiscoveryTaskID(taskID string) *PluginSpecBuilder {
+	b.spec.Components.Discovery = DiscoveryComponent{TaskID: taskID}
+	return b
+}
+
+func (b *PluginSpecBuilder) WithPlatformBinary(component Component) *PluginSpecBuilder {
+	b.spec.Components.PlatformBinary = component
+	return b
+}
+
+func (b *PluginSpecBuilder) WithCloudQLBinary(component Component) *PluginSpecBuilder {
+	b.spec.Components.CloudQLBinary = component
+	return b
+}
+
+func (b *PluginSpecBuilder) WithDocs(docs DocsComponent) *PluginSpecBuilder {
+	b.spec.Components.Docs = &docs
+	return b
+}
+
+func (b *PluginSpecBuilder) WithSampleData(component Component) *PluginSpecBuilder {
+	b.spec.SampleData = &component
+	return b
+}
+
+// WithTag sets the values of a tag.
+func (b *PluginSpecBuilder) WithTag(key string, values ...string) *PluginSpecBuilder {
+	if b.spec.Tags == nil {
+		b.spec.Tags = make(map[string]StringOrSlice)
+	}
+	b.spec.Tags[key] = StringOrSlice(slices.Clone(values))
+	return b
+}
+
+// WithClassification adds a classification path, e.g. ("Cloud", "AWS", "Compute").
+func (b *PluginSpecBuilder) WithClassification(path ...string) *PluginSpecBuilder {
+	b.spec.Classification = append(b.spec.Classification, slices.Clone(path))
+	return b
+}
+
+func (b *PluginSpecBuilder) WithCatalog(catalog PluginCatalog) *PluginSpecBuilder {
+	b.spec.Catalog = &catalog
+	return b
+}
+
+func (b *PluginSpecBuilder) WithProvenance(provenance Provenance) *PluginSpecBuilder {
+	b.spec.Provenance = &provenance
+	return b
+}
+
+func (b *PluginSpecBuilder) WithCapabilities(capabilities ...Capability) *PluginSpecBuilder {
+	b.spec.Capabilities = slices.Clone(capabilities)
+	return b
+}
+
+func (b *PluginSpecBuilder) WithPermissions(permissions Permissions) *PluginSpecBuilder {
+	b.spec.Permissions = &permissions
+	return b
+}
+
+// WithValidator sets the validator Build uses, e.g. one with custom limits;
+// NewDefaultValidator() if unset.
+func (b *PluginSpecBuilder) WithValidator(validator Validator) *PluginSpecBuilder {
+	b.validator = validator
+	return b
+}
+
+// Build validates the specification as ProcessSpecification does for a plugin file,
+// without downloading artifacts, and returns it frozen.
+func (b *PluginSpecBuilder) Build() (*PluginSpecification, error) {
+	spec, err := buildSpec(b.validator, &b.spec, "PluginSpecBuilder")
+	if err != nil {
+		return nil, err
+	}
+	return spec.(*PluginSpecification), nil
+}
+
+// BuildYAML builds the specification and returns it as canonical YAML (see Canonicalize).
+func (b *PluginSpecBuilder) BuildYAML() ([]byte, error) {
+	spec, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+	return Canonicalize(spec, FormatYAML)
+}
+
+// TaskSpecBuilder builds a task specification programmatically, either standalone with
+// Build or to embed in a plugin with Spec. The With methods never fail; every error is
+// returned by Build.
+type TaskSpecBuilder struct {
+	spec      TaskSpecification
+	validator Validator
+}
+
+// NewTaskSpecBuilder returns a builder of an enabled task specification.
+func NewTaskSpecBuilder() *TaskSpecBuilder {
+	return &TaskSpecBuilder{spec: TaskSpecification{Type: SpecTypeTask, IsEnabled: true}}
+}
+
+func (b *TaskSpecBuilder) WithID(id string) *TaskSpecBuilder {
+	b.spec.ID = id
+	return b
+}
+
+func (b *TaskSpecBuilder) WithName(name string) *TaskSpecBuilder {
+	b.spec.Name = name
+	return b
+}
+
+func (b *TaskSpecBuilder) WithDescription(description string) *TaskSpecBuilder {
+	b.spec.Description = description
+	return b
+}
+
+func (b *TaskSpecBuilder) WithEnabled(enabled bool) *TaskSpecBuilder {
+	b.spec.IsEnabled = enabled
+	return b
+}
+
+// WithMetadata sets the metadata of a standalone task; embedded tasks inherit the
+// plugin's.
+func (b *TaskSpecBuilder) WithMetadata(metadata Metadata) *TaskSpecBuilder {
+	b.spec.Metadata = &metadata
+	return b
+}
+
+func (b *TaskSpecBuilder) WithSupportedPlatformVersions(constraints ...string) *TaskSpecBuilder {
+	b.spec.SupportedPlatformVersions = slices.Clone(constraints)
+	return b
+}
+
+// WithImageURL sets the task image, which must be pinned by digest (image@sha256:...).
+func (b *TaskSpecBuilder) WithImageURL(imageURL string) *TaskSpecBuilder {
+	b.spec.ImageURL = imageURL
+	return b
+}
+
+func (b *TaskSpecBuilder) WithSteampipePluginName(name string) *TaskSpecBuilder {
+	b.spec.SteampipePluginName = name
+	return b
+}
+
+func (b *TaskSpecBuilder) WithArtifactsURL(artifactsURL string) *TaskSpecBuilder {
+	b.spec.ArtifactsURL = artifactsURL
+	return b
+}
+
+func (b *TaskSpecBuilder) WithCommand(command ...string) *TaskSpecBuilder {
+	b.spec.Command = slices.Clone(command)
+	return b
+}
+
+func (b *TaskSpecBuilder) WithTimeout(timeout string) *TaskSpecBuilder {
+	b.spec.Timeout = timeout
+	return b
+}
+
+func (b *TaskSpecBuilder) WithScaleConfig(scaleConfig ScaleConfig) *TaskSpecBuilder {
+	b.spec.ScaleConfig = scaleConfig
+	return b
+}
+
+func (b *TaskSpecBuilder) WithNatsConfig(natsConfig NatsConfig) *TaskSpecBuilder {
+	b.spec.NatsConfig = natsConfig
+	return b
+}
+
+func (b *TaskSpecBuilder) WithParams(params ...string) *TaskSpecBuilder {
+	b.spec.Params = slices.Clone(params)
+	return b
+}
+
+func (b *TaskSpecBuilder) WithConfigs(configs ...interface{}) *TaskSpecBuilder {
+	b.spec.Configs = slices.Clone(configs)
+	return b
+}
+
+// WithRunSchedule adds a run_schedule entry running the task every frequency (e.g.
+// "1d" or a cron expression) with params.
+func (b *TaskSpecBuilder) WithRunSchedule(id, frequency string, params map[string]any) *TaskSpecBuilder {
+	b.spec.RunSchedule = append(b.spec.RunSchedule, RunScheduleEntry{ID: id, Params: params, Frequency: frequency})
+	return b
+}
+
+// WithTag sets the values of a tag.
+func (b *TaskSpecBuilder) WithTag(key string, values ...string) *TaskSpecBuilder {
+	if b.spec.Tags == nil {
+		b.spec.Tags = make(map[string]StringOrSlice)
+	}
+	b.spec.Tags[key] = StringOrSlice(slices.Clone(values))
+	return b
+}
+
+// WithClassification adds a classification path.
+func (b *TaskSpecBuilder) WithClassification(path ...string) *TaskSpecBuilder {
+	b.spec.Classification = append(b.spec.Classification, slices.Clone(path))
+	return b
+}
+
+func (b *TaskSpecBuilder) WithProvenance(provenance Provenance) *TaskSpecBuilder {
+	b.spec.Provenance = &provenance
+	return b
+}
+
+func (b *TaskSpecBuilder) WithIncremental(incremental IncrementalDescribe) *TaskSpecBuilder {
+	b.spec.Incremental = &incremental
+	return b
+}
+
+func (b *TaskSpecBuilder) WithPermissions(permissions Permissions) *TaskSpecBuilder {
+	b.spec.Permissions = &permissions
+	return b
+}
+
+// WithValidator sets the validator Build uses; NewDefaultValidator() if unset.
+func (b *TaskSpecBuilder) WithValidator(validator Validator) *TaskSpecBuilder {
+	b.validator = validator
+	return b
+}
+
+// Spec returns a copy of the task as built so far, unvalidated, e.g. to embed it in a
+// plugin with PluginSpecBuilder.WithDiscoveryTask, which validates it. Embedded tasks
+// inherit the metadata, supported platform versions and provenance of the plugin and
+// must not set their own.
+func (b *TaskSpecBuilder) Spec() *TaskSpecification {
+	return b.spec.DeepCopy()
+}
+
+// Build validates the specification as ProcessSpecification does for a v1 standalone
+// task file, without checking its image, and returns it frozen.
+func (b *TaskSpecBuilder) Build() (*TaskSpecification, error) {
+	standalone := b.spec
+	standalone.APIVersion = APIVersionV1
+	spec, err := buildSpec(b.validator, &standalone, "TaskSpecBuilder")
+	if err != nil {
+		return nil, err
+	}
+	return spec.(*TaskSpecification), nil
+}
+
+// BuildYAML builds the specification and returns it as canonical YAML (see Canonicalize).
+func (b *TaskSpecBuilder) BuildYAML() ([]byte, error) {
+	spec, err := b.Build()
+	if err != nil {
+		return nil, err
+	}
+	return Canonicalize(spec, FormatYAML)
+}
+
+// buildSpec validates a built specification by processing its YAML, so that builders
+// accept exactly what ProcessSpecification accepts from files.
+func buildSpec(validator Validator, spec interface{}, builderName string) (interface{}, error) {
+	if validator == nil {
+		validator = NewDefaultValidator()
+	}
+	data, err := yaml.Marshal(spec)
+	if err != nil {
+		return nil, fmt.Errorf("%s: failed to marshal specification: %w", builderName, err)
+	}
+	return validator.ProcessSpecification(data, builderName, "", ArtifactTypeAll, true)
+}
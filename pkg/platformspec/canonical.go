@@ -0,0 +1,168 @@
+// canonical.go
+package platformspec
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// canonicalDefaultComment is attached (YAML only) to every field whose value was
+// materialized by Canonicalize rather than present in the input specification.
+const canonicalDefaultComment = "default applied"
+
+// appliedDefault records a single default materialized during canonicalization.
+// Path is the sequence of YAML keys leading to the defaulted field.
+type appliedDefault struct {
+	Path  []string
+	Value string
+}
+
+// Canonicalize re-serializes a specification (as returned by ProcessSpecification)
+// into a deterministic representation suitable for storage, diffing, and signing.
+//
+// The output has a stable key order (struct field order for known fields, sorted
+// keys for maps), uses the YAML field names for both formats, and has all known
+// defaults materialized. In YAML output, every materialized default is annotated
+// with a trailing comment. JSON has no comment syntax, so defaults are materialized
+// silently there.
+//
+// The input spec is never modified. Supported format values are FormatYAML (also
+// used when format is empty) and FormatJSON.
+func Canonicalize(spec interface{}, format string) ([]byte, error) {
+	if spec == nil {
+		return nil, errors.New("specification cannot be nil for canonicalization")
+	}
+
+	outputFormat := strings.ToLower(strings.TrimSpace(format))
+	if outputFormat == "" {
+		outputFormat = FormatYAML
+	}
+	if outputFormat != FormatYAML && outputFormat != FormatJSON {
+		return nil, fmt.Errorf("unsupported canonical format '%s'. Must be '%s' or '%s'", format, FormatYAML, FormatJSON)
+	}
+
+	// Work on a private copy so materializing defaults never touches the caller's spec.
+	specCopy, err := deepCopySpec(spec)
+	if err != nil {
+		return nil, err
+	}
+	defaults := materializeDefaults(specCopy)
+
+	var node yaml.Node
+	if err := node.Encode(specCopy); err != nil {
+		return nil, fmt.Errorf("failed to encode specification for canonicalization: %w", err)
+	}
+
+	if outputFormat == FormatJSON {
+		var generic map[string]interface{}
+		if err := node.Decode(&generic); err != nil {
+			return nil, fmt.Errorf("failed to convert specification to generic form: %w", err)
+		}
+		// encoding/json sorts map keys, which gives us a stable order.
+		out, err := json.MarshalIndent(generic, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("failed to marshal canonical specification to JSON: %w", err)
+		}
+		return append(out, '\n'), nil
+	}
+
+	for _, d := range defaults {
+		if keyNode := findMappingKey(&node, d.Path); keyNode != nil {
+			keyNode.LineComment = canonicalDefaultComment
+		}
+	}
+	out, err := yaml.Marshal(&node)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal canonical specification to YAML: %w", err)
+	}
+	return out, nil
+}
+
+// deepCopySpec returns an unfrozen deep copy of a known specification type.
+func deepCopySpec(spec interface{}) (interface{}, error) {
+	switch s := spec.(type) {
+	case *PluginSpecification:
+		return s.DeepCopy(), nil
+	case *TaskSpecification:
+		return s.DeepCopy(), nil
+	case *QuerySpecification:
+		return s.DeepCopy(), nil
+	case *ControlSpecification:
+		return s.DeepCopy(), nil
+	default:
+		return nil, fmt.Errorf("unsupported specification type: %T", spec)
+	}
+}
+
+// materializeDefaults fills in the same defaults ProcessSpecification applies, for any
+// that are still missing, and returns the list of fields it changed.
+func materializeDefaults(spec interface{}) []appliedDefault {
+	var applied []appliedDefault
+	setDefault := func(field *string, value string, path ...string) {
+		if !isNonEmpty(*field) {
+			*field = value
+			applied = append(applied, appliedDefault{Path: path, Value: value})
+		}
+	}
+
+	switch s := spec.(type) {
+	case *PluginSpecification:
+		setDefault(&s.APIVersion, APIVersionV1, "api_version")
+		setDefault(&s.Type, SpecTypePlugin, "type")
+		if task := s.Components.Discovery.TaskSpec; task != nil && isNonEmpty(s.Name) {
+			// Mirrors the embedded task defaulting in validatePluginStructure.
+			base := []string{"components", "discovery", "task_spec"}
+			setDefault(&task.ID, s.Name+"-task", append(base, "id")...)
+			setDefault(&task.Name, s.Name+"-task", append(base, "name")...)
+			setDefault(&task.Description, s.Name+" Task", append(base, "description")...)
+			setDefault(&task.Type, SpecTypeTask, append(base, "type")...)
+		}
+	case *TaskSpecification:
+		setDefault(&s.APIVersion, APIVersionV1, "api_version")
+		setDefault(&s.Type, SpecTypeTask, "type")
+	case *QuerySpecification:
+		setDefault(&s.APIVersion, APIVersionV1, "api_version")
+		setDefault(&s.Type, SpecTypeQuery, "type")
+		if s.Parameters == nil {
+			s.Parameters = []QueryParameter{}
+			applied = append(applied, appliedDefault{Path: []string{"parameters"}, Value: "[]"})
+		}
+	case *ControlSpecification:
+		setDefault(&s.APIVersion, APIVersionV1, "api_version")
+		setDefault(&s.Type, SpecTypeControl, "type")
+	}
+	return applied
+}
+
+// findMappingKey walks nested mapping nodes following path and returns the key node
+// of the final element, or nil if any element of the path is missing.
+func findMappingKey(node *yaml.Node, path []string) *yaml.Node {
+	current := node
+	if current.Kind == yaml.DocumentNode && len(current.Content) > 0 {
+		current = current.Content[0]
+	}
+	for i, key := range path {
+		if current.Kind != yaml.MappingNode {
+			return nil
+		}
+		var next *yaml.Node
+		for j := 0; j+1 < len(current.Content); j += 2 {
+			if current.Content[j].Value == key {
+				if i == len(path)-1 {
+					return current.Content[j]
+				}
+				next = current.Content[j+1]
+				break
+			}
+		}
+		if next == nil {
+			return nil
+		}
+		current = next
+	}
+	return nil
+}
@@ -0,0 +1,76 @@
+// capabilities.go
+package platformspec
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"sync"
+)
+
+var capabilityFormatRegex = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+var (
+	capabilityRegistryMu sync.RWMutex
+	capabilityRegistry   = map[Capability]string{
+		CapabilityIncrementalDescribe: "Describer supports cursor-based incremental runs",
+		CapabilityRegionsFilter:       "Describer restricts discovery to the 'regions' param",
+		CapabilityEmitsDeletions:      "Describer emits explicit deletion events",
+	}
+)
+
+// RegisterCapability adds a capability to the registry that plugin specs are validated
+// against. Registering an existing capability updates its description.
+func RegisterCapability(capability Capability, description string) error {
+	if !capabilityFormatRegex.MatchString(string(capability)) {
+		return fmt.Errorf("capability '%s' must be lowercase words separated by '-'", capability)
+	}
+	capabilityRegistryMu.Lock()
+	defer capabilityRegistryMu.Unlock()
+	capabilityRegistry[capability] = description
+	return nil
+}
+
+// KnownCapabilities returns the registered capabilities in sorted order.
+func KnownCapabilities() []Capability {
+	capabilityRegistryMu.RLock()
+	defer capabilityRegistryMu.RUnlock()
+	capabilities := make([]Capability, 0, len(capabilityRegistry))
+	for c := range capabilityRegistry {
+		capabilities = append(capabilities, c)
+	}
+	sort.Slice(capabilities, func(i, j int) bool { return capabilities[i] < capabilities[j] })
+	return capabilities
+}
+
+// IsKnownCapability reports whether the capability is registered.
+func IsKnownCapability(capability Capability) bool {
+	capabilityRegistryMu.RLock()
+	defer capabilityRegistryMu.RUnlock()
+	_, ok := capabilityRegistry[capability]
+	return ok
+}
+
+// validateCapabilities checks that every declared capability is registered and unique.
+func validateCapabilities(capabilities []Capability, specContext string) error {
+	seen := make(map[Capability]bool, len(capabilities))
+	for i, capability := range capabilities {
+		if seen[capability] {
+			return fmt.Errorf("%s: capabilities entry %d ('%s') is duplicated", specContext, i, capability)
+		}
+		seen[capability] = true
+		if IsKnownCapability(capability) {
+			continue
+		}
+		known := KnownCapabilities()
+		names := make([]string, len(known))
+		for j, c := range known {
+			names[j] = string(c)
+		}
+		if suggestion := closestMatch(string(capability), names); suggestion != "" {
+			return fmt.Errorf("%s: capabilities entry %d ('%s') is not a known capability (did you mean '%s'?)", specContext, i, capability, suggestion)
+		}
+		return fmt.Errorf("%s: capabilities entry %d ('%s') is not a known capability", specContext, i, capability)
+	}
+	return nil
+}
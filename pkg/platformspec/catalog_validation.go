@@ -0,0 +1,129 @@
+// catalog_validation.go
+package platformspec
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// catalogParamLookup maps run_schedule parameter names that carry typed values to the
+// PluginCatalog enumeration they must be drawn from.
+var catalogParamLookup = map[string]func(*PluginCatalog) []string{
+	"region":         func(c *PluginCatalog) []string { return c.Regions },
+	"regions":        func(c *PluginCatalog) []string { return c.Regions },
+	"resource_type":  func(c *PluginCatalog) []string { return c.ResourceTypes },
+	"resource_types": func(c *PluginCatalog) []string { return c.ResourceTypes },
+}
+
+// validateScheduleParamsAgainstCatalog checks that every run_schedule param with a known
+// typed name (see catalogParamLookup) only contains values declared in the plugin catalog.
+// Comparison is case-insensitive. Params are skipped when the matching catalog list is
+// empty, so plugins can opt in one enumeration at a time.
+func validateScheduleParamsAgainstCatalog(task *TaskSpecification, catalog *PluginCatalog, specContext string) error {
+	if task == nil || catalog == nil {
+		return nil
+	}
+	for i, schedule := range task.RunSchedule {
+		// Iterate params in sorted order so the first reported error is deterministic
+		names := make([]string, 0, len(schedule.Params))
+		for name := range schedule.Params {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			lookup, ok := catalogParamLookup[strings.ToLower(name)]
+			if !ok {
+				continue
+			}
+			allowed := lookup(catalog)
+			if len(allowed) == 0 {
+				continue
+			}
+			entryContext := fmt.Sprintf("%s run_schedule entry %d (id: '%s') param '%s'", specContext, i, schedule.ID, name)
+			values, err := catalogParamValues(schedule.Params[name])
+			if err != nil {
+				return fmt.Errorf("%s: %w", entryContext, err)
+			}
+			for _, value := range values {
+				if containsFold(allowed, value) {
+					continue
+				}
+				if suggestion := closestMatch(value, allowed); suggestion != "" {
+					return fmt.Errorf("%s: value '%s' is not declared in the plugin catalog (did you mean '%s'?)", entryContext, value, suggestion)
+				}
+				return fmt.Errorf("%s: value '%s' is not declared in the plugin catalog", entryContext, value)
+			}
+		}
+	}
+	return nil
+}
+
+// catalogParamValues normalizes a typed param value, which may be a single string or a
+// list of strings, into a slice.
+func catalogParamValues(raw interface{}) ([]string, error) {
+	switch val := raw.(type) {
+	case string:
+		return []string{val}, nil
+	case []string:
+		return val, nil
+	case []interface{}:
+		values := make([]string, 0, len(val))
+		for j, item := range val {
+			s, ok := item.(string)
+			if !ok {
+				return nil, fmt.Errorf("list entry %d must be a string, got %T", j, item)
+			}
+			values = append(values, s)
+		}
+		return values, nil
+	default:
+		return nil, fmt.Errorf("value must be a string or a list of strings, got %T", raw)
+	}
+}
+
+func containsFold(list []string, value string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, value) {
+			return true
+		}
+	}
+	return false
+}
+
+// closestMatch returns the candidate within a small edit distance of value, or "" if
+// none is close enough to be a plausible typo.
+func closestMatch(value string, candidates []string) string {
+	const maxDistance = 2
+	best, bestDistance := "", maxDistance+1
+	lowerValue := strings.ToLower(value)
+	for _, candidate := range candidates {
+		if d := levenshtein(lowerValue, strings.ToLower(candidate)); d < bestDistance {
+			best, bestDistance = candidate, d
+		}
+	}
+	return best
+}
+
+// levenshtein computes the edit distance between two strings.
+func levenshtein(a, b string) int {
+	ra, rb := []rune(a), []rune(b)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+	return prev[len(rb)]
+}
@@ -155,6 +155,9 @@ func validateOptionalTagsMap(tags map[string]StringOrSlice, specContext string)
 			}
 		}
 	}
+	if err := validateWellKnownTags(tags, specContext); err != nil {
+		return err
+	}
 	return nil // Tags are valid
 }
 
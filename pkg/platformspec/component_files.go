@@ -0,0 +1,166 @@
+// component_files.go
+package platformspec
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/bzip2"
+	"compress/gzip"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+// MaxConcurrentFileChecks bounds the files of one archive verified at the same time.
+const MaxConcurrentFileChecks = 4
+
+var fileChecksumRegex = regexp.MustCompile(`^sha256:[a-fA-F0-9]{64}$`)
+
+// validateComponentFiles checks the structure of a component's files: each needs a
+// relative path inside the archive, unique within the component and distinct from
+// path_in_archive, and a sha256 checksum.
+func validateComponentFiles(component Component, componentName, specContext string) error {
+	if len(component.Files) == 0 {
+		return nil
+	}
+	if _, err := archiveTypeForURI(component.URI); err != nil {
+		return fmt.Errorf("%s: %s.files requires the uri to be an archive: %w", specContext, componentName, err)
+	}
+	seen := make(map[string]bool, len(component.Files))
+	pathInArchive := ""
+	if isNonEmpty(component.PathInArchive) {
+		pathInArchive = cleanArchivePath(component.PathInArchive)
+	}
+	for i, file := range component.Files {
+		if !isNonEmpty(file.Path) {
+			return fmt.Errorf("%s: %s.files entry %d requires 'path'", specContext, componentName, i)
+		}
+		cleaned := cleanArchivePath(file.Path)
+		if cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+			return fmt.Errorf("%s: %s.files entry %d path '%s' must be inside the archive", specContext, componentName, i, file.Path)
+		}
+		if cleaned == pathInArchive {
+			return fmt.Errorf("%s: %s.files entry %d path '%s' is already the path_in_archive", specContext, componentName, i, file.Path)
+		}
+		if seen[cleaned] {
+			return fmt.Errorf("%s: %s.files entry %d path '%s' is duplicated", specContext, componentName, i, file.Path)
+		}
+		seen[cleaned] = true
+		if !fileChecksumRegex.MatchString(file.Checksum) {
+			return fmt.Errorf("%s: %s.files entry %d ('%s') requires a checksum in the format 'sha256:<64 hex chars>'", specContext, componentName, i, file.Path)
+		}
+	}
+	return nil
+}
+
+func cleanArchivePath(path string) string {
+	return filepath.ToSlash(filepath.Clean(strings.Trim(path, "/")))
+}
+
+// validateArchiveFiles verifies that every file exists in the archive and matches its
+// checksum, checking up to MaxConcurrentFileChecks files concurrently. All failures are
+// returned, not just the first.
+func (v *defaultValidator) validateArchiveFiles(archiveData []byte, files []ComponentFile, archiveURI string) error {
+	defer v.stats.startPhase(PhaseArchive)()
+	archiveType, err := archiveTypeForURI(archiveURI)
+	if err != nil {
+		return err
+	}
+
+	errs := make([]error, len(files))
+	sem := make(chan struct{}, MaxConcurrentFileChecks)
+	var wg sync.WaitGroup
+	for i, file := range files {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			if err := verifyArchiveFile(archiveData, archiveType, file); err != nil {
+				errs[i] = fmt.Errorf("file '%s': %w", file.Path, err)
+				return
+			}
+			log.Printf("Verified file '%s' in archive '%s'.", file.Path, archiveURI)
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
+
+// verifyArchiveFile finds the file in the archive and verifies its checksum while
+// reading it.
+func verifyArchiveFile(archiveData []byte, archiveType string, file ComponentFile) error {
+	cleanedPath := cleanArchivePath(file.Path)
+	byteReader := bytes.NewReader(archiveData)
+
+	switch archiveType {
+	case "zip":
+		zipReader, err := zip.NewReader(byteReader, int64(len(archiveData)))
+		if err != nil {
+			return fmt.Errorf("failed to create zip reader: %w", err)
+		}
+		for _, f := range zipReader.File {
+			if cleanArchivePath(f.Name) != cleanedPath {
+				continue
+			}
+			if f.FileInfo().IsDir() {
+				return errors.New("path is a directory, not a file")
+			}
+			rc, err := f.Open()
+			if err != nil {
+				return fmt.Errorf("failed to open: %w", err)
+			}
+			defer rc.Close()
+			return verifyChecksumReader(rc, file.Checksum)
+		}
+
+	case "tar.gz", "tar.bz2":
+		var r io.Reader
+		if archiveType == "tar.gz" {
+			gzipReader, err := gzip.NewReader(byteReader)
+			if err != nil {
+				return fmt.Errorf("failed to create gzip reader: %w", err)
+			}
+			defer gzipReader.Close()
+			r = gzipReader
+		} else {
+			r = bzip2.NewReader(byteReader)
+		}
+		tarReader := tar.NewReader(r)
+		for {
+			header, err := tarReader.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return fmt.Errorf("failed to read next tar header: %w", err)
+			}
+			if cleanArchivePath(header.Name) != cleanedPath {
+				continue
+			}
+			if header.Typeflag != tar.TypeReg && header.Typeflag != tar.TypeRegA {
+				return fmt.Errorf("path exists but is not a regular file (typeflag: %v)", header.Typeflag)
+			}
+			return verifyChecksumReader(tarReader, file.Checksum)
+		}
+
+	default:
+		return fmt.Errorf("internal error: unexpected archive type '%s'", archiveType)
+	}
+	return fmt.Errorf("not found in the %s archive", archiveType)
+}
+
+func copyComponentFiles(in []ComponentFile) []ComponentFile {
+	if in == nil {
+		return nil
+	}
+	out := make([]ComponentFile, len(in))
+	copy(out, in)
+	return out
+}
@@ -0,0 +1,100 @@
+// component_variants.go
+package platformspec
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"sync"
+
+	"github.com/opengovern/og-util/pkg/platformspec/types"
+)
+
+// KnownVariantOS and KnownVariantArch are the GOOS and GOARCH values a component variant
+// may declare.
+var (
+	KnownVariantOS   = []string{"darwin", "freebsd", "linux", "windows"}
+	KnownVariantArch = []string{"386", "amd64", "arm", "arm64", "ppc64le", "riscv64", "s390x"}
+)
+
+// validateComponentVariants checks the structure of a component's variants: known and
+// unique platforms, a URI each, and valid files.
+func validateComponentVariants(component Component, componentName, specContext string) error {
+	seen := make(map[string]bool, len(component.Variants))
+	for i, variant := range component.Variants {
+		variantName := fmt.Sprintf("%s.variants entry %d", componentName, i)
+		if !containsString(KnownVariantOS, variant.OS) {
+			return fmt.Errorf("%s: %s os '%s' is not supported, expected one of: %s", specContext, variantName, variant.OS, strings.Join(KnownVariantOS, ", "))
+		}
+		if variant.Arch != "" && !containsString(KnownVariantArch, variant.Arch) {
+			if alias := types.NormalizeArch(variant.Arch); alias != strings.ToLower(variant.Arch) && containsString(KnownVariantArch, alias) {
+				return fmt.Errorf("%s: %s arch '%s' is not supported (did you mean '%s'?)", specContext, variantName, variant.Arch, alias)
+			}
+			return fmt.Errorf("%s: %s arch '%s' is not supported, expected one of: %s", specContext, variantName, variant.Arch, strings.Join(KnownVariantArch, ", "))
+		}
+		if seen[variant.Platform()] {
+			return fmt.Errorf("%s: %s platform '%s' is duplicated", specContext, variantName, variant.Platform())
+		}
+		seen[variant.Platform()] = true
+		if !isNonEmpty(variant.URI) {
+			return fmt.Errorf("%s: %s.uri is required", specContext, variantName)
+		}
+		if err := validateComponentFiles(variant.Component(), variantName, specContext); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
+
+// selectedVariants returns the variants to validate: those of the validator's platforms,
+// or all of them if none are configured.
+func (v *defaultValidator) selectedVariants(component Component) []ComponentVariant {
+	if len(v.platforms) == 0 {
+		return component.Variants
+	}
+	var selected []ComponentVariant
+	for _, variant := range component.Variants {
+		for _, platform := range v.platforms {
+			goos, goarch, _ := strings.Cut(platform, "/")
+			if variant.OS == goos && (variant.Arch == "" || goarch == "" || variant.Arch == types.NormalizeArch(goarch)) {
+				selected = append(selected, variant)
+				break
+			}
+		}
+	}
+	return selected
+}
+
+// validateVariantArtifacts downloads and verifies the selected variants of a component
+// concurrently, returning all failures.
+func (v *defaultValidator) validateVariantArtifacts(component Component, componentName string) error {
+	variants := v.selectedVariants(component)
+	if len(variants) == 0 {
+		return nil
+	}
+	log.Printf("Validating %d of %d %s variants...", len(variants), len(component.Variants), componentName)
+	errs := make([]error, len(variants))
+	var wg sync.WaitGroup
+	for i, variant := range variants {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			name := fmt.Sprintf("%s (%s)", componentName, variant.Platform())
+			if _, err := v.validateSingleDownloadableComponent(variant.Component(), name); err != nil {
+				errs[i] = err
+			}
+		}()
+	}
+	wg.Wait()
+	return errors.Join(errs...)
+}
@@ -0,0 +1,306 @@
+package platformspec
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Kubernetes CronJob concurrency policies.
+const (
+	ConcurrencyAllow   = "Allow"
+	ConcurrencyForbid  = "Forbid"
+	ConcurrencyReplace = "Replace"
+)
+
+const (
+	// DefaultParamEnvPrefix prefixes the environment variable of each schedule param.
+	DefaultParamEnvPrefix = "PARAM_"
+	// maxCronJobNameLength leaves room for the 11-character suffix Kubernetes appends
+	// to the Jobs it creates.
+	maxCronJobNameLength = 52
+)
+
+// Manifest types for the subset of batch/v1 CronJob the converter emits. They marshal to
+// the same JSON/YAML as the upstream Kubernetes types.
+
+type CronJob struct {
+	APIVersion string      `json:"apiVersion" yaml:"apiVersion"`
+	Kind       string      `json:"kind" yaml:"kind"`
+	Metadata   ObjectMeta  `json:"metadata" yaml:"metadata"`
+	Spec       CronJobSpec `json:"spec" yaml:"spec"`
+}
+
+type ObjectMeta struct {
+	Name      string            `json:"name,omitempty" yaml:"name,omitempty"`
+	Namespace string            `json:"namespace,omitempty" yaml:"namespace,omitempty"`
+	Labels    map[string]string `json:"labels,omitempty" yaml:"labels,omitempty"`
+}
+
+type CronJobSpec struct {
+	Schedule                   string          `json:"schedule" yaml:"schedule"`
+	ConcurrencyPolicy          string          `json:"concurrencyPolicy,omitempty" yaml:"concurrencyPolicy,omitempty"`
+	Suspend                    *bool           `json:"suspend,omitempty" yaml:"suspend,omitempty"`
+	SuccessfulJobsHistoryLimit *int32          `json:"successfulJobsHistoryLimit,omitempty" yaml:"successfulJobsHistoryLimit,omitempty"`
+	FailedJobsHistoryLimit     *int32          `json:"failedJobsHistoryLimit,omitempty" yaml:"failedJobsHistoryLimit,omitempty"`
+	JobTemplate                JobTemplateSpec `json:"jobTemplate" yaml:"jobTemplate"`
+}
+
+type JobTemplateSpec struct {
+	Metadata ObjectMeta `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+	Spec     JobSpec    `json:"spec" yaml:"spec"`
+}
+
+type JobSpec struct {
+	ActiveDeadlineSeconds *int64          `json:"activeDeadlineSeconds,omitempty" yaml:"activeDeadlineSeconds,omitempty"`
+	BackoffLimit          *int32          `json:"backoffLimit,omitempty" yaml:"backoffLimit,omitempty"`
+	Template              PodTemplateSpec `json:"template" yaml:"template"`
+}
+
+type PodTemplateSpec struct {
+	Metadata ObjectMeta `json:"metadata,omitempty" yaml:"metadata,omitempty"`
+	Spec     PodSpec    `json:"spec" yaml:"spec"`
+}
+
+type PodSpec struct {
+	RestartPolicy      string      `json:"restartPolicy,omitempty" yaml:"restartPolicy,omitempty"`
+	ServiceAccountName string      `json:"serviceAccountName,omitempty" yaml:"serviceAccountName,omitempty"`
+	Containers         []Container `json:"containers" yaml:"containers"`
+}
+
+type Container struct {
+	Name    string   `json:"name" yaml:"name"`
+	Image   string   `json:"image" yaml:"image"`
+	Command []string `json:"command,omitempty" yaml:"command,omitempty"`
+	Env     []EnvVar `json:"env,omitempty" yaml:"env,omitempty"`
+}
+
+type EnvVar struct {
+	Name  string `json:"name" yaml:"name"`
+	Value string `json:"value" yaml:"value"`
+}
+
+// CronJobOptions configures BuildCronJobs.
+type CronJobOptions struct {
+	Namespace          string
+	ServiceAccountName string
+	Labels             map[string]string // Added to the CronJob and its pods
+	// ConcurrencyPolicy is Allow, Forbid or Replace; defaults to Forbid so a slow run
+	// is never overlapped by the next one.
+	ConcurrencyPolicy          string
+	Suspend                    bool
+	SuccessfulJobsHistoryLimit *int32
+	FailedJobsHistoryLimit     *int32
+	BackoffLimit               *int32
+	// ParamEnvPrefix prefixes param environment variables; defaults to DefaultParamEnvPrefix.
+	ParamEnvPrefix string
+	// Image overrides the task's image_url, e.g. with the validated digest reference.
+	Image string
+}
+
+var invalidNameChars = regexp.MustCompile(`[^a-z0-9-]+`)
+
+// BuildCronJobs converts each run_schedule entry of a validated task into a CronJob.
+// Entry params become environment variables named <prefix><PARAM_NAME>, with strings
+// passed as-is and other values JSON-encoded; TASK_ID and SCHEDULE_ID are always set.
+// The task timeout becomes the Job's activeDeadlineSeconds, rounded up to whole seconds.
+func BuildCronJobs(task *TaskSpecification, opts CronJobOptions) ([]CronJob, error) {
+	if task == nil {
+		return nil, fmt.Errorf("task specification is nil")
+	}
+	policy := opts.ConcurrencyPolicy
+	switch policy {
+	case "":
+		policy = ConcurrencyForbid
+	case ConcurrencyAllow, ConcurrencyForbid, ConcurrencyReplace:
+	default:
+		return nil, fmt.Errorf("task '%s': invalid concurrency policy %q", task.ID, policy)
+	}
+	prefix := opts.ParamEnvPrefix
+	if prefix == "" {
+		prefix = DefaultParamEnvPrefix
+	}
+	image := opts.Image
+	if image == "" {
+		image = task.ImageURL
+	}
+	if image == "" {
+		return nil, fmt.Errorf("task '%s': image is required", task.ID)
+	}
+
+	var deadline *int64
+	if task.Timeout != "" {
+		timeout, err := time.ParseDuration(task.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("task '%s': invalid timeout '%s': %w", task.ID, task.Timeout, err)
+		}
+		if timeout < time.Second {
+			return nil, fmt.Errorf("task '%s': timeout '%s' must be at least 1s", task.ID, task.Timeout)
+		}
+		// Partial seconds are rounded up so the task never gets less than its timeout
+		seconds := int64(timeout / time.Second)
+		if timeout%time.Second != 0 {
+			seconds++
+		}
+		deadline = &seconds
+	}
+
+	declared := make(map[string]bool, len(task.Params))
+	for _, p := range task.Params {
+		declared[p] = true
+	}
+
+	jobs := make([]CronJob, 0, len(task.RunSchedule))
+	names := make(map[string]string, len(task.RunSchedule))
+	for _, entry := range task.RunSchedule {
+		entryDesc := fmt.Sprintf("task '%s' run_schedule '%s'", task.ID, entry.ID)
+		frequency, err := ParseFrequency(entry.Frequency)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", entryDesc, err)
+		}
+		schedule, err := frequency.CronSchedule()
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", entryDesc, err)
+		}
+		env, err := paramsToEnv(entry.Params, declared, prefix)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", entryDesc, err)
+		}
+		env = append([]EnvVar{
+			{Name: "TASK_ID", Value: task.ID},
+			{Name: "SCHEDULE_ID", Value: entry.ID},
+		}, env...)
+
+		name := cronJobName(task.ID, entry.ID)
+		if other, ok := names[name]; ok {
+			return nil, fmt.Errorf("%s: CronJob name '%s' collides with run_schedule '%s'", entryDesc, name, other)
+		}
+		names[name] = entry.ID
+
+		labels := map[string]string{
+			"app.kubernetes.io/managed-by": "og-util",
+			"opengovernance.io/task-id":    cronJobName(task.ID, ""),
+			"opengovernance.io/schedule":   cronJobName(entry.ID, ""),
+		}
+		for k, v := range opts.Labels {
+			labels[k] = v
+		}
+
+		job := CronJob{
+			APIVersion: "batch/v1",
+			Kind:       "CronJob",
+			Metadata:   ObjectMeta{Name: name, Namespace: opts.Namespace, Labels: labels},
+			Spec: CronJobSpec{
+				Schedule:                   schedule,
+				ConcurrencyPolicy:          policy,
+				SuccessfulJobsHistoryLimit: opts.SuccessfulJobsHistoryLimit,
+				FailedJobsHistoryLimit:     opts.FailedJobsHistoryLimit,
+				JobTemplate: JobTemplateSpec{
+					Metadata: ObjectMeta{Labels: labels},
+					Spec: JobSpec{
+						ActiveDeadlineSeconds: deadline,
+						BackoffLimit:          opts.BackoffLimit,
+						Template: PodTemplateSpec{
+							Metadata: ObjectMeta{Labels: labels},
+							Spec: PodSpec{
+								RestartPolicy:      "Never",
+								ServiceAccountName: opts.ServiceAccountName,
+								Containers: []Container{{
+									Name:    "task",
+									Image:   image,
+									Command: append([]string(nil), task.Command...),
+									Env:     env,
+								}},
+							},
+						},
+					},
+				},
+			},
+		}
+		if opts.Suspend {
+			suspend := true
+			job.Spec.Suspend = &suspend
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, nil
+}
+
+// MarshalCronJobsYAML renders jobs as a multi-document YAML stream for kubectl apply.
+func MarshalCronJobsYAML(jobs []CronJob) ([]byte, error) {
+	var buf bytes.Buffer
+	enc := yaml.NewEncoder(&buf)
+	enc.SetIndent(2)
+	for _, job := range jobs {
+		if err := enc.Encode(job); err != nil {
+			return nil, fmt.Errorf("marshal CronJob %s: %w", job.Metadata.Name, err)
+		}
+	}
+	if err := enc.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func paramsToEnv(params map[string]any, declared map[string]bool, prefix string) ([]EnvVar, error) {
+	keys := make([]string, 0, len(params))
+	for k := range params {
+		if !declared[k] {
+			return nil, fmt.Errorf("param '%s' is not declared in the task's params", k)
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+
+	env := make([]EnvVar, 0, len(keys))
+	for _, k := range keys {
+		var value string
+		switch v := params[k].(type) {
+		case string:
+			value = v
+		case nil:
+		default:
+			b, err := json.Marshal(v)
+			if err != nil {
+				return nil, fmt.Errorf("param '%s': %w", k, err)
+			}
+			value = string(b)
+		}
+		env = append(env, EnvVar{Name: prefix + envName(k), Value: value})
+	}
+	return env, nil
+}
+
+func envName(param string) string {
+	var b strings.Builder
+	for _, r := range strings.ToUpper(param) {
+		if (r >= 'A' && r <= 'Z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		} else {
+			b.WriteByte('_')
+		}
+	}
+	return b.String()
+}
+
+// cronJobName builds a DNS-1123 name from parts, truncated to the CronJob name limit.
+func cronJobName(parts ...string) string {
+	var cleaned []string
+	for _, p := range parts {
+		p = strings.Trim(invalidNameChars.ReplaceAllString(strings.ToLower(p), "-"), "-")
+		if p != "" {
+			cleaned = append(cleaned, p)
+		}
+	}
+	name := strings.Join(cleaned, "-")
+	if len(name) > maxCronJobNameLength {
+		name = strings.TrimRight(name[:maxCronJobNameLength], "-")
+	}
+	return name
+}
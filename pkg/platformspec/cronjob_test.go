@@ -0,0 +1,52 @@
+package platformspec_test
+
+import (
+	"testing"
+
+	"github.com/opengovern/og-util/pkg/platformspec"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseFrequency(t *testing.T) {
+	r := require.New(t)
+
+	for _, valid := range []string{"15m", "1d", "@daily", "*/5 * * * *", "0 9-17 * JAN-MAR mon-fri", "30 2 ? * 7", "0,30 0 1 */2 *"} {
+		_, err := platformspec.ParseFrequency(valid)
+		r.NoError(err, valid)
+	}
+	for _, invalid := range []string{
+		"0m",
+		"9999999999999999w", // Overflows time.Duration
+		"60 * * * *",
+		"* 24 * * *",
+		"* * 0 * *",
+		"* * * 13 *",
+		"* * * * 8",
+		"*/0 * * * *",
+		"5-1 * * * *",
+		"a b c d e",
+		"? * * * *",
+	} {
+		_, err := platformspec.ParseFrequency(invalid)
+		r.Error(err, invalid)
+	}
+}
+
+func TestBuildCronJobsTimeout(t *testing.T) {
+	r := require.New(t)
+
+	task := embeddedTaskPlugin().Components.Discovery.TaskSpec
+	for timeout, expected := range map[string]int64{"30m": 1800, "1s": 1, "1.5s": 2} {
+		task.Timeout = timeout
+		jobs, err := platformspec.BuildCronJobs(task, platformspec.CronJobOptions{})
+		r.NoError(err, timeout)
+		r.Len(jobs, 1)
+		r.Equal(expected, *jobs[0].Spec.JobTemplate.Spec.ActiveDeadlineSeconds, timeout)
+		r.Equal("0 0 * * *", jobs[0].Spec.Schedule)
+	}
+	for _, timeout := range []string{"0s", "500ms", "-1m"} {
+		task.Timeout = timeout
+		_, err := platformspec.BuildCronJobs(task, platformspec.CronJobOptions{})
+		r.ErrorContains(err, "must be at least 1s", timeout)
+	}
+}
@@ -0,0 +1,225 @@
+//go:build !platformspec_lite
+
+// devserver.go
+package platformspec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fatih/color"
+	"github.com/fsnotify/fsnotify"
+)
+
+// DefaultDevServerDebounce is how long the DevServer waits after the last file event
+// before re-validating, so that editors writing in several steps trigger one run.
+const DefaultDevServerDebounce = 300 * time.Millisecond
+
+// DevServerOptions configures a DevServer.
+type DevServerOptions struct {
+	Path                   string        // Spec file or directory of specs (*.yaml, *.yml) to watch. Required.
+	PlatformVersion        string        // Optional; passed to ProcessSpecification for the platform support check.
+	ArtifactValidationType string        // Passed to ProcessSpecification; defaults to ArtifactTypeAll.
+	SkipArtifactValidation bool          // Skip artifact downloads for a faster local loop.
+	Debounce               time.Duration // Defaults to DefaultDevServerDebounce.
+	HTTPAddr               string        // If set (e.g., ":8089"), serve generated embedded task specs over HTTP.
+	Output                 io.Writer     // Report destination; defaults to os.Stdout.
+}
+
+// DevServer watches specification files for local plugin development, re-validates
+// them on change, prints a colored report, and optionally serves the embedded
+// TaskSpecification of valid plugin specs over HTTP at:
+//
+//	GET /plugins/{name}/task-spec?format=yaml|json
+type DevServer struct {
+	validator Validator
+	opts      DevServerOptions
+
+	mu      sync.RWMutex
+	plugins map[string]*PluginSpecification // Last valid plugin specs, keyed by plugin name
+}
+
+// NewDevServer creates a DevServer using the given validator.
+func NewDevServer(validator Validator, opts DevServerOptions) (*DevServer, error) {
+	if validator == nil {
+		return nil, errors.New("validator cannot be nil for dev server")
+	}
+	if !isNonEmpty(opts.Path) {
+		return nil, errors.New("dev server path is required")
+	}
+	if _, err := os.Stat(opts.Path); err != nil {
+		return nil, fmt.Errorf("dev server path '%s' is not accessible: %w", opts.Path, err)
+	}
+	if opts.Debounce <= 0 {
+		opts.Debounce = DefaultDevServerDebounce
+	}
+	if opts.Output == nil {
+		opts.Output = os.Stdout
+	}
+	return &DevServer{
+		validator: validator,
+		opts:      opts,
+		plugins:   make(map[string]*PluginSpecification),
+	}, nil
+}
+
+// Run validates all watched specs once, then blocks re-validating on change until ctx
+// is cancelled. If HTTPAddr is set, the HTTP server runs for the same lifetime.
+func (s *DevServer) Run(ctx context.Context) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("failed to create file watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	// Watch the directory even for a single file: editors often save by rename,
+	// which drops a watch placed on the file itself.
+	watchDir, onlyFile := s.opts.Path, ""
+	if info, statErr := os.Stat(s.opts.Path); statErr == nil && !info.IsDir() {
+		watchDir, onlyFile = filepath.Dir(s.opts.Path), filepath.Clean(s.opts.Path)
+	}
+	if err := watcher.Add(watchDir); err != nil {
+		return fmt.Errorf("failed to watch '%s': %w", watchDir, err)
+	}
+
+	if isNonEmpty(s.opts.HTTPAddr) {
+		server := &http.Server{Addr: s.opts.HTTPAddr, Handler: s.Handler()}
+		go func() {
+			log.Printf("Dev server serving embedded task specs on %s", s.opts.HTTPAddr)
+			if err := server.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+				log.Printf("Warning: dev server HTTP listener stopped: %v", err)
+			}
+		}()
+		defer server.Close()
+	}
+
+	s.validateAll()
+
+	var debounce <-chan time.Time
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if !isSpecFile(event.Name) || (onlyFile != "" && filepath.Clean(event.Name) != onlyFile) {
+				continue
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename|fsnotify.Remove) == 0 {
+				continue
+			}
+			debounce = time.After(s.opts.Debounce)
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			log.Printf("Warning: file watcher error: %v", err)
+		case <-debounce:
+			debounce = nil
+			s.validateAll()
+		}
+	}
+}
+
+// Handler returns the HTTP handler serving generated embedded task specs.
+func (s *DevServer) Handler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /plugins/{name}/task-spec", func(w http.ResponseWriter, r *http.Request) {
+		s.mu.RLock()
+		spec, ok := s.plugins[r.PathValue("name")]
+		s.mu.RUnlock()
+		if !ok {
+			http.Error(w, "no valid plugin specification with that name", http.StatusNotFound)
+			return
+		}
+		format := r.URL.Query().Get("format")
+		out, err := s.validator.GetEmbeddedTaskSpecification(spec, format)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusUnprocessableEntity)
+			return
+		}
+		contentType := "application/yaml"
+		if strings.EqualFold(format, FormatJSON) {
+			contentType = "application/json"
+		}
+		w.Header().Set("Content-Type", contentType)
+		_, _ = io.WriteString(w, out)
+	})
+	return mux
+}
+
+// validateAll re-validates every watched spec, prints the report, and refreshes the
+// set of plugin specs served over HTTP.
+func (s *DevServer) validateAll() {
+	files, err := s.specFiles()
+	if err != nil {
+		fmt.Fprintf(s.opts.Output, "%s %v\n", color.RedString("ERROR"), err)
+		return
+	}
+
+	plugins := make(map[string]*PluginSpecification)
+	passed := 0
+	fmt.Fprintf(s.opts.Output, "\n%s %s\n", color.CyanString("==>"), time.Now().Format("15:04:05"))
+	for _, file := range files {
+		spec, err := s.validator.ProcessSpecification(nil, file, s.opts.PlatformVersion, s.opts.ArtifactValidationType, s.opts.SkipArtifactValidation)
+		if err != nil {
+			fmt.Fprintf(s.opts.Output, "  %s %s\n      %v\n", color.RedString("FAIL"), file, err)
+			continue
+		}
+		passed++
+		fmt.Fprintf(s.opts.Output, "  %s %s (%T)\n", color.GreenString("PASS"), file, spec)
+		if plugin, ok := spec.(*PluginSpecification); ok {
+			plugins[plugin.Name] = plugin
+		}
+	}
+
+	summary := color.GreenString("%d/%d specifications valid", passed, len(files))
+	if passed != len(files) {
+		summary = color.YellowString("%d/%d specifications valid", passed, len(files))
+	}
+	fmt.Fprintf(s.opts.Output, "%s %s\n", color.CyanString("==>"), summary)
+
+	s.mu.Lock()
+	s.plugins = plugins
+	s.mu.Unlock()
+}
+
+// specFiles lists the spec files under the watched path in a stable order.
+func (s *DevServer) specFiles() ([]string, error) {
+	info, err := os.Stat(s.opts.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to stat '%s': %w", s.opts.Path, err)
+	}
+	if !info.IsDir() {
+		return []string{s.opts.Path}, nil
+	}
+	entries, err := os.ReadDir(s.opts.Path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read directory '%s': %w", s.opts.Path, err)
+	}
+	var files []string
+	for _, entry := range entries {
+		if !entry.IsDir() && isSpecFile(entry.Name()) {
+			files = append(files, filepath.Join(s.opts.Path, entry.Name()))
+		}
+	}
+	sort.Strings(files)
+	return files, nil
+}
+
+func isSpecFile(name string) bool {
+	ext := strings.ToLower(filepath.Ext(name))
+	return ext == ".yaml" || ext == ".yml"
+}
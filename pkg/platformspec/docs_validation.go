@@ -0,0 +1,122 @@
+// docs_validation.go
+package platformspec
+
+import (
+	"fmt"
+	"log"
+	"net/url"
+	"strings"
+	"unicode/utf8"
+)
+
+// RequiredReadmeSections are the headings a plugin README must contain, matched
+// case-insensitively anywhere in a heading (e.g. "Required Permissions").
+var RequiredReadmeSections = []string{"Configuration", "Permissions"}
+
+// validateDocsComponent checks the structure of the optional components.docs section.
+func validateDocsComponent(docs *DocsComponent, specContext string) error {
+	if docs == nil {
+		return nil
+	}
+	if !isNonEmpty(docs.ReadmeURI) && !isNonEmpty(docs.ChangelogURI) {
+		return fmt.Errorf("%s: components.docs requires 'readme-uri' or 'changelog-uri'", specContext)
+	}
+	for _, doc := range []struct {
+		name string
+		uri  string
+	}{
+		{"readme-uri", docs.ReadmeURI},
+		{"changelog-uri", docs.ChangelogURI},
+	} {
+		if !isNonEmpty(doc.uri) {
+			continue
+		}
+		u, err := url.Parse(doc.uri)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return fmt.Errorf("%s: components.docs.%s '%s' must be an http(s) URL", specContext, doc.name, doc.uri)
+		}
+	}
+	return nil
+}
+
+// validatePluginDocs downloads the plugin's README and changelog and checks that they are
+// UTF-8 markdown within MaxDocSizeBytes, and that the README has the
+// RequiredReadmeSections.
+func (v *defaultValidator) validatePluginDocs(docs *DocsComponent) []error {
+	if docs == nil {
+		log.Println("Scope: No docs component declared.")
+		return nil
+	}
+	var errs []error
+	if isNonEmpty(docs.ReadmeURI) {
+		if err := v.validateDoc(docs.ReadmeURI, "readme", RequiredReadmeSections); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if isNonEmpty(docs.ChangelogURI) {
+		if err := v.validateDoc(docs.ChangelogURI, "changelog", nil); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errs
+}
+
+func (v *defaultValidator) validateDoc(uri, name string, requiredSections []string) error {
+	log.Printf("Validating %s document: %s", name, uri)
+	data, err := v.downloadWithRetry(uri)
+	if err != nil {
+		return fmt.Errorf("%s download failed from URI '%s': %w", name, uri, err)
+	}
+	if limit := v.limits.MaxDocSizeBytes; limit > 0 && len(data) > limit {
+		return fmt.Errorf("%s '%s' is %d bytes, exceeding the maximum allowed %d bytes", name, uri, len(data), limit)
+	}
+	if !utf8.Valid(data) {
+		return fmt.Errorf("%s '%s' is not valid UTF-8", name, uri)
+	}
+	headings := markdownHeadings(string(data))
+	if len(headings) == 0 {
+		return fmt.Errorf("%s '%s' does not look like markdown: it has no headings", name, uri)
+	}
+	var missing []string
+	for _, section := range requiredSections {
+		found := false
+		for _, heading := range headings {
+			if strings.Contains(strings.ToLower(heading), strings.ToLower(section)) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, section)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("%s '%s' is missing required sections: %s", name, uri, strings.Join(missing, ", "))
+	}
+	log.Printf("%s document valid: %s", name, uri)
+	return nil
+}
+
+// markdownHeadings returns the text of the ATX headings ("# Title") of a markdown
+// document, skipping fenced code blocks.
+func markdownHeadings(doc string) []string {
+	var headings []string
+	inFence := false
+	for _, line := range strings.Split(doc, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if inFence || !strings.HasPrefix(trimmed, "#") {
+			continue
+		}
+		level := len(trimmed) - len(strings.TrimLeft(trimmed, "#"))
+		text := trimmed[level:]
+		if level > 6 || (text != "" && text[0] != ' ' && text[0] != '\t') {
+			continue
+		}
+		headings = append(headings, strings.TrimSpace(strings.TrimRight(text, "# ")))
+	}
+	return headings
+}
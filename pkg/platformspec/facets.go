@@ -0,0 +1,211 @@
+// facets.go
+package platformspec
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Well-known tag keys the marketplace indexes as facets.
+const (
+	TagKeyCategory = "category" // One or more of KnownCategories
+	TagKeyVendor   = "vendor"   // Free-form, a single value
+	TagKeyMaturity = "maturity" // A single Maturity
+	TagKeyPricing  = "pricing"  // A single Pricing
+)
+
+// Maturity is the stability level a specification declares in its 'maturity' tag.
+type Maturity string
+
+const (
+	MaturityExperimental Maturity = "experimental"
+	MaturityBeta         Maturity = "beta"
+	MaturityStable       Maturity = "stable"
+	MaturityDeprecated   Maturity = "deprecated"
+)
+
+// Pricing is the pricing model a specification declares in its 'pricing' tag.
+type Pricing string
+
+const (
+	PricingFree       Pricing = "free"
+	PricingFreemium   Pricing = "freemium"
+	PricingPaid       Pricing = "paid"
+	PricingEnterprise Pricing = "enterprise"
+)
+
+// KnownCategories are the values allowed in the 'category' tag.
+var KnownCategories = []string{
+	"compliance",
+	"cost",
+	"data",
+	"devops",
+	"identity",
+	"inventory",
+	"networking",
+	"observability",
+	"security",
+}
+
+var (
+	knownMaturities = []string{string(MaturityExperimental), string(MaturityBeta), string(MaturityStable), string(MaturityDeprecated)}
+	knownPricings   = []string{string(PricingFree), string(PricingFreemium), string(PricingPaid), string(PricingEnterprise)}
+)
+
+// Facets is the normalized facet data of a specification: enumerated values are
+// lowercase, and categories are sorted and deduplicated.
+type Facets struct {
+	Categories []string `json:"categories,omitempty"`
+	Vendor     string   `json:"vendor,omitempty"`
+	Maturity   Maturity `json:"maturity,omitempty"`
+	Pricing    Pricing  `json:"pricing,omitempty"`
+}
+
+// GetFacets returns the facets of a validated specification (see GetFlattenedTags for
+// the supported types). Facets without a tag are left empty.
+func GetFacets(spec interface{}) Facets {
+	tags := specTags(spec)
+	return Facets{
+		Categories: categoriesFromTags(tags),
+		Vendor:     vendorFromTags(tags),
+		Maturity:   Maturity(enumFromTags(tags, TagKeyMaturity)),
+		Pricing:    Pricing(enumFromTags(tags, TagKeyPricing)),
+	}
+}
+
+// GetCategories returns the normalized 'category' tag values of a specification.
+func GetCategories(spec interface{}) []string {
+	return categoriesFromTags(specTags(spec))
+}
+
+// GetVendor returns the 'vendor' tag of a specification, or "" if it has none.
+func GetVendor(spec interface{}) string {
+	return vendorFromTags(specTags(spec))
+}
+
+// GetMaturity returns the 'maturity' tag of a specification, or "" if it has none.
+func GetMaturity(spec interface{}) Maturity {
+	return Maturity(enumFromTags(specTags(spec), TagKeyMaturity))
+}
+
+// GetPricing returns the 'pricing' tag of a specification, or "" if it has none.
+func GetPricing(spec interface{}) Pricing {
+	return Pricing(enumFromTags(specTags(spec), TagKeyPricing))
+}
+
+// specTags returns the tags of the specification types having them.
+func specTags(spec interface{}) map[string]StringOrSlice {
+	switch s := spec.(type) {
+	case *QuerySpecification:
+		return s.Tags
+	case *PluginSpecification:
+		return s.Tags
+	case *TaskSpecification:
+		return s.Tags
+	case *ControlSpecification:
+		return s.Tags
+	case *TaskDetails:
+		return s.Tags
+	}
+	return nil
+}
+
+// tagValues returns the values of a tag, matching its key case-insensitively.
+func tagValues(tags map[string]StringOrSlice, key string) StringOrSlice {
+	if values, ok := tags[key]; ok {
+		return values
+	}
+	for k, values := range tags {
+		if strings.EqualFold(strings.TrimSpace(k), key) {
+			return values
+		}
+	}
+	return nil
+}
+
+func normalizeTagValue(value string) string {
+	return strings.ToLower(strings.TrimSpace(value))
+}
+
+func categoriesFromTags(tags map[string]StringOrSlice) []string {
+	values := tagValues(tags, TagKeyCategory)
+	if len(values) == 0 {
+		return nil
+	}
+	seen := make(map[string]bool, len(values))
+	var categories []string
+	for _, value := range values {
+		category := normalizeTagValue(value)
+		if category == "" || seen[category] {
+			continue
+		}
+		seen[category] = true
+		categories = append(categories, category)
+	}
+	sort.Strings(categories)
+	return categories
+}
+
+func vendorFromTags(tags map[string]StringOrSlice) string {
+	values := tagValues(tags, TagKeyVendor)
+	if len(values) == 0 {
+		return ""
+	}
+	return strings.TrimSpace(values[0])
+}
+
+func enumFromTags(tags map[string]StringOrSlice, key string) string {
+	values := tagValues(tags, key)
+	if len(values) == 0 {
+		return ""
+	}
+	return normalizeTagValue(values[0])
+}
+
+// validateWellKnownTags checks the values of the well-known tag keys: categories,
+// maturity and pricing must be known values, and vendor, maturity and pricing take a
+// single value.
+func validateWellKnownTags(tags map[string]StringOrSlice, specContext string) error {
+	for key, values := range tags {
+		switch strings.ToLower(strings.TrimSpace(key)) {
+		case TagKeyCategory:
+			for j, value := range values {
+				if err := checkTagEnum(key, j, value, KnownCategories, specContext); err != nil {
+					return err
+				}
+			}
+		case TagKeyVendor:
+			if len(values) > 1 {
+				return fmt.Errorf("%s: tags key '%s' takes a single value, got %d", specContext, key, len(values))
+			}
+		case TagKeyMaturity, TagKeyPricing:
+			if len(values) > 1 {
+				return fmt.Errorf("%s: tags key '%s' takes a single value, got %d", specContext, key, len(values))
+			}
+			known := knownMaturities
+			if strings.EqualFold(strings.TrimSpace(key), TagKeyPricing) {
+				known = knownPricings
+			}
+			for j, value := range values {
+				if err := checkTagEnum(key, j, value, known, specContext); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func checkTagEnum(key string, index int, value string, known []string, specContext string) error {
+	normalized := normalizeTagValue(value)
+	for _, k := range known {
+		if normalized == k {
+			return nil
+		}
+	}
+	if suggestion := closestMatch(normalized, known); suggestion != "" {
+		return fmt.Errorf("%s: tags value entry %d ('%s') for key '%s' is not allowed (did you mean '%s'?)", specContext, index, value, key, suggestion)
+	}
+	return fmt.Errorf("%s: tags value entry %d ('%s') for key '%s' is not allowed, expected one of: %s", specContext, index, value, key, strings.Join(known, ", "))
+}
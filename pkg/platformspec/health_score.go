@@ -0,0 +1,197 @@
+// health_score.go
+package platformspec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+)
+
+// Health score component names.
+const (
+	HealthComponentValidation = "validation"
+	HealthComponentImageScan  = "image_scan"
+	HealthComponentRuntime    = "runtime_success"
+)
+
+// minRuntimeRunsForConfidence is the number of historical task runs below which the
+// runtime success rate is still used but flagged as low confidence in the explanation.
+const minRuntimeRunsForConfidence = 10
+
+// ImageScanFindings summarizes vulnerability scan results for a container image.
+type ImageScanFindings struct {
+	Critical int
+	High     int
+	Medium   int
+	Low      int
+}
+
+// TaskRunStats summarizes historical executions of a plugin's discovery task.
+type TaskRunStats struct {
+	TotalRuns      int
+	SuccessfulRuns int
+}
+
+// HealthSignalProvider supplies the runtime signals used by ComputeHealthScore. Either
+// method may return (nil, nil) when no data is available; that component is then
+// excluded from the score and the remaining weights are renormalized.
+type HealthSignalProvider interface {
+	ImageScanFindings(ctx context.Context, imageURI string) (*ImageScanFindings, error)
+	TaskRunStats(ctx context.Context, pluginName string) (*TaskRunStats, error)
+}
+
+// HealthScoreWeights sets the relative weight of each score component. Weights do not
+// need to sum to 1; they are normalized over the components that have data.
+type HealthScoreWeights struct {
+	Validation float64
+	ImageScan  float64
+	Runtime    float64
+}
+
+// DefaultHealthScoreWeights returns the weights used when ranking marketplace listings.
+func DefaultHealthScoreWeights() HealthScoreWeights {
+	return HealthScoreWeights{
+		Validation: 0.4,
+		ImageScan:  0.3,
+		Runtime:    0.3,
+	}
+}
+
+// HealthScoreComponent is one explained input to a HealthScore.
+type HealthScoreComponent struct {
+	Name        string  `json:"name"`
+	Score       float64 `json:"score"`  // 0-100
+	Weight      float64 `json:"weight"` // Normalized weight applied; 0 if the component had no data
+	Available   bool    `json:"available"`
+	Explanation string  `json:"explanation"`
+}
+
+// HealthScore is a plugin's composite health score (0-100) with per-component explanations.
+type HealthScore struct {
+	PluginName string                 `json:"plugin_name"`
+	Score      float64                `json:"score"`
+	Components []HealthScoreComponent `json:"components"`
+}
+
+// ComputeHealthScore combines the validation outcome of pluginSpec (validationErr, nil
+// meaning it passed), image scan findings for its embedded discovery image, and
+// historical task success rates into a composite score. provider may be nil, in which
+// case only the validation component is scored.
+func ComputeHealthScore(ctx context.Context, pluginSpec *PluginSpecification, validationErr error, provider HealthSignalProvider, weights HealthScoreWeights) (*HealthScore, error) {
+	if pluginSpec == nil {
+		return nil, errors.New("plugin specification cannot be nil for health score computation")
+	}
+	if weights.Validation < 0 || weights.ImageScan < 0 || weights.Runtime < 0 {
+		return nil, fmt.Errorf("health score weights cannot be negative: %+v", weights)
+	}
+
+	components := []HealthScoreComponent{scoreValidation(validationErr, weights.Validation)}
+	imageComp, err := scoreImageScan(ctx, pluginSpec, provider, weights.ImageScan)
+	if err != nil {
+		return nil, err
+	}
+	components = append(components, imageComp)
+	runtimeComp, err := scoreRuntime(ctx, pluginSpec, provider, weights.Runtime)
+	if err != nil {
+		return nil, err
+	}
+	components = append(components, runtimeComp)
+
+	// Normalize weights over the components that have data
+	totalWeight := 0.0
+	for _, c := range components {
+		if c.Available {
+			totalWeight += c.Weight
+		}
+	}
+	score := 0.0
+	for i := range components {
+		if !components[i].Available || totalWeight == 0 {
+			components[i].Weight = 0
+			continue
+		}
+		components[i].Weight /= totalWeight
+		score += components[i].Score * components[i].Weight
+	}
+
+	result := &HealthScore{
+		PluginName: pluginSpec.Name,
+		Score:      math.Round(score*10) / 10,
+		Components: components,
+	}
+	log.Printf("Computed health score %.1f for plugin '%s'.", result.Score, result.PluginName)
+	return result, nil
+}
+
+func scoreValidation(validationErr error, weight float64) HealthScoreComponent {
+	c := HealthScoreComponent{Name: HealthComponentValidation, Weight: weight, Available: true}
+	if validationErr == nil {
+		c.Score = 100
+		c.Explanation = "specification passed validation"
+	} else {
+		c.Score = 0
+		c.Explanation = fmt.Sprintf("specification failed validation: %v", validationErr)
+	}
+	return c
+}
+
+func scoreImageScan(ctx context.Context, pluginSpec *PluginSpecification, provider HealthSignalProvider, weight float64) (HealthScoreComponent, error) {
+	c := HealthScoreComponent{Name: HealthComponentImageScan, Weight: weight}
+	taskSpec := pluginSpec.Components.Discovery.TaskSpec
+	switch {
+	case provider == nil:
+		c.Explanation = "no signal provider configured"
+		return c, nil
+	case taskSpec == nil || !isNonEmpty(taskSpec.ImageURL):
+		c.Explanation = "discovery task is referenced, not embedded; no image to assess"
+		return c, nil
+	}
+
+	findings, err := provider.ImageScanFindings(ctx, taskSpec.ImageURL)
+	if err != nil {
+		return c, fmt.Errorf("failed to get image scan findings for '%s': %w", taskSpec.ImageURL, err)
+	}
+	if findings == nil {
+		c.Explanation = "no image scan results available"
+		return c, nil
+	}
+
+	// Penalties are steep for critical/high so a single critical finding dominates
+	penalty := 40*findings.Critical + 15*findings.High + 5*findings.Medium + 1*findings.Low
+	c.Available = true
+	c.Score = math.Max(0, float64(100-penalty))
+	c.Explanation = fmt.Sprintf("image scan found %d critical, %d high, %d medium, %d low findings",
+		findings.Critical, findings.High, findings.Medium, findings.Low)
+	return c, nil
+}
+
+func scoreRuntime(ctx context.Context, pluginSpec *PluginSpecification, provider HealthSignalProvider, weight float64) (HealthScoreComponent, error) {
+	c := HealthScoreComponent{Name: HealthComponentRuntime, Weight: weight}
+	if provider == nil {
+		c.Explanation = "no signal provider configured"
+		return c, nil
+	}
+
+	stats, err := provider.TaskRunStats(ctx, pluginSpec.Name)
+	if err != nil {
+		return c, fmt.Errorf("failed to get task run stats for plugin '%s': %w", pluginSpec.Name, err)
+	}
+	if stats == nil || stats.TotalRuns <= 0 {
+		c.Explanation = "no historical task runs"
+		return c, nil
+	}
+	if stats.SuccessfulRuns < 0 || stats.SuccessfulRuns > stats.TotalRuns {
+		return c, fmt.Errorf("invalid task run stats for plugin '%s': %d successful of %d total", pluginSpec.Name, stats.SuccessfulRuns, stats.TotalRuns)
+	}
+
+	rate := float64(stats.SuccessfulRuns) / float64(stats.TotalRuns)
+	c.Available = true
+	c.Score = rate * 100
+	c.Explanation = fmt.Sprintf("%d of %d historical task runs succeeded (%.1f%%)", stats.SuccessfulRuns, stats.TotalRuns, c.Score)
+	if stats.TotalRuns < minRuntimeRunsForConfidence {
+		c.Explanation += fmt.Sprintf("; low confidence, fewer than %d runs", minRuntimeRunsForConfidence)
+	}
+	return c, nil
+}
@@ -0,0 +1,31 @@
+// incremental.go
+package platformspec
+
+import (
+	"fmt"
+	"strings"
+)
+
+// validateIncrementalDescribe checks the incremental section of a task.
+func validateIncrementalDescribe(incremental *IncrementalDescribe, taskDesc string) error {
+	if incremental == nil {
+		return nil
+	}
+	switch strings.ToLower(incremental.Cursor) {
+	case CursorTimestamp, CursorToken:
+	case "":
+		return fmt.Errorf("%s: incremental.cursor is required", taskDesc)
+	default:
+		return fmt.Errorf("%s: incremental.cursor '%s' must be '%s' or '%s'", taskDesc, incremental.Cursor, CursorTimestamp, CursorToken)
+	}
+	if isNonEmpty(incremental.MinInterval) {
+		frequency, err := ParseFrequency(incremental.MinInterval)
+		if err != nil {
+			return fmt.Errorf("%s: incremental.min_interval: %w", taskDesc, err)
+		}
+		if frequency.Interval <= 0 {
+			return fmt.Errorf("%s: incremental.min_interval '%s' must be an interval such as '15m', not a cron expression", taskDesc, incremental.MinInterval)
+		}
+	}
+	return nil
+}
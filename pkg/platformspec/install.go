@@ -0,0 +1,253 @@
+// install.go
+package platformspec
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"runtime"
+	"strings"
+)
+
+// DefaultInstallMode is the permission of installed binaries, whatever the archive says.
+const DefaultInstallMode os.FileMode = 0o755
+
+// InstallOptions configures InstallWithOptions. The zero value is equivalent to Install.
+type InstallOptions struct {
+	// CacheDir, if set, keeps downloaded artifacts by checksum so that reinstalling the
+	// same component does not download it again. Components without a checksum are never
+	// cached, since a cached copy could not be verified.
+	CacheDir string
+	// Mode is the permission of the installed file; DefaultInstallMode if zero. Setuid,
+	// setgid and sticky bits are always cleared.
+	Mode os.FileMode
+	// GOOS and GOARCH select the component variant to install; runtime.GOOS and
+	// runtime.GOARCH if empty.
+	GOOS   string
+	GOARCH string
+	// HTTPClient downloads the artifact; the package's shared client if nil.
+	HTTPClient *http.Client
+}
+
+// Install downloads a component such as components.platform-binary for the current
+// platform, verifies its checksum, extracts its path_in_archive (or takes the download
+// itself if it has none) into destDir and returns the path of the installed file.
+func Install(component Component, destDir string) (string, error) {
+	return InstallWithOptions(component, destDir, InstallOptions{})
+}
+
+// InstallWithOptions is Install with options. The file is written to a temporary file
+// and renamed into place, so a failed install never leaves a partial binary behind.
+func InstallWithOptions(component Component, destDir string, opts InstallOptions) (string, error) {
+	goos, goarch := opts.GOOS, opts.GOARCH
+	if goos == "" {
+		goos = runtime.GOOS
+	}
+	if goarch == "" {
+		goarch = runtime.GOARCH
+	}
+	selected, err := component.SelectVariant(goos, goarch)
+	if err != nil {
+		return "", err
+	}
+	if !isNonEmpty(selected.URI) {
+		return "", errors.New("install failed: component URI is missing")
+	}
+	if !isNonEmpty(destDir) {
+		return "", errors.New("install failed: destination directory is required")
+	}
+
+	name, err := installedFileName(selected)
+	if err != nil {
+		return "", err
+	}
+	v := &defaultValidator{limits: DefaultSpecLimits(), httpClient: opts.HTTPClient}
+	data, err := v.fetchComponent(selected, opts.CacheDir)
+	if err != nil {
+		return "", err
+	}
+
+	var content io.Reader = bytes.NewReader(data)
+	if isNonEmpty(selected.PathInArchive) {
+		archiveType, err := archiveTypeForURI(selected.URI)
+		if err != nil {
+			return "", err
+		}
+		if err := checkArchiveEntriesSafe(data, archiveType); err != nil {
+			return "", fmt.Errorf("install failed: archive '%s' is unsafe to extract: %w", selected.URI, err)
+		}
+		content, err = openArchiveEntry(data, archiveType, selected.PathInArchive)
+		if err != nil {
+			return "", fmt.Errorf("install failed: path '%s' in archive '%s': %w", selected.PathInArchive, selected.URI, err)
+		}
+	}
+
+	if err := os.MkdirAll(destDir, 0o755); err != nil {
+		return "", fmt.Errorf("install failed: create destination directory: %w", err)
+	}
+	mode := opts.Mode.Perm()
+	if mode == 0 {
+		mode = DefaultInstallMode
+	}
+	target, err := SafeJoin(destDir, name)
+	if err != nil {
+		return "", fmt.Errorf("install failed: %w", err)
+	}
+	if err := writeFileAtomic(target, content, mode); err != nil {
+		return "", fmt.Errorf("install failed: %w", err)
+	}
+	log.Printf("Installed '%s' from '%s' to '%s'.", name, selected.URI, target)
+	return target, nil
+}
+
+// installedFileName is the base name of path_in_archive, or of the URI path if the
+// download is the file itself.
+func installedFileName(component Component) (string, error) {
+	var name string
+	if isNonEmpty(component.PathInArchive) {
+		cleaned := cleanArchivePath(component.PathInArchive)
+		if cleaned == "." || cleaned == ".." || strings.HasPrefix(cleaned, "../") {
+			return "", fmt.Errorf("install failed: path_in_archive '%s' must be inside the archive", component.PathInArchive)
+		}
+		name = path.Base(cleaned)
+	} else {
+		u, err := url.Parse(component.URI)
+		if err != nil {
+			return "", fmt.Errorf("install failed: invalid URI '%s': %w", component.URI, err)
+		}
+		name = path.Base(u.Path)
+	}
+	if name == "" || name == "." || name == ".." || name == "/" || strings.ContainsAny(name, `/\`) {
+		return "", fmt.Errorf("install failed: cannot derive a file name from '%s'", component.URI)
+	}
+	return name, nil
+}
+
+// fetchComponent returns the verified artifact of a component, from cacheDir when it
+// holds a copy matching the checksum, else downloaded (and cached).
+func (v *defaultValidator) fetchComponent(component Component, cacheDir string) ([]byte, error) {
+	cachePath := ""
+	if isNonEmpty(cacheDir) && isNonEmpty(component.Checksum) {
+		// The checksum names the cache file, so it must be valid before any path is built
+		hash, err := parseChecksum(component.Checksum)
+		if err != nil {
+			return nil, fmt.Errorf("install failed: %w", err)
+		}
+		cachePath, err = cacheFilePath(cacheDir, "sha256-"+hash)
+		if err != nil {
+			return nil, fmt.Errorf("install failed: %w", err)
+		}
+		if data, err := os.ReadFile(cachePath); err == nil {
+			if err := verifyChecksumReader(bytes.NewReader(data), component.Checksum); err == nil {
+				log.Printf("Using cached artifact '%s' for '%s'.", cachePath, component.URI)
+				return data, nil
+			}
+			log.Printf("Warning: cached artifact '%s' does not match its checksum, downloading again.", cachePath)
+			_ = os.Remove(cachePath)
+		}
+	}
+
+	data, err := v.downloadWithRetry(component.URI)
+	if err != nil {
+		return nil, fmt.Errorf("install failed: download from URI '%s': %w", component.URI, err)
+	}
+	if err := v.verifyChecksum(data, component.Checksum); err != nil {
+		return nil, fmt.Errorf("install failed: checksum verification for URI '%s': %w", component.URI, err)
+	}
+	if cachePath != "" {
+		if err := os.MkdirAll(cacheDir, 0o755); err == nil {
+			err = writeFileAtomic(cachePath, bytes.NewReader(data), 0o644)
+		}
+		if err != nil {
+			// The install itself does not need the cache
+			log.Printf("Warning: failed to cache artifact '%s': %v", cachePath, err)
+		}
+	}
+	return data, nil
+}
+
+// cacheFilePath joins name to cacheDir, refusing any result outside of cacheDir.
+func cacheFilePath(cacheDir, name string) (string, error) {
+	cachePath := filepath.Join(cacheDir, name)
+	rel, err := filepath.Rel(filepath.Clean(cacheDir), cachePath)
+	if err != nil || rel == "." || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) || strings.ContainsRune(rel, filepath.Separator) {
+		return "", fmt.Errorf("cache path for '%s' is outside of the cache directory", name)
+	}
+	return cachePath, nil
+}
+
+// openArchiveEntry returns the content of the regular file at pathInArchive. Symlinks,
+// hard links and directories are rejected so that nothing outside the entry is read.
+func openArchiveEntry(archiveData []byte, archiveType, pathInArchive string) (io.Reader, error) {
+	cleanedPath := cleanArchivePath(pathInArchive)
+	var content io.Reader
+	errFound := errors.New("found")
+	err := walkArchive(archiveData, archiveType, func(entry archiveEntry) error {
+		if cleanArchivePath(entry.name) != cleanedPath {
+			return nil
+		}
+		if !entry.mode.IsRegular() || entry.hardLink {
+			return fmt.Errorf("not a regular file (mode: %v)", entry.mode)
+		}
+		rc, err := entry.open()
+		if err != nil {
+			return fmt.Errorf("failed to open: %w", err)
+		}
+		defer rc.Close()
+		if content, err = readLimited(rc); err != nil {
+			return err
+		}
+		return errFound
+	})
+	if errors.Is(err, errFound) {
+		return content, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("not found in the %s archive", archiveType)
+}
+
+// readLimited reads an archive entry, refusing entries larger than MaxDownloadSizeBytes
+// (e.g. decompression bombs).
+func readLimited(r io.Reader) (io.Reader, error) {
+	data, err := io.ReadAll(io.LimitReader(r, MaxDownloadSizeBytes+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read: %w", err)
+	}
+	if len(data) > MaxDownloadSizeBytes {
+		return nil, fmt.Errorf("larger than the %d bytes limit", MaxDownloadSizeBytes)
+	}
+	return bytes.NewReader(data), nil
+}
+
+// writeFileAtomic writes r to a temporary file next to target and renames it over target.
+func writeFileAtomic(target string, r io.Reader, mode os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(target), "."+filepath.Base(target)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("create temporary file: %w", err)
+	}
+	defer os.Remove(tmp.Name()) // No-op once renamed
+	if _, err := io.Copy(tmp, r); err != nil {
+		tmp.Close()
+		return fmt.Errorf("write '%s': %w", target, err)
+	}
+	if err := tmp.Chmod(mode); err != nil {
+		tmp.Close()
+		return fmt.Errorf("set permissions of '%s': %w", target, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("write '%s': %w", target, err)
+	}
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		return fmt.Errorf("rename to '%s': %w", target, err)
+	}
+	return nil
+}
@@ -0,0 +1,75 @@
+package platformspec_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/opengovern/og-util/pkg/platformspec"
+	"github.com/stretchr/testify/require"
+)
+
+func TestInstallCache(t *testing.T) {
+	r := require.New(t)
+
+	binary := []byte("#!/bin/sh\necho plugin\n")
+	sum := sha256.Sum256(binary)
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requests.Add(1)
+		w.Write(binary)
+	}))
+	defer server.Close()
+
+	component := platformspec.Component{
+		URI:      server.URL + "/og-describer",
+		Checksum: "sha256:" + hex.EncodeToString(sum[:]),
+	}
+	opts := platformspec.InstallOptions{CacheDir: t.TempDir(), HTTPClient: server.Client()}
+	for i := 0; i < 2; i++ {
+		target, err := platformspec.InstallWithOptions(component, t.TempDir(), opts)
+		r.NoError(err)
+		installed, err := os.ReadFile(target)
+		r.NoError(err)
+		r.Equal(binary, installed)
+	}
+	r.EqualValues(1, requests.Load())
+}
+
+func TestInstallRejectsTraversalChecksum(t *testing.T) {
+	r := require.New(t)
+
+	root := t.TempDir()
+	cacheDir := filepath.Join(root, "cache", "artifacts")
+	r.NoError(os.MkdirAll(cacheDir, 0o755))
+	victim := filepath.Join(root, "victim")
+	r.NoError(os.WriteFile(victim, []byte("keep me"), 0o644))
+
+	var requests atomic.Int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, req *http.Request) {
+		requests.Add(1)
+		w.Write([]byte("binary"))
+	}))
+	defer server.Close()
+
+	for _, checksum := range []string{
+		"sha256:/../../victim",
+		"sha256:/../../../victim",
+		"sha256-/../../../victim:x",
+		"md5:../../victim",
+		"sha256:" + "../" + "0000000000000000000000000000000000000000000000000000000000",
+	} {
+		component := platformspec.Component{URI: server.URL + "/og-describer", Checksum: checksum}
+		_, err := platformspec.InstallWithOptions(component, t.TempDir(), platformspec.InstallOptions{CacheDir: cacheDir, HTTPClient: server.Client()})
+		r.Error(err, checksum)
+		data, err := os.ReadFile(victim)
+		r.NoError(err, checksum)
+		r.Equal("keep me", string(data))
+	}
+	r.Zero(requests.Load())
+}
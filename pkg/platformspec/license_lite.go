@@ -0,0 +1,45 @@
+//go:build platformspec_lite
+
+// license_lite.go
+package platformspec
+
+import (
+	"log"
+	"regexp"
+	"strings"
+)
+
+// licenseIDRegex matches the syntax of SPDX license and exception identifiers, including
+// LicenseRef- references and the '+' suffix.
+var licenseIDRegex = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9.-]*\+?$`)
+
+func initializeSPDX() {
+	log.Println("SPDX license list not included in platformspec_lite builds; only the syntax of licenses is validated.")
+}
+
+// validateLicenseExpression checks the syntax of a license expression, e.g.
+// "MIT OR Apache-2.0", returning the invalid parts. Without the SPDX license list,
+// unknown identifiers are accepted.
+func validateLicenseExpression(license string) (bool, []string) {
+	fields := strings.Fields(strings.NewReplacer("(", " ", ")", " ").Replace(license))
+	var invalid []string
+	expectID := true
+	for _, field := range fields {
+		switch strings.ToUpper(field) {
+		case "AND", "OR", "WITH":
+			if expectID {
+				invalid = append(invalid, field)
+			}
+			expectID = true
+			continue
+		}
+		if !expectID || !licenseIDRegex.MatchString(field) {
+			invalid = append(invalid, field)
+		}
+		expectID = false
+	}
+	if len(fields) == 0 || expectID {
+		invalid = append(invalid, license)
+	}
+	return len(invalid) == 0, invalid
+}
@@ -0,0 +1,30 @@
+//go:build !platformspec_lite
+
+// license_spdx.go
+package platformspec
+
+import (
+	"log"
+
+	"github.com/github/go-spdx/v2/spdxexp"
+)
+
+// initializeSPDX attempts to pre-load/check the SPDX license list.
+func initializeSPDX() {
+	// licenses.IsValidLicenseID implicitly handles loading/caching.
+	// Use a known valid license ID for the check.
+	// ValidateLicenses returns bool, []string. We only need the bool here.
+	valid, _ := spdxexp.ValidateLicenses([]string{"MIT"})
+	if !valid {
+		// This might log internal errors from the library if fetching fails.
+		log.Println("Warning: Initial check for SPDX license 'MIT' failed. SPDX validation might be unavailable or inaccurate if the license list couldn't be loaded.")
+	} else {
+		log.Println("SPDX license list appears available for validation.")
+	}
+}
+
+// validateLicenseExpression checks a license expression against the SPDX license list
+// using the github/go-spdx library, returning the invalid parts.
+func validateLicenseExpression(license string) (bool, []string) {
+	return spdxexp.ValidateLicenses([]string{license})
+}
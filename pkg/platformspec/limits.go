@@ -0,0 +1,77 @@
+// limits.go
+package platformspec
+
+import (
+	"fmt"
+)
+
+// Default policy limits. These are far above anything a legitimate specification needs
+// and exist to reject pathological manifests (e.g., thousands of schedule entries)
+// before they reach downstream consumers such as the UI.
+const (
+	DefaultMaxSpecSizeBytes      = 1 * 1024 * 1024 // 1 MiB of raw YAML
+	DefaultMaxRunScheduleEntries = 500
+	DefaultMaxParams             = 100
+	DefaultMaxConfigs            = 100
+	DefaultMaxTags               = 200 // Total key:value pairs after flattening
+	DefaultMaxDocSizeBytes       = 512 * 1024
+)
+
+// SpecLimits configures the policy limits enforced on specification contents.
+// A limit that is zero or negative is disabled.
+type SpecLimits struct {
+	MaxSpecSizeBytes      int // Raw specification size
+	MaxRunScheduleEntries int // Task run_schedule entries
+	MaxParams             int // Task params or query parameters
+	MaxConfigs            int // Task configs
+	MaxTags               int // Flattened tag key:value pairs
+	MaxDocSizeBytes       int // Each downloaded plugin README or changelog
+}
+
+// DefaultSpecLimits returns the limits applied by NewDefaultValidator.
+func DefaultSpecLimits() SpecLimits {
+	return SpecLimits{
+		MaxSpecSizeBytes:      DefaultMaxSpecSizeBytes,
+		MaxRunScheduleEntries: DefaultMaxRunScheduleEntries,
+		MaxParams:             DefaultMaxParams,
+		MaxConfigs:            DefaultMaxConfigs,
+		MaxTags:               DefaultMaxTags,
+		MaxDocSizeBytes:       DefaultMaxDocSizeBytes,
+	}
+}
+
+// checkLimit returns an error if count exceeds a positive limit.
+func checkLimit(count, limit int, what, specContext string) error {
+	if limit > 0 && count > limit {
+		return fmt.Errorf("%s: %s count %d exceeds the maximum allowed %d", specContext, what, count, limit)
+	}
+	return nil
+}
+
+// checkSpecSize enforces MaxSpecSizeBytes on the raw specification data.
+func (l SpecLimits) checkSpecSize(size int, filePath string) error {
+	if l.MaxSpecSizeBytes > 0 && size > l.MaxSpecSizeBytes {
+		return fmt.Errorf("specification '%s' is %d bytes, exceeding the maximum allowed %d bytes", filePath, size, l.MaxSpecSizeBytes)
+	}
+	return nil
+}
+
+// checkTaskCounts enforces the run_schedule, params, and configs limits on a task.
+func (l SpecLimits) checkTaskCounts(spec *TaskSpecification, taskDesc string) error {
+	if err := checkLimit(len(spec.RunSchedule), l.MaxRunScheduleEntries, "run_schedule entry", taskDesc); err != nil {
+		return err
+	}
+	if err := checkLimit(len(spec.Params), l.MaxParams, "params", taskDesc); err != nil {
+		return err
+	}
+	return checkLimit(len(spec.Configs), l.MaxConfigs, "configs", taskDesc)
+}
+
+// checkTagCount enforces MaxTags on the total number of key:value pairs in a tags map.
+func (l SpecLimits) checkTagCount(tags map[string]StringOrSlice, specContext string) error {
+	total := 0
+	for _, values := range tags {
+		total += len(values)
+	}
+	return checkLimit(total, l.MaxTags, "tag", specContext)
+}
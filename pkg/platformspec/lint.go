@@ -0,0 +1,79 @@
+// lint.go
+package platformspec
+
+import (
+	"fmt"
+	"strings"
+)
+
+// LintSeverity ranks a lint finding.
+type LintSeverity string
+
+const (
+	LintSeverityError   LintSeverity = "error"   // Blocks acceptance in the review workflow
+	LintSeverityWarning LintSeverity = "warning" // Needs a reviewer's attention
+	LintSeverityInfo    LintSeverity = "info"    // Informational only
+)
+
+// LintFinding is a single problem found in a specification that does not fail
+// structural validation on its own but is surfaced to reviewers.
+type LintFinding struct {
+	RuleID   string       `json:"rule_id" yaml:"rule_id"`
+	Severity LintSeverity `json:"severity" yaml:"severity"`
+	Field    string       `json:"field,omitempty" yaml:"field,omitempty"` // Dotted path in the spec, e.g. "provenance.commit"
+	Message  string       `json:"message" yaml:"message"`
+}
+
+func (f LintFinding) String() string {
+	if f.Field == "" {
+		return fmt.Sprintf("%s [%s] %s", f.Severity, f.RuleID, f.Message)
+	}
+	return fmt.Sprintf("%s [%s] %s: %s", f.Severity, f.RuleID, f.Field, f.Message)
+}
+
+// LintReport collects lint findings for one specification.
+type LintReport struct {
+	SpecType string        `json:"spec_type" yaml:"spec_type"`
+	SpecID   string        `json:"spec_id,omitempty" yaml:"spec_id,omitempty"`     // Plugin name or task ID
+	FilePath string        `json:"file_path,omitempty" yaml:"file_path,omitempty"` // Spec file, relative to the repository root for SARIF
+	Findings []LintFinding `json:"findings" yaml:"findings"`
+}
+
+// Add appends a finding to the report.
+func (r *LintReport) Add(ruleID string, severity LintSeverity, field, format string, args ...interface{}) {
+	r.Findings = append(r.Findings, LintFinding{
+		RuleID:   ruleID,
+		Severity: severity,
+		Field:    field,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// HasErrors reports whether any finding has error severity.
+func (r *LintReport) HasErrors() bool {
+	for _, f := range r.Findings {
+		if f.Severity == LintSeverityError {
+			return true
+		}
+	}
+	return false
+}
+
+// BySeverity returns the findings of the given severity.
+func (r *LintReport) BySeverity(severity LintSeverity) []LintFinding {
+	var findings []LintFinding
+	for _, f := range r.Findings {
+		if f.Severity == severity {
+			findings = append(findings, f)
+		}
+	}
+	return findings
+}
+
+func (r *LintReport) String() string {
+	lines := make([]string, len(r.Findings))
+	for i, f := range r.Findings {
+		lines[i] = f.String()
+	}
+	return strings.Join(lines, "\n")
+}
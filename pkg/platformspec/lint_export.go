@@ -0,0 +1,248 @@
+// lint_export.go
+package platformspec
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"sort"
+)
+
+// RuleValidationFailed is the rule of findings converted from ValidationReport errors.
+const RuleValidationFailed = "validation-failed"
+
+// LintToolName names the validator in SARIF output.
+const LintToolName = "og-platformspec"
+
+// LintReport converts the report's errors into error findings, so that validation
+// failures can be exported like lint findings.
+func (r ValidationReport) LintReport() LintReport {
+	report := LintReport{
+		SpecType: r.SpecType,
+		SpecID:   r.SpecID,
+		FilePath: r.FilePath,
+	}
+	for _, e := range r.Errors {
+		report.Findings = append(report.Findings, LintFinding{
+			RuleID:   RuleValidationFailed,
+			Severity: LintSeverityError,
+			Message:  e,
+		})
+	}
+	return report
+}
+
+// SARIF returns the report as a SARIF 2.1.0 log, e.g. for GitHub code scanning.
+func (r *LintReport) SARIF() ([]byte, error) {
+	return LintReportsSARIF([]LintReport{*r})
+}
+
+// JUnit returns the report as JUnit XML, e.g. for CI test summaries.
+func (r *LintReport) JUnit() ([]byte, error) {
+	return LintReportsJUnit([]LintReport{*r})
+}
+
+type sarifLog struct {
+	Schema  string     `json:"$schema"`
+	Version string     `json:"version"`
+	Runs    []sarifRun `json:"runs"`
+}
+
+type sarifRun struct {
+	Tool    sarifTool     `json:"tool"`
+	Results []sarifResult `json:"results"`
+}
+
+type sarifTool struct {
+	Driver struct {
+		Name  string      `json:"name"`
+		Rules []sarifRule `json:"rules"`
+	} `json:"driver"`
+}
+
+type sarifRule struct {
+	ID                   string `json:"id"`
+	DefaultConfiguration struct {
+		Level string `json:"level"`
+	} `json:"defaultConfiguration"`
+}
+
+type sarifResult struct {
+	RuleID    string          `json:"ruleId"`
+	Level     string          `json:"level"`
+	Message   sarifMessage    `json:"message"`
+	Locations []sarifLocation `json:"locations,omitempty"`
+}
+
+type sarifMessage struct {
+	Text string `json:"text"`
+}
+
+type sarifLocation struct {
+	PhysicalLocation *sarifPhysicalLocation `json:"physicalLocation,omitempty"`
+	LogicalLocations []sarifLogicalLocation `json:"logicalLocations,omitempty"`
+}
+
+type sarifPhysicalLocation struct {
+	ArtifactLocation struct {
+		URI string `json:"uri"`
+	} `json:"artifactLocation"`
+}
+
+type sarifLogicalLocation struct {
+	FullyQualifiedName string `json:"fullyQualifiedName"`
+}
+
+// sarifLevel maps a severity to a SARIF level.
+func sarifLevel(severity LintSeverity) string {
+	switch severity {
+	case LintSeverityError:
+		return "error"
+	case LintSeverityWarning:
+		return "warning"
+	default:
+		return "note"
+	}
+}
+
+// LintReportsSARIF returns the reports of several specifications, e.g. all specs of a
+// plugin repository, as one SARIF 2.1.0 log. Findings are located in the report's
+// FilePath, and in the finding's field as a logical location.
+func LintReportsSARIF(reports []LintReport) ([]byte, error) {
+	run := sarifRun{Results: []sarifResult{}}
+	run.Tool.Driver.Name = LintToolName
+	run.Tool.Driver.Rules = []sarifRule{}
+
+	rules := make(map[string]LintSeverity)
+	for _, report := range reports {
+		for _, f := range report.Findings {
+			// A rule's default level is the most severe level it is reported at
+			if current, ok := rules[f.RuleID]; !ok || lintSeverityRank(f.Severity) > lintSeverityRank(current) {
+				rules[f.RuleID] = f.Severity
+			}
+
+			result := sarifResult{
+				RuleID:  f.RuleID,
+				Level:   sarifLevel(f.Severity),
+				Message: sarifMessage{Text: f.Message},
+			}
+			var location sarifLocation
+			if report.FilePath != "" {
+				location.PhysicalLocation = &sarifPhysicalLocation{}
+				location.PhysicalLocation.ArtifactLocation.URI = report.FilePath
+			}
+			if f.Field != "" {
+				location.LogicalLocations = []sarifLogicalLocation{{FullyQualifiedName: f.Field}}
+			}
+			if location.PhysicalLocation != nil || location.LogicalLocations != nil {
+				result.Locations = []sarifLocation{location}
+			}
+			run.Results = append(run.Results, result)
+		}
+	}
+
+	ids := make([]string, 0, len(rules))
+	for id := range rules {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for _, id := range ids {
+		rule := sarifRule{ID: id}
+		rule.DefaultConfiguration.Level = sarifLevel(rules[id])
+		run.Tool.Driver.Rules = append(run.Tool.Driver.Rules, rule)
+	}
+
+	return json.MarshalIndent(sarifLog{
+		Schema:  "https://json.schemastore.org/sarif-2.1.0.json",
+		Version: "2.1.0",
+		Runs:    []sarifRun{run},
+	}, "", "  ")
+}
+
+func lintSeverityRank(severity LintSeverity) int {
+	switch severity {
+	case LintSeverityError:
+		return 2
+	case LintSeverityWarning:
+		return 1
+	default:
+		return 0
+	}
+}
+
+type junitTestSuites struct {
+	XMLName  xml.Name         `xml:"testsuites"`
+	Tests    int              `xml:"tests,attr"`
+	Failures int              `xml:"failures,attr"`
+	Suites   []junitTestSuite `xml:"testsuite"`
+}
+
+type junitTestSuite struct {
+	Name      string          `xml:"name,attr"`
+	Tests     int             `xml:"tests,attr"`
+	Failures  int             `xml:"failures,attr"`
+	TestCases []junitTestCase `xml:"testcase"`
+}
+
+type junitTestCase struct {
+	Name      string        `xml:"name,attr"`
+	ClassName string        `xml:"classname,attr"`
+	File      string        `xml:"file,attr,omitempty"`
+	Failure   *junitFailure `xml:"failure,omitempty"`
+	SystemOut string        `xml:"system-out,omitempty"`
+}
+
+type junitFailure struct {
+	Message string `xml:"message,attr"`
+	Type    string `xml:"type,attr"`
+	Text    string `xml:",chardata"`
+}
+
+// LintReportsJUnit returns the reports as JUnit XML with a test suite per specification.
+// Error findings are failed test cases; warnings and info findings pass with their
+// message as output. A specification without findings has a single passing test case.
+func LintReportsJUnit(reports []LintReport) ([]byte, error) {
+	suites := junitTestSuites{}
+	for _, report := range reports {
+		name := report.SpecType
+		switch {
+		case report.SpecID != "":
+			name = fmt.Sprintf("%s %s", report.SpecType, report.SpecID)
+		case name == "" && report.FilePath != "":
+			name = report.FilePath
+		case name == "":
+			name = "unknown"
+		}
+		suite := junitTestSuite{Name: name}
+		for _, f := range report.Findings {
+			testCase := junitTestCase{
+				Name:      f.RuleID,
+				ClassName: name,
+				File:      report.FilePath,
+			}
+			if f.Field != "" {
+				testCase.Name = fmt.Sprintf("%s %s", f.RuleID, f.Field)
+			}
+			if f.Severity == LintSeverityError {
+				testCase.Failure = &junitFailure{Message: f.Message, Type: string(f.Severity), Text: f.String()}
+				suite.Failures++
+			} else {
+				testCase.SystemOut = f.String()
+			}
+			suite.TestCases = append(suite.TestCases, testCase)
+		}
+		if len(suite.TestCases) == 0 {
+			suite.TestCases = append(suite.TestCases, junitTestCase{Name: "lint", ClassName: name, File: report.FilePath})
+		}
+		suite.Tests = len(suite.TestCases)
+		suites.Tests += suite.Tests
+		suites.Failures += suite.Failures
+		suites.Suites = append(suites.Suites, suite)
+	}
+
+	b, err := xml.MarshalIndent(suites, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), b...), nil
+}
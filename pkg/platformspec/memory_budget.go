@@ -0,0 +1,75 @@
+// memory_budget.go
+package platformspec
+
+import (
+	"log"
+	"time"
+)
+
+// DefaultMaxConcurrentArchives is the number of artifacts a validator holds in memory at
+// the same time unless configured otherwise. With MaxDownloadSizeBytes per artifact it
+// bounds validation to about 2 GiB of artifact buffers.
+const DefaultMaxConcurrentArchives = 2
+
+// MemoryBudget bounds the memory used by artifact validation. Artifacts are downloaded
+// into memory to be verified, so validating several large archives at once, e.g. the
+// variants of a plugin or concurrent ProcessSpecification calls, could exhaust it.
+type MemoryBudget struct {
+	// MaxConcurrentArchives is the number of artifacts downloaded and verified at the same
+	// time by all ProcessSpecification calls of a validator; further artifacts wait for a
+	// slot. DefaultMaxConcurrentArchives if zero; unlimited if negative.
+	MaxConcurrentArchives int
+	// MaxArchiveBytes is the largest artifact downloaded; larger ones fail validation.
+	// MaxDownloadSizeBytes if zero, and capped to it.
+	MaxArchiveBytes int64
+}
+
+// DefaultMemoryBudget returns the budget of validators created without one.
+func DefaultMemoryBudget() MemoryBudget {
+	return MemoryBudget{
+		MaxConcurrentArchives: DefaultMaxConcurrentArchives,
+		MaxArchiveBytes:       MaxDownloadSizeBytes,
+	}
+}
+
+// archiveSlots is the pool of in-memory artifact slots shared by the per-call copies of a
+// validator. A nil *archiveSlots is unlimited.
+type archiveSlots struct {
+	slots chan struct{}
+}
+
+func newArchiveSlots(budget MemoryBudget) *archiveSlots {
+	n := budget.MaxConcurrentArchives
+	if n == 0 {
+		n = DefaultMaxConcurrentArchives
+	}
+	if n < 0 {
+		return nil
+	}
+	return &archiveSlots{slots: make(chan struct{}, n)}
+}
+
+// acquire waits for a free slot, logging when it has to queue, and returns the function
+// releasing it.
+func (s *archiveSlots) acquire(name string) func() {
+	if s == nil {
+		return func() {}
+	}
+	select {
+	case s.slots <- struct{}{}:
+	default:
+		log.Printf("Memory budget: %d artifacts already in memory, %s waits for a slot...", cap(s.slots), name)
+		start := time.Now()
+		s.slots <- struct{}{}
+		log.Printf("Memory budget: %s got a slot after %v.", name, time.Since(start).Round(time.Millisecond))
+	}
+	return func() { <-s.slots }
+}
+
+// maxArchiveBytes returns the largest artifact the validator downloads.
+func (v *defaultValidator) maxArchiveBytes() int64 {
+	if v.memoryBudget.MaxArchiveBytes <= 0 || v.memoryBudget.MaxArchiveBytes > MaxDownloadSizeBytes {
+		return MaxDownloadSizeBytes
+	}
+	return v.memoryBudget.MaxArchiveBytes
+}
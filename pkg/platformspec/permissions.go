@@ -0,0 +1,128 @@
+// permissions.go
+package platformspec
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Permissions lint rule IDs.
+const (
+	RulePermissionsMissing         = "permissions-missing"
+	RulePermissionsWildcard        = "permissions-wildcard"
+	RulePermissionsServiceWildcard = "permissions-service-wildcard"
+	RulePermissionsPrivilegedRole  = "permissions-privileged-role"
+)
+
+var (
+	awsActionRegex     = regexp.MustCompile(`^(\*|[a-z0-9*-]+:[A-Za-z0-9*]+)$`)
+	azureActionRegex   = regexp.MustCompile(`^(\*|[A-Za-z0-9.*]+(/[A-Za-z0-9.*]+)*)$`)
+	azureRoleRegex     = regexp.MustCompile(`^[A-Za-z0-9][A-Za-z0-9 ._()-]*$`)
+	gcpPermissionRegex = regexp.MustCompile(`^(\*|[a-zA-Z0-9*]+(\.[a-zA-Z0-9*]+){2})$`)
+)
+
+// Azure roles granting write or role assignment access to everything in their scope.
+var privilegedAzureRoles = map[string]bool{
+	"owner":                     true,
+	"contributor":               true,
+	"user access administrator": true,
+}
+
+type permissionList struct {
+	field   string
+	entries []string
+	regex   *regexp.Regexp
+	example string
+}
+
+func permissionLists(p *Permissions) []permissionList {
+	return []permissionList{
+		{"aws_actions", p.AWSActions, awsActionRegex, "ec2:DescribeInstances"},
+		{"azure_roles", p.AzureRoles, azureRoleRegex, "Reader"},
+		{"azure_actions", p.AzureActions, azureActionRegex, "Microsoft.Compute/virtualMachines/read"},
+		{"gcp_permissions", p.GCPPermissions, gcpPermissionRegex, "compute.instances.list"},
+	}
+}
+
+// validatePermissions checks the format of every declared permission and that none is
+// duplicated.
+func validatePermissions(p *Permissions, specContext string) error {
+	if p == nil {
+		return nil
+	}
+	for _, list := range permissionLists(p) {
+		seen := make(map[string]bool, len(list.entries))
+		for i, entry := range list.entries {
+			if !list.regex.MatchString(entry) {
+				return fmt.Errorf("%s: permissions.%s entry %d ('%s') is not valid, expected e.g. '%s'", specContext, list.field, i, entry, list.example)
+			}
+			key := strings.ToLower(entry)
+			if seen[key] {
+				return fmt.Errorf("%s: permissions.%s entry %d ('%s') is duplicated", specContext, list.field, i, entry)
+			}
+			seen[key] = true
+		}
+	}
+	return nil
+}
+
+// LintPermissions adds findings for permissions that customers should scrutinize:
+// wildcards granting everything, wildcards granting a whole service and privileged
+// Azure roles. A spec declaring no permissions gets a warning.
+func LintPermissions(p *Permissions, report *LintReport) {
+	if p.IsEmpty() {
+		report.Add(RulePermissionsMissing, LintSeverityWarning, "permissions", "specification declares no cloud permissions")
+		return
+	}
+	for _, list := range permissionLists(p) {
+		for i, entry := range list.entries {
+			field := fmt.Sprintf("permissions.%s[%d]", list.field, i)
+			switch {
+			case list.field == "azure_roles":
+				if privilegedAzureRoles[strings.ToLower(entry)] {
+					report.Add(RulePermissionsPrivilegedRole, LintSeverityWarning, field, "role '%s' grants write access to every resource in its scope", entry)
+				}
+			case isFullWildcard(entry):
+				report.Add(RulePermissionsWildcard, LintSeverityError, field, "'%s' grants every permission", entry)
+			case strings.Contains(entry, "*"):
+				if service, ok := serviceWildcard(list.field, entry); ok {
+					report.Add(RulePermissionsServiceWildcard, LintSeverityWarning, field, "'%s' grants every permission of %s", entry, service)
+				}
+			}
+		}
+	}
+}
+
+// isFullWildcard reports whether a permission matches everything, e.g. "*" or "*:*".
+func isFullWildcard(entry string) bool {
+	return strings.Trim(entry, "*:/.") == ""
+}
+
+// serviceWildcard reports whether a permission grants every action of a service, e.g.
+// "ec2:*" or "Microsoft.Compute/*", and returns the service. Narrower wildcards such as
+// "ec2:Describe*" are expected for read-only access.
+func serviceWildcard(field, entry string) (string, bool) {
+	switch field {
+	case "aws_actions":
+		service, action, _ := strings.Cut(entry, ":")
+		return service, action == "*"
+	case "azure_actions":
+		provider, rest, _ := strings.Cut(entry, "/")
+		return provider, rest == "*" || strings.Trim(rest, "*/") == ""
+	case "gcp_permissions":
+		service, rest, _ := strings.Cut(entry, ".")
+		return service, strings.Trim(rest, "*.") == ""
+	}
+	return "", false
+}
+
+// inheritedPermissions merges the permissions of a plugin and its embedded task, or
+// returns nil if neither declares any.
+func inheritedPermissions(plugin, task *Permissions) *Permissions {
+	if plugin.IsEmpty() && task.IsEmpty() {
+		return nil
+	}
+	merged := MergePermissions(plugin, task)
+	return &merged
+}
@@ -6,20 +6,26 @@ import (
 	"errors"
 	"fmt"
 	"log"
+	"reflect"
+	"slices"
 	"strings"
 	"sync"
 
 	"github.com/Masterminds/semver/v3"
+	"github.com/pelletier/go-toml"
 	"gopkg.in/yaml.v3"
 )
 
 // processPluginSpec handles the parsing and validation specific to plugin specifications.
 // It's called by ProcessSpecification in validator.go.
 // Assumes isNonEmpty, v.CheckPlatformSupport, v.validatePluginArtifacts are defined elsewhere.
-func (v *defaultValidator) processPluginSpec(data []byte, filePath string, platformVersion string, artifactValidationType string, skipArtifactValidation bool) (*PluginSpecification, error) {
+func (v *defaultValidator) processPluginSpec(data []byte, filePath string, platformVersion string, artifactValidationType string, skipArtifactValidation bool, stats *validationStats) (*PluginSpecification, error) {
 	var spec PluginSpecification
 	// Unmarshal directly into the PluginSpecification struct
-	if err := yaml.Unmarshal(data, &spec); err != nil {
+	endParse := v.stats.startPhase(PhaseParse)
+	err := yaml.Unmarshal(data, &spec)
+	endParse()
+	if err != nil {
 		return nil, fmt.Errorf("failed to parse YAML file '%s' as plugin spec: %w", filePath, err)
 	}
 
@@ -33,7 +39,10 @@ func (v *defaultValidator) processPluginSpec(data []byte, filePath string, platf
 
 	log.Printf("Validating plugin specification structure for '%s'...", filePath)
 	// Defaulting for embedded task happens inside validatePluginStructure
-	if err := v.validatePluginStructure(&spec); err != nil {
+	endStructure := v.stats.startPhase(PhaseStructure)
+	err = v.validatePluginStructure(&spec)
+	endStructure()
+	if err != nil {
 		return nil, fmt.Errorf("plugin specification structure validation failed for '%s': %w", filePath, err)
 	}
 	log.Printf("Plugin specification '%s' (Name: %s) structure validation successful.", filePath, spec.Name)
@@ -42,7 +51,9 @@ func (v *defaultValidator) processPluginSpec(data []byte, filePath string, platf
 	// Platform Support Check
 	if isNonEmpty(platformVersion) {
 		log.Printf("Checking platform support for plugin '%s' (Version: %s) against platform '%s'", spec.Name, spec.Version, platformVersion)
+		endPlatform := v.stats.startPhase(PhasePlatform)
 		supported, supportErr := v.CheckPlatformSupport(&spec, platformVersion) // Assumes method exists on v
+		endPlatform()
 		if supportErr != nil {
 			log.Printf("Warning: Error checking platform support for plugin '%s': %v", spec.Name, supportErr)
 		} else {
@@ -60,7 +71,7 @@ func (v *defaultValidator) processPluginSpec(data []byte, filePath string, platf
 	if !skipArtifactValidation {
 		log.Printf("Starting plugin artifact validation for '%s'...", spec.Name)
 		// Assumes validatePluginArtifacts method exists on v
-		if err := v.validatePluginArtifacts(&spec, artifactValidationType); err != nil {
+		if err := v.validatePluginArtifacts(&spec, artifactValidationType, stats); err != nil {
 			return nil, fmt.Errorf("plugin artifact validation failed for '%s': %w", filePath, err)
 		}
 		log.Printf("Plugin artifact validation successful for '%s'.", spec.Name)
@@ -68,6 +79,7 @@ func (v *defaultValidator) processPluginSpec(data []byte, filePath string, platf
 		log.Println("Skipping plugin artifact validation as requested.")
 	}
 
+	spec.Freeze() // Validated specs are read-only; callers use DeepCopy() to modify
 	return &spec, nil
 }
 
@@ -110,9 +122,18 @@ func (v *defaultValidator) validatePluginStructure(spec *PluginSpecification) er
 	if err := v.validateMetadata(&spec.Metadata, specContext+" metadata"); err != nil {
 		return err
 	} // Assumes method exists
+	if err := validateProvenance(spec.Provenance, specContext); err != nil {
+		return err
+	}
+	if err := validateCapabilities(spec.Capabilities, specContext); err != nil {
+		return err
+	}
+	if err := validatePermissions(spec.Permissions, specContext); err != nil {
+		return err
+	}
 
 	// --- Components Block Fields ---
-	if spec.Components == (PluginComponents{}) {
+	if reflect.ValueOf(spec.Components).IsZero() {
 		return fmt.Errorf("%s: components section is required", specContext)
 	}
 	components := &spec.Components
@@ -159,6 +180,12 @@ func (v *defaultValidator) validatePluginStructure(spec *PluginSpecification) er
 		if embeddedTask.Type != SpecTypeTask {
 			return fmt.Errorf("%s: embedded task type must be '%s', got '%s'", specContext, SpecTypeTask, embeddedTask.Type)
 		}
+		if err := validateScheduleParamsAgainstCatalog(embeddedTask, spec.Catalog, specContext); err != nil {
+			return err
+		}
+		if embeddedTask.Incremental != nil && !slices.Contains(spec.Capabilities, CapabilityIncrementalDescribe) {
+			return fmt.Errorf("%s: embedded task declares incremental describe but the plugin lacks the '%s' capability", specContext, CapabilityIncrementalDescribe)
+		}
 	}
 
 	// --- Downloadable Components ---
@@ -182,6 +209,24 @@ func (v *defaultValidator) validatePluginStructure(spec *PluginSpecification) er
 		}
 	}
 
+	if err := validateComponentFiles(platformComp, "platform-binary", specContext); err != nil {
+		return err
+	}
+	if err := validateComponentFiles(cloudqlComp, "cloudql-binary", specContext); err != nil {
+		return err
+	}
+	if err := validateComponentVariants(platformComp, "platform-binary", specContext); err != nil {
+		return err
+	}
+	if err := validateComponentVariants(cloudqlComp, "cloudql-binary", specContext); err != nil {
+		return err
+	}
+
+	// --- Docs ---
+	if err := validateDocsComponent(components.Docs, specContext); err != nil {
+		return err
+	}
+
 	// --- Sample Data ---
 	if spec.SampleData != nil && !isNonEmpty(spec.SampleData.URI) {
 		return fmt.Errorf("%s: sample-data.uri is required when sample-data section present", specContext)
@@ -191,6 +236,9 @@ func (v *defaultValidator) validatePluginStructure(spec *PluginSpecification) er
 	if err := validateOptionalTagsMap(spec.Tags, specContext); err != nil {
 		return err
 	} // Assumes helper exists
+	if err := v.limits.checkTagCount(spec.Tags, specContext); err != nil {
+		return err
+	}
 
 	// --- Classification Validation --- <<< ADDED THIS CALL
 	if err := validateOptionalClassification(spec.Classification, specContext); err != nil {
@@ -213,13 +261,16 @@ func (v *defaultValidator) getTaskDetailsFromPluginSpecificationImpl(pluginSpec
 	if isNonEmpty(discoveryComp.TaskID) {
 		log.Printf("Returning partial task details for referenced task ID '%s' from plugin '%s'", discoveryComp.TaskID, pluginSpec.Name)
 		// NOTE: Tags & Classification are NOT inherited when referencing an external task ID.
+		plugin := pluginSpec.DeepCopy()
 		return &TaskDetails{
 			PluginName:                pluginSpec.Name,
 			APIVersion:                pluginSpec.APIVersion,
-			SupportedPlatformVersions: pluginSpec.SupportedPlatformVersions,
+			SupportedPlatformVersions: plugin.SupportedPlatformVersions,
 			Metadata:                  pluginSpec.Metadata,
 			IsReference:               true,
 			ReferencedTaskID:          discoveryComp.TaskID,
+			Capabilities:              plugin.Capabilities,
+			Permissions:               inheritedPermissions(pluginSpec.Permissions, nil), // The referenced task's are not known here
 			// Tags: nil, // Omitted
 			// Classification: nil, // Omitted
 		}, nil
@@ -240,35 +291,29 @@ func (v *defaultValidator) getTaskDetailsFromPluginSpecificationImpl(pluginSpec
 	log.Printf("Image existence validated successfully for: %s", embeddedTask.ImageURL)
 
 	// Populate TaskDetails, including inherited fields
-	// Create copies of slices to prevent accidental modification
-	commandCopy := make([]string, len(embeddedTask.Command))
-	copy(commandCopy, embeddedTask.Command)
-	paramsCopy := make([]string, len(embeddedTask.Params))
-	copy(paramsCopy, embeddedTask.Params)
-	configsCopy := make([]interface{}, len(embeddedTask.Configs))
-	copy(configsCopy, embeddedTask.Configs)
-	runScheduleCopy := make([]RunScheduleEntry, len(embeddedTask.RunSchedule))
-	copy(runScheduleCopy, embeddedTask.RunSchedule)
-	supportedVersionsCopy := make([]string, len(pluginSpec.SupportedPlatformVersions))
-	copy(supportedVersionsCopy, pluginSpec.SupportedPlatformVersions)
-	// Tags map and Classification slice are assigned directly (shallow copy)
+	// Deep copy slices and maps so the returned details never alias the (frozen) spec
+	plugin := pluginSpec.DeepCopy()
+	task := plugin.Components.Discovery.TaskSpec
 
 	details := &TaskDetails{
-		TaskID:                    embeddedTask.ID,
-		TaskName:                  embeddedTask.Name,
-		TaskDescription:           embeddedTask.Description,
-		ValidatedImageURI:         embeddedTask.ImageURL,
-		Command:                   commandCopy,
-		Timeout:                   embeddedTask.Timeout,
-		ScaleConfig:               embeddedTask.ScaleConfig, // Struct copy ok
-		Params:                    paramsCopy,
-		Configs:                   configsCopy,
-		RunSchedule:               runScheduleCopy,
+		TaskID:                    task.ID,
+		TaskName:                  task.Name,
+		TaskDescription:           task.Description,
+		ValidatedImageURI:         task.ImageURL,
+		Command:                   task.Command,
+		Timeout:                   task.Timeout,
+		ScaleConfig:               task.ScaleConfig, // Struct copy ok
+		Params:                    task.Params,
+		Configs:                   task.Configs,
+		RunSchedule:               task.RunSchedule,
 		PluginName:                pluginSpec.Name,
 		APIVersion:                pluginSpec.APIVersion,
-		SupportedPlatformVersions: supportedVersionsCopy,
+		SupportedPlatformVersions: plugin.SupportedPlatformVersions,
 		Metadata:                  pluginSpec.Metadata, // Struct copy ok
-		Tags:                      pluginSpec.Tags,     // Inherit Tags
+		Tags:                      plugin.Tags,         // Inherit Tags
+		Capabilities:              plugin.Capabilities,
+		Incremental:               task.Incremental,
+		Permissions:               inheritedPermissions(pluginSpec.Permissions, embeddedTask.Permissions),
 		// Classification: pluginSpec.Classification, // <<< REMOVED: Classification not in TaskDetails anymore
 		IsReference: false,
 	}
@@ -278,8 +323,9 @@ func (v *defaultValidator) getTaskDetailsFromPluginSpecificationImpl(pluginSpec
 } // --- END getTaskDetailsFromPluginSpecificationImpl ---
 
 // validatePluginArtifacts handles artifact validation logic.
+// Downloaded artifact sizes are recorded on stats, which may be nil.
 // Assumes isNonEmpty and artifact validation methods (v.validate...) exist elsewhere.
-func (v *defaultValidator) validatePluginArtifacts(spec *PluginSpecification, artifactType string) error {
+func (v *defaultValidator) validatePluginArtifacts(spec *PluginSpecification, artifactType string, stats *validationStats) error {
 	if spec == nil {
 		return errors.New("plugin spec cannot be nil for artifact validation")
 	}
@@ -290,7 +336,7 @@ func (v *defaultValidator) validatePluginArtifacts(spec *PluginSpecification, ar
 	}
 	log.Printf("--- Starting Plugin Artifact Validation (Plugin: %s, Type: %s) ---", spec.Name, normalizedType)
 
-	validateDiscovery, validatePlatform, validateCloudQL := false, false, false
+	validateDiscovery, validatePlatform, validateCloudQL, validateDocs := false, false, false, false
 	discoveryIsEmbedded := spec.Components.Discovery.TaskSpec != nil
 
 	switch normalizedType {
@@ -300,6 +346,7 @@ func (v *defaultValidator) validatePluginArtifacts(spec *PluginSpecification, ar
 		}
 		validatePlatform = true
 		validateCloudQL = true
+		validateDocs = true
 		logScope := "PlatformBinary, CloudQLBinary artifacts"
 		if discoveryIsEmbedded {
 			logScope = "Discovery Image, " + logScope
@@ -320,12 +367,15 @@ func (v *defaultValidator) validatePluginArtifacts(spec *PluginSpecification, ar
 	case ArtifactTypeCloudQLBinary:
 		validateCloudQL = true
 		log.Println("Scope: Validating only CloudQLBinary.")
+	case ArtifactTypeDocs:
+		validateDocs = true
+		log.Println("Scope: Validating only Docs.")
 	default:
-		return fmt.Errorf("invalid artifactType '%s'. Must be one of: '%s', '%s', '%s', or '%s'", artifactType, ArtifactTypeDiscovery, ArtifactTypePlatformBinary, ArtifactTypeCloudQLBinary, ArtifactTypeAll)
+		return fmt.Errorf("invalid artifactType '%s'. Must be one of: '%s', '%s', '%s', '%s', or '%s'", artifactType, ArtifactTypeDiscovery, ArtifactTypePlatformBinary, ArtifactTypeCloudQLBinary, ArtifactTypeDocs, ArtifactTypeAll)
 	}
 
 	var wg sync.WaitGroup
-	errChan := make(chan error, 3)
+	errChan := make(chan error, 5)
 	var platformData []byte
 	platformComp := spec.Components.PlatformBinary
 	cloudqlComp := spec.Components.CloudQLBinary
@@ -353,8 +403,12 @@ func (v *defaultValidator) validatePluginArtifacts(spec *PluginSpecification, ar
 				errChan <- fmt.Errorf("platform-binary artifact validation failed for URI '%s': %w", comp.URI, err)
 				platformData = nil
 			} else {
+				stats.recordArtifactSize(ArtifactTypePlatformBinary, len(platformData))
 				log.Printf("PlatformBinary artifact valid: %s", comp.URI)
 			}
+			if err := v.validateVariantArtifacts(comp, ArtifactTypePlatformBinary); err != nil {
+				errChan <- fmt.Errorf("platform-binary variant validation failed: %w", err)
+			}
 		}(platformComp)
 	}
 
@@ -364,14 +418,24 @@ func (v *defaultValidator) validatePluginArtifacts(spec *PluginSpecification, ar
 		go func(comp Component) {
 			defer wg.Done()
 			log.Printf("Validating CloudQLBinary artifact (separate URI): %s", comp.URI)
-			_, err := v.validateSingleDownloadableComponent(comp, ArtifactTypeCloudQLBinary)
+			cloudqlData, err := v.validateSingleDownloadableComponent(comp, ArtifactTypeCloudQLBinary)
 			if err != nil {
 				errChan <- fmt.Errorf("cloudql-binary artifact validation failed for URI '%s': %w", comp.URI, err)
 			} else {
+				stats.recordArtifactSize(ArtifactTypeCloudQLBinary, len(cloudqlData))
 				log.Printf("CloudQLBinary artifact valid (separate URI): %s", comp.URI)
 			}
 		}(cloudqlComp)
 	}
+	if validateCloudQL && len(cloudqlComp.Variants) > 0 {
+		wg.Add(1)
+		go func(comp Component) {
+			defer wg.Done()
+			if err := v.validateVariantArtifacts(comp, ArtifactTypeCloudQLBinary); err != nil {
+				errChan <- fmt.Errorf("cloudql-binary variant validation failed: %w", err)
+			}
+		}(cloudqlComp)
+	}
 
 	wg.Wait() // Wait for binary downloads
 
@@ -403,11 +467,20 @@ func (v *defaultValidator) validatePluginArtifacts(spec *PluginSpecification, ar
 		}
 	}
 
+	// Validate Docs (optional, sequentially)
+	var docErrors []error
+	if validateDocs {
+		docErrors = v.validatePluginDocs(spec.Components.Docs)
+	}
+
 	close(errChan)
 	var combinedErrors []string
 	for err := range errChan {
 		combinedErrors = append(combinedErrors, err.Error())
 	}
+	for _, err := range docErrors {
+		combinedErrors = append(combinedErrors, fmt.Sprintf("docs validation failed: %v", err))
+	}
 	if len(combinedErrors) > 0 {
 		return fmt.Errorf("one or more artifact validations failed for plugin '%s': %s", spec.Name, strings.Join(combinedErrors, "; "))
 	}
@@ -416,36 +489,37 @@ func (v *defaultValidator) validatePluginArtifacts(spec *PluginSpecification, ar
 	return nil
 } // --- END validatePluginArtifacts ---
 
-// getEmbeddedTaskSpecificationImpl generates a standalone TaskSpecification string from an embedded task.
-// Assumes isNonEmpty is defined elsewhere.
-func (v *defaultValidator) getEmbeddedTaskSpecificationImpl(pluginSpec *PluginSpecification, format string) (string, error) {
+// EmbeddedTaskSpecification builds a standalone TaskSpecification from the task embedded
+// in a plugin, as GetEmbeddedTaskSpecification does before formatting it. The result is
+// a deep copy: it shares no slices, maps, or pointers with pluginSpec, so it can be
+// freely modified. It is a function rather than a Validator method so that adding it
+// does not break other implementations of the interface.
+func EmbeddedTaskSpecification(pluginSpec *PluginSpecification) (*TaskSpecification, error) {
 	if pluginSpec == nil {
-		return "", errors.New("input PluginSpecification cannot be nil")
+		return nil, errors.New("input PluginSpecification cannot be nil")
 	}
 	discoveryComp := pluginSpec.Components.Discovery
 	if isNonEmpty(discoveryComp.TaskID) {
-		return "", fmt.Errorf("plugin '%s' uses task-id reference; cannot generate embedded specification", pluginSpec.Name)
+		return nil, fmt.Errorf("plugin '%s' uses task-id reference; cannot generate embedded specification", pluginSpec.Name)
 	}
 	if discoveryComp.TaskSpec == nil {
-		return "", fmt.Errorf("internal error: plugin '%s' discovery has no embedded task-spec", pluginSpec.Name)
+		return nil, fmt.Errorf("internal error: plugin '%s' discovery has no embedded task-spec", pluginSpec.Name)
 	}
 
-	log.Printf("Generating standalone specification string (format: %s) for embedded task from plugin: %s", format, pluginSpec.Name)
-	embeddedTask := discoveryComp.TaskSpec
+	// Copies, so that the result shares nothing with pluginSpec
+	plugin := pluginSpec.DeepCopy()
+	embeddedTask := plugin.Components.Discovery.TaskSpec
 
 	// Construct standalone struct, inheriting Plugin fields where appropriate for standalone Tasks
 	metadataCopy := pluginSpec.Metadata
-	supportedVersionsCopy := make([]string, len(pluginSpec.SupportedPlatformVersions))
-	copy(supportedVersionsCopy, pluginSpec.SupportedPlatformVersions)
-	metadataPtr := &metadataCopy
 
 	// NOTE: Classification IS NOT inherited/included as per requirement
-	standaloneTask := TaskSpecification{
-		APIVersion:                pluginSpec.APIVersion, // Inherited
-		Type:                      SpecTypeTask,          // Explicit
-		Metadata:                  metadataPtr,           // Inherited
-		SupportedPlatformVersions: supportedVersionsCopy, // Inherited
-		ID:                        embeddedTask.ID,       // From (defaulted) embedded
+	standaloneTask := &TaskSpecification{
+		APIVersion:                pluginSpec.APIVersion,            // Inherited
+		Type:                      SpecTypeTask,                     // Explicit
+		Metadata:                  &metadataCopy,                    // Inherited
+		SupportedPlatformVersions: plugin.SupportedPlatformVersions, // Inherited
+		ID:                        embeddedTask.ID,                  // From (defaulted) embedded
 		Name:                      embeddedTask.Name,
 		Description:               embeddedTask.Description,
 		IsEnabled:                 embeddedTask.IsEnabled,
@@ -456,25 +530,45 @@ func (v *defaultValidator) getEmbeddedTaskSpecificationImpl(pluginSpec *PluginSp
 		Params:                    embeddedTask.Params,
 		Configs:                   embeddedTask.Configs,
 		RunSchedule:               embeddedTask.RunSchedule,
-		Tags:                      pluginSpec.Tags, // Inherited Tags
+		Incremental:               embeddedTask.Incremental,
+		Permissions:               inheritedPermissions(pluginSpec.Permissions, embeddedTask.Permissions),
+		Tags:                      plugin.Tags,       // Inherited Tags
+		Provenance:                plugin.Provenance, // Inherited
 		// Classification field omitted
 	}
+	return standaloneTask, nil
+} // --- END EmbeddedTaskSpecification ---
+
+// getEmbeddedTaskSpecificationImpl generates a standalone TaskSpecification string from an embedded task.
+// Supported formats are FormatYAML (the default), FormatJSON, and FormatTOML.
+func (v *defaultValidator) getEmbeddedTaskSpecificationImpl(pluginSpec *PluginSpecification, format string) (string, error) {
+	standaloneTask, err := EmbeddedTaskSpecification(pluginSpec)
+	if err != nil {
+		return "", err
+	}
+	log.Printf("Generating standalone specification string (format: %s) for embedded task from plugin: %s", format, pluginSpec.Name)
 
 	// Marshal to requested format
 	var outputBytes []byte
-	var err error
 	outputFormat := strings.ToLower(strings.TrimSpace(format))
-	if outputFormat == FormatJSON {
-		outputBytes, err = json.MarshalIndent(&standaloneTask, "", "  ")
+	switch outputFormat {
+	case FormatJSON:
+		outputBytes, err = json.MarshalIndent(standaloneTask, "", "  ")
 		if err != nil {
 			return "", fmt.Errorf("failed to marshal standalone task spec to JSON: %w", err)
 		}
 		log.Printf("Successfully marshaled embedded task spec to JSON.")
-	} else {
+	case FormatTOML:
+		outputBytes, err = marshalTOMLViaYAML(standaloneTask)
+		if err != nil {
+			return "", fmt.Errorf("failed to marshal standalone task spec to TOML: %w", err)
+		}
+		log.Printf("Successfully marshaled embedded task spec to TOML.")
+	default:
 		if outputFormat != FormatYAML && format != "" {
 			log.Printf("Warning: Invalid format '%s', defaulting to YAML.", format)
 		}
-		outputBytes, err = yaml.Marshal(&standaloneTask)
+		outputBytes, err = yaml.Marshal(standaloneTask)
 		if err != nil {
 			return "", fmt.Errorf("failed to marshal standalone task spec to YAML: %w", err)
 		}
@@ -483,3 +577,21 @@ func (v *defaultValidator) getEmbeddedTaskSpecificationImpl(pluginSpec *PluginSp
 
 	return string(outputBytes), nil
 } // --- END getEmbeddedTaskSpecificationImpl ---
+
+// marshalTOMLViaYAML encodes v as TOML using its YAML field names. The spec structs only
+// carry yaml tags, so v is first converted to a generic map through its YAML form.
+func marshalTOMLViaYAML(v interface{}) ([]byte, error) {
+	var node yaml.Node
+	if err := node.Encode(v); err != nil {
+		return nil, err
+	}
+	var generic map[string]interface{}
+	if err := node.Decode(&generic); err != nil {
+		return nil, err
+	}
+	tree, err := toml.TreeFromMap(generic)
+	if err != nil {
+		return nil, err
+	}
+	return []byte(tree.String()), nil
+}
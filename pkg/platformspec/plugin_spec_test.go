@@ -0,0 +1,118 @@
+package platformspec_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/opengovern/og-util/pkg/platformspec"
+	"github.com/pelletier/go-toml"
+	"github.com/stretchr/testify/require"
+	"gopkg.in/yaml.v3"
+)
+
+func embeddedTaskPlugin() *platformspec.PluginSpecification {
+	return &platformspec.PluginSpecification{
+		APIVersion:                platformspec.APIVersionV1,
+		Type:                      platformspec.SpecTypePlugin,
+		Name:                      "aws",
+		Version:                   "1.0.0",
+		SupportedPlatformVersions: []string{">=1.0.0"},
+		Metadata: platformspec.Metadata{
+			Author:        "OpenGovernance",
+			PublishedDate: "2025-01-01",
+			Contact:       "support@opengovernance.io",
+			License:       "Apache-2.0",
+		},
+		Components: platformspec.PluginComponents{
+			Discovery: platformspec.DiscoveryComponent{
+				TaskSpec: &platformspec.TaskSpecification{
+					ID:          "aws-task",
+					Name:        "aws-task",
+					Description: "aws Task",
+					IsEnabled:   true,
+					Type:        platformspec.SpecTypeTask,
+					ImageURL:    "ghcr.io/opengovern/aws@sha256:0000000000000000000000000000000000000000000000000000000000000000",
+					Command:     []string{"/og-describer-aws"},
+					Timeout:     "30m",
+					ScaleConfig: platformspec.ScaleConfig{LagThreshold: "1", MinReplica: 0, MaxReplica: 5},
+					Params:      []string{"regions"},
+					Configs:     []interface{}{"config-a"},
+					RunSchedule: []platformspec.RunScheduleEntry{
+						{ID: "default", Params: map[string]any{"regions": []interface{}{"us-east-1"}}, Frequency: "1d"},
+					},
+				},
+			},
+		},
+		Tags: map[string]platformspec.StringOrSlice{"provider": {"aws"}},
+	}
+}
+
+func TestEmbeddedTaskSpecificationYAMLRoundTrip(t *testing.T) {
+	require := require.New(t)
+	v := platformspec.NewDefaultValidator()
+	plugin := embeddedTaskPlugin()
+
+	expected, err := platformspec.EmbeddedTaskSpecification(plugin)
+	require.NoError(err)
+
+	out, err := v.GetEmbeddedTaskSpecification(plugin, platformspec.FormatYAML)
+	require.NoError(err)
+	var actual platformspec.TaskSpecification
+	require.NoError(yaml.Unmarshal([]byte(out), &actual))
+	require.Equal(expected, &actual)
+}
+
+func TestEmbeddedTaskSpecificationJSONRoundTrip(t *testing.T) {
+	require := require.New(t)
+	v := platformspec.NewDefaultValidator()
+	plugin := embeddedTaskPlugin()
+
+	expected, err := platformspec.EmbeddedTaskSpecification(plugin)
+	require.NoError(err)
+
+	out, err := v.GetEmbeddedTaskSpecification(plugin, platformspec.FormatJSON)
+	require.NoError(err)
+	var actual platformspec.TaskSpecification
+	require.NoError(json.Unmarshal([]byte(out), &actual))
+	require.Equal(expected, &actual)
+}
+
+func TestEmbeddedTaskSpecificationTOMLRoundTrip(t *testing.T) {
+	require := require.New(t)
+	v := platformspec.NewDefaultValidator()
+	plugin := embeddedTaskPlugin()
+
+	expected, err := platformspec.EmbeddedTaskSpecification(plugin)
+	require.NoError(err)
+
+	out, err := v.GetEmbeddedTaskSpecification(plugin, platformspec.FormatTOML)
+	require.NoError(err)
+
+	// TOML uses the YAML field names, so decode back through YAML.
+	tree, err := toml.Load(out)
+	require.NoError(err)
+	yamlBytes, err := yaml.Marshal(tree.ToMap())
+	require.NoError(err)
+	var actual platformspec.TaskSpecification
+	require.NoError(yaml.Unmarshal(yamlBytes, &actual))
+	require.Equal(expected, &actual)
+}
+
+func TestEmbeddedTaskSpecificationStructIsDeepCopy(t *testing.T) {
+	require := require.New(t)
+	plugin := embeddedTaskPlugin()
+
+	task, err := platformspec.EmbeddedTaskSpecification(plugin)
+	require.NoError(err)
+
+	task.Command[0] = "mutated"
+	task.RunSchedule[0].Params["regions"] = "mutated"
+	task.Tags["provider"][0] = "mutated"
+	task.SupportedPlatformVersions[0] = "mutated"
+
+	embedded := plugin.Components.Discovery.TaskSpec
+	require.Equal("/og-describer-aws", embedded.Command[0])
+	require.Equal([]interface{}{"us-east-1"}, embedded.RunSchedule[0].Params["regions"])
+	require.Equal("aws", plugin.Tags["provider"][0])
+	require.Equal(">=1.0.0", plugin.SupportedPlatformVersions[0])
+}
@@ -0,0 +1,143 @@
+// provenance.go
+package platformspec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+// Provenance lint rule IDs.
+const (
+	RuleProvenanceMissing         = "provenance-missing"
+	RuleProvenanceUnsigned        = "provenance-unsigned"
+	RuleProvenanceSubmitter       = "provenance-submitter-mismatch"
+	RuleProvenanceRepository      = "provenance-repository-mismatch"
+	RuleProvenanceCommit          = "provenance-commit-mismatch"
+	RuleProvenanceTagNotFound     = "provenance-tag-not-found"
+	RuleProvenanceTagCommit       = "provenance-tag-commit-mismatch"
+	RuleProvenanceTagVersion      = "provenance-tag-version-mismatch"
+	RuleProvenanceTagUnverifiable = "provenance-tag-unverifiable"
+)
+
+// ErrTagNotFound is returned by TagResolver implementations for tags that do not exist.
+var ErrTagNotFound = errors.New("tag not found")
+
+var commitSHARegex = regexp.MustCompile(`^([a-f0-9]{40}|[a-f0-9]{64})$`)
+
+// SigningIdentity is the verified identity of whoever signed the submission, as extracted
+// by the caller from the signature (e.g. the subject and source repository extensions of
+// a keyless signing certificate). The caller must also have checked that the certificate
+// was issued by a trusted OIDC issuer, since a subject is only meaningful for its issuer.
+// Empty fields are not checked.
+type SigningIdentity struct {
+	Subject          string
+	SourceRepository string
+	Commit           string
+}
+
+// TagResolver looks up tags in a source repository.
+type TagResolver interface {
+	// ResolveTag returns the commit SHA the tag points to, or an error wrapping ErrTagNotFound.
+	ResolveTag(ctx context.Context, repository, tag string) (string, error)
+}
+
+// validateProvenance checks the format of the provenance fields.
+func validateProvenance(p *Provenance, specContext string) error {
+	if p == nil {
+		return nil
+	}
+	if isNonEmpty(p.SourceRepository) {
+		u, err := url.Parse(p.SourceRepository)
+		if err != nil || (u.Scheme != "https" && u.Scheme != "http") || u.Host == "" {
+			return fmt.Errorf("%s: provenance.source_repository '%s' must be an http(s) URL", specContext, p.SourceRepository)
+		}
+	}
+	if isNonEmpty(p.Commit) && !commitSHARegex.MatchString(strings.ToLower(p.Commit)) {
+		return fmt.Errorf("%s: provenance.commit '%s' must be a full 40 or 64 character hex commit SHA", specContext, p.Commit)
+	}
+	if isNonEmpty(p.Tag) && !isNonEmpty(p.SourceRepository) {
+		return fmt.Errorf("%s: provenance.tag requires provenance.source_repository", specContext)
+	}
+	return nil
+}
+
+// VerifyProvenance cross-checks the provenance of a plugin or task specification against
+// the signing identity and the source repository's tags, adding any mismatches to report.
+// identity and tags may be nil, which skips the corresponding checks. The returned error
+// is only set for a spec of an unsupported type; lookup failures become findings.
+func VerifyProvenance(ctx context.Context, spec interface{}, identity *SigningIdentity, tags TagResolver, report *LintReport) error {
+	var provenance *Provenance
+	version := ""
+	switch s := spec.(type) {
+	case *PluginSpecification:
+		provenance, version = s.Provenance, s.Version
+	case *TaskSpecification:
+		provenance = s.Provenance
+	default:
+		return fmt.Errorf("provenance verification is not supported for %T", spec)
+	}
+
+	if provenance == nil {
+		report.Add(RuleProvenanceMissing, LintSeverityWarning, "provenance", "specification declares no provenance")
+		return nil
+	}
+	p := *provenance
+
+	if identity == nil {
+		report.Add(RuleProvenanceUnsigned, LintSeverityWarning, "provenance", "no signing identity available to verify provenance against")
+	} else {
+		if isNonEmpty(p.SubmittedBy) && isNonEmpty(identity.Subject) && !strings.EqualFold(p.SubmittedBy, identity.Subject) {
+			report.Add(RuleProvenanceSubmitter, LintSeverityError, "provenance.submitted_by",
+				"'%s' does not match signing identity '%s'", p.SubmittedBy, identity.Subject)
+		}
+		if isNonEmpty(p.SourceRepository) && isNonEmpty(identity.SourceRepository) &&
+			normalizeRepository(p.SourceRepository) != normalizeRepository(identity.SourceRepository) {
+			report.Add(RuleProvenanceRepository, LintSeverityError, "provenance.source_repository",
+				"'%s' does not match signed source repository '%s'", p.SourceRepository, identity.SourceRepository)
+		}
+		if isNonEmpty(p.Commit) && isNonEmpty(identity.Commit) && !strings.EqualFold(p.Commit, identity.Commit) {
+			report.Add(RuleProvenanceCommit, LintSeverityError, "provenance.commit",
+				"'%s' does not match signed commit '%s'", p.Commit, identity.Commit)
+		}
+	}
+
+	if !isNonEmpty(p.Tag) {
+		return nil
+	}
+	if isNonEmpty(version) && strings.TrimPrefix(p.Tag, "v") != strings.TrimPrefix(version, "v") {
+		report.Add(RuleProvenanceTagVersion, LintSeverityWarning, "provenance.tag",
+			"tag '%s' does not match plugin version '%s'", p.Tag, version)
+	}
+	if tags == nil || !isNonEmpty(p.SourceRepository) {
+		return nil
+	}
+	commit, err := tags.ResolveTag(ctx, p.SourceRepository, p.Tag)
+	switch {
+	case errors.Is(err, ErrTagNotFound):
+		report.Add(RuleProvenanceTagNotFound, LintSeverityError, "provenance.tag",
+			"tag '%s' does not exist in '%s'", p.Tag, p.SourceRepository)
+	case err != nil:
+		report.Add(RuleProvenanceTagUnverifiable, LintSeverityWarning, "provenance.tag",
+			"could not resolve tag '%s' in '%s': %v", p.Tag, p.SourceRepository, err)
+	case isNonEmpty(p.Commit) && !strings.EqualFold(commit, p.Commit):
+		report.Add(RuleProvenanceTagCommit, LintSeverityError, "provenance.tag",
+			"tag '%s' points to commit '%s', not '%s'", p.Tag, commit, p.Commit)
+	}
+	return nil
+}
+
+// normalizeRepository reduces a repository URL to host/path for comparison, so
+// "https://github.com/Org/Repo.git/" and "github.com/org/repo" are equal.
+func normalizeRepository(repository string) string {
+	r := strings.ToLower(strings.TrimSpace(repository))
+	if i := strings.Index(r, "://"); i >= 0 {
+		r = r[i+3:]
+	}
+	r = strings.TrimSuffix(r, "/")
+	r = strings.TrimSuffix(r, ".git")
+	return r
+}
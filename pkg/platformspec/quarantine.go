@@ -0,0 +1,320 @@
+// quarantine.go
+package platformspec
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/google/uuid"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultQuarantineRetention is how long quarantine records are kept when the
+// RetentionPolicy does not set a duration.
+const DefaultQuarantineRetention = 90 * 24 * time.Hour
+
+// ErrQuarantineRecordNotFound is returned by QuarantineStore.Get when no record exists for the id.
+var ErrQuarantineRecordNotFound = errors.New("quarantine record not found")
+
+// ValidationReport is the structured outcome of a failed ProcessSpecification call.
+type ValidationReport struct {
+	SpecType               string   `json:"spec_type" yaml:"spec_type"` // Lowercased, or "unknown"
+	SpecID                 string   `json:"spec_id,omitempty" yaml:"spec_id,omitempty"`
+	APIVersion             string   `json:"api_version,omitempty" yaml:"api_version,omitempty"`
+	FilePath               string   `json:"file_path,omitempty" yaml:"file_path,omitempty"`
+	PlatformVersion        string   `json:"platform_version,omitempty" yaml:"platform_version,omitempty"`
+	ArtifactValidationType string   `json:"artifact_validation_type,omitempty" yaml:"artifact_validation_type,omitempty"`
+	SkipArtifactValidation bool     `json:"skip_artifact_validation" yaml:"skip_artifact_validation"`
+	Errors                 []string `json:"errors" yaml:"errors"` // One entry per joined error
+	// Timings is the time spent per validation phase in milliseconds, keyed by the Phase*
+	// constants. Phases that did not run are absent.
+	Timings map[string]int64 `json:"timings_ms,omitempty" yaml:"timings_ms,omitempty"`
+}
+
+// QuarantinedArtifact describes an artifact referenced by a quarantined spec. Nothing is
+// downloaded to build it; Size is only known for artifacts downloaded during validation.
+type QuarantinedArtifact struct {
+	Type          string          `json:"type" yaml:"type"`                             // One of the ArtifactType* constants
+	Platform      string          `json:"platform,omitempty" yaml:"platform,omitempty"` // Set for component variants
+	URI           string          `json:"uri" yaml:"uri"`
+	PathInArchive string          `json:"path_in_archive,omitempty" yaml:"path_in_archive,omitempty"`
+	Checksum      string          `json:"checksum,omitempty" yaml:"checksum,omitempty"`
+	Files         []ComponentFile `json:"files,omitempty" yaml:"files,omitempty"`
+	Size          int64           `json:"size,omitempty" yaml:"size,omitempty"`
+}
+
+// QuarantineRecord packages a rejected specification for security review.
+type QuarantineRecord struct {
+	ID         string                `json:"id" yaml:"id"`
+	CreatedAt  time.Time             `json:"created_at" yaml:"created_at"`
+	ExpiresAt  time.Time             `json:"expires_at" yaml:"expires_at"`
+	SpecSHA256 string                `json:"spec_sha256" yaml:"spec_sha256"`
+	Spec       []byte                `json:"spec" yaml:"spec"` // Raw submitted bytes, possibly truncated to RetentionPolicy.MaxSpecBytes
+	Truncated  bool                  `json:"truncated,omitempty" yaml:"truncated,omitempty"`
+	Report     ValidationReport      `json:"report" yaml:"report"`
+	Artifacts  []QuarantinedArtifact `json:"artifacts,omitempty" yaml:"artifacts,omitempty"`
+}
+
+// Expired reports whether the record is past its retention period at now.
+func (r QuarantineRecord) Expired(now time.Time) bool {
+	return !r.ExpiresAt.IsZero() && !now.Before(r.ExpiresAt)
+}
+
+// QuarantineStore persists quarantine records.
+type QuarantineStore interface {
+	Save(ctx context.Context, record QuarantineRecord) error
+	Get(ctx context.Context, id string) (QuarantineRecord, error)
+	// List returns all records that are not expired at now, oldest first.
+	List(ctx context.Context, now time.Time) ([]QuarantineRecord, error)
+	// DeleteExpired removes records expired at now and returns how many were removed.
+	DeleteExpired(ctx context.Context, now time.Time) (int, error)
+}
+
+// RetentionPolicy controls how long and how much of a rejected submission is kept.
+type RetentionPolicy struct {
+	// Retention is how long a record is kept; DefaultQuarantineRetention when zero.
+	Retention time.Duration
+	// MaxSpecBytes caps the stored spec bytes; the spec hash always covers the full input.
+	// Zero keeps the whole spec.
+	MaxSpecBytes int
+}
+
+// QuarantineOptions enables quarantining of failed validations in NewValidator.
+type QuarantineOptions struct {
+	Store     QuarantineStore
+	Retention RetentionPolicy
+	// OnQuarantine, if set, is called after a record has been saved, e.g. to notify reviewers.
+	OnQuarantine func(record QuarantineRecord)
+	// Now is used for timestamps; defaults to time.Now.
+	Now func() time.Time
+}
+
+// NewQuarantineRecord packages a spec that failed validation. data may be nil, in which
+// case the spec is read from report.FilePath on a best-effort basis. sizes holds downloaded
+// artifact sizes keyed by artifact type and may be nil.
+func NewQuarantineRecord(data []byte, report ValidationReport, sizes map[string]int64, policy RetentionPolicy, now time.Time) QuarantineRecord {
+	if data == nil && report.FilePath != "" {
+		if fileData, err := os.ReadFile(report.FilePath); err == nil {
+			data = fileData
+		} else {
+			log.Printf("Warning: could not read '%s' for quarantine: %v", report.FilePath, err)
+		}
+	}
+	retention := policy.Retention
+	if retention <= 0 {
+		retention = DefaultQuarantineRetention
+	}
+	sum := sha256.Sum256(data)
+	record := QuarantineRecord{
+		ID:         uuid.NewString(),
+		CreatedAt:  now.UTC(),
+		ExpiresAt:  now.UTC().Add(retention),
+		SpecSHA256: hex.EncodeToString(sum[:]),
+		Spec:       append([]byte(nil), data...),
+		Report:     report,
+		Artifacts:  quarantinedArtifacts(data, sizes),
+	}
+	if policy.MaxSpecBytes > 0 && len(record.Spec) > policy.MaxSpecBytes {
+		record.Spec = record.Spec[:policy.MaxSpecBytes]
+		record.Truncated = true
+	}
+	if record.Report.SpecType == "" {
+		record.Report.SpecType = "unknown"
+	}
+	return record
+}
+
+// newValidationReport builds the report of a failed ProcessSpecification call. Joined
+// errors are split into separate entries.
+func newValidationReport(data []byte, filePath, platformVersion, artifactValidationType string, skipArtifactValidation bool, err error) ValidationReport {
+	report := ValidationReport{
+		FilePath:               filePath,
+		PlatformVersion:        platformVersion,
+		ArtifactValidationType: artifactValidationType,
+		SkipArtifactValidation: skipArtifactValidation,
+		Errors:                 splitErrors(err),
+	}
+	var base BaseSpecification
+	if data != nil && yaml.Unmarshal(data, &base) == nil {
+		report.SpecType = strings.ToLower(base.Type)
+		report.SpecID = base.ID
+		report.APIVersion = base.APIVersion
+	}
+	return report
+}
+
+func splitErrors(err error) []string {
+	if err == nil {
+		return nil
+	}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		var messages []string
+		for _, e := range joined.Unwrap() {
+			messages = append(messages, splitErrors(e)...)
+		}
+		return messages
+	}
+	return []string{err.Error()}
+}
+
+// quarantineArtifactsView picks artifact references out of any spec type without
+// requiring the spec to be valid.
+type quarantineArtifactsView struct {
+	ImageURL   string `yaml:"image_url"`
+	Components struct {
+		Discovery struct {
+			TaskSpec struct {
+				ImageURL string `yaml:"image_url"`
+			} `yaml:"task_spec"`
+		} `yaml:"discovery"`
+		PlatformBinary Component     `yaml:"platform_binary"`
+		CloudQLBinary  Component     `yaml:"cloudql_binary"`
+		Docs           DocsComponent `yaml:"docs"`
+	} `yaml:"components"`
+}
+
+func quarantinedArtifacts(data []byte, sizes map[string]int64) []QuarantinedArtifact {
+	var view quarantineArtifactsView
+	if len(data) == 0 || yaml.Unmarshal(data, &view) != nil {
+		return nil
+	}
+	var artifacts []QuarantinedArtifact
+	addImage := func(imageURL string) {
+		if isNonEmpty(imageURL) {
+			artifacts = append(artifacts, QuarantinedArtifact{Type: ArtifactTypeDiscovery, URI: imageURL})
+		}
+	}
+	addImage(view.ImageURL)
+	addImage(view.Components.Discovery.TaskSpec.ImageURL)
+	for _, c := range []struct {
+		artifactType string
+		component    Component
+	}{
+		{ArtifactTypePlatformBinary, view.Components.PlatformBinary},
+		{ArtifactTypeCloudQLBinary, view.Components.CloudQLBinary},
+	} {
+		if !isNonEmpty(c.component.URI) {
+			continue
+		}
+		artifacts = append(artifacts, QuarantinedArtifact{
+			Type:          c.artifactType,
+			URI:           c.component.URI,
+			PathInArchive: c.component.PathInArchive,
+			Checksum:      c.component.Checksum,
+			Files:         c.component.Files,
+			Size:          sizes[c.artifactType],
+		})
+		for _, variant := range c.component.Variants {
+			artifacts = append(artifacts, QuarantinedArtifact{
+				Type:          c.artifactType,
+				Platform:      variant.Platform(),
+				URI:           variant.URI,
+				PathInArchive: variant.PathInArchive,
+				Checksum:      variant.Checksum,
+				Files:         variant.Files,
+			})
+		}
+	}
+	for _, uri := range []string{view.Components.Docs.ReadmeURI, view.Components.Docs.ChangelogURI} {
+		if isNonEmpty(uri) {
+			artifacts = append(artifacts, QuarantinedArtifact{Type: ArtifactTypeDocs, URI: uri})
+		}
+	}
+	return artifacts
+}
+
+// quarantine saves a record for a failed validation. Store errors and callback panics are
+// logged, never returned, so quarantining cannot change the validation result.
+func (q *QuarantineOptions) quarantine(data []byte, report ValidationReport, stats *validationStats) {
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Warning: quarantine callback panicked and was ignored: %v", r)
+		}
+	}()
+	now := time.Now
+	if q.Now != nil {
+		now = q.Now
+	}
+	record := NewQuarantineRecord(data, report, stats.artifactSizesCopy(), q.Retention, now())
+	if err := q.Store.Save(context.Background(), record); err != nil {
+		log.Printf("Warning: failed to quarantine rejected spec '%s': %v", report.FilePath, err)
+		return
+	}
+	log.Printf("Rejected spec '%s' quarantined as %s (expires %s).", report.FilePath, record.ID, record.ExpiresAt.Format(time.RFC3339))
+	if q.OnQuarantine != nil {
+		q.OnQuarantine(record)
+	}
+}
+
+// MemoryQuarantineStore is an in-process QuarantineStore, useful for tests and the dev server.
+type MemoryQuarantineStore struct {
+	mu      sync.Mutex
+	records map[string]QuarantineRecord
+}
+
+func NewMemoryQuarantineStore() *MemoryQuarantineStore {
+	return &MemoryQuarantineStore{records: make(map[string]QuarantineRecord)}
+}
+
+func (m *MemoryQuarantineStore) Save(_ context.Context, record QuarantineRecord) error {
+	if record.ID == "" {
+		return fmt.Errorf("quarantine record has no id")
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.records[record.ID] = record.clone()
+	return nil
+}
+
+func (m *MemoryQuarantineStore) Get(_ context.Context, id string) (QuarantineRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	record, ok := m.records[id]
+	if !ok {
+		return QuarantineRecord{}, ErrQuarantineRecordNotFound
+	}
+	return record.clone(), nil
+}
+
+func (m *MemoryQuarantineStore) List(_ context.Context, now time.Time) ([]QuarantineRecord, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	var records []QuarantineRecord
+	for _, record := range m.records {
+		if !record.Expired(now) {
+			records = append(records, record.clone())
+		}
+	}
+	sort.Slice(records, func(i, j int) bool { return records[i].CreatedAt.Before(records[j].CreatedAt) })
+	return records, nil
+}
+
+func (m *MemoryQuarantineStore) DeleteExpired(_ context.Context, now time.Time) (int, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	removed := 0
+	for id, record := range m.records {
+		if record.Expired(now) {
+			delete(m.records, id)
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+func (r QuarantineRecord) clone() QuarantineRecord {
+	c := r
+	c.Spec = append([]byte(nil), r.Spec...)
+	c.Report.Errors = append([]string(nil), r.Report.Errors...)
+	c.Artifacts = append([]QuarantinedArtifact(nil), r.Artifacts...)
+	return c
+}
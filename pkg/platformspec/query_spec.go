@@ -52,7 +52,10 @@ func detectQueryParams(query string) []string {
 // Assumes isNonEmpty is defined elsewhere (e.g., common.go)
 func (v *defaultValidator) processQuerySpec(data []byte, filePath string, defaultedAPIVersion, originalAPIVersion string) (*QuerySpecification, error) {
 	var spec QuerySpecification
-	if err := yaml.Unmarshal(data, &spec); err != nil {
+	endParse := v.stats.startPhase(PhaseParse)
+	err := yaml.Unmarshal(data, &spec)
+	endParse()
+	if err != nil {
 		// Provide slightly more context in the parsing error
 		return nil, fmt.Errorf("failed to parse YAML file '%s' as query spec: %w", filePath, err)
 	}
@@ -82,7 +85,10 @@ func (v *defaultValidator) processQuerySpec(data []byte, filePath string, defaul
 	}
 
 	log.Printf("Validating query specification structure for '%s' (ID: %s)...", filePath, spec.ID)
-	if err := v.validateQueryStructure(&spec); err != nil {
+	endStructure := v.stats.startPhase(PhaseStructure)
+	err = v.validateQueryStructure(&spec)
+	endStructure()
+	if err != nil {
 		// Wrap error to include file path
 		return nil, fmt.Errorf("query specification structure validation failed for '%s': %w", filePath, err)
 	}
@@ -93,6 +99,7 @@ func (v *defaultValidator) processQuerySpec(data []byte, filePath string, defaul
 
 	log.Printf("Query specification '%s' (ID: %s) structure validation successful.", filePath, spec.ID)
 	// No artifact validation currently defined for queries
+	spec.Freeze() // Validated specs are read-only; callers use DeepCopy() to modify
 	return &spec, nil
 }
 
@@ -164,6 +171,9 @@ func (v *defaultValidator) validateQueryStructure(spec *QuerySpecification) erro
 	// is_view defaults to false - no structural validation needed here.
 
 	// Validate Parameters
+	if err := checkLimit(len(spec.Parameters), v.limits.MaxParams, "parameters", specContext); err != nil {
+		return err
+	}
 	if spec.Parameters == nil {
 		spec.Parameters = []QueryParameter{} // Ensure non-nil slice if omitted
 	} else if len(spec.Parameters) > 0 {
@@ -186,6 +196,9 @@ func (v *defaultValidator) validateQueryStructure(spec *QuerySpecification) erro
 	if err := validateOptionalTagsMap(spec.Tags, specContext); err != nil {
 		return err // Error is already contextualized by the helper
 	}
+	if err := v.limits.checkTagCount(spec.Tags, specContext); err != nil {
+		return err
+	}
 
 	// Validate Classification (Using Helper)
 	// Assumes validateOptionalClassification takes [][]string
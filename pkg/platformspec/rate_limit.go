@@ -0,0 +1,89 @@
+// rate_limit.go
+package platformspec
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// MaxRetryAfterWait is the longest Retry-After a registry or artifact host may ask for
+// before the validator gives up instead of waiting, e.g. when Docker Hub's pull quota is
+// exhausted for hours.
+const MaxRetryAfterWait = 2 * time.Minute
+
+// RateLimitStats counts the 429 Too Many Requests responses received by all validators
+// of the process, so that bulk validations can be monitored and throttled before a
+// registry bans them.
+type RateLimitStats struct {
+	RegistryHits int64            // 429s from image registries
+	DownloadHits int64            // 429s from artifact hosts
+	HitsByHost   map[string]int64 // All 429s by host
+	Waited       time.Duration    // Time spent waiting as told by Retry-After
+}
+
+var (
+	rateLimitMu    sync.Mutex
+	rateLimitStats = RateLimitStats{HitsByHost: make(map[string]int64)}
+)
+
+// GetRateLimitStats returns a snapshot of the rate limit counters.
+func GetRateLimitStats() RateLimitStats {
+	rateLimitMu.Lock()
+	defer rateLimitMu.Unlock()
+	stats := rateLimitStats
+	stats.HitsByHost = make(map[string]int64, len(rateLimitStats.HitsByHost))
+	for host, hits := range rateLimitStats.HitsByHost {
+		stats.HitsByHost[host] = hits
+	}
+	return stats
+}
+
+// ResetRateLimitStats zeroes the rate limit counters.
+func ResetRateLimitStats() {
+	rateLimitMu.Lock()
+	defer rateLimitMu.Unlock()
+	rateLimitStats = RateLimitStats{HitsByHost: make(map[string]int64)}
+}
+
+func recordRateLimitHit(host string, registry bool) {
+	rateLimitMu.Lock()
+	defer rateLimitMu.Unlock()
+	if registry {
+		rateLimitStats.RegistryHits++
+	} else {
+		rateLimitStats.DownloadHits++
+	}
+	rateLimitStats.HitsByHost[host]++
+}
+
+func recordRateLimitWait(wait time.Duration) {
+	rateLimitMu.Lock()
+	defer rateLimitMu.Unlock()
+	rateLimitStats.Waited += wait
+}
+
+// parseRetryAfter parses a Retry-After header, given either in seconds or as an HTTP
+// date, into the time to wait from now.
+func parseRetryAfter(header string, now time.Time) (time.Duration, bool) {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return 0, false
+	}
+	if seconds, err := strconv.ParseInt(header, 10, 64); err == nil {
+		if seconds < 0 {
+			return 0, false
+		}
+		return time.Duration(seconds) * time.Second, true
+	}
+	date, err := http.ParseTime(header)
+	if err != nil {
+		return 0, false
+	}
+	if wait := date.Sub(now); wait > 0 {
+		return wait, true
+	}
+	return 0, true
+}
@@ -0,0 +1,24 @@
+//go:build platformspec_lite
+
+// registry_lite.go
+package platformspec
+
+import (
+	"log"
+
+	"github.com/opengovern/og-util/pkg/faultinject"
+)
+
+// validateImageManifestExists only checks that imageURI is pinned by digest: registry
+// lookups need ORAS, which platformspec_lite builds leave out.
+func (v *defaultValidator) validateImageManifestExists(imageURI string) error {
+	if err := checkImageDigestURI(imageURI); err != nil {
+		return err
+	}
+	log.Printf("Warning: not checking that image '%s' exists, registry lookups are not available in platformspec_lite builds.", imageURI)
+	return nil
+}
+
+func faultInjectedRegistryClient(*faultinject.Injector) registryHTTPClient {
+	return nil
+}
@@ -0,0 +1,182 @@
+//go:build !platformspec_lite
+
+// registry_oras.go
+package platformspec
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"net"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/opengovern/og-util/pkg/faultinject"
+	"oras.land/oras-go/v2/registry"
+	"oras.land/oras-go/v2/registry/remote"
+	"oras.land/oras-go/v2/registry/remote/auth"
+	"oras.land/oras-go/v2/registry/remote/errcode"
+)
+
+// validateImageManifestExists checks if an image manifest exists in the remote registry using ORAS libraries.
+// It performs retries with exponential backoff for transient network or server errors,
+// and waits as long as a 429 response's Retry-After asks (up to MaxRetryAfterWait).
+func (v *defaultValidator) validateImageManifestExists(imageURI string) error {
+	defer v.stats.startPhase(PhaseRegistry)()
+	if err := checkImageDigestURI(imageURI); err != nil {
+		return err
+	}
+
+	log.Printf("--- Checking Image Manifest Existence (using ORAS): %s ---", imageURI)
+	var lastErr error
+	backoff := InitialBackoffDuration
+	var retryAfter time.Duration // Set when the registry asked to wait
+
+	for attempt := 0; attempt <= MaxRegistryRetries; attempt++ {
+		if attempt > 0 {
+			jitter := time.Duration(rand.Int63n(int64(backoff) / 2)) // Add jitter
+			waitTime := backoff + jitter
+			if retryAfter > 0 {
+				waitTime = retryAfter
+				recordRateLimitWait(waitTime)
+			}
+			log.Printf("Image resolve attempt %d for '%s' failed. Retrying in %v...", attempt, imageURI, waitTime)
+			time.Sleep(waitTime)
+			backoff *= 2 // Exponential backoff
+			retryAfter = 0
+		}
+
+		log.Printf("Image resolve attempt %d/%d for %s...", attempt+1, MaxRegistryRetries+1, imageURI)
+		hint := &retryAfterHint{}
+		ctx, cancel := context.WithTimeout(withRetryAfterHint(context.Background(), hint), OverallRequestTimeout) // Apply overall timeout
+
+		var err error // Declare err here for the scope
+
+		// 1. Parse the image reference
+		var ref registry.Reference
+		ref, err = registry.ParseReference(imageURI)
+		if err != nil {
+			cancel() // Release context resources
+			return fmt.Errorf("failed to parse image reference '%s': %w", imageURI, err)
+		}
+
+		// 2. Create a remote repository client
+		var repo registry.Repository
+		// *** FIX: Use RepositoryWithRegistry() to include the hostname ***
+		// FIX: Combine Host() and Repository() for the full name
+		repoNameWithRegistry := fmt.Sprintf("%s/%s", ref.Host(), ref.Repository)
+		log.Printf("[Debug] Creating remote repository client for: %s", repoNameWithRegistry) // Add debug log
+		remoteRepo, err := remote.NewRepository(repoNameWithRegistry)
+		if err != nil {
+			lastErr = fmt.Errorf("attempt %d: failed to create ORAS repository client for '%s': %w", attempt+1, repoNameWithRegistry, err)
+			cancel()
+			continue // Retry might not help, but let's follow the loop structure
+		}
+		remoteRepo.Client = v.registryLookupClient()
+		repo = remoteRepo
+
+		// 3. Resolve the manifest by digest
+		log.Printf("Attempting to resolve digest '%s' in repository '%s'...", ref.Reference, repoNameWithRegistry) // Log full name
+		_, err = repo.Resolve(ctx, ref.Reference)                                                                  // ref.Reference contains the digest
+		cancel()                                                                                                   // Release context resources after the operation
+
+		// 4. Handle results
+		if err == nil {
+			log.Printf("Successfully resolved image manifest for '%s'.", imageURI)
+			return nil // Success! Manifest exists.
+		}
+
+		// --- Error Handling ---
+		lastErr = fmt.Errorf("attempt %d: failed to resolve image manifest for '%s': %w", attempt+1, imageURI, err)
+		log.Printf("ORAS resolve error details: %v", err)
+
+		var errResp *errcode.ErrorResponse
+		if errors.As(err, &errResp) {
+			log.Printf("Registry returned HTTP status %d: %s", errResp.StatusCode, errResp.Error())
+			if errResp.StatusCode == http.StatusTooManyRequests {
+				wait, ok := hint.get()
+				if ok && wait > MaxRetryAfterWait {
+					return fmt.Errorf("registry rate limit for '%s' resets in %v, longer than the maximum wait of %v: %w", imageURI, wait.Round(time.Second), MaxRetryAfterWait, lastErr)
+				}
+				retryAfter = wait
+				log.Printf("Attempt %d: Registry rate limit hit (Retry-After %v). Allowing retry.", attempt+1, wait)
+				continue
+			}
+			if errResp.StatusCode >= 400 && errResp.StatusCode < 500 {
+				log.Printf("Attempt %d: Received client error %d. Aborting retries.", attempt+1, errResp.StatusCode)
+				return lastErr // Return the specific error, don't retry
+			}
+		} else if errors.Is(err, context.DeadlineExceeded) {
+			log.Printf("Attempt %d: Operation timed out.", attempt+1)
+		} else if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
+			log.Printf("Attempt %d: Network timeout detected.", attempt+1)
+		} else {
+			log.Printf("Attempt %d: Encountered non-HTTP or unknown error type. Retrying allowed.", attempt+1)
+		}
+	} // End retry loop
+
+	return fmt.Errorf("failed to resolve image manifest '%s' after %d attempts: %w", imageURI, MaxRegistryRetries+1, lastErr)
+}
+
+// retryAfterHint receives the Retry-After of a 429 response to a registry request made
+// with its context. ORAS errors carry the status code but not the response headers.
+type retryAfterHint struct {
+	mu    sync.Mutex
+	wait  time.Duration
+	found bool
+}
+
+type retryAfterHintKey struct{}
+
+func withRetryAfterHint(ctx context.Context, hint *retryAfterHint) context.Context {
+	return context.WithValue(ctx, retryAfterHintKey{}, hint)
+}
+
+func (h *retryAfterHint) get() (time.Duration, bool) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.wait, h.found
+}
+
+// rateLimitedRegistryClient counts 429 responses of a registry client and passes their
+// Retry-After to the request's retryAfterHint.
+type rateLimitedRegistryClient struct {
+	client remote.Client
+}
+
+func (c rateLimitedRegistryClient) Do(req *http.Request) (*http.Response, error) {
+	resp, err := c.client.Do(req)
+	if err != nil || resp.StatusCode != http.StatusTooManyRequests {
+		return resp, err
+	}
+	recordRateLimitHit(req.URL.Host, true)
+	if hint, ok := req.Context().Value(retryAfterHintKey{}).(*retryAfterHint); ok {
+		if wait, ok := parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()); ok {
+			hint.mu.Lock()
+			hint.wait, hint.found = wait, true
+			hint.mu.Unlock()
+		}
+	}
+	return resp, nil
+}
+
+// registryLookupClient returns the client for registry lookups.
+func (v *defaultValidator) registryLookupClient() remote.Client {
+	client := v.registryClient
+	if client == nil {
+		client = auth.DefaultClient
+	}
+	return rateLimitedRegistryClient{client: client}
+}
+
+// faultInjectedRegistryClient returns the registry client of a validator whose registry
+// lookups go through injector.
+func faultInjectedRegistryClient(injector *faultinject.Injector) registryHTTPClient {
+	return &auth.Client{
+		Client: injector.Client(http.DefaultClient, operationName("registry-resolve")),
+		Cache:  auth.NewCache(),
+	}
+}
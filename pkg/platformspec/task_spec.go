@@ -18,7 +18,10 @@ import (
 // Assumes isNonEmpty and v.validateImageManifestExists are defined elsewhere.
 func (v *defaultValidator) processTaskSpec(data []byte, filePath string, skipArtifactValidation bool, defaultedAPIVersion, originalAPIVersion string) (*TaskSpecification, error) {
 	var spec TaskSpecification
-	if err := yaml.Unmarshal(data, &spec); err != nil {
+	endParse := v.stats.startPhase(PhaseParse)
+	err := yaml.Unmarshal(data, &spec)
+	endParse()
+	if err != nil {
 		return nil, fmt.Errorf("failed to parse specification file '%s' as task: %w", filePath, err)
 	}
 
@@ -43,7 +46,10 @@ func (v *defaultValidator) processTaskSpec(data []byte, filePath string, skipArt
 
 	log.Printf("Validating standalone task specification structure for '%s'...", filePath)
 	// Pass true for isStandalone check
-	if err := v.validateTaskStructure(&spec, true); err != nil {
+	endStructure := v.stats.startPhase(PhaseStructure)
+	err = v.validateTaskStructure(&spec, true)
+	endStructure()
+	if err != nil {
 		// Wrap validation error with file path context
 		return nil, fmt.Errorf("standalone task specification structure validation failed for '%s': %w", filePath, err)
 	}
@@ -61,6 +67,7 @@ func (v *defaultValidator) processTaskSpec(data []byte, filePath string, skipArt
 	} else if !skipArtifactValidation {
 		log.Printf("Skipping standalone task image validation (ImageURL empty or validation skipped) for task ID: %s.", spec.ID)
 	}
+	spec.Freeze() // Validated specs are read-only; callers use DeepCopy() to modify
 	return &spec, nil
 }
 
@@ -155,10 +162,16 @@ func (v *defaultValidator) validateTaskStructure(spec *TaskSpecification, isStan
 		if err := validateOptionalTagsMap(spec.Tags, taskDesc); err != nil { // Assumes helper exists
 			return err
 		}
+		if err := v.limits.checkTagCount(spec.Tags, taskDesc); err != nil {
+			return err
+		}
 		// Validate Classification (Optional) <<< ADDED THIS CALL
 		if err := validateOptionalClassification(spec.Classification, taskDesc); err != nil { // Assumes helper exists
 			return err
 		}
+		if err := validateProvenance(spec.Provenance, taskDesc); err != nil {
+			return err
+		}
 
 	} else { // --- Embedded task specific checks ---
 		// Ensure standalone-only fields are ABSENT
@@ -184,6 +197,9 @@ func (v *defaultValidator) validateTaskStructure(spec *TaskSpecification, isStan
 		if spec.Classification != nil {
 			log.Printf("Warning: %s: contains 'classification' field, which is ignored for embedded tasks (inherited from plugin).", taskDesc)
 		}
+		if spec.Provenance != nil {
+			return fmt.Errorf("%s: must not contain provenance (declared on the plugin)", taskDesc)
+		}
 	}
 
 	// --- Common Task Field Checks (Required for both Standalone and Embedded) ---
@@ -236,6 +252,13 @@ func (v *defaultValidator) validateTaskStructure(spec *TaskSpecification, isStan
 		return fmt.Errorf("%s: scale_config.max_replica (%d) must be >= min_replica (%d)", taskDesc, sc.MaxReplica, sc.MinReplica)
 	}
 
+	if err := validateIncrementalDescribe(spec.Incremental, taskDesc); err != nil {
+		return err
+	}
+	if err := validatePermissions(spec.Permissions, taskDesc); err != nil {
+		return err
+	}
+
 	// Params & Configs presence checks (must exist, can be empty list)
 	if spec.Params == nil {
 		return fmt.Errorf("%s: params field is required (use [] for none)", taskDesc)
@@ -252,6 +275,11 @@ func (v *defaultValidator) validateTaskStructure(spec *TaskSpecification, isStan
 		return fmt.Errorf("%s: run_schedule must contain at least one entry", taskDesc)
 	}
 
+	// Policy limits (checked before the per-entry loops below)
+	if err := v.limits.checkTaskCounts(spec, taskDesc); err != nil {
+		return err
+	}
+
 	// Detailed Run Schedule Entry checks
 	paramSet := make(map[string]struct{})
 	for _, p := range spec.Params {
@@ -280,6 +308,9 @@ func (v *defaultValidator) validateTaskStructure(spec *TaskSpecification, isStan
 		if !isNonEmpty(schedule.Frequency) {
 			return fmt.Errorf("%s: frequency field is required", entryContext)
 		}
+		if _, err := ParseFrequency(schedule.Frequency); err != nil {
+			return fmt.Errorf("%s: %w", entryContext, err)
+		}
 	}
 
 	return nil // All checks passed
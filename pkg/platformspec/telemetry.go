@@ -0,0 +1,184 @@
+// telemetry.go
+package platformspec
+
+import (
+	"fmt"
+	"log"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Validation phases timed by ProcessSpecification. Artifact phases run concurrently, so
+// their durations are summed over all artifacts and may exceed the wall-clock Duration.
+const (
+	PhaseParse      = "parse"       // YAML decoding
+	PhaseStructure  = "structure"   // Structure, metadata and policy checks
+	PhasePlatform   = "platform"    // Platform version support check
+	PhaseRegistry   = "registry"    // Image manifest lookups in registries
+	PhaseDownload   = "download"    // Artifact downloads, including retries and waits
+	PhaseArchive    = "archive"     // Checksum, path_in_archive and file checks on downloaded artifacts
+	PhaseMemoryWait = "memory_wait" // Waiting for an artifact slot of the MemoryBudget
+)
+
+// ValidationEvent is the anonymized record emitted to a TelemetrySink after each
+// ProcessSpecification call. It intentionally carries no identifying data: no file
+// paths, names, IDs, URIs, or error messages.
+type ValidationEvent struct {
+	SpecType      string                   // One of the SpecType* constants, or "unknown" for a missing or unsupported type
+	Duration      time.Duration            // Wall-clock time spent in ProcessSpecification
+	Success       bool                     // Whether validation passed
+	ArtifactSizes map[string]int64         // Downloaded artifact sizes in bytes, keyed by artifact type (e.g., "platform-binary")
+	Phases        map[string]time.Duration // Time spent per validation phase, keyed by the Phase* constants
+}
+
+// TelemetrySink receives validation events. Implementations are called synchronously
+// on the validating goroutine, so they should return quickly (e.g., by buffering or
+// handing off to a channel). Panics raised by a sink are recovered and logged.
+type TelemetrySink interface {
+	RecordValidation(event ValidationEvent)
+}
+
+// validationStats accumulates per-call telemetry data while a specification is processed.
+// A nil *validationStats is valid and records nothing, which is the telemetry-disabled path.
+type validationStats struct {
+	mu            sync.Mutex
+	specType      string
+	artifactSizes map[string]int64
+	phases        map[string]time.Duration
+}
+
+func newValidationStats() *validationStats {
+	return &validationStats{artifactSizes: make(map[string]int64), phases: make(map[string]time.Duration)}
+}
+
+// startPhase starts timing a validation phase; call the returned function when it ends.
+// It is safe to call from concurrent goroutines.
+func (s *validationStats) startPhase(phase string) func() {
+	if s == nil {
+		return func() {}
+	}
+	start := time.Now()
+	return func() {
+		elapsed := time.Since(start)
+		s.mu.Lock()
+		defer s.mu.Unlock()
+		s.phases[phase] += elapsed
+	}
+}
+
+// phasesCopy returns a copy of the phase durations, or nil for a nil receiver.
+func (s *validationStats) phasesCopy() map[string]time.Duration {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	phases := make(map[string]time.Duration, len(s.phases))
+	for k, v := range s.phases {
+		phases[k] = v
+	}
+	return phases
+}
+
+// phaseMillis converts phase durations to milliseconds for reports.
+func phaseMillis(phases map[string]time.Duration) map[string]int64 {
+	if len(phases) == 0 {
+		return nil
+	}
+	millis := make(map[string]int64, len(phases))
+	for k, v := range phases {
+		millis[k] = v.Milliseconds()
+	}
+	return millis
+}
+
+// formatPhases renders phase durations as "parse=12ms structure=3ms ...", slowest first.
+func formatPhases(phases map[string]time.Duration) string {
+	names := make([]string, 0, len(phases))
+	for name := range phases {
+		names = append(names, name)
+	}
+	sort.Slice(names, func(i, j int) bool {
+		if phases[names[i]] != phases[names[j]] {
+			return phases[names[i]] > phases[names[j]]
+		}
+		return names[i] < names[j]
+	})
+	parts := make([]string, len(names))
+	for i, name := range names {
+		parts[i] = fmt.Sprintf("%s=%s", name, phases[name].Round(time.Millisecond))
+	}
+	return strings.Join(parts, " ")
+}
+
+// setSpecType records the spec type, keeping only the known ones so that a spec cannot
+// put arbitrary values into the telemetry (e.g. as metric labels).
+func (s *validationStats) setSpecType(specType string) {
+	if s == nil {
+		return
+	}
+	switch specType {
+	case SpecTypePlugin, SpecTypeTask, SpecTypeQuery, SpecTypeControl:
+	default:
+		specType = "unknown"
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.specType = specType
+}
+
+// recordArtifactSize is safe to call from the concurrent artifact download goroutines.
+func (s *validationStats) recordArtifactSize(artifactType string, size int) {
+	if s == nil {
+		return
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.artifactSizes[artifactType] = int64(size)
+}
+
+// artifactSizesCopy returns a copy of the recorded sizes, or nil for a nil receiver.
+func (s *validationStats) artifactSizesCopy() map[string]int64 {
+	if s == nil {
+		return nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	sizes := make(map[string]int64, len(s.artifactSizes))
+	for k, v := range s.artifactSizes {
+		sizes[k] = v
+	}
+	return sizes
+}
+
+// emitValidationEvent builds the anonymized event and hands it to the sink.
+func emitValidationEvent(sink TelemetrySink, stats *validationStats, duration time.Duration, err error) {
+	if sink == nil || stats == nil {
+		return
+	}
+	defer func() {
+		if r := recover(); r != nil {
+			log.Printf("Warning: telemetry sink panicked and was ignored: %v", r)
+		}
+	}()
+
+	stats.mu.Lock()
+	event := ValidationEvent{
+		SpecType:      stats.specType,
+		Duration:      duration,
+		Success:       err == nil,
+		ArtifactSizes: make(map[string]int64, len(stats.artifactSizes)),
+	}
+	for k, v := range stats.artifactSizes {
+		event.ArtifactSizes[k] = v
+	}
+	stats.mu.Unlock()
+	event.Phases = stats.phasesCopy()
+
+	if event.SpecType == "" {
+		event.SpecType = "unknown"
+	}
+	sink.RecordValidation(event)
+}
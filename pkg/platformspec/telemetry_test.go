@@ -0,0 +1,30 @@
+package platformspec_test
+
+import (
+	"testing"
+
+	"github.com/opengovern/og-util/pkg/platformspec"
+	"github.com/stretchr/testify/require"
+)
+
+type recordingSink struct {
+	events []platformspec.ValidationEvent
+}
+
+func (s *recordingSink) RecordValidation(event platformspec.ValidationEvent) {
+	s.events = append(s.events, event)
+}
+
+func TestTelemetrySpecType(t *testing.T) {
+	r := require.New(t)
+
+	sink := &recordingSink{}
+	v := platformspec.NewValidator(platformspec.ValidatorOptions{TelemetrySink: sink})
+	for _, specType := range []string{"Query", "user-chosen-value-123"} {
+		data := []byte("api_version: v1\ntype: " + specType + "\n")
+		_, _ = v.ProcessSpecification(data, "spec.yaml", "", "", true)
+	}
+	r.Len(sink.events, 2)
+	r.Equal(platformspec.SpecTypeQuery, sink.events[0].SpecType)
+	r.Equal("unknown", sink.events[1].SpecType)
+}
@@ -0,0 +1,41 @@
+// capabilities.go
+package types
+
+import "slices"
+
+// Capability is a feature flag a plugin declares so the scheduler can adapt its behavior
+// without sniffing plugin versions.
+type Capability string
+
+// Well-known capabilities.
+const (
+	// CapabilityIncrementalDescribe: the describer can resume from a cursor and only emit changes.
+	CapabilityIncrementalDescribe Capability = "supports-incremental-describe"
+	// CapabilityRegionsFilter: the describer honors a 'regions' param and only describes those regions.
+	CapabilityRegionsFilter Capability = "supports-regions-filter"
+	// CapabilityEmitsDeletions: the describer reports deleted resources explicitly, so
+	// the platform need not infer deletions from missing resources.
+	CapabilityEmitsDeletions Capability = "emits-deletions"
+)
+
+// --- TaskDetails accessors ---
+
+// HasCapability reports whether the plugin providing the task declared the capability.
+func (d *TaskDetails) HasCapability(capability Capability) bool {
+	return d != nil && slices.Contains(d.Capabilities, capability)
+}
+
+// SupportsIncrementalDescribe reports whether the task can run incremental describes.
+func (d *TaskDetails) SupportsIncrementalDescribe() bool {
+	return d.HasCapability(CapabilityIncrementalDescribe)
+}
+
+// SupportsRegionsFilter reports whether the task honors the 'regions' param.
+func (d *TaskDetails) SupportsRegionsFilter() bool {
+	return d.HasCapability(CapabilityRegionsFilter)
+}
+
+// EmitsDeletions reports whether the task reports deleted resources explicitly.
+func (d *TaskDetails) EmitsDeletions() bool {
+	return d.HasCapability(CapabilityEmitsDeletions)
+}
@@ -0,0 +1,109 @@
+// component_variants.go
+package types
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// ErrNoMatchingVariant is returned by SelectVariant when a component has no build for the
+// requested platform.
+var ErrNoMatchingVariant = errors.New("no component variant matches the platform")
+
+// Common spellings of architectures, e.g. from 'uname -m'.
+var archAliases = map[string]string{
+	"x86_64":  "amd64",
+	"x64":     "amd64",
+	"aarch64": "arm64",
+	"i386":    "386",
+	"i686":    "386",
+}
+
+// ComponentVariant is the build of a component for one platform. Arch may be empty for a
+// build that runs on every architecture of OS.
+type ComponentVariant struct {
+	OS            string          `yaml:"os" json:"os"`
+	Arch          string          `yaml:"arch,omitempty" json:"arch,omitempty"`
+	URI           string          `yaml:"uri" json:"uri"`
+	PathInArchive string          `yaml:"path_in_archive,omitempty" json:"path_in_archive,omitempty"`
+	Checksum      string          `yaml:"checksum,omitempty" json:"checksum,omitempty"`
+	Files         []ComponentFile `yaml:"files,omitempty" json:"files,omitempty"`
+}
+
+// Platform returns the variant's platform as "os/arch", or "os" if it has no arch.
+func (cv ComponentVariant) Platform() string {
+	if cv.Arch == "" {
+		return cv.OS
+	}
+	return cv.OS + "/" + cv.Arch
+}
+
+// Component returns the variant as a downloadable component.
+func (cv ComponentVariant) Component() Component {
+	return Component{
+		URI:           cv.URI,
+		PathInArchive: cv.PathInArchive,
+		Checksum:      cv.Checksum,
+		Files:         cv.Files,
+	}
+}
+
+// NormalizeArch returns the GOARCH spelling of an architecture, e.g. "amd64" for
+// "x86_64".
+func NormalizeArch(arch string) string {
+	arch = strings.ToLower(strings.TrimSpace(arch))
+	if alias, ok := archAliases[arch]; ok {
+		return alias
+	}
+	return arch
+}
+
+// SelectVariant returns the build of the component for goos and goarch (e.g.
+// runtime.GOOS and runtime.GOARCH): the variant for both, else the variant for goos
+// without an arch. A component without variants is returned as is, since its single
+// build is meant for every platform; otherwise ErrNoMatchingVariant is returned.
+func (c Component) SelectVariant(goos, goarch string) (Component, error) {
+	if len(c.Variants) == 0 {
+		return c, nil
+	}
+	goos = strings.ToLower(strings.TrimSpace(goos))
+	goarch = NormalizeArch(goarch)
+	var osOnly *ComponentVariant
+	for i, variant := range c.Variants {
+		if strings.ToLower(variant.OS) != goos {
+			continue
+		}
+		switch NormalizeArch(variant.Arch) {
+		case goarch:
+			return variant.Component(), nil
+		case "":
+			osOnly = &c.Variants[i]
+		}
+	}
+	if osOnly != nil {
+		return osOnly.Component(), nil
+	}
+	return Component{}, fmt.Errorf("%w: %s/%s", ErrNoMatchingVariant, goos, goarch)
+}
+
+func copyComponentFiles(in []ComponentFile) []ComponentFile {
+	if in == nil {
+		return nil
+	}
+	out := make([]ComponentFile, len(in))
+	copy(out, in)
+	return out
+}
+
+func copyComponentVariants(in []ComponentVariant) []ComponentVariant {
+	if in == nil {
+		return nil
+	}
+	out := make([]ComponentVariant, len(in))
+	for i, variant := range in {
+		out[i] = variant
+		out[i].Files = copyComponentFiles(variant.Files)
+	}
+	return out
+}
@@ -0,0 +1,285 @@
+// deepcopy.go
+package types
+
+// --- Freeze ---
+// Go cannot enforce immutability at the type level, so freezing is advisory: it marks
+// a spec as validated and signals to callers that they should DeepCopy before making
+// changes.
+
+// Freeze marks the plugin specification (and its embedded task, if any) as read-only.
+func (s *PluginSpecification) Freeze() {
+	if s == nil {
+		return
+	}
+	s.frozen = true
+	if s.Components.Discovery.TaskSpec != nil {
+		s.Components.Discovery.TaskSpec.Freeze()
+	}
+}
+
+// IsFrozen reports whether the plugin specification has been frozen.
+func (s *PluginSpecification) IsFrozen() bool { return s != nil && s.frozen }
+
+// Freeze marks the task specification as read-only.
+func (s *TaskSpecification) Freeze() {
+	if s != nil {
+		s.frozen = true
+	}
+}
+
+// IsFrozen reports whether the task specification has been frozen.
+func (s *TaskSpecification) IsFrozen() bool { return s != nil && s.frozen }
+
+// Freeze marks the query specification as read-only.
+func (s *QuerySpecification) Freeze() {
+	if s != nil {
+		s.frozen = true
+	}
+}
+
+// IsFrozen reports whether the query specification has been frozen.
+func (s *QuerySpecification) IsFrozen() bool { return s != nil && s.frozen }
+
+// Freeze marks the control specification as read-only.
+func (s *ControlSpecification) Freeze() {
+	if s != nil {
+		s.frozen = true
+	}
+}
+
+// IsFrozen reports whether the control specification has been frozen.
+func (s *ControlSpecification) IsFrozen() bool { return s != nil && s.frozen }
+
+// --- DeepCopy ---
+// Every DeepCopy returns a fully independent, unfrozen copy. Nil receivers return nil.
+
+// DeepCopy returns an independent copy of the plugin specification.
+func (s *PluginSpecification) DeepCopy() *PluginSpecification {
+	if s == nil {
+		return nil
+	}
+	out := *s
+	out.frozen = false
+	out.SupportedPlatformVersions = copyStringSlice(s.SupportedPlatformVersions)
+	out.Components.Discovery.TaskSpec = s.Components.Discovery.TaskSpec.DeepCopy()
+	out.Components.PlatformBinary.Files = copyComponentFiles(s.Components.PlatformBinary.Files)
+	out.Components.PlatformBinary.Variants = copyComponentVariants(s.Components.PlatformBinary.Variants)
+	out.Components.CloudQLBinary.Files = copyComponentFiles(s.Components.CloudQLBinary.Files)
+	out.Components.CloudQLBinary.Variants = copyComponentVariants(s.Components.CloudQLBinary.Variants)
+	if s.Components.Docs != nil {
+		docs := *s.Components.Docs
+		out.Components.Docs = &docs
+	}
+	if s.SampleData != nil {
+		sampleData := *s.SampleData
+		sampleData.Files = copyComponentFiles(s.SampleData.Files)
+		sampleData.Variants = copyComponentVariants(s.SampleData.Variants)
+		out.SampleData = &sampleData
+	}
+	out.Tags = copyTagsMap(s.Tags)
+	out.Classification = copyClassification(s.Classification)
+	if s.Catalog != nil {
+		out.Catalog = &PluginCatalog{
+			Regions:       copyStringSlice(s.Catalog.Regions),
+			ResourceTypes: copyStringSlice(s.Catalog.ResourceTypes),
+		}
+	}
+	out.Provenance = copyProvenance(s.Provenance)
+	out.Capabilities = copyCapabilities(s.Capabilities)
+	out.Permissions = copyPermissions(s.Permissions)
+	return &out
+}
+
+// DeepCopy returns an independent copy of the task specification.
+func (s *TaskSpecification) DeepCopy() *TaskSpecification {
+	if s == nil {
+		return nil
+	}
+	out := *s
+	out.frozen = false
+	if s.Metadata != nil {
+		metadata := *s.Metadata
+		out.Metadata = &metadata
+	}
+	out.SupportedPlatformVersions = copyStringSlice(s.SupportedPlatformVersions)
+	out.Command = copyStringSlice(s.Command)
+	out.Params = copyStringSlice(s.Params)
+	out.Configs = copyInterfaceSlice(s.Configs)
+	out.RunSchedule = copyRunSchedule(s.RunSchedule)
+	out.Tags = copyTagsMap(s.Tags)
+	out.Classification = copyClassification(s.Classification)
+	out.Provenance = copyProvenance(s.Provenance)
+	out.Incremental = copyIncrementalDescribe(s.Incremental)
+	out.Permissions = copyPermissions(s.Permissions)
+	return &out
+}
+
+// DeepCopy returns an independent copy of the query specification.
+func (s *QuerySpecification) DeepCopy() *QuerySpecification {
+	if s == nil {
+		return nil
+	}
+	out := *s
+	out.frozen = false
+	out.IntegrationType = StringOrSlice(copyStringSlice(s.IntegrationType))
+	if s.Metadata != nil {
+		out.Metadata = make(map[string]string, len(s.Metadata))
+		for k, v := range s.Metadata {
+			out.Metadata[k] = v
+		}
+	}
+	if s.Parameters != nil {
+		out.Parameters = make([]QueryParameter, len(s.Parameters))
+		copy(out.Parameters, s.Parameters)
+	}
+	out.Tags = copyTagsMap(s.Tags)
+	out.Classification = copyClassification(s.Classification)
+	out.DetectedParams = copyStringSlice(s.DetectedParams)
+	return &out
+}
+
+// DeepCopy returns an independent copy of the control specification.
+func (s *ControlSpecification) DeepCopy() *ControlSpecification {
+	if s == nil {
+		return nil
+	}
+	out := *s
+	out.frozen = false
+	out.Frameworks = copyStringSlice(s.Frameworks)
+	if s.Parameters != nil {
+		out.Parameters = copyInterfaceMap(s.Parameters)
+	}
+	out.Tags = copyTagsMap(s.Tags)
+	out.Classification = copyClassification(s.Classification)
+	return &out
+}
+
+// --- Copy Helpers ---
+// All helpers preserve nil vs. empty so that validation semantics (e.g., "params: []"
+// being required) survive a copy.
+
+func copyProvenance(in *Provenance) *Provenance {
+	if in == nil {
+		return nil
+	}
+	out := *in
+	return &out
+}
+
+func copyStringSlice(in []string) []string {
+	if in == nil {
+		return nil
+	}
+	out := make([]string, len(in))
+	copy(out, in)
+	return out
+}
+
+func copyTagsMap(in map[string]StringOrSlice) map[string]StringOrSlice {
+	if in == nil {
+		return nil
+	}
+	out := make(map[string]StringOrSlice, len(in))
+	for k, v := range in {
+		if v == nil {
+			out[k] = nil
+			continue
+		}
+		out[k] = StringOrSlice(copyStringSlice(v))
+	}
+	return out
+}
+
+func copyClassification(in [][]string) [][]string {
+	if in == nil {
+		return nil
+	}
+	out := make([][]string, len(in))
+	for i, inner := range in {
+		out[i] = copyStringSlice(inner)
+	}
+	return out
+}
+
+func copyRunSchedule(in []RunScheduleEntry) []RunScheduleEntry {
+	if in == nil {
+		return nil
+	}
+	out := make([]RunScheduleEntry, len(in))
+	for i, entry := range in {
+		out[i] = entry
+		if entry.Params != nil {
+			out[i].Params = copyInterfaceMap(entry.Params)
+		}
+	}
+	return out
+}
+
+func copyInterfaceSlice(in []interface{}) []interface{} {
+	if in == nil {
+		return nil
+	}
+	out := make([]interface{}, len(in))
+	for i, v := range in {
+		out[i] = copyInterfaceValue(v)
+	}
+	return out
+}
+
+func copyInterfaceMap(in map[string]interface{}) map[string]interface{} {
+	out := make(map[string]interface{}, len(in))
+	for k, v := range in {
+		out[k] = copyInterfaceValue(v)
+	}
+	return out
+}
+
+// copyInterfaceValue deep copies the container types produced by decoding YAML/JSON
+// into interface{}. Scalars are immutable and returned as-is.
+func copyInterfaceValue(v interface{}) interface{} {
+	switch val := v.(type) {
+	case map[string]interface{}:
+		return copyInterfaceMap(val)
+	case map[interface{}]interface{}:
+		out := make(map[interface{}]interface{}, len(val))
+		for k, inner := range val {
+			out[k] = copyInterfaceValue(inner)
+		}
+		return out
+	case []interface{}:
+		return copyInterfaceSlice(val)
+	case []string:
+		return copyStringSlice(val)
+	default:
+		return val
+	}
+}
+
+func copyCapabilities(in []Capability) []Capability {
+	if in == nil {
+		return nil
+	}
+	out := make([]Capability, len(in))
+	copy(out, in)
+	return out
+}
+
+func copyIncrementalDescribe(in *IncrementalDescribe) *IncrementalDescribe {
+	if in == nil {
+		return nil
+	}
+	out := *in
+	return &out
+}
+
+func copyPermissions(p *Permissions) *Permissions {
+	if p == nil {
+		return nil
+	}
+	return &Permissions{
+		AWSActions:     copyStringSlice(p.AWSActions),
+		AzureRoles:     copyStringSlice(p.AzureRoles),
+		AzureActions:   copyStringSlice(p.AzureActions),
+		GCPPermissions: copyStringSlice(p.GCPPermissions),
+	}
+}
@@ -0,0 +1,170 @@
+// frequency.go
+package types
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Frequency is a parsed run_schedule frequency: either a fixed interval ("30m", "6h",
+// "1d", "1w") or a cron expression (five fields or a @macro such as "@daily").
+type Frequency struct {
+	Interval time.Duration // Set for interval frequencies
+	Cron     string        // Set for cron frequencies
+}
+
+var frequencyUnits = map[byte]time.Duration{
+	'm': time.Minute,
+	'h': time.Hour,
+	'd': 24 * time.Hour,
+	'w': 7 * 24 * time.Hour,
+}
+
+var cronMacros = map[string]bool{
+	"@yearly": true, "@annually": true, "@monthly": true, "@weekly": true,
+	"@daily": true, "@midnight": true, "@hourly": true,
+}
+
+// cronField is the range and names of one field of a five-field cron expression.
+type cronField struct {
+	name     string
+	min, max int
+	names    []string // Values from min, e.g. "JAN" for 1
+	anyMark  bool     // Accepts "?" like "*"
+}
+
+var cronFields = []cronField{
+	{name: "minute", min: 0, max: 59},
+	{name: "hour", min: 0, max: 23},
+	{name: "day of month", min: 1, max: 31, anyMark: true},
+	{name: "month", min: 1, max: 12, names: []string{"JAN", "FEB", "MAR", "APR", "MAY", "JUN", "JUL", "AUG", "SEP", "OCT", "NOV", "DEC"}},
+	{name: "day of week", min: 0, max: 7, names: []string{"SUN", "MON", "TUE", "WED", "THU", "FRI", "SAT"}, anyMark: true},
+}
+
+// ParseFrequency parses a run_schedule frequency.
+func ParseFrequency(s string) (Frequency, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return Frequency{}, fmt.Errorf("frequency is empty")
+	}
+	if strings.HasPrefix(s, "@") {
+		if !cronMacros[s] {
+			return Frequency{}, fmt.Errorf("unknown frequency macro %q", s)
+		}
+		return Frequency{Cron: s}, nil
+	}
+	if fields := strings.Fields(s); len(fields) > 1 {
+		if len(fields) != 5 {
+			return Frequency{}, fmt.Errorf("cron frequency %q must have 5 fields, got %d", s, len(fields))
+		}
+		for i, field := range fields {
+			if err := cronFields[i].validate(field); err != nil {
+				return Frequency{}, fmt.Errorf("cron frequency %q: %w", s, err)
+			}
+		}
+		return Frequency{Cron: strings.Join(fields, " ")}, nil
+	}
+
+	unit, ok := frequencyUnits[s[len(s)-1]]
+	if !ok {
+		return Frequency{}, fmt.Errorf("invalid frequency %q: expected <n>m, <n>h, <n>d, <n>w or a cron expression", s)
+	}
+	n, err := strconv.Atoi(s[:len(s)-1])
+	if err != nil || n < 1 {
+		return Frequency{}, fmt.Errorf("invalid frequency %q: count must be a positive integer", s)
+	}
+	if int64(n) > math.MaxInt64/int64(unit) {
+		return Frequency{}, fmt.Errorf("invalid frequency %q: interval is too long", s)
+	}
+	return Frequency{Interval: time.Duration(n) * unit}, nil
+}
+
+// validate checks one field of a cron expression: a comma-separated list of "*", a
+// value or a range, each optionally with a "/step".
+func (f cronField) validate(field string) error {
+	for _, item := range strings.Split(field, ",") {
+		rangePart, step, hasStep := strings.Cut(item, "/")
+		if hasStep {
+			if n, err := strconv.Atoi(step); err != nil || n < 1 || n > f.max {
+				return fmt.Errorf("invalid step %q in %s field %q", step, f.name, field)
+			}
+		}
+		if rangePart == "*" || (f.anyMark && rangePart == "?") {
+			continue
+		}
+		low, high, isRange := strings.Cut(rangePart, "-")
+		from, err := f.value(low)
+		if err != nil {
+			return fmt.Errorf("%s field %q: %w", f.name, field, err)
+		}
+		if !isRange {
+			continue
+		}
+		to, err := f.value(high)
+		if err != nil {
+			return fmt.Errorf("%s field %q: %w", f.name, field, err)
+		}
+		if from > to {
+			return fmt.Errorf("%s field %q: range %s is reversed", f.name, field, rangePart)
+		}
+	}
+	return nil
+}
+
+func (f cronField) value(s string) (int, error) {
+	for i, name := range f.names {
+		if strings.EqualFold(s, name) {
+			return f.min + i, nil
+		}
+	}
+	n, err := strconv.Atoi(s)
+	if err != nil {
+		return 0, fmt.Errorf("invalid value %q", s)
+	}
+	if n < f.min || n > f.max {
+		return 0, fmt.Errorf("value %d is out of range %d-%d", n, f.min, f.max)
+	}
+	return n, nil
+}
+
+// CronSchedule returns the frequency as a cron schedule. Intervals must divide evenly
+// into the next larger unit (e.g. 15m, 6h) or be exactly one day or one week; anything
+// else cannot be expressed as a cron schedule without drifting.
+func (f Frequency) CronSchedule() (string, error) {
+	if f.Cron != "" {
+		return f.Cron, nil
+	}
+	d := f.Interval
+	switch {
+	case d <= 0:
+		return "", fmt.Errorf("frequency has no interval")
+	case d%time.Minute != 0:
+		return "", fmt.Errorf("frequency %s is not a whole number of minutes", d)
+	case d < time.Hour:
+		m := int(d / time.Minute)
+		if 60%m != 0 {
+			return "", fmt.Errorf("frequency %s does not divide an hour evenly", d)
+		}
+		if m == 1 {
+			return "* * * * *", nil
+		}
+		return fmt.Sprintf("*/%d * * * *", m), nil
+	case d < 24*time.Hour:
+		if d%time.Hour != 0 || 24%int(d/time.Hour) != 0 {
+			return "", fmt.Errorf("frequency %s does not divide a day evenly", d)
+		}
+		if h := int(d / time.Hour); h > 1 {
+			return fmt.Sprintf("0 */%d * * *", h), nil
+		}
+		return "0 * * * *", nil
+	case d == 24*time.Hour:
+		return "0 0 * * *", nil
+	case d == 7*24*time.Hour:
+		return "0 0 * * 0", nil
+	default:
+		return "", fmt.Errorf("frequency %s cannot be expressed as a cron schedule; use 1d, 1w or a cron expression", d)
+	}
+}
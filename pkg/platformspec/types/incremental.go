@@ -0,0 +1,49 @@
+// incremental.go
+package types
+
+import (
+	"strings"
+	"time"
+)
+
+// Cursor kinds a task can declare in incremental.cursor.
+const (
+	CursorTimestamp = "timestamp" // The cursor is an RFC 3339 timestamp of the last change seen
+	CursorToken     = "token"     // The cursor is an opaque token issued by the provider's change feed
+)
+
+// IncrementalDescribe declares that a discovery task can describe only the changes since a
+// previous run, e.g. from AWS Config or Azure Resource Graph change feeds. The scheduler
+// passes the cursor of the last run as DescribeJob.last_cursor with changes_only set.
+type IncrementalDescribe struct {
+	Cursor      string `yaml:"cursor" json:"cursor"`                                 // One of the Cursor* kinds
+	MinInterval string `yaml:"min_interval,omitempty" json:"min_interval,omitempty"` // e.g. "15m"; minimum time between incremental runs
+}
+
+// MinIntervalDuration returns the parsed min_interval, or 0 if none is set.
+func (i *IncrementalDescribe) MinIntervalDuration() time.Duration {
+	if i == nil || strings.TrimSpace(i.MinInterval) == "" {
+		return 0
+	}
+	frequency, err := ParseFrequency(i.MinInterval)
+	if err != nil {
+		return 0 // Rejected by validation
+	}
+	return frequency.Interval
+}
+
+// UseIncrementalDescribe reports whether the next run of the task should only describe
+// changes since lastCursor. It requires the plugin to declare the incremental capability,
+// the task to declare an incremental section, and a cursor from a previous run.
+func (d *TaskDetails) UseIncrementalDescribe(lastCursor string) bool {
+	return d != nil && d.Incremental != nil && d.SupportsIncrementalDescribe() && lastCursor != ""
+}
+
+// IncrementalDescribeDue reports whether min_interval has passed since the last
+// incremental run, so schedulers do not poll change feeds more often than declared.
+func (d *TaskDetails) IncrementalDescribeDue(lastRun, now time.Time) bool {
+	if d == nil || d.Incremental == nil {
+		return true
+	}
+	return now.Sub(lastRun) >= d.Incremental.MinIntervalDuration()
+}
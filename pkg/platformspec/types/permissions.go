@@ -0,0 +1,130 @@
+// permissions.go
+package types
+
+import (
+	"encoding/json"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// Permissions declares the cloud permissions a plugin or task needs, so that customers
+// can review its access before installing it.
+type Permissions struct {
+	AWSActions     []string `yaml:"aws_actions,omitempty" json:"aws_actions,omitempty"`         // IAM actions, e.g. "ec2:DescribeInstances"
+	AzureRoles     []string `yaml:"azure_roles,omitempty" json:"azure_roles,omitempty"`         // Role names or IDs, e.g. "Reader"
+	AzureActions   []string `yaml:"azure_actions,omitempty" json:"azure_actions,omitempty"`     // e.g. "Microsoft.Compute/virtualMachines/read"
+	GCPPermissions []string `yaml:"gcp_permissions,omitempty" json:"gcp_permissions,omitempty"` // e.g. "compute.instances.list"
+}
+
+// IsEmpty reports whether no permission is declared.
+func (p *Permissions) IsEmpty() bool {
+	return p == nil || (len(p.AWSActions) == 0 && len(p.AzureRoles) == 0 && len(p.AzureActions) == 0 && len(p.GCPPermissions) == 0)
+}
+
+// MergePermissions returns the smallest set of permissions granting everything the
+// given ones grant: entries are deduplicated, entries covered by a wildcard entry (e.g.
+// "ec2:DescribeInstances" by "ec2:Describe*") are dropped, and lists are sorted.
+func MergePermissions(permissions ...*Permissions) Permissions {
+	var merged Permissions
+	for _, p := range permissions {
+		if p == nil {
+			continue
+		}
+		merged.AWSActions = append(merged.AWSActions, p.AWSActions...)
+		merged.AzureRoles = append(merged.AzureRoles, p.AzureRoles...)
+		merged.AzureActions = append(merged.AzureActions, p.AzureActions...)
+		merged.GCPPermissions = append(merged.GCPPermissions, p.GCPPermissions...)
+	}
+	merged.AWSActions = minimizePermissionList(merged.AWSActions)
+	merged.AzureRoles = minimizePermissionList(merged.AzureRoles)
+	merged.AzureActions = minimizePermissionList(merged.AzureActions)
+	merged.GCPPermissions = minimizePermissionList(merged.GCPPermissions)
+	return merged
+}
+
+// minimizePermissionList dedupes entries case-insensitively, as cloud providers compare
+// them, and drops those matched by another entry's wildcards.
+func minimizePermissionList(entries []string) []string {
+	unique := make(map[string]string, len(entries))
+	for _, entry := range entries {
+		key := strings.ToLower(entry)
+		if _, ok := unique[key]; !ok {
+			unique[key] = entry
+		}
+	}
+	var minimal []string
+	for key, entry := range unique {
+		covered := false
+		for other := range unique {
+			if other != key && strings.Contains(other, "*") && wildcardMatch(other, key) {
+				covered = true
+				break
+			}
+		}
+		if !covered {
+			minimal = append(minimal, entry)
+		}
+	}
+	sort.Strings(minimal)
+	return minimal
+}
+
+// wildcardMatch reports whether s matches pattern, where * matches any sequence of
+// characters.
+func wildcardMatch(pattern, s string) bool {
+	parts := strings.Split(pattern, "*")
+	if !strings.HasPrefix(s, parts[0]) {
+		return false
+	}
+	s = s[len(parts[0]):]
+	for i, part := range parts[1:] {
+		if i == len(parts)-2 {
+			return strings.HasSuffix(s, part)
+		}
+		idx := strings.Index(s, part)
+		if idx < 0 {
+			return false
+		}
+		s = s[idx+len(part):]
+	}
+	return true
+}
+
+// AWSPolicyDocument returns an IAM policy document allowing the AWS actions on all
+// resources, e.g. to create the role a plugin runs with.
+func (p Permissions) AWSPolicyDocument() ([]byte, error) {
+	actions := minimizePermissionList(p.AWSActions)
+	if len(actions) == 0 {
+		return nil, fmt.Errorf("no AWS actions declared")
+	}
+	return json.MarshalIndent(map[string]any{
+		"Version": "2012-10-17",
+		"Statement": []map[string]any{{
+			"Effect":   "Allow",
+			"Action":   actions,
+			"Resource": "*",
+		}},
+	}, "", "  ")
+}
+
+// AzureRoleDefinition returns a custom Azure role definition named name allowing the
+// Azure actions, assignable at assignableScopes, e.g. "/subscriptions/<id>". Declared
+// AzureRoles are assigned as they are and are not part of it.
+func (p Permissions) AzureRoleDefinition(name string, assignableScopes []string) ([]byte, error) {
+	actions := minimizePermissionList(p.AzureActions)
+	if len(actions) == 0 {
+		return nil, fmt.Errorf("no Azure actions declared")
+	}
+	if len(assignableScopes) == 0 {
+		return nil, fmt.Errorf("at least one assignable scope is required")
+	}
+	return json.MarshalIndent(map[string]any{
+		"Name":             name,
+		"IsCustom":         true,
+		"Description":      fmt.Sprintf("Permissions required by %s", name),
+		"Actions":          actions,
+		"NotActions":       []string{},
+		"AssignableScopes": assignableScopes,
+	}, "", "  ")
+}
@@ -0,0 +1,11 @@
+// provenance.go
+package types
+
+// Provenance records where a specification came from. All fields are optional; when
+// present they are cross-checked by platformspec.VerifyProvenance.
+type Provenance struct {
+	SubmittedBy      string `yaml:"submitted_by,omitempty" json:"submitted_by,omitempty"`           // Identity of the submitter, e.g. an email
+	SourceRepository string `yaml:"source_repository,omitempty" json:"source_repository,omitempty"` // e.g. https://github.com/org/repo
+	Commit           string `yaml:"commit,omitempty" json:"commit,omitempty"`                       // Full commit SHA
+	Tag              string `yaml:"tag,omitempty" json:"tag,omitempty"`                             // Release tag, e.g. v1.2.3
+}
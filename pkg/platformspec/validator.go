@@ -1,5 +1,11 @@
 // Package platformspec provides utilities for loading, validating, and verifying
 // various specification types (plugin, task, query, control, etc.).
+//
+// The specification structs live in the types subpackage, for consumers that only need
+// to decode them. Building with the platformspec_lite tag leaves out the heaviest
+// dependencies while keeping structural validation: image references are checked for
+// their format but not looked up in registries (ORAS), licenses are checked for their
+// syntax but not against the SPDX list, and the DevServer is not available.
 package platformspec
 
 import (
@@ -9,9 +15,12 @@ import (
 	"net/http" // Needed for init placeholder/actual
 	"os"
 	"regexp" // Needed for init
+	"slices"
 	"strings"
+	"time"
 
 	// Needed for init
+	"github.com/opengovern/og-util/pkg/faultinject"
 	"gopkg.in/yaml.v3"
 	// NOTE: Do not import packages solely used by implementations in other files
 	// e.g., remove "math/rand" if not used directly *in this file*.
@@ -36,11 +45,13 @@ const (
 	ArtifactTypeDiscovery      = "discovery"
 	ArtifactTypePlatformBinary = "platform-binary"
 	ArtifactTypeCloudQLBinary  = "cloudql-binary"
+	ArtifactTypeDocs           = "docs"
 	ArtifactTypeAll            = "all"
 
 	// Output Formats for GetEmbeddedTaskSpecification
 	FormatYAML = "yaml"
 	FormatJSON = "json"
+	FormatTOML = "toml"
 )
 
 // --- Exported Sentinel Error ---
@@ -130,19 +141,142 @@ func (v *defaultValidator) IdentifySpecificationTypes(filePath string) (*Specifi
 // --- Concrete Implementation ---
 
 // defaultValidator implements the Validator interface.
-type defaultValidator struct{}
+type defaultValidator struct {
+	telemetry  TelemetrySink      // Optional; nil disables telemetry (the default)
+	limits     SpecLimits         // Policy limits on spec contents
+	quarantine *QuarantineOptions // Optional; nil disables quarantining of failed validations
+
+	httpClient     *http.Client       // Artifact downloads; the shared package client if nil
+	registryClient registryHTTPClient // Registry lookups; the ORAS default if nil
+	platforms      []string           // Component variants validated, as "os/arch"; all if empty
+	profile        bool               // Log the phase timings of every ProcessSpecification call
+	memoryBudget   MemoryBudget       // Bounds artifacts held in memory
+	archiveSlots   *archiveSlots      // Enforces memoryBudget.MaxConcurrentArchives; unlimited if nil
+
+	stats *validationStats // Set on the per-call copy made by ProcessSpecification; nil records nothing
+}
+
+// ValidatorOptions configures optional behavior of a validator created with NewValidator.
+// The zero value is equivalent to NewDefaultValidator.
+type ValidatorOptions struct {
+	// TelemetrySink, if set, receives an anonymized ValidationEvent after every
+	// ProcessSpecification call. Telemetry is opt-in and disabled when nil.
+	TelemetrySink TelemetrySink
+	// Limits overrides the policy limits on spec contents. DefaultSpecLimits() is used when nil.
+	Limits *SpecLimits
+	// Quarantine, if set with a Store, saves a QuarantineRecord for every specification
+	// that fails ProcessSpecification so rejected submissions can be reviewed later.
+	Quarantine *QuarantineOptions
+	// FaultInjector, if set, injects faults into artifact downloads ("artifact-download")
+	// and registry lookups ("registry-resolve") so callers can test their failure
+	// handling. Create it with faultinject.New; intended for test environments only.
+	FaultInjector *faultinject.Injector
+	// Platforms restricts the component variants whose artifacts are validated to these
+	// platforms, given as "os/arch" or "os" (e.g. "linux/amd64"). All variants are
+	// validated when empty.
+	Platforms []string
+	// Profile logs how long each validation phase (see the Phase* constants) took after
+	// every ProcessSpecification call, to find out why a specification is slow to validate.
+	// The timings are also reported to the TelemetrySink and in quarantine reports.
+	Profile bool
+	// MemoryBudget bounds the artifacts held in memory during validation; artifacts beyond
+	// MaxConcurrentArchives queue instead of being downloaded at once. DefaultMemoryBudget()
+	// is used when nil.
+	MemoryBudget *MemoryBudget
+}
 
 // NewDefaultValidator creates a new instance of the default validator.
 func NewDefaultValidator() Validator {
-	return &defaultValidator{}
+	return &defaultValidator{
+		limits:       DefaultSpecLimits(),
+		memoryBudget: DefaultMemoryBudget(),
+		archiveSlots: newArchiveSlots(DefaultMemoryBudget()),
+	}
+}
+
+// NewValidator creates a validator configured with the given options.
+func NewValidator(options ValidatorOptions) Validator {
+	limits := DefaultSpecLimits()
+	if options.Limits != nil {
+		limits = *options.Limits
+	}
+	var quarantine *QuarantineOptions
+	if options.Quarantine != nil && options.Quarantine.Store != nil {
+		q := *options.Quarantine
+		quarantine = &q
+	}
+	memoryBudget := DefaultMemoryBudget()
+	if options.MemoryBudget != nil {
+		memoryBudget = *options.MemoryBudget
+	}
+	v := &defaultValidator{
+		telemetry:    options.TelemetrySink,
+		limits:       limits,
+		quarantine:   quarantine,
+		platforms:    slices.Clone(options.Platforms),
+		profile:      options.Profile,
+		memoryBudget: memoryBudget,
+		archiveSlots: newArchiveSlots(memoryBudget),
+	}
+	if options.FaultInjector != nil {
+		v.httpClient = options.FaultInjector.Client(httpClient, operationName("artifact-download"))
+		v.registryClient = faultInjectedRegistryClient(options.FaultInjector)
+	}
+	return v
+}
+
+// registryHTTPClient sends registry requests, like the remote.Client of ORAS.
+type registryHTTPClient interface {
+	Do(req *http.Request) (*http.Response, error)
+}
+
+func operationName(name string) faultinject.Classifier {
+	return func(*http.Request) string { return name }
+}
+
+// client returns the HTTP client used for artifact downloads.
+func (v *defaultValidator) client() *http.Client {
+	if v.httpClient != nil {
+		return v.httpClient
+	}
+	return httpClient
 }
 
 // --- Interface Method Implementations (Wrappers) ---
 
 // ProcessSpecification reads, identifies, validates structure, checks platform, and validates artifacts.
-// It dispatches to internal type-specific processor methods (process*Spec).
+// It dispatches to internal type-specific processor methods (process*Spec) and, if a
+// TelemetrySink is configured, emits an anonymized ValidationEvent on completion. Failed
+// specifications are quarantined when quarantining is configured.
 // Assumes isNonEmpty and process*Spec methods are defined elsewhere on *defaultValidator.
 func (v *defaultValidator) ProcessSpecification(data []byte, filePath string, platformVersion string, artifactValidationType string, skipArtifactValidation bool) (interface{}, error) {
+	if v.telemetry == nil && v.quarantine == nil && !v.profile {
+		return v.processSpecification(data, filePath, platformVersion, artifactValidationType, skipArtifactValidation, nil)
+	}
+	stats := newValidationStats()
+	// Phases are timed on a copy so that concurrent calls don't share stats
+	call := *v
+	call.stats = stats
+	start := time.Now()
+	spec, err := call.processSpecification(data, filePath, platformVersion, artifactValidationType, skipArtifactValidation, stats)
+	duration := time.Since(start)
+	if v.profile {
+		log.Printf("Validation of '%s' took %s: %s", filePath, duration.Round(time.Millisecond), formatPhases(stats.phasesCopy()))
+	}
+	emitValidationEvent(v.telemetry, stats, duration, err)
+	if err != nil && v.quarantine != nil {
+		if data == nil {
+			data, _ = os.ReadFile(filePath)
+		}
+		report := newValidationReport(data, filePath, platformVersion, artifactValidationType, skipArtifactValidation, err)
+		report.Timings = phaseMillis(stats.phasesCopy())
+		v.quarantine.quarantine(data, report, stats)
+	}
+	return spec, err
+}
+
+// processSpecification holds the ProcessSpecification logic. stats may be nil.
+func (v *defaultValidator) processSpecification(data []byte, filePath string, platformVersion string, artifactValidationType string, skipArtifactValidation bool, stats *validationStats) (interface{}, error) {
 	var err error
 	if data == nil {
 		data, err = os.ReadFile(filePath)
@@ -150,9 +284,15 @@ func (v *defaultValidator) ProcessSpecification(data []byte, filePath string, pl
 			return nil, fmt.Errorf("failed to read file '%s': %w", filePath, err)
 		}
 	}
+	if err := v.limits.checkSpecSize(len(data), filePath); err != nil {
+		return nil, err
+	}
 
 	var base BaseSpecification
-	if err := yaml.Unmarshal(data, &base); err != nil {
+	endParse := v.stats.startPhase(PhaseParse)
+	err = yaml.Unmarshal(data, &base)
+	endParse()
+	if err != nil {
 		return nil, fmt.Errorf("failed to parse base fields from '%s': %w", filePath, err)
 	}
 
@@ -162,6 +302,7 @@ func (v *defaultValidator) ProcessSpecification(data []byte, filePath string, pl
 		return nil, ErrMissingTypeField
 	}
 	specType := strings.ToLower(base.Type)
+	stats.setSpecType(specType)
 
 	originalAPIVersion := base.APIVersion
 	defaultedAPIVersion := base.APIVersion
@@ -176,7 +317,7 @@ func (v *defaultValidator) ProcessSpecification(data []byte, filePath string, pl
 	// Dispatch to specific processors implemented elsewhere
 	switch specType {
 	case SpecTypePlugin:
-		return v.processPluginSpec(data, filePath, platformVersion, artifactValidationType, skipArtifactValidation)
+		return v.processPluginSpec(data, filePath, platformVersion, artifactValidationType, skipArtifactValidation, stats)
 	case SpecTypeTask:
 		return v.processTaskSpec(data, filePath, skipArtifactValidation, defaultedAPIVersion, originalAPIVersion)
 	case SpecTypeQuery:
@@ -184,7 +325,10 @@ func (v *defaultValidator) ProcessSpecification(data []byte, filePath string, pl
 	case SpecTypeControl:
 		// Example handling for a future type
 		var spec ControlSpecification
-		if err := yaml.Unmarshal(data, &spec); err != nil {
+		endParse := v.stats.startPhase(PhaseParse)
+		err := yaml.Unmarshal(data, &spec)
+		endParse()
+		if err != nil {
 			return nil, fmt.Errorf("failed parse '%s' as control: %w", filePath, err)
 		}
 		if !isNonEmpty(spec.APIVersion) {
@@ -199,6 +343,7 @@ func (v *defaultValidator) ProcessSpecification(data []byte, filePath string, pl
 		}
 		// TODO: Add call to v.validateControlStructure(&spec) when implemented
 		log.Printf("Control specification '%s' validated (Placeholder).", filePath)
+		spec.Freeze()
 		return &spec, nil
 	default:
 		return nil, fmt.Errorf("unknown specification type '%s' in file '%s'", base.Type, filePath)
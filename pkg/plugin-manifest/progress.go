@@ -0,0 +1,118 @@
+package pluginmanifest
+
+import (
+	"sync"
+	"time"
+)
+
+// Progress phases.
+const (
+	PhaseDownload = "download" // Artifact archive download
+	PhaseResolve  = "resolve"  // Image manifest resolution in the registry
+)
+
+// progressInterval throttles download events so callbacks are not invoked per read.
+const progressInterval = 200 * time.Millisecond
+
+// ProgressEvent reports the progress of a download or registry resolution. Unknown values
+// are negative (TotalBytes, Percent) or zero (ETA).
+type ProgressEvent struct {
+	Phase      string
+	URI        string
+	Attempt    int // 1-based; a new attempt restarts BytesRead from zero
+	BytesRead  int64
+	TotalBytes int64         // Content-Length, or -1 if the server did not send one
+	Percent    float64       // 0-100, or -1 if TotalBytes is unknown
+	ETA        time.Duration // Estimated time remaining at the current rate
+	Done       bool          // Last event of the attempt
+	Err        error         // Set on the last event of a failed attempt
+}
+
+// ProgressFunc receives progress events. It may be called concurrently for different
+// components and must not block.
+type ProgressFunc func(ProgressEvent)
+
+// progressTracker turns byte counts of one download attempt into throttled events.
+type progressTracker struct {
+	fn      ProgressFunc
+	uri     string
+	attempt int
+	total   int64
+	start   time.Time
+
+	mu       sync.Mutex
+	lastEmit time.Time
+}
+
+func newProgressTracker(fn ProgressFunc, uri string, attempt int, total int64) *progressTracker {
+	if fn == nil {
+		return nil
+	}
+	now := time.Now()
+	t := &progressTracker{fn: fn, uri: uri, attempt: attempt, total: total, start: now, lastEmit: now}
+	t.emit(0, false, nil)
+	return t
+}
+
+// update reports that read bytes have been read so far.
+func (t *progressTracker) update(read int64) {
+	if t == nil {
+		return
+	}
+	t.mu.Lock()
+	now := time.Now()
+	if now.Sub(t.lastEmit) < progressInterval {
+		t.mu.Unlock()
+		return
+	}
+	t.lastEmit = now
+	t.mu.Unlock()
+	t.emit(read, false, nil)
+}
+
+// finish reports the end of the attempt.
+func (t *progressTracker) finish(read int64, err error) {
+	if t == nil {
+		return
+	}
+	t.emit(read, true, err)
+}
+
+func (t *progressTracker) emit(read int64, done bool, err error) {
+	event := ProgressEvent{
+		Phase:      PhaseDownload,
+		URI:        t.uri,
+		Attempt:    t.attempt,
+		BytesRead:  read,
+		TotalBytes: t.total,
+		Percent:    -1,
+		Done:       done,
+		Err:        err,
+	}
+	if t.total > 0 {
+		event.Percent = float64(read) * 100 / float64(t.total)
+		if elapsed := time.Since(t.start); read > 0 && read < t.total && !done {
+			rate := float64(read) / elapsed.Seconds()
+			event.ETA = time.Duration(float64(t.total-read) / rate * float64(time.Second))
+		}
+	} else if t.total == 0 {
+		event.Percent = 100
+	}
+	t.fn(event)
+}
+
+// reportResolve emits a registry resolution event.
+func (v *defaultValidator) reportResolve(uri string, attempt int, done bool, err error) {
+	if v.progress == nil {
+		return
+	}
+	v.progress(ProgressEvent{
+		Phase:      PhaseResolve,
+		URI:        uri,
+		Attempt:    attempt,
+		TotalBytes: -1,
+		Percent:    -1,
+		Done:       done,
+		Err:        err,
+	})
+}
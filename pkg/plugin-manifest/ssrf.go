@@ -0,0 +1,218 @@
+package pluginmanifest
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"sync"
+	"time"
+
+	"oras.land/oras-go/v2/registry/remote/auth"
+	"oras.land/oras-go/v2/registry/remote/retry"
+)
+
+// ErrAddressDenied is wrapped by errors for downloads whose host resolves to a denied
+// address. Such errors are never retried.
+var ErrAddressDenied = errors.New("address denied by ssrf policy")
+
+// alwaysDenied are never valid targets for an artifact download.
+var alwaysDenied = mustPrefixes(
+	"0.0.0.0/8",      // "This" network
+	"127.0.0.0/8",    // Loopback
+	"169.254.0.0/16", // Link-local, including cloud metadata endpoints
+	"224.0.0.0/4",    // Multicast
+	"240.0.0.0/4",    // Reserved and broadcast
+	"::/128",         // Unspecified
+	"::1/128",        // Loopback
+	"fe80::/10",      // Link-local
+	"ff00::/8",       // Multicast
+)
+
+// privateRanges are denied when SSRFPolicy.DenyPrivate is set.
+var privateRanges = mustPrefixes(
+	"10.0.0.0/8",
+	"172.16.0.0/12",
+	"192.168.0.0/16",
+	"100.64.0.0/10", // Carrier-grade NAT
+	"fc00::/7",      // Unique local
+)
+
+// SSRFPolicy protects services that validate untrusted manifests from being used to
+// reach internal addresses. Each component host is resolved once; if any of its
+// addresses is denied the download fails, otherwise every connection to that host,
+// including retries and redirects, is pinned to the resolved addresses so a later DNS
+// answer cannot rebind it. Loopback, link-local, multicast and unspecified addresses
+// are always denied. NAT64 and 6to4 addresses are checked against the IPv4 address
+// they embed.
+type SSRFPolicy struct {
+	// DenyPrivate also denies RFC 1918, carrier-grade NAT and IPv6 unique local ranges.
+	DenyPrivate bool
+	// DenyRanges lists additional ranges to deny, e.g. the cluster's service network.
+	DenyRanges []netip.Prefix
+	// Resolver resolves hostnames; net.DefaultResolver if nil.
+	Resolver *net.Resolver
+}
+
+// pinningDialer resolves each host once and only dials the pinned, allowed addresses.
+type pinningDialer struct {
+	deny     []netip.Prefix
+	resolver *net.Resolver
+	dialer   *net.Dialer
+
+	mu     sync.Mutex
+	pinned map[string][]netip.Addr
+}
+
+func newPinningDialer(policy SSRFPolicy) *pinningDialer {
+	deny := append([]netip.Prefix(nil), alwaysDenied...)
+	if policy.DenyPrivate {
+		deny = append(deny, privateRanges...)
+	}
+	for _, prefix := range policy.DenyRanges {
+		deny = append(deny, prefix.Masked())
+	}
+	resolver := policy.Resolver
+	if resolver == nil {
+		resolver = net.DefaultResolver
+	}
+	return &pinningDialer{
+		deny:     deny,
+		resolver: resolver,
+		dialer:   &net.Dialer{Timeout: ConnectTimeout, KeepAlive: 30 * time.Second},
+		pinned:   make(map[string][]netip.Addr),
+	}
+}
+
+// DialContext implements http.Transport.DialContext.
+func (d *pinningDialer) DialContext(ctx context.Context, network, address string) (net.Conn, error) {
+	host, port, err := net.SplitHostPort(address)
+	if err != nil {
+		return nil, err
+	}
+	addrs, err := d.resolve(ctx, host)
+	if err != nil {
+		return nil, err
+	}
+	var lastErr error
+	for _, addr := range addrs {
+		conn, err := d.dialer.DialContext(ctx, network, net.JoinHostPort(addr.String(), port))
+		if err == nil {
+			return conn, nil
+		}
+		lastErr = err
+	}
+	return nil, lastErr
+}
+
+// resolve returns the pinned addresses of host, resolving and checking them on first use.
+func (d *pinningDialer) resolve(ctx context.Context, host string) ([]netip.Addr, error) {
+	d.mu.Lock()
+	addrs, ok := d.pinned[host]
+	d.mu.Unlock()
+	if ok {
+		return addrs, nil
+	}
+
+	if addr, err := netip.ParseAddr(host); err == nil {
+		addrs = []netip.Addr{addr}
+	} else {
+		ips, err := d.resolver.LookupNetIP(ctx, "ip", host)
+		if err != nil {
+			return nil, fmt.Errorf("resolve '%s': %w", host, err)
+		}
+		if len(ips) == 0 {
+			return nil, fmt.Errorf("resolve '%s': no addresses", host)
+		}
+		addrs = ips
+	}
+	for i, addr := range addrs {
+		addr = addr.Unmap()
+		addrs[i] = addr
+		if prefix, ok := d.denied(addr); ok {
+			return nil, fmt.Errorf("%w: host '%s' resolves to %s (in %s)", ErrAddressDenied, host, addr, prefix)
+		}
+		if v4, ok := embeddedIPv4(addr); ok {
+			if prefix, ok := d.denied(v4); ok {
+				return nil, fmt.Errorf("%w: host '%s' resolves to %s, embedding %s (in %s)", ErrAddressDenied, host, addr, v4, prefix)
+			}
+		}
+	}
+
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if pinned, ok := d.pinned[host]; ok {
+		return pinned, nil
+	}
+	d.pinned[host] = addrs
+	return addrs, nil
+}
+
+// denied returns the deny range containing addr, if any.
+func (d *pinningDialer) denied(addr netip.Addr) (netip.Prefix, bool) {
+	for _, prefix := range d.deny {
+		if prefix.Contains(addr) {
+			return prefix, true
+		}
+	}
+	return netip.Prefix{}, false
+}
+
+var (
+	nat64Prefix = netip.MustParsePrefix("64:ff9b::/96") // RFC 6052, IPv4 in the last 32 bits
+	sixToFour   = netip.MustParsePrefix("2002::/16")    // RFC 3056, IPv4 in bits 16 to 47
+)
+
+// embeddedIPv4 returns the IPv4 address a NAT64 or 6to4 address reaches, which must be
+// checked like the IPv4 address itself.
+func embeddedIPv4(addr netip.Addr) (netip.Addr, bool) {
+	b := addr.As16()
+	switch {
+	case nat64Prefix.Contains(addr):
+		return netip.AddrFrom4([4]byte{b[12], b[13], b[14], b[15]}), true
+	case sixToFour.Contains(addr):
+		return netip.AddrFrom4([4]byte{b[2], b[3], b[4], b[5]}), true
+	}
+	return netip.Addr{}, false
+}
+
+// newDownloadClient builds the download client for a validator with policies set, sharing
+// the shared client's settings.
+func newDownloadClient(urlPolicy *URLPolicy, ssrfPolicy *SSRFPolicy) *http.Client {
+	client := *httpClient
+	if urlPolicy != nil {
+		client.CheckRedirect = urlPolicy.checkRedirect
+	}
+	if ssrfPolicy != nil {
+		dialer := newPinningDialer(*ssrfPolicy)
+		transport := httpClient.Transport.(*http.Transport).Clone()
+		transport.Proxy = nil // A proxy would resolve hosts itself, bypassing the checks
+		transport.DialContext = dialer.DialContext
+		client.Transport = transport
+	}
+	return &client
+}
+
+// newRegistryClient builds the client resolving image manifests for a validator with an
+// SSRF policy: oras-go's default auth client with its connections pinned like downloads.
+func newRegistryClient(ssrfPolicy SSRFPolicy) *auth.Client {
+	dialer := newPinningDialer(ssrfPolicy)
+	transport := httpClient.Transport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &auth.Client{
+		Client: &http.Client{Transport: retry.NewTransport(transport)},
+		Header: auth.DefaultClient.Header.Clone(),
+		Cache:  auth.NewCache(),
+	}
+}
+
+func mustPrefixes(cidrs ...string) []netip.Prefix {
+	prefixes := make([]netip.Prefix, len(cidrs))
+	for i, cidr := range cidrs {
+		prefixes[i] = netip.MustParsePrefix(cidr)
+	}
+	return prefixes
+}
@@ -0,0 +1,40 @@
+package pluginmanifest_test
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	pluginmanifest "github.com/opengovern/og-util/pkg/plugin-manifest"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSSRFPolicyRefusesDeniedRegistry(t *testing.T) {
+	var requests atomic.Int32
+	registry := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusNotFound)
+	}))
+	defer registry.Close()
+
+	digest := "@sha256:" + strings.Repeat("a", 64)
+	validator := pluginmanifest.NewValidator(pluginmanifest.ValidatorOptions{SSRFPolicy: &pluginmanifest.SSRFPolicy{}})
+	for _, image := range []string{
+		"169.254.169.254/latest/meta-data" + digest,                 // Cloud metadata endpoint
+		strings.TrimPrefix(registry.URL, "http://") + "/x" + digest, // Loopback
+		"[64:ff9b::a9fe:a9fe]/x" + digest,                           // NAT64 of the metadata endpoint
+		"[2002:7f00:1::1]/x" + digest,                               // 6to4 of loopback
+	} {
+		manifest := &pluginmanifest.PluginManifest{}
+		manifest.Plugin.Components.Discovery.ImageURI = image
+
+		start := time.Now()
+		err := validator.ValidateArtifact(manifest, pluginmanifest.ArtifactTypeDiscovery)
+		require.ErrorContains(t, err, pluginmanifest.ErrAddressDenied.Error(), image)
+		require.Less(t, time.Since(start), pluginmanifest.InitialBackoffDuration, "denied addresses must not be retried")
+	}
+	require.Zero(t, requests.Load())
+}
@@ -0,0 +1,118 @@
+package pluginmanifest
+
+import (
+	"archive/tar"
+	"compress/bzip2"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"log"
+	"path/filepath"
+	"strings"
+)
+
+// archiveTypeFor returns the archive type of archiveURI based on its extension.
+func archiveTypeFor(archiveURI string) (string, error) {
+	switch {
+	case strings.HasSuffix(archiveURI, ".tar.gz") || strings.HasSuffix(archiveURI, ".tgz"):
+		return "tar.gz", nil
+	case strings.HasSuffix(archiveURI, ".tar.bz2") || strings.HasSuffix(archiveURI, ".tbz2"):
+		return "tar.bz2", nil
+	case strings.ToLower(filepath.Ext(archiveURI)) == ".zip":
+		return "zip", nil
+	default:
+		return "", fmt.Errorf("unsupported archive extension for URI '%s'. Supported: .zip, .tar.gz, .tgz, .tar.bz2, .tbz2", archiveURI)
+	}
+}
+
+// streamsArchive reports whether uri is validated in streaming mode: only tar archives
+// can be read sequentially, zip needs the central directory at the end of the file.
+func (v *defaultValidator) streamsArchive(uri string) bool {
+	if !v.streaming {
+		return false
+	}
+	archiveType, err := archiveTypeFor(uri)
+	return err == nil && archiveType != "zip"
+}
+
+// streamComponent downloads a tar archive component once, checking its paths and its
+// checksum in the same pass without buffering the archive.
+func (v *defaultValidator) streamComponent(component Component, componentName string, extraPaths []string) error {
+	archiveType, err := archiveTypeFor(component.URI)
+	if err != nil {
+		return fmt.Errorf("%s validation failed: %w", componentName, err)
+	}
+	var paths []string
+	for _, p := range append([]string{component.PathInArchive}, extraPaths...) {
+		if isNonEmpty(p) {
+			paths = append(paths, p)
+		}
+	}
+
+	digest, size, err := v.fetchWithRetry(component.URI, func(body io.Reader, _ int64) error {
+		if len(paths) == 0 {
+			return nil
+		}
+		return scanTarPaths(body, archiveType, paths)
+	})
+	if err != nil {
+		return fmt.Errorf("%s validation failed: streaming check failed for URI %s: %w", componentName, component.URI, err)
+	}
+	if size == 0 {
+		return fmt.Errorf("%s validation failed: downloaded file from %s is empty", componentName, component.URI)
+	}
+	if err := v.verifyChecksum(digest, component.Checksum); err != nil {
+		return fmt.Errorf("%s validation failed: checksum error for URI %s: %w", componentName, component.URI, err)
+	}
+	log.Printf("Component %s streamed and verified (%d bytes, paths %v).", componentName, size, paths)
+	return nil
+}
+
+// scanTarPaths reads a compressed tar stream and checks that every path exists as a
+// readable regular file. It stops reading once all paths are found.
+func scanTarPaths(r io.Reader, archiveType string, paths []string) error {
+	var tarReader *tar.Reader
+	switch archiveType {
+	case "tar.gz":
+		gzipReader, err := gzip.NewReader(r)
+		if err != nil {
+			return fmt.Errorf("gzip reader failed: %w", err)
+		}
+		defer gzipReader.Close()
+		tarReader = tar.NewReader(gzipReader)
+	case "tar.bz2":
+		tarReader = tar.NewReader(bzip2.NewReader(r))
+	default:
+		return fmt.Errorf("archive type '%s' cannot be streamed", archiveType)
+	}
+
+	missing := make(map[string]bool, len(paths))
+	for _, p := range paths {
+		missing[p] = true
+	}
+	for len(missing) > 0 {
+		header, err := tarReader.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return fmt.Errorf("read tar header failed: %w", err)
+		}
+		if !missing[header.Name] {
+			continue
+		}
+		if header.Typeflag != tar.TypeReg && header.Typeflag != tar.TypeRegA && header.Typeflag != 0 {
+			return fmt.Errorf("tar path '%s' not regular file (typeflag %v)", header.Name, header.Typeflag)
+		}
+		if _, err := io.Copy(io.Discard, tarReader); err != nil {
+			return fmt.Errorf("tar path '%s' read failed (corrupt?): %w", header.Name, err)
+		}
+		delete(missing, header.Name)
+	}
+	for _, p := range paths {
+		if missing[p] {
+			return fmt.Errorf("path '%s' not found in %s archive", p, archiveType)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package pluginmanifest
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+// DefaultMaxRedirects is the redirect limit of a URLPolicy that does not set one.
+const DefaultMaxRedirects = 5
+
+// ErrURLPolicyViolation is wrapped by every error caused by a URLPolicy. Such errors are
+// never retried.
+var ErrURLPolicyViolation = errors.New("url policy violation")
+
+// URLPolicy restricts where downloadable components may be fetched from.
+type URLPolicy struct {
+	// AllowInsecureHTTP permits http:// URIs and redirects. Only https:// is allowed
+	// otherwise; other schemes such as file:// are always rejected.
+	AllowInsecureHTTP bool
+	// MaxRedirects caps the redirect chain. 0 uses DefaultMaxRedirects; a negative value
+	// disallows redirects.
+	MaxRedirects int
+	// AllowedRedirectHosts lists hosts redirects may lead to besides the host of the
+	// component URI, e.g. a release CDN. Entries match exactly or, when written as
+	// "*.example.com", any subdomain.
+	AllowedRedirectHosts []string
+}
+
+// checkURL validates the scheme of a component URI or redirect target.
+func (p *URLPolicy) checkURL(u *url.URL) error {
+	switch strings.ToLower(u.Scheme) {
+	case "https":
+	case "http":
+		if !p.AllowInsecureHTTP {
+			return fmt.Errorf("%w: scheme http is not allowed for '%s' (https required)", ErrURLPolicyViolation, u.Redacted())
+		}
+	default:
+		return fmt.Errorf("%w: scheme '%s' is not allowed for '%s'", ErrURLPolicyViolation, u.Scheme, u.Redacted())
+	}
+	if u.Hostname() == "" {
+		return fmt.Errorf("%w: '%s' has no host", ErrURLPolicyViolation, u.Redacted())
+	}
+	return nil
+}
+
+// checkRedirect implements http.Client.CheckRedirect.
+func (p *URLPolicy) checkRedirect(req *http.Request, via []*http.Request) error {
+	maxRedirects := p.MaxRedirects
+	if maxRedirects == 0 {
+		maxRedirects = DefaultMaxRedirects
+	}
+	if maxRedirects < 0 {
+		return fmt.Errorf("%w: redirects are not allowed (to '%s')", ErrURLPolicyViolation, req.URL.Redacted())
+	}
+	if len(via) > maxRedirects {
+		return fmt.Errorf("%w: more than %d redirects", ErrURLPolicyViolation, maxRedirects)
+	}
+	if err := p.checkURL(req.URL); err != nil {
+		return err
+	}
+	origin := via[0].URL.Hostname()
+	if host := req.URL.Hostname(); !strings.EqualFold(host, origin) && !p.redirectHostAllowed(host) {
+		return fmt.Errorf("%w: redirect from host '%s' to '%s' is not allowed", ErrURLPolicyViolation, origin, host)
+	}
+	return nil
+}
+
+func (p *URLPolicy) redirectHostAllowed(host string) bool {
+	host = strings.ToLower(host)
+	for _, allowed := range p.AllowedRedirectHosts {
+		allowed = strings.ToLower(strings.TrimSpace(allowed))
+		if suffix, ok := strings.CutPrefix(allowed, "*."); ok {
+			if strings.HasSuffix(host, "."+suffix) {
+				return true
+			}
+		} else if host == allowed {
+			return true
+		}
+	}
+	return false
+}
+
+// checkComponentURL applies the validator's URL policy, if any, to a component URI.
+func (v *defaultValidator) checkComponentURL(rawURL string) error {
+	if v.urlPolicy == nil {
+		return nil
+	}
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("%w: invalid URI '%s': %v", ErrURLPolicyViolation, rawURL, err)
+	}
+	return v.urlPolicy.checkURL(u)
+}
+
+// client returns the HTTP client for artifact downloads.
+func (v *defaultValidator) client() *http.Client {
+	if v.httpClient != nil {
+		return v.httpClient
+	}
+	return httpClient
+}
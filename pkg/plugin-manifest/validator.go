@@ -20,7 +20,6 @@ import (
 	"net"
 	"net/http"
 	"os"
-	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
@@ -140,7 +139,7 @@ const (
 
 // --- Global HTTP Client ---
 // This client is primarily used for artifact downloads.
-// Registry operations will use the oras-go default client unless auth is needed.
+// Registry operations use the oras-go default client unless an SSRF policy is set.
 var httpClient *http.Client
 
 // --- Regular Expression for Image Digest ---
@@ -182,13 +181,57 @@ type PluginValidator interface {
 // --- Concrete Implementation ---
 
 // defaultValidator implements the PluginValidator interface.
-type defaultValidator struct{}
+type defaultValidator struct {
+	streaming  bool         // Validate tar archives while downloading instead of buffering them
+	progress   ProgressFunc // Optional; receives download and resolution progress
+	urlPolicy  *URLPolicy   // Optional; restricts component URIs and redirects
+	httpClient *http.Client // Download client enforcing the policies; the shared httpClient if nil
+	// registryClient resolves image manifests under the SSRF policy; oras-go's default
+	// client if nil.
+	registryClient remote.Client
+}
+
+// ValidatorOptions configures optional behavior of a validator created with NewValidator.
+// The zero value is equivalent to NewDefaultValidator.
+type ValidatorOptions struct {
+	// Streaming verifies the checksum and archive paths of tar archives in a single pass
+	// while they download, without holding the archive in memory. Zip archives need
+	// random access and are still buffered.
+	Streaming bool
+	// Progress, if set, receives progress events for artifact downloads and image
+	// manifest resolution, e.g. to drive a progress bar.
+	Progress ProgressFunc
+	// URLPolicy, if set, restricts component URI schemes and the redirects followed
+	// while downloading them.
+	URLPolicy *URLPolicy
+	// SSRFPolicy, if set, rejects component and image registry hosts that resolve to
+	// internal addresses and pins downloads and manifest lookups to the addresses
+	// resolved first.
+	SSRFPolicy *SSRFPolicy
+}
 
 // NewDefaultValidator creates a new instance of the default validator.
 func NewDefaultValidator() PluginValidator {
 	return &defaultValidator{}
 }
 
+// NewValidator creates a validator configured with the given options.
+func NewValidator(options ValidatorOptions) PluginValidator {
+	v := &defaultValidator{streaming: options.Streaming, progress: options.Progress}
+	if options.URLPolicy != nil {
+		policy := *options.URLPolicy
+		policy.AllowedRedirectHosts = append([]string(nil), options.URLPolicy.AllowedRedirectHosts...)
+		v.urlPolicy = &policy
+	}
+	if v.urlPolicy != nil || options.SSRFPolicy != nil {
+		v.httpClient = newDownloadClient(v.urlPolicy, options.SSRFPolicy)
+	}
+	if options.SSRFPolicy != nil {
+		v.registryClient = newRegistryClient(*options.SSRFPolicy)
+	}
+	return v
+}
+
 // --- Helper Function ---
 func isNonEmpty(s string) bool {
 	return strings.TrimSpace(s) != ""
@@ -269,6 +312,12 @@ func (v *defaultValidator) ValidateManifestStructure(manifest *PluginManifest) e
 	if !isNonEmpty(cloudqlComp.URI) {
 		return fmt.Errorf("plugin.components.cloudql_binary.uri is required")
 	}
+	if err := v.checkComponentURL(platformComp.URI); err != nil {
+		return fmt.Errorf("plugin.components.platform_binary.uri: %w", err)
+	}
+	if err := v.checkComponentURL(cloudqlComp.URI); err != nil {
+		return fmt.Errorf("plugin.components.cloudql_binary.uri: %w", err)
+	}
 	if platformComp.URI == cloudqlComp.URI {
 		if !isNonEmpty(platformComp.PathInArchive) {
 			return fmt.Errorf("plugin.components.platform_binary.path_in_archive required when URIs match ('%s')", platformComp.URI)
@@ -352,6 +401,14 @@ func (v *defaultValidator) ValidateArtifact(manifest *PluginManifest, artifactTy
 	var platformData []byte
 	platformComp := manifest.Plugin.Components.PlatformBinary
 	cloudqlComp := manifest.Plugin.Components.CloudQLBinary
+	sharedURI := validateCloudQL && platformComp.URI == cloudqlComp.URI
+	// A streamed shared archive is not kept in memory, so the cloudql path is checked
+	// in the same pass as the platform path.
+	var sharedPaths []string
+	sharedStreamed := sharedURI && validatePlatform && v.streamsArchive(platformComp.URI)
+	if sharedStreamed {
+		sharedPaths = append(sharedPaths, cloudqlComp.PathInArchive)
+	}
 
 	if validateDiscovery {
 		log.Println("Initiating Discovery image validation...")
@@ -368,13 +425,13 @@ func (v *defaultValidator) ValidateArtifact(manifest *PluginManifest, artifactTy
 		go func() {
 			defer wg.Done()
 			log.Println("Initiating PlatformBinary artifact validation...")
-			platformData, platformErr = v.validateSingleDownloadableComponent(platformComp, ArtifactTypePlatformBinary)
+			platformData, platformErr = v.validateSingleDownloadableComponent(platformComp, ArtifactTypePlatformBinary, sharedPaths...)
 			if platformErr == nil {
 				log.Println("PlatformBinary artifact validation successful.")
 			}
 		}()
 	}
-	if validateCloudQL && platformComp.URI != cloudqlComp.URI {
+	if validateCloudQL && !sharedURI {
 		wg.Add(1)
 		go func() {
 			defer wg.Done()
@@ -387,10 +444,12 @@ func (v *defaultValidator) ValidateArtifact(manifest *PluginManifest, artifactTy
 	}
 	wg.Wait() // Wait for downloads
 
-	if validateCloudQL && platformComp.URI == cloudqlComp.URI {
+	if sharedURI {
 		log.Println("Initiating CloudQLBinary artifact validation (shared URI)...")
 		if platformErr != nil {
 			cloudqlErr = fmt.Errorf("cannot validate cloudql-binary path in shared archive because platform-binary validation failed: %w", platformErr)
+		} else if sharedStreamed {
+			log.Println("CloudQLBinary artifact validation successful (shared URI path checked while streaming).")
 		} else if platformData == nil {
 			cloudqlErr = fmt.Errorf("internal logic error: platform data not available for shared URI validation")
 		} else {
@@ -446,6 +505,7 @@ func (v *defaultValidator) validateImageManifestExists(imageURI string) error {
 			backoff *= 2
 		}
 		log.Printf("Image resolve attempt %d/%d for %s...", attempt+1, MaxRegistryRetries+1, imageURI)
+		v.reportResolve(imageURI, attempt+1, false, nil)
 		ctx, cancel := context.WithTimeout(context.Background(), OverallRequestTimeout)
 		defer cancel() // Ensure cancel is called
 
@@ -460,9 +520,11 @@ func (v *defaultValidator) validateImageManifestExists(imageURI string) error {
 			continue
 		}
 
-		// *** REMOVED repo.Client = httpClient ***
-		// Let oras-go use its default client which handles anonymous auth correctly
-		// If authentication is needed later, repo.Client can be set to an auth.Client
+		// Without an SSRF policy, let oras-go use its default client, which handles
+		// anonymous auth correctly
+		if v.registryClient != nil {
+			repo.Client = v.registryClient
+		}
 		log.Printf("[DEBUG] Attempting to resolve manifest using ORAS default client for host: %s, repository: %s", repo.Reference.Registry, repo.Reference.Repository)
 
 		// Resolve attempts to fetch manifest metadata (HEAD or GET) using the digest
@@ -470,13 +532,18 @@ func (v *defaultValidator) validateImageManifestExists(imageURI string) error {
 
 		if err == nil {
 			log.Printf("Successfully resolved image manifest for %s.", imageURI)
+			v.reportResolve(imageURI, attempt+1, true, nil)
 			return nil
 		} // Success
 
 		lastErr = fmt.Errorf("attempt %d: failed resolve image manifest for '%s': %w", attempt+1, imageURI, err)
+		v.reportResolve(imageURI, attempt+1, true, lastErr)
 		log.Printf("Error details: %v", err)
 
 		// Check for specific error types that shouldn't be retried
+		if errors.Is(err, ErrAddressDenied) {
+			return lastErr
+		}
 		var errResp *errcode.ErrorResponse // Use the correct error type from errcode package
 		if errors.As(err, &errResp) {
 			// Treat 4xx client errors (like 404 Not Found, 401/403 Unauthorized) as non-retriable
@@ -495,19 +562,24 @@ func (v *defaultValidator) validateImageManifestExists(imageURI string) error {
 }
 
 // validateSingleDownloadableComponent downloads and validates a specific downloadable binary component.
-func (v *defaultValidator) validateSingleDownloadableComponent(component Component, componentName string) ([]byte, error) {
+// extraPaths are additional paths that must exist in the archive; they are only checked in
+// streaming mode, where the returned data is nil because the archive is not buffered.
+func (v *defaultValidator) validateSingleDownloadableComponent(component Component, componentName string, extraPaths ...string) ([]byte, error) {
 	log.Printf("--- Validating Downloadable Component: %s ---", componentName)
 	if !isNonEmpty(component.URI) {
 		return nil, fmt.Errorf("%s validation failed: URI is missing", componentName)
 	}
-	downloadedData, err := v.downloadWithRetry(component.URI)
+	if v.streamsArchive(component.URI) {
+		return nil, v.streamComponent(component, componentName, extraPaths)
+	}
+	downloadedData, digest, err := v.downloadWithRetry(component.URI)
 	if err != nil {
 		return nil, fmt.Errorf("%s download failed: %w", componentName, err)
 	}
 	if len(downloadedData) == 0 {
 		return nil, fmt.Errorf("%s validation failed: downloaded file from %s is empty", componentName, component.URI)
 	}
-	err = v.verifyChecksum(downloadedData, component.Checksum)
+	err = v.verifyChecksum(digest, component.Checksum)
 	if err != nil {
 		return nil, fmt.Errorf("%s validation failed: checksum error for URI %s: %w", componentName, component.URI, err)
 	}
@@ -522,9 +594,34 @@ func (v *defaultValidator) validateSingleDownloadableComponent(component Compone
 	return downloadedData, nil
 }
 
-// downloadWithRetry attempts to download a file from a URL with exponential backoff and checks.
-// Uses the globally configured httpClient.
-func (v *defaultValidator) downloadWithRetry(url string) ([]byte, error) {
+// downloadWithRetry downloads a file into memory, returning its contents and the hex sha256
+// digest computed while it was read.
+func (v *defaultValidator) downloadWithRetry(url string) ([]byte, string, error) {
+	var buf bytes.Buffer
+	digest, _, err := v.fetchWithRetry(url, func(body io.Reader, expectedSize int64) error {
+		buf.Reset()
+		if expectedSize > 0 {
+			buf.Grow(int(expectedSize))
+		}
+		_, err := buf.ReadFrom(body)
+		return err
+	})
+	if err != nil {
+		return nil, "", err
+	}
+	return buf.Bytes(), digest, nil
+}
+
+// fetchWithRetry downloads url with exponential backoff, passing the body to consume as it
+// streams. The sha256 of the body is computed on the fly and the body is drained after
+// consume returns, so the returned digest and size always cover the whole file. consume
+// starts from scratch on every attempt; errors it returns that are not caused by reading
+// the body are not retried.
+// Uses the globally configured httpClient unless a URL or SSRF policy is set.
+func (v *defaultValidator) fetchWithRetry(url string, consume func(body io.Reader, expectedSize int64) error) (string, int64, error) {
+	if err := v.checkComponentURL(url); err != nil {
+		return "", 0, err
+	}
 	var lastErr error
 	backoff := InitialBackoffDuration
 	for attempt := 0; attempt <= MaxDownloadRetries; attempt++ {
@@ -543,9 +640,12 @@ func (v *defaultValidator) downloadWithRetry(url string) ([]byte, error) {
 			lastErr = fmt.Errorf("attempt %d: failed create request: %w", attempt+1, err)
 			continue
 		}
-		resp, err := httpClient.Do(req)
+		resp, err := v.client().Do(req)
 		if err != nil {
 			lastErr = fmt.Errorf("attempt %d: request failed: %w", attempt+1, err)
+			if errors.Is(err, ErrURLPolicyViolation) || errors.Is(err, ErrAddressDenied) {
+				return "", 0, lastErr
+			}
 			if errors.Is(err, context.DeadlineExceeded) {
 				log.Printf("Attempt %d: Timeout", attempt+1)
 			}
@@ -556,7 +656,7 @@ func (v *defaultValidator) downloadWithRetry(url string) ([]byte, error) {
 			resp.Body.Close()
 			lastErr = fmt.Errorf("attempt %d: status code %d. Body: %s", attempt+1, resp.StatusCode, string(bodyBytes))
 			if resp.StatusCode >= 400 && resp.StatusCode < 500 {
-				return nil, lastErr
+				return "", 0, lastErr
 			}
 			continue
 		}
@@ -567,7 +667,7 @@ func (v *defaultValidator) downloadWithRetry(url string) ([]byte, error) {
 				expectedSize = parsedSize
 				if expectedSize > MaxDownloadSizeBytes {
 					resp.Body.Close()
-					return nil, fmt.Errorf("attempt %d: content length %d > max %d", attempt+1, expectedSize, MaxDownloadSizeBytes)
+					return "", 0, fmt.Errorf("attempt %d: content length %d > max %d", attempt+1, expectedSize, MaxDownloadSizeBytes)
 				}
 			} else {
 				log.Printf("Attempt %d: Warning - invalid Content-Length '%s'", attempt+1, contentLengthHeader)
@@ -575,32 +675,70 @@ func (v *defaultValidator) downloadWithRetry(url string) ([]byte, error) {
 		} else {
 			log.Printf("Attempt %d: Warning - Content-Length missing", attempt+1)
 		}
-		limitedReader := io.LimitedReader{R: resp.Body, N: MaxDownloadSizeBytes + 1}
-		bodyBytes, err := io.ReadAll(&limitedReader)
+
+		progress := newProgressTracker(v.progress, url, attempt+1, expectedSize)
+		body := &trackingReader{r: &io.LimitedReader{R: resp.Body, N: MaxDownloadSizeBytes + 1}, onRead: progress.update}
+		hasher := sha256.New()
+		tee := io.TeeReader(body, hasher)
+		consumeErr := consume(tee, expectedSize)
+		if consumeErr == nil {
+			_, consumeErr = io.Copy(io.Discard, tee)
+		}
 		closeErr := resp.Body.Close()
-		if err != nil {
-			lastErr = fmt.Errorf("attempt %d: read body failed: %w", attempt+1, err)
+		if body.err != nil {
+			lastErr = fmt.Errorf("attempt %d: read body failed: %w", attempt+1, body.err)
+			progress.finish(body.n, lastErr)
 			continue
 		}
 		if closeErr != nil {
 			log.Printf("Warning: error closing response body for %s: %v", url, closeErr)
 		}
-		if limitedReader.N == 0 {
-			return nil, fmt.Errorf("attempt %d: file > max %d bytes", attempt+1, MaxDownloadSizeBytes)
+		if body.n > MaxDownloadSizeBytes {
+			err := fmt.Errorf("attempt %d: file > max %d bytes", attempt+1, MaxDownloadSizeBytes)
+			progress.finish(body.n, err)
+			return "", 0, err
 		}
-		actualSize := int64(len(bodyBytes))
-		if expectedSize != -1 && actualSize != expectedSize {
-			lastErr = fmt.Errorf("attempt %d: size %d != Content-Length %d", attempt+1, actualSize, expectedSize)
+		if consumeErr != nil {
+			err := fmt.Errorf("attempt %d: %w", attempt+1, consumeErr)
+			progress.finish(body.n, err)
+			return "", 0, err
+		}
+		if expectedSize != -1 && body.n != expectedSize {
+			lastErr = fmt.Errorf("attempt %d: size %d != Content-Length %d", attempt+1, body.n, expectedSize)
+			progress.finish(body.n, lastErr)
 			continue
 		}
-		log.Printf("Download successful for %s (%d bytes)", url, actualSize)
-		return bodyBytes, nil
+		log.Printf("Download successful for %s (%d bytes)", url, body.n)
+		progress.finish(body.n, nil)
+		return hex.EncodeToString(hasher.Sum(nil)), body.n, nil
 	}
-	return nil, fmt.Errorf("download failed after %d attempts: %w", MaxDownloadRetries+1, lastErr)
+	return "", 0, fmt.Errorf("download failed after %d attempts: %w", MaxDownloadRetries+1, lastErr)
 }
 
-// verifyChecksum compares the SHA256 hash of data against an expected checksum string.
-func (v *defaultValidator) verifyChecksum(data []byte, expectedChecksum string) error {
+// trackingReader counts the bytes read from r and records the first read error other than
+// io.EOF, so failures of the network stream can be told apart from errors of its consumer.
+type trackingReader struct {
+	r      io.Reader
+	n      int64
+	err    error
+	onRead func(total int64) // Optional; called with the running total after each read
+}
+
+func (t *trackingReader) Read(p []byte) (int, error) {
+	n, err := t.r.Read(p)
+	t.n += int64(n)
+	if t.onRead != nil && n > 0 {
+		t.onRead(t.n)
+	}
+	if err != nil && err != io.EOF && t.err == nil {
+		t.err = err
+	}
+	return n, err
+}
+
+// verifyChecksum compares the hex SHA256 digest computed during download against an
+// expected checksum string.
+func (v *defaultValidator) verifyChecksum(actualHash string, expectedChecksum string) error {
 	if !isNonEmpty(expectedChecksum) {
 		log.Println("Warning: No checksum provided.")
 		return nil
@@ -613,11 +751,6 @@ func (v *defaultValidator) verifyChecksum(data []byte, expectedChecksum string)
 	if algo != "sha256" {
 		return fmt.Errorf("unsupported checksum algorithm '%s'", algo)
 	}
-	hasher := sha256.New()
-	if _, err := io.Copy(hasher, bytes.NewReader(data)); err != nil {
-		return fmt.Errorf("failed to calculate sha256: %w", err)
-	}
-	actualHash := hex.EncodeToString(hasher.Sum(nil))
 	if actualHash != expectedHash {
 		return fmt.Errorf("checksum mismatch: expected %s, got %s", expectedHash, actualHash)
 	}
@@ -633,18 +766,10 @@ func (v *defaultValidator) validateArchivePathExists(archiveData []byte, pathInA
 	if !isNonEmpty(pathInArchive) {
 		return fmt.Errorf("pathInArchive empty")
 	}
-	ext := strings.ToLower(filepath.Ext(archiveURI))
-	archiveType := ""
-	if strings.HasSuffix(archiveURI, ".tar.gz") || strings.HasSuffix(archiveURI, ".tgz") {
-		archiveType = "tar.gz"
-	} else if strings.HasSuffix(archiveURI, ".tar.bz2") || strings.HasSuffix(archiveURI, ".tbz2") {
-		archiveType = "tar.bz2"
-	} else if ext == ".zip" {
-		archiveType = "zip"
-	} else {
-		return fmt.Errorf("unsupported archive extension for URI '%s'. Supported: .zip, .tar.gz, .tgz, .tar.bz2, .tbz2", archiveURI)
+	archiveType, err := archiveTypeFor(archiveURI)
+	if err != nil {
+		return err
 	}
-	var err error
 	found := false
 	byteReader := bytes.NewReader(archiveData)
 	switch archiveType {
@@ -0,0 +1,105 @@
+// Package resourcecollection defines the filters of a resource collection, which scope
+// queries to a subset of resources, and their base64 JSON encoding passed to plugins in
+// the resource_collection_filters config.
+package resourcecollection
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Filter selects the resources matching all of its non-empty fields. A collection
+// matches the resources matching any of its filters.
+type Filter struct {
+	Connectors    []string          `json:"connectors"`
+	AccountIDs    []string          `json:"account_ids"`
+	Regions       []string          `json:"regions"`
+	Zones         []string          `json:"zones,omitempty"` // Availability zones, e.g. GCP "us-central1-a"
+	ResourceTypes []string          `json:"resource_types"`
+	Tags          map[string]string `json:"tags"`
+}
+
+// IsEmpty reports whether f has no criteria, which would match every resource.
+func (f Filter) IsEmpty() bool {
+	return len(f.Connectors) == 0 && len(f.AccountIDs) == 0 && len(f.Regions) == 0 &&
+		len(f.Zones) == 0 && len(f.ResourceTypes) == 0 && len(f.Tags) == 0
+}
+
+// Validate checks that f has criteria and no blank values.
+func (f Filter) Validate() error {
+	if f.IsEmpty() {
+		return errors.New("filter has no criteria")
+	}
+	for field, values := range map[string][]string{
+		"connectors":     f.Connectors,
+		"account_ids":    f.AccountIDs,
+		"regions":        f.Regions,
+		"zones":          f.Zones,
+		"resource_types": f.ResourceTypes,
+	} {
+		for _, v := range values {
+			if strings.TrimSpace(v) == "" {
+				return fmt.Errorf("%s contains an empty value", field)
+			}
+		}
+	}
+	for k := range f.Tags {
+		if strings.TrimSpace(k) == "" {
+			return errors.New("tags contains an empty key")
+		}
+	}
+	return nil
+}
+
+// Validate checks that filters is not empty and every filter is valid.
+func Validate(filters []Filter) error {
+	if len(filters) == 0 {
+		return errors.New("resource collection has no filters")
+	}
+	for i, f := range filters {
+		if err := f.Validate(); err != nil {
+			return fmt.Errorf("filter %d: %w", i, err)
+		}
+	}
+	return nil
+}
+
+// Decode parses and validates base64 encoded JSON filters. Unknown fields are rejected
+// so a misspelled criterion cannot silently widen the collection.
+func Decode(encoded string) ([]Filter, error) {
+	data, err := base64.StdEncoding.DecodeString(encoded)
+	if err != nil {
+		return nil, fmt.Errorf("decode base64: %w", err)
+	}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	var filters []Filter
+	if err := decoder.Decode(&filters); err != nil {
+		return nil, fmt.Errorf("unmarshal filters: %w", err)
+	}
+	if _, err := decoder.Token(); err != io.EOF {
+		return nil, errors.New("unmarshal filters: unexpected data after filters")
+	}
+	if err := Validate(filters); err != nil {
+		return nil, err
+	}
+	return filters, nil
+}
+
+// Encode validates filters and returns their encoding for the resource_collection_filters
+// config.
+func Encode(filters []Filter) (string, error) {
+	if err := Validate(filters); err != nil {
+		return "", err
+	}
+	data, err := json.Marshal(filters)
+	if err != nil {
+		return "", err
+	}
+	return base64.StdEncoding.EncodeToString(data), nil
+}
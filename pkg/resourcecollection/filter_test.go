@@ -0,0 +1,37 @@
+package resourcecollection_test
+
+import (
+	"encoding/base64"
+	"testing"
+
+	"github.com/opengovern/og-util/pkg/resourcecollection"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDecode(t *testing.T) {
+	require := require.New(t)
+
+	encoded, err := resourcecollection.Encode([]resourcecollection.Filter{
+		{Connectors: []string{"AWS"}, Tags: map[string]string{"env": "prod"}},
+	})
+	require.NoError(err)
+	filters, err := resourcecollection.Decode(encoded)
+	require.NoError(err)
+	require.Equal([]string{"AWS"}, filters[0].Connectors)
+	require.Equal("prod", filters[0].Tags["env"])
+
+	for name, raw := range map[string]string{
+		"unknown field": `[{"connector": ["AWS"]}]`,
+		"no filters":    `[]`,
+		"empty filter":  `[{"regions": []}]`,
+		"blank value":   `[{"regions": [""]}]`,
+		"blank tag key": `[{"tags": {" ": "x"}}]`,
+		"trailing data": `[{"regions": ["us-east-1"]}] []`,
+		"not a list":    `{"regions": ["us-east-1"]}`,
+	} {
+		_, err := resourcecollection.Decode(base64.StdEncoding.EncodeToString([]byte(raw)))
+		require.Error(err, name)
+	}
+	_, err = resourcecollection.Decode("not base64!")
+	require.Error(err)
+}
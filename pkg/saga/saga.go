@@ -0,0 +1,316 @@
+// Package saga runs multi-step operations whose completed steps are undone by
+// compensators when a later step fails. Progress is persisted after every transition so
+// an interrupted run can be resumed from where it stopped.
+package saga
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+)
+
+type Status string
+
+const (
+	StatusPending            Status = "PENDING"
+	StatusRunning            Status = "RUNNING"
+	StatusCompleted          Status = "COMPLETED"
+	StatusFailed             Status = "FAILED" // Step only
+	StatusCompensating       Status = "COMPENSATING"
+	StatusCompensated        Status = "COMPENSATED"
+	StatusCompensationFailed Status = "COMPENSATION_FAILED"
+)
+
+// ErrNotFound is returned by Store.Load when no record exists for the id.
+var ErrNotFound = errors.New("saga record not found")
+
+// Data carries values between steps and into compensators, e.g. the id of a resource a
+// step created. It is persisted with the record, so values must be JSON-encodable.
+type Data map[string]json.RawMessage
+
+func (d Data) Set(key string, value any) error {
+	b, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("saga data %s: %w", key, err)
+	}
+	d[key] = b
+	return nil
+}
+
+// Get decodes the value stored under key into out and reports whether it was present.
+func (d Data) Get(key string, out any) (bool, error) {
+	b, ok := d[key]
+	if !ok {
+		return false, nil
+	}
+	if err := json.Unmarshal(b, out); err != nil {
+		return true, fmt.Errorf("saga data %s: %w", key, err)
+	}
+	return true, nil
+}
+
+// Step is one unit of a saga. Run may be retried after a crash, so it must be
+// idempotent. Compensate undoes a completed Run and is optional for steps with nothing
+// to undo; it may also be retried.
+type Step struct {
+	Name       string
+	Run        func(ctx context.Context, data Data) error
+	Compensate func(ctx context.Context, data Data) error
+}
+
+// StepRecord is the persisted progress of a step.
+type StepRecord struct {
+	Name       string     `json:"name"`
+	Status     Status     `json:"status"`
+	Error      string     `json:"error,omitempty"`
+	StartedAt  *time.Time `json:"startedAt,omitempty"`
+	FinishedAt *time.Time `json:"finishedAt,omitempty"`
+}
+
+// Record is the persisted state of a saga run and doubles as its status report.
+type Record struct {
+	ID        string       `json:"id"`
+	Name      string       `json:"name"`
+	Status    Status       `json:"status"`
+	Error     string       `json:"error,omitempty"` // Error of the failed step
+	Steps     []StepRecord `json:"steps"`
+	Data      Data         `json:"data"`
+	CreatedAt time.Time    `json:"createdAt"`
+	UpdatedAt time.Time    `json:"updatedAt"`
+}
+
+// Done reports whether the run reached a state it will not leave when resumed.
+func (r Record) Done() bool {
+	return r.Status == StatusCompleted || r.Status == StatusCompensated
+}
+
+// Store persists saga records.
+type Store interface {
+	Load(ctx context.Context, id string) (*Record, error)
+	Save(ctx context.Context, record *Record) error
+}
+
+// Options configures New.
+type Options struct {
+	Store Store
+	// OnUpdate is called with a copy of the record after every persisted transition.
+	OnUpdate func(Record)
+	// Now is used for timestamps; defaults to time.Now.
+	Now func() time.Time
+}
+
+type Saga struct {
+	name    string
+	steps   []Step
+	options Options
+}
+
+func New(name string, steps []Step, opts Options) (*Saga, error) {
+	if opts.Store == nil {
+		return nil, errors.New("saga store is required")
+	}
+	if opts.Now == nil {
+		opts.Now = time.Now
+	}
+	seen := make(map[string]bool, len(steps))
+	for i, step := range steps {
+		if step.Name == "" {
+			return nil, fmt.Errorf("saga %s: step %d has no name", name, i)
+		}
+		if seen[step.Name] {
+			return nil, fmt.Errorf("saga %s: duplicate step %s", name, step.Name)
+		}
+		if step.Run == nil {
+			return nil, fmt.Errorf("saga %s: step %s has no run function", name, step.Name)
+		}
+		seen[step.Name] = true
+	}
+	return &Saga{name: name, steps: steps, options: opts}, nil
+}
+
+// Run executes the saga under id, or resumes it if a record for id exists. data seeds
+// a new run and is ignored on resume. If a step fails, completed steps are compensated
+// in reverse order and the step error is returned. The returned record is the final
+// persisted state.
+func (s *Saga) Run(ctx context.Context, id string, data Data) (Record, error) {
+	record, err := s.options.Store.Load(ctx, id)
+	if errors.Is(err, ErrNotFound) {
+		record, err = s.newRecord(ctx, id, data)
+	}
+	if err != nil {
+		return Record{}, err
+	}
+	if err := s.checkDefinition(record); err != nil {
+		return *record, err
+	}
+
+	switch record.Status {
+	case StatusCompleted, StatusCompensated:
+		return *record, nil
+	case StatusCompensating, StatusCompensationFailed:
+		return s.compensate(ctx, record, errors.New(record.Error))
+	}
+
+	record.Status = StatusRunning
+	for i, step := range s.steps {
+		if record.Steps[i].Status == StatusCompleted {
+			continue
+		}
+		now := s.options.Now()
+		record.Steps[i] = StepRecord{Name: step.Name, Status: StatusRunning, StartedAt: &now}
+		if err := s.save(ctx, record); err != nil {
+			return *record, err
+		}
+
+		runErr := runStep(ctx, step.Run, record.Data)
+		now = s.options.Now()
+		record.Steps[i].FinishedAt = &now
+		if runErr != nil {
+			record.Steps[i].Status = StatusFailed
+			record.Steps[i].Error = runErr.Error()
+			record.Error = fmt.Sprintf("step %s: %v", step.Name, runErr)
+			return s.compensate(ctx, record, fmt.Errorf("saga %s step %s: %w", s.name, step.Name, runErr))
+		}
+		record.Steps[i].Status = StatusCompleted
+		if err := s.save(ctx, record); err != nil {
+			return *record, err
+		}
+	}
+
+	record.Status = StatusCompleted
+	return *record, s.save(ctx, record)
+}
+
+// compensate undoes completed steps in reverse order. cause is the error that triggered
+// compensation and is returned unless compensation itself fails.
+func (s *Saga) compensate(ctx context.Context, record *Record, cause error) (Record, error) {
+	record.Status = StatusCompensating
+	if err := s.save(ctx, record); err != nil {
+		return *record, err
+	}
+	for i := len(s.steps) - 1; i >= 0; i-- {
+		step := s.steps[i]
+		if record.Steps[i].Status != StatusCompleted {
+			continue
+		}
+		if step.Compensate != nil {
+			if err := runStep(ctx, step.Compensate, record.Data); err != nil {
+				record.Status = StatusCompensationFailed
+				record.Steps[i].Error = err.Error()
+				if saveErr := s.save(ctx, record); saveErr != nil {
+					return *record, saveErr
+				}
+				return *record, fmt.Errorf("saga %s compensate %s: %w (after: %v)", s.name, step.Name, err, cause)
+			}
+		}
+		now := s.options.Now()
+		record.Steps[i].Status = StatusCompensated
+		record.Steps[i].FinishedAt = &now
+		if err := s.save(ctx, record); err != nil {
+			return *record, err
+		}
+	}
+	record.Status = StatusCompensated
+	if err := s.save(ctx, record); err != nil {
+		return *record, err
+	}
+	return *record, cause
+}
+
+func (s *Saga) newRecord(ctx context.Context, id string, data Data) (*Record, error) {
+	now := s.options.Now()
+	record := &Record{
+		ID:        id,
+		Name:      s.name,
+		Status:    StatusPending,
+		Steps:     make([]StepRecord, len(s.steps)),
+		Data:      make(Data, len(data)),
+		CreatedAt: now,
+	}
+	for k, v := range data {
+		record.Data[k] = v
+	}
+	for i, step := range s.steps {
+		record.Steps[i] = StepRecord{Name: step.Name, Status: StatusPending}
+	}
+	return record, s.save(ctx, record)
+}
+
+// checkDefinition refuses to resume a record written by a different set of steps.
+func (s *Saga) checkDefinition(record *Record) error {
+	if record.Name != s.name || len(record.Steps) != len(s.steps) {
+		return fmt.Errorf("saga record %s does not match saga %s", record.ID, s.name)
+	}
+	for i, step := range s.steps {
+		if record.Steps[i].Name != step.Name {
+			return fmt.Errorf("saga record %s step %d is %s, expected %s", record.ID, i, record.Steps[i].Name, step.Name)
+		}
+	}
+	if record.Data == nil {
+		record.Data = make(Data)
+	}
+	return nil
+}
+
+func (s *Saga) save(ctx context.Context, record *Record) error {
+	record.UpdatedAt = s.options.Now()
+	if err := s.options.Store.Save(ctx, record); err != nil {
+		return fmt.Errorf("save saga %s: %w", record.ID, err)
+	}
+	if s.options.OnUpdate != nil {
+		s.options.OnUpdate(record.clone())
+	}
+	return nil
+}
+
+// runStep calls fn, converting a panic into an error.
+func runStep(ctx context.Context, fn func(context.Context, Data) error, data Data) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panicked with %v", r)
+		}
+	}()
+	return fn(ctx, data)
+}
+
+func (r Record) clone() Record {
+	c := r
+	c.Steps = append([]StepRecord(nil), r.Steps...)
+	c.Data = make(Data, len(r.Data))
+	for k, v := range r.Data {
+		c.Data[k] = append(json.RawMessage(nil), v...)
+	}
+	return c
+}
+
+// MemoryStore is an in-process Store, useful for tests and for sagas that do not need
+// to survive a restart.
+type MemoryStore struct {
+	mu      sync.Mutex
+	records map[string]Record
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{records: make(map[string]Record)}
+}
+
+func (m *MemoryStore) Load(_ context.Context, id string) (*Record, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	record, ok := m.records[id]
+	if !ok {
+		return nil, ErrNotFound
+	}
+	c := record.clone()
+	return &c, nil
+}
+
+func (m *MemoryStore) Save(_ context.Context, record *Record) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.records[record.ID] = record.clone()
+	return nil
+}
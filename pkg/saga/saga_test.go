@@ -0,0 +1,96 @@
+package saga_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/opengovern/og-util/pkg/saga"
+	"github.com/stretchr/testify/require"
+)
+
+type recorder struct {
+	calls []string
+}
+
+func (r *recorder) step(name string, fail *bool) saga.Step {
+	return saga.Step{
+		Name: name,
+		Run: func(ctx context.Context, data saga.Data) error {
+			r.calls = append(r.calls, "run "+name)
+			if fail != nil && *fail {
+				return errors.New(name + " failed")
+			}
+			return data.Set(name, true)
+		},
+		Compensate: func(ctx context.Context, data saga.Data) error {
+			r.calls = append(r.calls, "undo "+name)
+			return nil
+		},
+	}
+}
+
+func TestSagaCompensatesCompletedSteps(t *testing.T) {
+	require := require.New(t)
+
+	rec := &recorder{}
+	fail := true
+	s, err := saga.New("install", []saga.Step{
+		rec.step("download", nil),
+		rec.step("migrate", nil),
+		rec.step("register", &fail),
+	}, saga.Options{Store: saga.NewMemoryStore()})
+	require.NoError(err)
+
+	record, err := s.Run(context.Background(), "plan-1", nil)
+	require.ErrorContains(err, "register failed")
+	require.Equal(saga.StatusCompensated, record.Status)
+	require.Equal([]string{"run download", "run migrate", "run register", "undo migrate", "undo download"}, rec.calls)
+	require.Equal(saga.StatusFailed, record.Steps[2].Status)
+}
+
+func TestSagaResumesAfterCrash(t *testing.T) {
+	require := require.New(t)
+
+	store := saga.NewMemoryStore()
+	rec := &recorder{}
+	crash := true
+	steps := []saga.Step{
+		rec.step("download", nil),
+		{
+			Name: "migrate",
+			Run: func(ctx context.Context, data saga.Data) error {
+				if crash {
+					panic("process killed")
+				}
+				rec.calls = append(rec.calls, "run migrate")
+				return nil
+			},
+		},
+	}
+
+	// Simulate a crash mid-step by persisting the record and abandoning the run.
+	var lastRunning *saga.Record
+	s, err := saga.New("install", steps, saga.Options{Store: store, OnUpdate: func(r saga.Record) {
+		if r.Status == saga.StatusRunning && r.Steps[1].Status == saga.StatusRunning && lastRunning == nil {
+			lastRunning = &r
+		}
+	}})
+	require.NoError(err)
+	_, err = s.Run(context.Background(), "plan-2", nil)
+	require.Error(err)
+	require.NotNil(lastRunning)
+	require.NoError(store.Save(context.Background(), lastRunning))
+
+	crash = false
+	rec.calls = nil
+	record, err := s.Run(context.Background(), "plan-2", nil)
+	require.NoError(err)
+	require.Equal(saga.StatusCompleted, record.Status)
+	require.Equal([]string{"run migrate"}, rec.calls)
+
+	var downloaded bool
+	ok, err := record.Data.Get("download", &downloaded)
+	require.NoError(err)
+	require.True(ok && downloaded)
+}
@@ -0,0 +1,364 @@
+// Package slo tracks the success and latency of named operations, such as ES queries,
+// validations and deliveries, against service level objectives. It computes error budget
+// burn rates over sliding windows, evaluates multi-window burn rate alerts and exposes
+// both as Prometheus metrics.
+package slo
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// SLIs tracked for each objective.
+const (
+	SLIAvailability = "availability" // Share of operations that succeeded
+	SLILatency      = "latency"      // Share of operations faster than the latency threshold
+)
+
+// Objective is the SLO of one operation.
+type Objective struct {
+	Operation string
+	// SuccessTarget is the share of operations that must succeed, e.g. 0.999.
+	SuccessTarget float64
+	// LatencyThreshold and LatencyTarget define the latency SLI: LatencyTarget of the
+	// operations must complete within LatencyThreshold. Unset disables it.
+	LatencyThreshold time.Duration
+	LatencyTarget    float64
+	// Window is the period error budgets are computed over (default 30 days).
+	Window time.Duration
+}
+
+func (o Objective) validate() error {
+	if o.Operation == "" {
+		return errors.New("objective has no operation")
+	}
+	if o.SuccessTarget <= 0 || o.SuccessTarget >= 1 {
+		return fmt.Errorf("objective %s: success target must be between 0 and 1, got %v", o.Operation, o.SuccessTarget)
+	}
+	if o.LatencyThreshold > 0 && (o.LatencyTarget <= 0 || o.LatencyTarget >= 1) {
+		return fmt.Errorf("objective %s: latency target must be between 0 and 1, got %v", o.Operation, o.LatencyTarget)
+	}
+	return nil
+}
+
+// AlertPolicy fires when the burn rate exceeds BurnRate over both LongWindow and
+// ShortWindow; the short window makes the alert reset soon after the problem stops.
+type AlertPolicy struct {
+	Name        string // e.g. "page" or "ticket"
+	LongWindow  time.Duration
+	ShortWindow time.Duration
+	BurnRate    float64
+}
+
+// DefaultAlertPolicies are the multi-window burn rate alerts of the Google SRE workbook
+// for a 30 day window: a page when 2% of the budget is spent in an hour, a ticket when
+// 5% is spent in six hours.
+var DefaultAlertPolicies = []AlertPolicy{
+	{Name: "page", LongWindow: time.Hour, ShortWindow: 5 * time.Minute, BurnRate: 14.4},
+	{Name: "ticket", LongWindow: 6 * time.Hour, ShortWindow: 30 * time.Minute, BurnRate: 6},
+}
+
+// Config configures a Tracker.
+type Config struct {
+	Objectives []Objective
+	// AlertPolicies default to DefaultAlertPolicies.
+	AlertPolicies []AlertPolicy
+	// Namespace of the Prometheus metrics (default "opengovernance").
+	Namespace string
+	// LatencyBuckets of the duration histogram, in seconds (default prometheus.DefBuckets).
+	LatencyBuckets []float64
+	// Clock returns the current time (default time.Now).
+	Clock func() time.Time
+}
+
+// Status is the state of one SLI of an operation.
+type Status struct {
+	Operation string
+	SLI       string
+	Target    float64
+	// BudgetRemaining is the share of the error budget of the objective's window left,
+	// 1 if no operation failed and negative once the budget is overspent.
+	BudgetRemaining float64
+	// BurnRates by window: how many times faster than sustainable the budget is spent.
+	BurnRates map[time.Duration]float64
+	// Alerts lists the names of the firing alert policies.
+	Alerts []string
+}
+
+const (
+	fineResolution   = time.Minute
+	coarseResolution = time.Hour
+)
+
+// Tracker records operation outcomes and evaluates them against objectives. Register it
+// with a Prometheus registry to export its metrics. It is safe for concurrent use.
+type Tracker struct {
+	objectives map[string]Objective
+	policies   []AlertPolicy
+	clock      func() time.Time
+
+	mu         sync.Mutex
+	operations map[string]*operation
+
+	requests  *prometheus.CounterVec
+	durations *prometheus.HistogramVec
+
+	targetDesc, budgetDesc, burnRateDesc, alertDesc *prometheus.Desc
+}
+
+type operation struct {
+	fine   *ring // For burn rates
+	coarse *ring // For the error budget
+}
+
+// New returns a Tracker for cfg.
+func New(cfg Config) (*Tracker, error) {
+	if cfg.Namespace == "" {
+		cfg.Namespace = "opengovernance"
+	}
+	if cfg.LatencyBuckets == nil {
+		cfg.LatencyBuckets = prometheus.DefBuckets
+	}
+	if cfg.AlertPolicies == nil {
+		cfg.AlertPolicies = DefaultAlertPolicies
+	}
+	if cfg.Clock == nil {
+		cfg.Clock = time.Now
+	}
+
+	t := &Tracker{
+		objectives: make(map[string]Objective, len(cfg.Objectives)),
+		policies:   cfg.AlertPolicies,
+		clock:      cfg.Clock,
+		operations: make(map[string]*operation),
+	}
+	var longestWindow time.Duration
+	for _, p := range cfg.AlertPolicies {
+		if p.LongWindow <= 0 || p.ShortWindow <= 0 || p.BurnRate <= 0 {
+			return nil, fmt.Errorf("alert policy %s: windows and burn rate must be positive", p.Name)
+		}
+		longestWindow = max(longestWindow, p.LongWindow, p.ShortWindow)
+	}
+	for _, o := range cfg.Objectives {
+		if err := o.validate(); err != nil {
+			return nil, err
+		}
+		if _, ok := t.objectives[o.Operation]; ok {
+			return nil, fmt.Errorf("duplicate objective for operation %s", o.Operation)
+		}
+		if o.Window <= 0 {
+			o.Window = 30 * 24 * time.Hour
+		}
+		t.objectives[o.Operation] = o
+		t.operations[o.Operation] = &operation{
+			fine:   newRing(fineResolution, longestWindow),
+			coarse: newRing(coarseResolution, o.Window),
+		}
+	}
+
+	t.requests = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: cfg.Namespace,
+		Subsystem: "slo",
+		Name:      "operations_total",
+		Help:      "Number of tracked operations by result",
+	}, []string{"operation", "result"})
+	t.durations = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: cfg.Namespace,
+		Subsystem: "slo",
+		Name:      "operation_duration_seconds",
+		Help:      "Duration of tracked operations",
+		Buckets:   cfg.LatencyBuckets,
+	}, []string{"operation"})
+	t.targetDesc = prometheus.NewDesc(prometheus.BuildFQName(cfg.Namespace, "slo", "target"),
+		"Objective of the SLI", []string{"operation", "sli"}, nil)
+	t.budgetDesc = prometheus.NewDesc(prometheus.BuildFQName(cfg.Namespace, "slo", "error_budget_remaining"),
+		"Share of the error budget left in the objective's window", []string{"operation", "sli"}, nil)
+	t.burnRateDesc = prometheus.NewDesc(prometheus.BuildFQName(cfg.Namespace, "slo", "burn_rate"),
+		"Error budget burn rate over the window", []string{"operation", "sli", "window"}, nil)
+	t.alertDesc = prometheus.NewDesc(prometheus.BuildFQName(cfg.Namespace, "slo", "alert_firing"),
+		"1 if the burn rate alert policy fires", []string{"operation", "sli", "alert"}, nil)
+	return t, nil
+}
+
+// Observe records an operation that took latency and failed if err is not nil.
+// Operations without an objective are only counted in the metrics.
+func (t *Tracker) Observe(operationName string, latency time.Duration, err error) {
+	result := "success"
+	if err != nil {
+		result = "failure"
+	}
+	t.requests.WithLabelValues(operationName, result).Inc()
+	t.durations.WithLabelValues(operationName).Observe(latency.Seconds())
+
+	objective, ok := t.objectives[operationName]
+	if !ok {
+		return
+	}
+	c := counts{total: 1}
+	if err != nil {
+		c.failed = 1
+	}
+	if objective.LatencyThreshold > 0 && latency > objective.LatencyThreshold {
+		c.slow = 1
+	}
+	now := t.clock()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	op := t.operations[operationName]
+	op.fine.add(now, c)
+	op.coarse.add(now, c)
+}
+
+// Start returns a function that observes operationName with the time elapsed since Start:
+//
+//	done := tracker.Start("es-search")
+//	err := search()
+//	done(err)
+func (t *Tracker) Start(operationName string) func(err error) {
+	start := t.clock()
+	return func(err error) {
+		t.Observe(operationName, t.clock().Sub(start), err)
+	}
+}
+
+// Status returns the state of the SLIs of operationName, availability first, or nil if it
+// has no objective.
+func (t *Tracker) Status(operationName string) []Status {
+	objective, ok := t.objectives[operationName]
+	if !ok {
+		return nil
+	}
+	now := t.clock()
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	op := t.operations[operationName]
+
+	slis := []sli{{SLIAvailability, objective.SuccessTarget, func(c counts) int64 { return c.failed }}}
+	if objective.LatencyThreshold > 0 {
+		slis = append(slis, sli{SLILatency, objective.LatencyTarget, func(c counts) int64 { return c.slow }})
+	}
+
+	statuses := make([]Status, 0, len(slis))
+	for _, sli := range slis {
+		budget := 1 - sli.target
+		burnRate := func(c counts) float64 {
+			if c.total == 0 {
+				return 0
+			}
+			return float64(sli.bad(c)) / float64(c.total) / budget
+		}
+		status := Status{
+			Operation:       operationName,
+			SLI:             sli.name,
+			Target:          sli.target,
+			BudgetRemaining: 1 - burnRate(op.coarse.sum(now, objective.Window)),
+			BurnRates:       make(map[time.Duration]float64),
+		}
+		for _, p := range t.policies {
+			long := burnRate(op.fine.sum(now, p.LongWindow))
+			short := burnRate(op.fine.sum(now, p.ShortWindow))
+			status.BurnRates[p.LongWindow] = long
+			status.BurnRates[p.ShortWindow] = short
+			if long >= p.BurnRate && short >= p.BurnRate {
+				status.Alerts = append(status.Alerts, p.Name)
+			}
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+// Describe implements prometheus.Collector.
+func (t *Tracker) Describe(ch chan<- *prometheus.Desc) {
+	t.requests.Describe(ch)
+	t.durations.Describe(ch)
+	ch <- t.targetDesc
+	ch <- t.budgetDesc
+	ch <- t.burnRateDesc
+	ch <- t.alertDesc
+}
+
+// Collect implements prometheus.Collector.
+func (t *Tracker) Collect(ch chan<- prometheus.Metric) {
+	t.requests.Collect(ch)
+	t.durations.Collect(ch)
+
+	operations := make([]string, 0, len(t.objectives))
+	for name := range t.objectives {
+		operations = append(operations, name)
+	}
+	sort.Strings(operations)
+	for _, name := range operations {
+		for _, status := range t.Status(name) {
+			ch <- prometheus.MustNewConstMetric(t.targetDesc, prometheus.GaugeValue, status.Target, name, status.SLI)
+			ch <- prometheus.MustNewConstMetric(t.budgetDesc, prometheus.GaugeValue, status.BudgetRemaining, name, status.SLI)
+			for window, rate := range status.BurnRates {
+				ch <- prometheus.MustNewConstMetric(t.burnRateDesc, prometheus.GaugeValue, rate, name, status.SLI, window.String())
+			}
+			for _, p := range t.policies {
+				firing := 0.0
+				for _, alert := range status.Alerts {
+					if alert == p.Name {
+						firing = 1
+					}
+				}
+				ch <- prometheus.MustNewConstMetric(t.alertDesc, prometheus.GaugeValue, firing, name, status.SLI, p.Name)
+			}
+		}
+	}
+}
+
+type sli struct {
+	name   string
+	target float64
+	bad    func(counts) int64 // Events counting against the SLI
+}
+
+type counts struct {
+	total, failed, slow int64
+}
+
+// ring holds counts in buckets of resolution covering a sliding window.
+type ring struct {
+	resolution time.Duration
+	buckets    []counts
+	ids        []int64 // Bucket number held in each slot, to detect stale slots
+}
+
+func newRing(resolution, window time.Duration) *ring {
+	size := int(window/resolution) + 1
+	return &ring{resolution: resolution, buckets: make([]counts, size), ids: make([]int64, size)}
+}
+
+func (r *ring) add(now time.Time, c counts) {
+	id := now.UnixNano() / int64(r.resolution)
+	slot := int(id % int64(len(r.buckets)))
+	if r.ids[slot] != id {
+		r.ids[slot], r.buckets[slot] = id, counts{}
+	}
+	r.buckets[slot].total += c.total
+	r.buckets[slot].failed += c.failed
+	r.buckets[slot].slow += c.slow
+}
+
+// sum returns the counts of the buckets overlapping the last window, which rounds the
+// window up to the resolution.
+func (r *ring) sum(now time.Time, window time.Duration) counts {
+	newest := now.UnixNano() / int64(r.resolution)
+	n := min(int64(window/r.resolution)+1, int64(len(r.buckets)))
+	var total counts
+	for id := newest - n + 1; id <= newest; id++ {
+		slot := int(id % int64(len(r.buckets)))
+		if r.ids[slot] == id {
+			total.total += r.buckets[slot].total
+			total.failed += r.buckets[slot].failed
+			total.slow += r.buckets[slot].slow
+		}
+	}
+	return total
+}
@@ -0,0 +1,72 @@
+package slo_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/opengovern/og-util/pkg/slo"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTracker(t *testing.T) {
+	require := require.New(t)
+
+	now := time.Date(2024, 6, 1, 12, 0, 0, 0, time.UTC)
+	tracker, err := slo.New(slo.Config{
+		Objectives: []slo.Objective{{
+			Operation:        "es-search",
+			SuccessTarget:    0.99,
+			LatencyThreshold: time.Second,
+			LatencyTarget:    0.9,
+		}},
+		Clock: func() time.Time { return now },
+	})
+	require.NoError(err)
+
+	// An hour at 1% errors burns the budget exactly as fast as sustainable
+	for i := 0; i < 60; i++ {
+		for j := 0; j < 99; j++ {
+			tracker.Observe("es-search", 100*time.Millisecond, nil)
+		}
+		tracker.Observe("es-search", 2*time.Second, errors.New("unavailable"))
+		now = now.Add(time.Minute)
+	}
+	statuses := tracker.Status("es-search")
+	require.Len(statuses, 2)
+	availability, latency := statuses[0], statuses[1]
+	require.Equal(slo.SLIAvailability, availability.SLI)
+	require.InDelta(1.0, availability.BurnRates[time.Hour], 0.05)
+	require.Empty(availability.Alerts)
+	require.InDelta(0.1, latency.BurnRates[time.Hour], 0.01)
+
+	// A burst of failures fires the page alert
+	for j := 0; j < 1500; j++ {
+		tracker.Observe("es-search", 100*time.Millisecond, errors.New("unavailable"))
+	}
+	statuses = tracker.Status("es-search")
+	require.Equal([]string{"page", "ticket"}, statuses[0].Alerts)
+	require.Less(statuses[0].BudgetRemaining, 0.0)
+
+	require.Nil(tracker.Status("unknown"))
+	tracker.Observe("unknown", time.Millisecond, nil)
+
+	registry := prometheus.NewRegistry()
+	require.NoError(registry.Register(tracker))
+	require.NoError(testutil.GatherAndCompare(registry, strings.NewReader(`
+# HELP opengovernance_slo_alert_firing 1 if the burn rate alert policy fires
+# TYPE opengovernance_slo_alert_firing gauge
+opengovernance_slo_alert_firing{alert="page",operation="es-search",sli="availability"} 1
+opengovernance_slo_alert_firing{alert="page",operation="es-search",sli="latency"} 0
+opengovernance_slo_alert_firing{alert="ticket",operation="es-search",sli="availability"} 1
+opengovernance_slo_alert_firing{alert="ticket",operation="es-search",sli="latency"} 0
+`), "opengovernance_slo_alert_firing"))
+}
+
+func TestNewValidatesObjectives(t *testing.T) {
+	_, err := slo.New(slo.Config{Objectives: []slo.Objective{{Operation: "x", SuccessTarget: 1}}})
+	require.Error(t, err)
+}
@@ -0,0 +1,75 @@
+// Command og-codegen generates Steampipe columns, ES mapping fragments and filtersQuals
+// maps from annotated resource model structs. It is meant to be run via go:generate:
+//
+//	//go:generate go run github.com/opengovern/og-util/pkg/steampipe/codegen/cmd/og-codegen -type EC2Instance -output ec2_instance_gen.go
+//
+// See package codegen for the annotation format.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"go/parser"
+	"go/token"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/opengovern/og-util/pkg/steampipe/codegen"
+)
+
+func main() {
+	typeNames := flag.String("type", "", "comma-separated list of resource types to generate; all annotated types if empty")
+	input := flag.String("input", os.Getenv("GOFILE"), "Go source file containing the resource models")
+	output := flag.String("output", "", "output file; defaults to <input>_gen.go")
+	packageName := flag.String("package", os.Getenv("GOPACKAGE"), "package name of the generated file; defaults to the input's package")
+	flag.Parse()
+
+	if err := run(*input, *output, *packageName, *typeNames); err != nil {
+		fmt.Fprintln(os.Stderr, "og-codegen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(input, output, packageName, typeNames string) error {
+	if input == "" {
+		return fmt.Errorf("no input file; set -input or run via go:generate")
+	}
+	src, err := os.ReadFile(input)
+	if err != nil {
+		return err
+	}
+
+	if packageName == "" {
+		file, err := parser.ParseFile(token.NewFileSet(), input, src, parser.PackageClauseOnly)
+		if err != nil {
+			return err
+		}
+		packageName = file.Name.Name
+	}
+
+	var types []string
+	if typeNames != "" {
+		for _, name := range strings.Split(typeNames, ",") {
+			types = append(types, strings.TrimSpace(name))
+		}
+	}
+
+	resources, err := codegen.ParseSource(input, src, types)
+	if err != nil {
+		return err
+	}
+	if len(resources) == 0 {
+		return fmt.Errorf("no annotated resource types found in %s", input)
+	}
+
+	generated, err := codegen.Generate(packageName, resources)
+	if err != nil {
+		return err
+	}
+
+	if output == "" {
+		output = strings.TrimSuffix(input, filepath.Ext(input)) + "_gen.go"
+	}
+	return os.WriteFile(output, generated, 0644)
+}
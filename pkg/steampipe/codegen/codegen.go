@@ -0,0 +1,427 @@
+// Package codegen generates Steampipe column definitions, Elasticsearch mapping fragments
+// and BuildFilter filtersQuals maps from annotated resource model structs, so that the
+// three field lists no longer have to be maintained by hand.
+//
+// A resource model is marked with an "og:resource" line in its doc comment:
+//
+//	// og:resource table=aws_ec2_instance index=aws_ec2_instance prefix=description
+//	type EC2Instance struct {
+//		// The ID of the instance.
+//		InstanceID string    `json:"InstanceId" og:",filter"`
+//		LaunchTime time.Time `json:"LaunchTime"`
+//		Internal   string    `og:"-"`
+//	}
+//
+// Every exported field becomes a column. The og struct tag customizes it:
+// "<column name>,type=<steampipe type>,path=<es path>,filter". The column name defaults
+// to the snake_case field name, the type is inferred from the Go type, and the ES path
+// is the resource prefix joined with the field's json name. Fields marked filter are
+// added to the filtersQuals map. The field doc or line comment is the description.
+package codegen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/ast"
+	"go/format"
+	"go/parser"
+	"go/token"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"text/template"
+	"unicode"
+)
+
+const resourceMarker = "og:resource"
+
+// Steampipe column types, named after proto.ColumnType values.
+const (
+	ColumnTypeString    = "STRING"
+	ColumnTypeInt       = "INT"
+	ColumnTypeDouble    = "DOUBLE"
+	ColumnTypeBool      = "BOOL"
+	ColumnTypeTimestamp = "TIMESTAMP"
+	ColumnTypeIPAddr    = "IPADDR"
+	ColumnTypeCIDR      = "CIDR"
+	ColumnTypeJSON      = "JSON"
+)
+
+// esMappingTypes maps Steampipe column types to Elasticsearch field mappings.
+var esMappingTypes = map[string]map[string]any{
+	ColumnTypeString:    {"type": "keyword"},
+	ColumnTypeInt:       {"type": "long"},
+	ColumnTypeDouble:    {"type": "double"},
+	ColumnTypeBool:      {"type": "boolean"},
+	ColumnTypeTimestamp: {"type": "date"},
+	ColumnTypeIPAddr:    {"type": "ip"},
+	ColumnTypeCIDR:      {"type": "keyword"},
+	ColumnTypeJSON:      {"type": "object", "enabled": false},
+}
+
+// Field is a single column of a resource model.
+type Field struct {
+	GoName      string
+	Column      string
+	Type        string
+	ESPath      string
+	Filter      bool
+	Description string
+}
+
+// Resource is an annotated resource model struct.
+type Resource struct {
+	TypeName string
+	Table    string
+	Index    string
+	Prefix   string
+	Fields   []Field
+}
+
+// ParseSource parses Go source and returns the annotated resource models it declares.
+// If typeNames is non-empty only those types are returned, and each must be annotated.
+func ParseSource(filename string, src []byte, typeNames []string) ([]Resource, error) {
+	fset := token.NewFileSet()
+	file, err := parser.ParseFile(fset, filename, src, parser.ParseComments)
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", filename, err)
+	}
+
+	wanted := make(map[string]bool, len(typeNames))
+	for _, name := range typeNames {
+		wanted[name] = true
+	}
+
+	var resources []Resource
+	for _, decl := range file.Decls {
+		genDecl, ok := decl.(*ast.GenDecl)
+		if !ok || genDecl.Tok != token.TYPE {
+			continue
+		}
+		for _, spec := range genDecl.Specs {
+			typeSpec := spec.(*ast.TypeSpec)
+			if len(wanted) > 0 && !wanted[typeSpec.Name.Name] {
+				continue
+			}
+			doc := typeSpec.Doc
+			if doc == nil && len(genDecl.Specs) == 1 {
+				doc = genDecl.Doc
+			}
+			options, ok := resourceOptions(doc)
+			if !ok {
+				if wanted[typeSpec.Name.Name] {
+					return nil, fmt.Errorf("type %s has no %s annotation", typeSpec.Name.Name, resourceMarker)
+				}
+				continue
+			}
+			structType, ok := typeSpec.Type.(*ast.StructType)
+			if !ok {
+				return nil, fmt.Errorf("type %s is annotated with %s but is not a struct", typeSpec.Name.Name, resourceMarker)
+			}
+			resource, err := parseResource(typeSpec.Name.Name, options, structType)
+			if err != nil {
+				return nil, err
+			}
+			delete(wanted, typeSpec.Name.Name)
+			resources = append(resources, resource)
+		}
+	}
+	for name := range wanted {
+		return nil, fmt.Errorf("type %s not found in %s", name, filename)
+	}
+	return resources, nil
+}
+
+// resourceOptions extracts the key=value options of the og:resource doc comment line.
+func resourceOptions(doc *ast.CommentGroup) (map[string]string, bool) {
+	if doc == nil {
+		return nil, false
+	}
+	for _, comment := range doc.List {
+		text := strings.TrimSpace(strings.TrimPrefix(comment.Text, "//"))
+		if !strings.HasPrefix(text, resourceMarker) {
+			continue
+		}
+		options := make(map[string]string)
+		for _, option := range strings.Fields(strings.TrimPrefix(text, resourceMarker)) {
+			key, value, _ := strings.Cut(option, "=")
+			options[key] = value
+		}
+		return options, true
+	}
+	return nil, false
+}
+
+func parseResource(typeName string, options map[string]string, structType *ast.StructType) (Resource, error) {
+	resource := Resource{
+		TypeName: typeName,
+		Table:    options["table"],
+		Index:    options["index"],
+		Prefix:   options["prefix"],
+	}
+	if resource.Table == "" {
+		return Resource{}, fmt.Errorf("type %s: %s requires a table option", typeName, resourceMarker)
+	}
+	if resource.Index == "" {
+		resource.Index = resource.Table
+	}
+
+	seen := make(map[string]string)
+	for _, astField := range structType.Fields.List {
+		if len(astField.Names) == 0 {
+			return Resource{}, fmt.Errorf("type %s: embedded fields are not supported", typeName)
+		}
+		var tag reflect.StructTag
+		if astField.Tag != nil {
+			unquoted, err := strconv.Unquote(astField.Tag.Value)
+			if err != nil {
+				return Resource{}, fmt.Errorf("type %s: invalid struct tag %s", typeName, astField.Tag.Value)
+			}
+			tag = reflect.StructTag(unquoted)
+		}
+		ogTag := tag.Get("og")
+		if ogTag == "-" {
+			continue
+		}
+		for _, name := range astField.Names {
+			if !name.IsExported() {
+				continue
+			}
+			field, err := parseField(name.Name, astField, tag, ogTag, resource.Prefix)
+			if err != nil {
+				return Resource{}, fmt.Errorf("type %s field %s: %w", typeName, name.Name, err)
+			}
+			if other, ok := seen[field.Column]; ok {
+				return Resource{}, fmt.Errorf("type %s: fields %s and %s both map to column %s", typeName, other, name.Name, field.Column)
+			}
+			seen[field.Column] = name.Name
+			resource.Fields = append(resource.Fields, field)
+		}
+	}
+	return resource, nil
+}
+
+func parseField(goName string, astField *ast.Field, tag reflect.StructTag, ogTag string, prefix string) (Field, error) {
+	field := Field{
+		GoName: goName,
+		Column: toSnakeCase(goName),
+		Type:   inferColumnType(astField.Type),
+	}
+
+	jsonName := goName
+	if name, _, _ := strings.Cut(tag.Get("json"), ","); name != "" && name != "-" {
+		jsonName = name
+	}
+	field.ESPath = jsonName
+	if prefix != "" {
+		field.ESPath = prefix + "." + jsonName
+	}
+
+	parts := strings.Split(ogTag, ",")
+	if parts[0] != "" {
+		field.Column = parts[0]
+	}
+	for _, option := range parts[1:] {
+		key, value, _ := strings.Cut(strings.TrimSpace(option), "=")
+		switch key {
+		case "type":
+			value = strings.ToUpper(value)
+			if _, ok := esMappingTypes[value]; !ok {
+				return Field{}, fmt.Errorf("unknown column type %q", value)
+			}
+			field.Type = value
+		case "path":
+			if value == "" {
+				return Field{}, fmt.Errorf("path option requires a value")
+			}
+			field.ESPath = value
+		case "filter":
+			field.Filter = true
+		case "":
+		default:
+			return Field{}, fmt.Errorf("unknown og tag option %q", key)
+		}
+	}
+
+	doc := astField.Doc
+	if doc == nil {
+		doc = astField.Comment
+	}
+	if doc != nil {
+		field.Description = strings.Join(strings.Fields(doc.Text()), " ")
+	}
+	return field, nil
+}
+
+// inferColumnType maps a Go field type to a Steampipe column type.
+func inferColumnType(expr ast.Expr) string {
+	switch t := expr.(type) {
+	case *ast.StarExpr:
+		return inferColumnType(t.X)
+	case *ast.Ident:
+		switch t.Name {
+		case "string":
+			return ColumnTypeString
+		case "int", "int8", "int16", "int32", "int64", "uint", "uint8", "uint16", "uint32", "uint64":
+			return ColumnTypeInt
+		case "float32", "float64":
+			return ColumnTypeDouble
+		case "bool":
+			return ColumnTypeBool
+		}
+	case *ast.SelectorExpr:
+		if pkg, ok := t.X.(*ast.Ident); ok && pkg.Name == "time" && t.Sel.Name == "Time" {
+			return ColumnTypeTimestamp
+		}
+	}
+	return ColumnTypeJSON
+}
+
+// toSnakeCase converts a Go identifier to snake_case, keeping acronyms together
+// (InstanceID -> instance_id, VPCEndpointId -> vpc_endpoint_id).
+func toSnakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				(i+1 < len(runes) && unicode.IsLower(runes[i+1]) && unicode.IsUpper(runes[i-1]))) {
+				b.WriteByte('_')
+			}
+			b.WriteRune(unicode.ToLower(r))
+			continue
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+// FiltersQuals returns the column -> ES path map for fields marked filter.
+func (r Resource) FiltersQuals() map[string]string {
+	quals := make(map[string]string)
+	for _, f := range r.Fields {
+		if f.Filter {
+			quals[f.Column] = f.ESPath
+		}
+	}
+	return quals
+}
+
+// ESMapping returns the Elasticsearch mapping fragment ({"properties": ...}) for the
+// resource fields, nesting dotted ES paths as object properties.
+func (r Resource) ESMapping() (map[string]any, error) {
+	root := map[string]any{}
+	for _, f := range r.Fields {
+		segments := strings.Split(f.ESPath, ".")
+		properties := root
+		for i, segment := range segments[:len(segments)-1] {
+			next, ok := properties[segment].(map[string]any)
+			if !ok {
+				if _, exists := properties[segment]; exists {
+					return nil, fmt.Errorf("es path %s of column %s conflicts with a leaf field at %s", f.ESPath, f.Column, strings.Join(segments[:i+1], "."))
+				}
+				next = map[string]any{"properties": map[string]any{}}
+				properties[segment] = next
+			}
+			properties = next["properties"].(map[string]any)
+		}
+		leaf := segments[len(segments)-1]
+		if _, exists := properties[leaf]; exists {
+			return nil, fmt.Errorf("es path %s of column %s is declared more than once", f.ESPath, f.Column)
+		}
+		mapping := make(map[string]any, len(esMappingTypes[f.Type]))
+		for k, v := range esMappingTypes[f.Type] {
+			mapping[k] = v
+		}
+		properties[leaf] = mapping
+	}
+	return map[string]any{"properties": root}, nil
+}
+
+type templateResource struct {
+	Resource
+	Mapping      string
+	FiltersQuals []templateQual
+}
+
+type templateQual struct {
+	Column string
+	Path   string
+}
+
+var outputTemplate = template.Must(template.New("codegen").Parse(`// Code generated by og-codegen. DO NOT EDIT.
+
+package {{ .Package }}
+
+import (
+	"github.com/turbot/steampipe-plugin-sdk/v5/grpc/proto"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin"
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+{{ range .Resources }}
+// {{ .TypeName }}Columns returns the Steampipe columns of table {{ .Table }}.
+func {{ .TypeName }}Columns() []*plugin.Column {
+	return []*plugin.Column{
+	{{- range .Fields }}
+		{Name: {{ printf "%q" .Column }}, Type: proto.ColumnType_{{ .Type }}, Description: {{ printf "%q" .Description }}, Transform: transform.FromField({{ printf "%q" .GoName }})},
+	{{- end }}
+	}
+}
+
+// {{ .TypeName }}FiltersQuals maps {{ .Table }} columns to their ES fields for BuildFilter.
+var {{ .TypeName }}FiltersQuals = map[string]string{
+{{- range .FiltersQuals }}
+	{{ printf "%q" .Column }}: {{ printf "%q" .Path }},
+{{- end }}
+}
+
+// {{ .TypeName }}ESIndex is the ES index of table {{ .Table }}.
+const {{ .TypeName }}ESIndex = {{ printf "%q" .Index }}
+
+// {{ .TypeName }}ESMapping is the ES mapping fragment for index {{ .Index }}.
+const {{ .TypeName }}ESMapping = {{ .Mapping }}
+{{ end }}`))
+
+// Generate renders the gofmt'ed Go source declaring, for each resource, a
+// <Type>Columns function, <Type>FiltersQuals map, <Type>ESIndex and <Type>ESMapping.
+func Generate(packageName string, resources []Resource) ([]byte, error) {
+	data := struct {
+		Package   string
+		Resources []templateResource
+	}{Package: packageName}
+
+	for _, r := range resources {
+		mapping, err := r.ESMapping()
+		if err != nil {
+			return nil, fmt.Errorf("type %s: %w", r.TypeName, err)
+		}
+		mappingJSON, err := json.MarshalIndent(mapping, "", "  ")
+		if err != nil {
+			return nil, fmt.Errorf("type %s: marshal mapping: %w", r.TypeName, err)
+		}
+
+		quals := r.FiltersQuals()
+		columns := make([]string, 0, len(quals))
+		for column := range quals {
+			columns = append(columns, column)
+		}
+		sort.Strings(columns)
+		tr := templateResource{Resource: r, Mapping: "`" + string(mappingJSON) + "`"}
+		for _, column := range columns {
+			tr.FiltersQuals = append(tr.FiltersQuals, templateQual{Column: column, Path: quals[column]})
+		}
+		data.Resources = append(data.Resources, tr)
+	}
+
+	var buf bytes.Buffer
+	if err := outputTemplate.Execute(&buf, data); err != nil {
+		return nil, fmt.Errorf("execute template: %w", err)
+	}
+	src, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("format generated source: %w\n%s", err, buf.String())
+	}
+	return src, nil
+}
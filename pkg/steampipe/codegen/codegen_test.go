@@ -0,0 +1,86 @@
+package codegen_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/opengovern/og-util/pkg/steampipe/codegen"
+	"github.com/stretchr/testify/require"
+)
+
+const modelSource = `package model
+
+import "time"
+
+// og:resource table=aws_ec2_instance prefix=description
+type EC2Instance struct {
+	// The ID of the instance.
+	InstanceID string    ` + "`json:\"InstanceId\" og:\",filter\"`" + `
+	LaunchTime time.Time // When the instance was launched.
+	CPUCount   *int32    ` + "`og:\"cpu,filter,path=description.Cpu.Count\"`" + `
+	PublicIP   string    ` + "`og:\",type=ipaddr\"`" + `
+	Tags       map[string]string
+	Internal   string ` + "`og:\"-\"`" + `
+	private    string
+}
+
+type NotAResource struct {
+	Name string
+}
+`
+
+func TestParseSource(t *testing.T) {
+	require := require.New(t)
+
+	resources, err := codegen.ParseSource("model.go", []byte(modelSource), nil)
+	require.NoError(err)
+	require.Len(resources, 1)
+
+	r := resources[0]
+	require.Equal("EC2Instance", r.TypeName)
+	require.Equal("aws_ec2_instance", r.Index)
+	require.Equal([]codegen.Field{
+		{GoName: "InstanceID", Column: "instance_id", Type: codegen.ColumnTypeString, ESPath: "description.InstanceId", Filter: true, Description: "The ID of the instance."},
+		{GoName: "LaunchTime", Column: "launch_time", Type: codegen.ColumnTypeTimestamp, ESPath: "description.LaunchTime", Description: "When the instance was launched."},
+		{GoName: "CPUCount", Column: "cpu", Type: codegen.ColumnTypeInt, ESPath: "description.Cpu.Count", Filter: true},
+		{GoName: "PublicIP", Column: "public_ip", Type: codegen.ColumnTypeIPAddr, ESPath: "description.PublicIP"},
+		{GoName: "Tags", Column: "tags", Type: codegen.ColumnTypeJSON, ESPath: "description.Tags"},
+	}, r.Fields)
+	require.Equal(map[string]string{
+		"instance_id": "description.InstanceId",
+		"cpu":         "description.Cpu.Count",
+	}, r.FiltersQuals())
+
+	mapping, err := r.ESMapping()
+	require.NoError(err)
+	mappingJSON, err := json.Marshal(mapping)
+	require.NoError(err)
+	require.JSONEq(`{"properties":{"description":{"properties":{
+		"InstanceId":{"type":"keyword"},
+		"LaunchTime":{"type":"date"},
+		"Cpu":{"properties":{"Count":{"type":"long"}}},
+		"PublicIP":{"type":"ip"},
+		"Tags":{"type":"object","enabled":false}
+	}}}}`, string(mappingJSON))
+}
+
+func TestParseSourceRejectsUnannotatedType(t *testing.T) {
+	_, err := codegen.ParseSource("model.go", []byte(modelSource), []string{"NotAResource"})
+	require.Error(t, err)
+}
+
+func TestGenerate(t *testing.T) {
+	require := require.New(t)
+
+	resources, err := codegen.ParseSource("model.go", []byte(modelSource), []string{"EC2Instance"})
+	require.NoError(err)
+	src, err := codegen.Generate("model", resources)
+	require.NoError(err)
+
+	out := string(src)
+	require.Contains(out, "// Code generated by og-codegen. DO NOT EDIT.")
+	require.Contains(out, "func EC2InstanceColumns() []*plugin.Column {")
+	require.Contains(out, `{Name: "cpu", Type: proto.ColumnType_INT, Description: "", Transform: transform.FromField("CPUCount")},`)
+	require.Contains(out, `"instance_id": "description.InstanceId",`)
+	require.Contains(out, `const EC2InstanceESIndex = "aws_ec2_instance"`)
+}
@@ -0,0 +1,353 @@
+package steampipe
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"math"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/turbot/steampipe-plugin-sdk/v5/plugin/transform"
+)
+
+// ExtractPath returns the value at path in doc. doc may be raw JSON ([]byte, string or
+// json.RawMessage), an already decoded value, or any struct, which is converted through
+// its JSON encoding. path is a dotted JSONPath subset: an optional "$." prefix, field
+// names, [n] indexes and * or [*] wildcards, e.g. "$.Instance.Tags[*].Key". A wildcard
+// yields a []any of the matches. Missing fields yield (nil, nil).
+func ExtractPath(doc any, path string) (any, error) {
+	value, err := toJSONValue(doc)
+	if err != nil {
+		return nil, err
+	}
+	segments, err := parsePath(path)
+	if err != nil {
+		return nil, err
+	}
+	return extractSegments(value, segments), nil
+}
+
+// FromJSONPath is a transform that extracts path from the hydrate item, for use as
+// Transform: steampipe.FromJSONPath("$.Description.Instance.InstanceId").
+func FromJSONPath(path string) *transform.ColumnTransforms {
+	return transform.From(func(ctx context.Context, d *transform.TransformData) (interface{}, error) {
+		return ExtractPath(d.HydrateItem, path)
+	})
+}
+
+// JSONPathTransform is a transform func that extracts d.Param (a path string) from the
+// current value, for use in a chain: transform.FromField("Description").TransformP(steampipe.JSONPathTransform, "Tags[*].Key").
+func JSONPathTransform(ctx context.Context, d *transform.TransformData) (interface{}, error) {
+	path, ok := d.Param.(string)
+	if !ok {
+		return nil, fmt.Errorf("JSONPathTransform requires a string path param, got %T", d.Param)
+	}
+	return ExtractPath(d.Value, path)
+}
+
+// FlattenTags converts the common tag representations into a map[string]string:
+// maps with string or *string values, and lists of {Key, Value} objects (AWS style),
+// where keyField/valueField name the key and value properties (matched
+// case-insensitively). Nil input yields nil.
+func FlattenTags(value any, keyField, valueField string) (map[string]string, error) {
+	decoded, err := toJSONValue(value)
+	if err != nil {
+		return nil, err
+	}
+	switch v := decoded.(type) {
+	case nil:
+		return nil, nil
+	case map[string]any:
+		tags := make(map[string]string, len(v))
+		for key, tagValue := range v {
+			tags[key] = scalarString(tagValue)
+		}
+		return tags, nil
+	case []any:
+		tags := make(map[string]string, len(v))
+		for i, item := range v {
+			entry, ok := item.(map[string]any)
+			if !ok {
+				return nil, fmt.Errorf("tag entry %d is not an object", i)
+			}
+			key, ok := lookupFold(entry, keyField)
+			if !ok {
+				return nil, fmt.Errorf("tag entry %d has no %s field", i, keyField)
+			}
+			tagValue, _ := lookupFold(entry, valueField)
+			tags[scalarString(key)] = scalarString(tagValue)
+		}
+		return tags, nil
+	default:
+		return nil, fmt.Errorf("unsupported tags type %T", value)
+	}
+}
+
+// TagsTransform is a transform func that flattens d.Value with FlattenTags using the
+// default "Key"/"Value" field names, or the two-element []string in d.Param if set.
+func TagsTransform(ctx context.Context, d *transform.TransformData) (interface{}, error) {
+	keyField, valueField := "Key", "Value"
+	if fields, ok := d.Param.([]string); ok && len(fields) == 2 {
+		keyField, valueField = fields[0], fields[1]
+	}
+	tags, err := FlattenTags(d.Value, keyField, valueField)
+	if err != nil || tags == nil {
+		return nil, err
+	}
+	return tags, nil
+}
+
+// NormalizeTimestamp converts the timestamp formats found in descriptions to UTC:
+// time.Time, RFC 3339 (with or without fractional seconds or zone), "2006-01-02 15:04:05"
+// and dates, and epoch seconds or milliseconds as numbers or numeric strings. Nil and
+// empty input yields (nil, nil).
+func NormalizeTimestamp(value any) (*time.Time, error) {
+	switch v := value.(type) {
+	case nil:
+		return nil, nil
+	case time.Time:
+		if v.IsZero() {
+			return nil, nil
+		}
+		t := v.UTC()
+		return &t, nil
+	case *time.Time:
+		if v == nil {
+			return nil, nil
+		}
+		return NormalizeTimestamp(*v)
+	case *string:
+		if v == nil {
+			return nil, nil
+		}
+		return NormalizeTimestamp(*v)
+	case string:
+		s := strings.TrimSpace(v)
+		if s == "" {
+			return nil, nil
+		}
+		if f, err := strconv.ParseFloat(s, 64); err == nil {
+			return epochToTime(f), nil
+		}
+		for _, layout := range timestampLayouts {
+			if t, err := time.Parse(layout, s); err == nil {
+				t = t.UTC()
+				return &t, nil
+			}
+		}
+		return nil, fmt.Errorf("unrecognized timestamp %q", v)
+	case json.Number:
+		f, err := v.Float64()
+		if err != nil {
+			return nil, fmt.Errorf("invalid numeric timestamp %q: %w", v, err)
+		}
+		return epochToTime(f), nil
+	}
+
+	rv := reflect.ValueOf(value)
+	switch rv.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return epochToTime(float64(rv.Int())), nil
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return epochToTime(float64(rv.Uint())), nil
+	case reflect.Float32, reflect.Float64:
+		return epochToTime(rv.Float()), nil
+	}
+	return nil, fmt.Errorf("unsupported timestamp type %T", value)
+}
+
+// TimestampTransform is a transform func that normalizes d.Value with NormalizeTimestamp.
+func TimestampTransform(ctx context.Context, d *transform.TransformData) (interface{}, error) {
+	t, err := NormalizeTimestamp(d.Value)
+	if err != nil || t == nil {
+		return nil, err
+	}
+	return *t, nil
+}
+
+var timestampLayouts = []string{
+	time.RFC3339Nano,
+	"2006-01-02T15:04:05",
+	"2006-01-02T15:04:05.999999999",
+	"2006-01-02 15:04:05Z07:00",
+	"2006-01-02 15:04:05",
+	"2006-01-02",
+}
+
+// epochMillisThreshold separates epoch seconds from milliseconds: a seconds value this
+// large would be past the year 5000.
+const epochMillisThreshold = 1e11
+
+func epochToTime(f float64) *time.Time {
+	var t time.Time
+	if math.Abs(f) >= epochMillisThreshold {
+		t = time.UnixMilli(int64(f)).UTC()
+	} else {
+		sec, frac := math.Modf(f)
+		t = time.Unix(int64(sec), int64(frac*1e9)).UTC()
+	}
+	return &t
+}
+
+type pathSegment struct {
+	field    string
+	index    int
+	isIndex  bool
+	wildcard bool
+}
+
+func parsePath(path string) ([]pathSegment, error) {
+	path = strings.TrimPrefix(strings.TrimPrefix(path, "$"), ".")
+	var segments []pathSegment
+	for path != "" {
+		switch {
+		case strings.HasPrefix(path, "["):
+			end := strings.Index(path, "]")
+			if end < 0 {
+				return nil, fmt.Errorf("unterminated index in path")
+			}
+			inner := path[1:end]
+			if inner == "*" {
+				segments = append(segments, pathSegment{wildcard: true})
+			} else {
+				index, err := strconv.Atoi(inner)
+				if err != nil {
+					return nil, fmt.Errorf("invalid index %q in path", inner)
+				}
+				segments = append(segments, pathSegment{index: index, isIndex: true})
+			}
+			path = strings.TrimPrefix(path[end+1:], ".")
+		default:
+			end := strings.IndexAny(path, ".[")
+			if end < 0 {
+				end = len(path)
+			}
+			field := path[:end]
+			if field == "*" {
+				segments = append(segments, pathSegment{wildcard: true})
+			} else {
+				segments = append(segments, pathSegment{field: field})
+			}
+			path = strings.TrimPrefix(path[end:], ".")
+		}
+	}
+	return segments, nil
+}
+
+func extractSegments(value any, segments []pathSegment) any {
+	for i, segment := range segments {
+		switch {
+		case segment.wildcard:
+			var children []any
+			switch v := value.(type) {
+			case []any:
+				children = v
+			case map[string]any:
+				// Visit keys in sorted order so results are deterministic
+				keys := make([]string, 0, len(v))
+				for key := range v {
+					keys = append(keys, key)
+				}
+				sort.Strings(keys)
+				for _, key := range keys {
+					children = append(children, v[key])
+				}
+			default:
+				return nil
+			}
+			results := make([]any, 0, len(children))
+			for _, child := range children {
+				if result := extractSegments(child, segments[i+1:]); result != nil {
+					results = append(results, result)
+				}
+			}
+			return results
+		case segment.isIndex:
+			list, ok := value.([]any)
+			if !ok {
+				return nil
+			}
+			index := segment.index
+			if index < 0 {
+				index += len(list)
+			}
+			if index < 0 || index >= len(list) {
+				return nil
+			}
+			value = list[index]
+		default:
+			object, ok := value.(map[string]any)
+			if !ok {
+				return nil
+			}
+			value, ok = object[segment.field]
+			if !ok {
+				return nil
+			}
+		}
+	}
+	return value
+}
+
+// toJSONValue converts doc into the generic form produced by json.Unmarshal.
+func toJSONValue(doc any) (any, error) {
+	var data []byte
+	switch v := doc.(type) {
+	case nil:
+		return nil, nil
+	case []byte:
+		data = v
+	case json.RawMessage:
+		data = v
+	case string:
+		data = []byte(v)
+	case map[string]any, []any, bool, float64:
+		return v, nil
+	default:
+		var err error
+		data, err = json.Marshal(doc)
+		if err != nil {
+			return nil, fmt.Errorf("marshal %T: %w", doc, err)
+		}
+	}
+	if len(data) == 0 {
+		return nil, nil
+	}
+	var value any
+	if err := json.Unmarshal(data, &value); err != nil {
+		return nil, fmt.Errorf("unmarshal json: %w", err)
+	}
+	return value, nil
+}
+
+func lookupFold(object map[string]any, key string) (any, bool) {
+	if v, ok := object[key]; ok {
+		return v, true
+	}
+	for k, v := range object {
+		if strings.EqualFold(k, key) {
+			return v, true
+		}
+	}
+	return nil, false
+}
+
+func scalarString(value any) string {
+	switch v := value.(type) {
+	case nil:
+		return ""
+	case string:
+		return v
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bool:
+		return strconv.FormatBool(v)
+	default:
+		data, _ := json.Marshal(v)
+		return string(data)
+	}
+}
@@ -0,0 +1,78 @@
+package steampipe_test
+
+import (
+	"testing"
+	"time"
+
+	"github.com/opengovern/og-util/pkg/steampipe"
+	"github.com/stretchr/testify/require"
+)
+
+const descriptionJSON = `{
+	"Instance": {
+		"InstanceId": "i-0123",
+		"LaunchTime": "2024-05-01T10:00:00.123Z",
+		"Tags": [{"Key": "env", "Value": "prod"}, {"Key": "team", "Value": "core"}]
+	}
+}`
+
+func TestExtractPath(t *testing.T) {
+	require := require.New(t)
+
+	v, err := steampipe.ExtractPath(descriptionJSON, "$.Instance.InstanceId")
+	require.NoError(err)
+	require.Equal("i-0123", v)
+
+	v, err = steampipe.ExtractPath(descriptionJSON, "Instance.Tags[*].Key")
+	require.NoError(err)
+	require.Equal([]any{"env", "team"}, v)
+
+	v, err = steampipe.ExtractPath(descriptionJSON, "Instance.Tags[-1].Value")
+	require.NoError(err)
+	require.Equal("core", v)
+
+	v, err = steampipe.ExtractPath(descriptionJSON, "Instance.Missing.Field")
+	require.NoError(err)
+	require.Nil(v)
+}
+
+func TestFlattenTags(t *testing.T) {
+	require := require.New(t)
+
+	tags, err := steampipe.ExtractPath(descriptionJSON, "Instance.Tags")
+	require.NoError(err)
+	flat, err := steampipe.FlattenTags(tags, "key", "value")
+	require.NoError(err)
+	require.Equal(map[string]string{"env": "prod", "team": "core"}, flat)
+
+	value := "prod"
+	flat, err = steampipe.FlattenTags(map[string]*string{"env": &value}, "Key", "Value")
+	require.NoError(err)
+	require.Equal(map[string]string{"env": "prod"}, flat)
+}
+
+func TestNormalizeTimestamp(t *testing.T) {
+	require := require.New(t)
+	expected := time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC)
+
+	for _, input := range []any{
+		"2024-05-01T10:00:00Z",
+		"2024-05-01T12:00:00+02:00",
+		"2024-05-01 10:00:00",
+		int64(1714557600),
+		int64(1714557600000),
+		"1714557600",
+		float64(1714557600),
+	} {
+		ts, err := steampipe.NormalizeTimestamp(input)
+		require.NoError(err, "input %v", input)
+		require.True(expected.Equal(*ts), "input %v gave %s", input, ts)
+	}
+
+	ts, err := steampipe.NormalizeTimestamp("")
+	require.NoError(err)
+	require.Nil(ts)
+
+	_, err = steampipe.NormalizeTimestamp("not a time")
+	require.Error(err)
+}
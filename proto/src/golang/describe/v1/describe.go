@@ -0,0 +1,26 @@
+// Package describev1 exposes the v1 describe API alongside describe/v2. The generated
+// v1 code stays in package golang so existing importers keep working; these are aliases.
+package describev1
+
+import (
+	golang "github.com/opengovern/og-util/proto/src/golang"
+)
+
+const (
+	DescribeService_DeliverResult_FullMethodName = golang.DescribeService_DeliverResult_FullMethodName
+	DescribeService_SetInProgress_FullMethodName = golang.DescribeService_SetInProgress_FullMethodName
+)
+
+type (
+	DeliverResultRequest               = golang.DeliverResultRequest
+	SetInProgressRequest               = golang.SetInProgressRequest
+	DescribeServiceClient              = golang.DescribeServiceClient
+	DescribeServiceServer              = golang.DescribeServiceServer
+	UnimplementedDescribeServiceServer = golang.UnimplementedDescribeServiceServer
+)
+
+var (
+	File_describe_proto           = golang.File_describe_proto
+	NewDescribeServiceClient      = golang.NewDescribeServiceClient
+	RegisterDescribeServiceServer = golang.RegisterDescribeServiceServer
+)
@@ -0,0 +1,413 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.35.1
+// 	protoc        v3.12.4
+// source: describe/v2/describe.proto
+
+package describev2
+
+import (
+	golang "github.com/opengovern/og-util/proto/src/golang"
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+type DescribeJobStatus int32
+
+const (
+	DescribeJobStatus_DESCRIBE_JOB_STATUS_UNSPECIFIED DescribeJobStatus = 0
+	DescribeJobStatus_DESCRIBE_JOB_STATUS_IN_PROGRESS DescribeJobStatus = 1
+	DescribeJobStatus_DESCRIBE_JOB_STATUS_SUCCEEDED   DescribeJobStatus = 2
+	DescribeJobStatus_DESCRIBE_JOB_STATUS_FAILED      DescribeJobStatus = 3
+	DescribeJobStatus_DESCRIBE_JOB_STATUS_TIMEOUT     DescribeJobStatus = 4
+)
+
+// Enum value maps for DescribeJobStatus.
+var (
+	DescribeJobStatus_name = map[int32]string{
+		0: "DESCRIBE_JOB_STATUS_UNSPECIFIED",
+		1: "DESCRIBE_JOB_STATUS_IN_PROGRESS",
+		2: "DESCRIBE_JOB_STATUS_SUCCEEDED",
+		3: "DESCRIBE_JOB_STATUS_FAILED",
+		4: "DESCRIBE_JOB_STATUS_TIMEOUT",
+	}
+	DescribeJobStatus_value = map[string]int32{
+		"DESCRIBE_JOB_STATUS_UNSPECIFIED": 0,
+		"DESCRIBE_JOB_STATUS_IN_PROGRESS": 1,
+		"DESCRIBE_JOB_STATUS_SUCCEEDED":   2,
+		"DESCRIBE_JOB_STATUS_FAILED":      3,
+		"DESCRIBE_JOB_STATUS_TIMEOUT":     4,
+	}
+)
+
+func (x DescribeJobStatus) Enum() *DescribeJobStatus {
+	p := new(DescribeJobStatus)
+	*p = x
+	return p
+}
+
+func (x DescribeJobStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (DescribeJobStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_describe_v2_describe_proto_enumTypes[0].Descriptor()
+}
+
+func (DescribeJobStatus) Type() protoreflect.EnumType {
+	return &file_describe_v2_describe_proto_enumTypes[0]
+}
+
+func (x DescribeJobStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use DescribeJobStatus.Descriptor instead.
+func (DescribeJobStatus) EnumDescriptor() ([]byte, []int) {
+	return file_describe_v2_describe_proto_rawDescGZIP(), []int{0}
+}
+
+type DescribeError struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Code      string `protobuf:"bytes,1,opt,name=code,proto3" json:"code,omitempty"`
+	Message   string `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	Retryable bool   `protobuf:"varint,3,opt,name=retryable,proto3" json:"retryable,omitempty"`
+}
+
+func (x *DescribeError) Reset() {
+	*x = DescribeError{}
+	mi := &file_describe_v2_describe_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DescribeError) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DescribeError) ProtoMessage() {}
+
+func (x *DescribeError) ProtoReflect() protoreflect.Message {
+	mi := &file_describe_v2_describe_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DescribeError.ProtoReflect.Descriptor instead.
+func (*DescribeError) Descriptor() ([]byte, []int) {
+	return file_describe_v2_describe_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *DescribeError) GetCode() string {
+	if x != nil {
+		return x.Code
+	}
+	return ""
+}
+
+func (x *DescribeError) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+func (x *DescribeError) GetRetryable() bool {
+	if x != nil {
+		return x.Retryable
+	}
+	return false
+}
+
+type DeliverResultRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	JobId                uint32              `protobuf:"varint,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+	ParentJobId          uint32              `protobuf:"varint,2,opt,name=parent_job_id,json=parentJobId,proto3" json:"parent_job_id,omitempty"`
+	Status               DescribeJobStatus   `protobuf:"varint,3,opt,name=status,proto3,enum=opengovernance.describe.v2.DescribeJobStatus" json:"status,omitempty"`
+	Error                *DescribeError      `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	DescribeJob          *golang.DescribeJob `protobuf:"bytes,5,opt,name=describe_job,json=describeJob,proto3" json:"describe_job,omitempty"`
+	DescribedResourceIds []string            `protobuf:"bytes,6,rep,name=described_resource_ids,json=describedResourceIds,proto3" json:"described_resource_ids,omitempty"`
+	NextCursor           string              `protobuf:"bytes,7,opt,name=next_cursor,json=nextCursor,proto3" json:"next_cursor,omitempty"`
+}
+
+func (x *DeliverResultRequest) Reset() {
+	*x = DeliverResultRequest{}
+	mi := &file_describe_v2_describe_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeliverResultRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeliverResultRequest) ProtoMessage() {}
+
+func (x *DeliverResultRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_describe_v2_describe_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeliverResultRequest.ProtoReflect.Descriptor instead.
+func (*DeliverResultRequest) Descriptor() ([]byte, []int) {
+	return file_describe_v2_describe_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *DeliverResultRequest) GetJobId() uint32 {
+	if x != nil {
+		return x.JobId
+	}
+	return 0
+}
+
+func (x *DeliverResultRequest) GetParentJobId() uint32 {
+	if x != nil {
+		return x.ParentJobId
+	}
+	return 0
+}
+
+func (x *DeliverResultRequest) GetStatus() DescribeJobStatus {
+	if x != nil {
+		return x.Status
+	}
+	return DescribeJobStatus_DESCRIBE_JOB_STATUS_UNSPECIFIED
+}
+
+func (x *DeliverResultRequest) GetError() *DescribeError {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+func (x *DeliverResultRequest) GetDescribeJob() *golang.DescribeJob {
+	if x != nil {
+		return x.DescribeJob
+	}
+	return nil
+}
+
+func (x *DeliverResultRequest) GetDescribedResourceIds() []string {
+	if x != nil {
+		return x.DescribedResourceIds
+	}
+	return nil
+}
+
+func (x *DeliverResultRequest) GetNextCursor() string {
+	if x != nil {
+		return x.NextCursor
+	}
+	return ""
+}
+
+type SetInProgressRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	JobId uint32 `protobuf:"varint,1,opt,name=job_id,json=jobId,proto3" json:"job_id,omitempty"`
+}
+
+func (x *SetInProgressRequest) Reset() {
+	*x = SetInProgressRequest{}
+	mi := &file_describe_v2_describe_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SetInProgressRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SetInProgressRequest) ProtoMessage() {}
+
+func (x *SetInProgressRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_describe_v2_describe_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SetInProgressRequest.ProtoReflect.Descriptor instead.
+func (*SetInProgressRequest) Descriptor() ([]byte, []int) {
+	return file_describe_v2_describe_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *SetInProgressRequest) GetJobId() uint32 {
+	if x != nil {
+		return x.JobId
+	}
+	return 0
+}
+
+var File_describe_v2_describe_proto protoreflect.FileDescriptor
+
+var file_describe_v2_describe_proto_rawDesc = []byte{
+	0x0a, 0x1a, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x2f, 0x76, 0x32, 0x2f, 0x64, 0x65,
+	0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x1a, 0x6f, 0x70,
+	0x65, 0x6e, 0x67, 0x6f, 0x76, 0x65, 0x72, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x2e, 0x64, 0x65, 0x73,
+	0x63, 0x72, 0x69, 0x62, 0x65, 0x2e, 0x76, 0x32, 0x1a, 0x0c, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79,
+	0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x22, 0x5b, 0x0a, 0x0d, 0x44, 0x65, 0x73, 0x63, 0x72, 0x69,
+	0x62, 0x65, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x12, 0x12, 0x0a, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x18,
+	0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x04, 0x63, 0x6f, 0x64, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x6d,
+	0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52, 0x07, 0x6d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x1c, 0x0a, 0x09, 0x72, 0x65, 0x74, 0x72, 0x79, 0x61, 0x62,
+	0x6c, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x08, 0x52, 0x09, 0x72, 0x65, 0x74, 0x72, 0x79, 0x61,
+	0x62, 0x6c, 0x65, 0x22, 0xfa, 0x02, 0x0a, 0x14, 0x44, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x52,
+	0x65, 0x73, 0x75, 0x6c, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x15, 0x0a, 0x06,
+	0x6a, 0x6f, 0x62, 0x5f, 0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x6a, 0x6f,
+	0x62, 0x49, 0x64, 0x12, 0x22, 0x0a, 0x0d, 0x70, 0x61, 0x72, 0x65, 0x6e, 0x74, 0x5f, 0x6a, 0x6f,
+	0x62, 0x5f, 0x69, 0x64, 0x18, 0x02, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0b, 0x70, 0x61, 0x72, 0x65,
+	0x6e, 0x74, 0x4a, 0x6f, 0x62, 0x49, 0x64, 0x12, 0x45, 0x0a, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75,
+	0x73, 0x18, 0x03, 0x20, 0x01, 0x28, 0x0e, 0x32, 0x2d, 0x2e, 0x6f, 0x70, 0x65, 0x6e, 0x67, 0x6f,
+	0x76, 0x65, 0x72, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x2e, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x62,
+	0x65, 0x2e, 0x76, 0x32, 0x2e, 0x44, 0x65, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x4a, 0x6f, 0x62,
+	0x53, 0x74, 0x61, 0x74, 0x75, 0x73, 0x52, 0x06, 0x73, 0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x3f,
+	0x0a, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x29, 0x2e,
+	0x6f, 0x70, 0x65, 0x6e, 0x67, 0x6f, 0x76, 0x65, 0x72, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x2e, 0x64,
+	0x65, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x2e, 0x76, 0x32, 0x2e, 0x44, 0x65, 0x73, 0x63, 0x72,
+	0x69, 0x62, 0x65, 0x45, 0x72, 0x72, 0x6f, 0x72, 0x52, 0x05, 0x65, 0x72, 0x72, 0x6f, 0x72, 0x12,
+	0x48, 0x0a, 0x0c, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x5f, 0x6a, 0x6f, 0x62, 0x18,
+	0x05, 0x20, 0x01, 0x28, 0x0b, 0x32, 0x25, 0x2e, 0x6f, 0x70, 0x65, 0x6e, 0x67, 0x6f, 0x76, 0x65,
+	0x72, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x2e, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x2e, 0x76, 0x31,
+	0x2e, 0x44, 0x65, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x4a, 0x6f, 0x62, 0x52, 0x0b, 0x64, 0x65,
+	0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x4a, 0x6f, 0x62, 0x12, 0x34, 0x0a, 0x16, 0x64, 0x65, 0x73,
+	0x63, 0x72, 0x69, 0x62, 0x65, 0x64, 0x5f, 0x72, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x5f,
+	0x69, 0x64, 0x73, 0x18, 0x06, 0x20, 0x03, 0x28, 0x09, 0x52, 0x14, 0x64, 0x65, 0x73, 0x63, 0x72,
+	0x69, 0x62, 0x65, 0x64, 0x52, 0x65, 0x73, 0x6f, 0x75, 0x72, 0x63, 0x65, 0x49, 0x64, 0x73, 0x12,
+	0x1f, 0x0a, 0x0b, 0x6e, 0x65, 0x78, 0x74, 0x5f, 0x63, 0x75, 0x72, 0x73, 0x6f, 0x72, 0x18, 0x07,
+	0x20, 0x01, 0x28, 0x09, 0x52, 0x0a, 0x6e, 0x65, 0x78, 0x74, 0x43, 0x75, 0x72, 0x73, 0x6f, 0x72,
+	0x22, 0x2d, 0x0a, 0x14, 0x53, 0x65, 0x74, 0x49, 0x6e, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73,
+	0x73, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x15, 0x0a, 0x06, 0x6a, 0x6f, 0x62, 0x5f,
+	0x69, 0x64, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x05, 0x6a, 0x6f, 0x62, 0x49, 0x64, 0x2a,
+	0xc1, 0x01, 0x0a, 0x11, 0x44, 0x65, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x4a, 0x6f, 0x62, 0x53,
+	0x74, 0x61, 0x74, 0x75, 0x73, 0x12, 0x23, 0x0a, 0x1f, 0x44, 0x45, 0x53, 0x43, 0x52, 0x49, 0x42,
+	0x45, 0x5f, 0x4a, 0x4f, 0x42, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x55, 0x4e, 0x53,
+	0x50, 0x45, 0x43, 0x49, 0x46, 0x49, 0x45, 0x44, 0x10, 0x00, 0x12, 0x23, 0x0a, 0x1f, 0x44, 0x45,
+	0x53, 0x43, 0x52, 0x49, 0x42, 0x45, 0x5f, 0x4a, 0x4f, 0x42, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55,
+	0x53, 0x5f, 0x49, 0x4e, 0x5f, 0x50, 0x52, 0x4f, 0x47, 0x52, 0x45, 0x53, 0x53, 0x10, 0x01, 0x12,
+	0x21, 0x0a, 0x1d, 0x44, 0x45, 0x53, 0x43, 0x52, 0x49, 0x42, 0x45, 0x5f, 0x4a, 0x4f, 0x42, 0x5f,
+	0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x53, 0x55, 0x43, 0x43, 0x45, 0x45, 0x44, 0x45, 0x44,
+	0x10, 0x02, 0x12, 0x1e, 0x0a, 0x1a, 0x44, 0x45, 0x53, 0x43, 0x52, 0x49, 0x42, 0x45, 0x5f, 0x4a,
+	0x4f, 0x42, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x46, 0x41, 0x49, 0x4c, 0x45, 0x44,
+	0x10, 0x03, 0x12, 0x1f, 0x0a, 0x1b, 0x44, 0x45, 0x53, 0x43, 0x52, 0x49, 0x42, 0x45, 0x5f, 0x4a,
+	0x4f, 0x42, 0x5f, 0x53, 0x54, 0x41, 0x54, 0x55, 0x53, 0x5f, 0x54, 0x49, 0x4d, 0x45, 0x4f, 0x55,
+	0x54, 0x10, 0x04, 0x32, 0xe7, 0x01, 0x0a, 0x0f, 0x44, 0x65, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65,
+	0x53, 0x65, 0x72, 0x76, 0x69, 0x63, 0x65, 0x12, 0x69, 0x0a, 0x0d, 0x44, 0x65, 0x6c, 0x69, 0x76,
+	0x65, 0x72, 0x52, 0x65, 0x73, 0x75, 0x6c, 0x74, 0x12, 0x30, 0x2e, 0x6f, 0x70, 0x65, 0x6e, 0x67,
+	0x6f, 0x76, 0x65, 0x72, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x2e, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69,
+	0x62, 0x65, 0x2e, 0x76, 0x32, 0x2e, 0x44, 0x65, 0x6c, 0x69, 0x76, 0x65, 0x72, 0x52, 0x65, 0x73,
+	0x75, 0x6c, 0x74, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x24, 0x2e, 0x6f, 0x70, 0x65,
+	0x6e, 0x67, 0x6f, 0x76, 0x65, 0x72, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x2e, 0x65, 0x6e, 0x74, 0x69,
+	0x74, 0x79, 0x2e, 0x76, 0x31, 0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x4f, 0x4b,
+	0x22, 0x00, 0x12, 0x69, 0x0a, 0x0d, 0x53, 0x65, 0x74, 0x49, 0x6e, 0x50, 0x72, 0x6f, 0x67, 0x72,
+	0x65, 0x73, 0x73, 0x12, 0x30, 0x2e, 0x6f, 0x70, 0x65, 0x6e, 0x67, 0x6f, 0x76, 0x65, 0x72, 0x6e,
+	0x61, 0x6e, 0x63, 0x65, 0x2e, 0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x2e, 0x76, 0x32,
+	0x2e, 0x53, 0x65, 0x74, 0x49, 0x6e, 0x50, 0x72, 0x6f, 0x67, 0x72, 0x65, 0x73, 0x73, 0x52, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x1a, 0x24, 0x2e, 0x6f, 0x70, 0x65, 0x6e, 0x67, 0x6f, 0x76, 0x65,
+	0x72, 0x6e, 0x61, 0x6e, 0x63, 0x65, 0x2e, 0x65, 0x6e, 0x74, 0x69, 0x74, 0x79, 0x2e, 0x76, 0x31,
+	0x2e, 0x52, 0x65, 0x73, 0x70, 0x6f, 0x6e, 0x73, 0x65, 0x4f, 0x4b, 0x22, 0x00, 0x42, 0x47, 0x5a,
+	0x45, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e, 0x63, 0x6f, 0x6d, 0x2f, 0x6f, 0x70, 0x65, 0x6e,
+	0x67, 0x6f, 0x76, 0x65, 0x72, 0x6e, 0x2f, 0x6f, 0x67, 0x2d, 0x75, 0x74, 0x69, 0x6c, 0x2f, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x2f, 0x73, 0x72, 0x63, 0x2f, 0x67, 0x6f, 0x6c, 0x61, 0x6e, 0x67, 0x2f,
+	0x64, 0x65, 0x73, 0x63, 0x72, 0x69, 0x62, 0x65, 0x2f, 0x76, 0x32, 0x3b, 0x64, 0x65, 0x73, 0x63,
+	0x72, 0x69, 0x62, 0x65, 0x76, 0x32, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x33,
+}
+
+var (
+	file_describe_v2_describe_proto_rawDescOnce sync.Once
+	file_describe_v2_describe_proto_rawDescData = file_describe_v2_describe_proto_rawDesc
+)
+
+func file_describe_v2_describe_proto_rawDescGZIP() []byte {
+	file_describe_v2_describe_proto_rawDescOnce.Do(func() {
+		file_describe_v2_describe_proto_rawDescData = protoimpl.X.CompressGZIP(file_describe_v2_describe_proto_rawDescData)
+	})
+	return file_describe_v2_describe_proto_rawDescData
+}
+
+var file_describe_v2_describe_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
+var file_describe_v2_describe_proto_msgTypes = make([]protoimpl.MessageInfo, 3)
+var file_describe_v2_describe_proto_goTypes = []any{
+	(DescribeJobStatus)(0),       // 0: opengovernance.describe.v2.DescribeJobStatus
+	(*DescribeError)(nil),        // 1: opengovernance.describe.v2.DescribeError
+	(*DeliverResultRequest)(nil), // 2: opengovernance.describe.v2.DeliverResultRequest
+	(*SetInProgressRequest)(nil), // 3: opengovernance.describe.v2.SetInProgressRequest
+	(*golang.DescribeJob)(nil),   // 4: opengovernance.entity.v1.DescribeJob
+	(*golang.ResponseOK)(nil),    // 5: opengovernance.entity.v1.ResponseOK
+}
+var file_describe_v2_describe_proto_depIdxs = []int32{
+	0, // 0: opengovernance.describe.v2.DeliverResultRequest.status:type_name -> opengovernance.describe.v2.DescribeJobStatus
+	1, // 1: opengovernance.describe.v2.DeliverResultRequest.error:type_name -> opengovernance.describe.v2.DescribeError
+	4, // 2: opengovernance.describe.v2.DeliverResultRequest.describe_job:type_name -> opengovernance.entity.v1.DescribeJob
+	2, // 3: opengovernance.describe.v2.DescribeService.DeliverResult:input_type -> opengovernance.describe.v2.DeliverResultRequest
+	3, // 4: opengovernance.describe.v2.DescribeService.SetInProgress:input_type -> opengovernance.describe.v2.SetInProgressRequest
+	5, // 5: opengovernance.describe.v2.DescribeService.DeliverResult:output_type -> opengovernance.entity.v1.ResponseOK
+	5, // 6: opengovernance.describe.v2.DescribeService.SetInProgress:output_type -> opengovernance.entity.v1.ResponseOK
+	5, // [5:7] is the sub-list for method output_type
+	3, // [3:5] is the sub-list for method input_type
+	3, // [3:3] is the sub-list for extension type_name
+	3, // [3:3] is the sub-list for extension extendee
+	0, // [0:3] is the sub-list for field type_name
+}
+
+func init() { file_describe_v2_describe_proto_init() }
+func file_describe_v2_describe_proto_init() {
+	if File_describe_v2_describe_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_describe_v2_describe_proto_rawDesc,
+			NumEnums:      1,
+			NumMessages:   3,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_describe_v2_describe_proto_goTypes,
+		DependencyIndexes: file_describe_v2_describe_proto_depIdxs,
+		EnumInfos:         file_describe_v2_describe_proto_enumTypes,
+		MessageInfos:      file_describe_v2_describe_proto_msgTypes,
+	}.Build()
+	File_describe_v2_describe_proto = out.File
+	file_describe_v2_describe_proto_rawDesc = nil
+	file_describe_v2_describe_proto_goTypes = nil
+	file_describe_v2_describe_proto_depIdxs = nil
+}
@@ -0,0 +1,167 @@
+// Package describe maps describe API versions to their generated packages and
+// negotiates which version a describer and the scheduler should speak at runtime.
+package describe
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	golang "github.com/opengovern/og-util/proto/src/golang"
+	describev2 "github.com/opengovern/og-util/proto/src/golang/describe/v2"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Version is a describe API version, e.g. "v1".
+type Version string
+
+const (
+	V1 Version = "v1"
+	V2 Version = "v2"
+)
+
+// VersionMetadataKey is the gRPC metadata key a peer uses to advertise the describe
+// versions it supports, as a comma-separated list.
+const VersionMetadataKey = "og-describe-versions"
+
+// VersionInfo describes one registered describe API version.
+type VersionInfo struct {
+	Version     Version
+	ServiceName string
+	File        protoreflect.FileDescriptor
+}
+
+var registry = map[Version]VersionInfo{
+	V1: {Version: V1, ServiceName: golang.DescribeService_ServiceDesc.ServiceName, File: golang.File_describe_proto},
+	V2: {Version: V2, ServiceName: describev2.DescribeService_ServiceDesc.ServiceName, File: describev2.File_describe_v2_describe_proto},
+}
+
+// Lookup returns the registered info for v.
+func Lookup(v Version) (VersionInfo, bool) {
+	info, ok := registry[v]
+	return info, ok
+}
+
+// Supported returns all registered versions, newest first.
+func Supported() []Version {
+	versions := make([]Version, 0, len(registry))
+	for v := range registry {
+		versions = append(versions, v)
+	}
+	sortNewestFirst(versions)
+	return versions
+}
+
+// Negotiate returns the newest version present in both local and remote.
+func Negotiate(local, remote []Version) (Version, error) {
+	remoteSet := make(map[Version]bool, len(remote))
+	for _, v := range remote {
+		remoteSet[v] = true
+	}
+	candidates := append([]Version(nil), local...)
+	sortNewestFirst(candidates)
+	for _, v := range candidates {
+		if _, ok := registry[v]; ok && remoteSet[v] {
+			return v, nil
+		}
+	}
+	return "", fmt.Errorf("no common describe version: local %v, remote %v", local, remote)
+}
+
+// WithSupportedVersions advertises versions to the server on outgoing gRPC calls made
+// with the returned context.
+func WithSupportedVersions(ctx context.Context, versions ...Version) context.Context {
+	values := make([]string, 0, len(versions))
+	for _, v := range versions {
+		values = append(values, string(v))
+	}
+	return metadata.AppendToOutgoingContext(ctx, VersionMetadataKey, strings.Join(values, ","))
+}
+
+// RemoteVersions returns the versions the caller advertised in incoming gRPC metadata.
+// Describers that predate versioning send nothing and are treated as V1-only.
+func RemoteVersions(ctx context.Context) []Version {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok || len(md.Get(VersionMetadataKey)) == 0 {
+		return []Version{V1}
+	}
+	var versions []Version
+	for _, value := range md.Get(VersionMetadataKey) {
+		for _, v := range strings.Split(value, ",") {
+			if v = strings.TrimSpace(v); v != "" {
+				versions = append(versions, Version(v))
+			}
+		}
+	}
+	return versions
+}
+
+// NegotiateFromContext negotiates between local and the versions advertised in the
+// incoming gRPC metadata of ctx.
+func NegotiateFromContext(ctx context.Context, local ...Version) (Version, error) {
+	return Negotiate(local, RemoteVersions(ctx))
+}
+
+func sortNewestFirst(versions []Version) {
+	sort.Slice(versions, func(i, j int) bool {
+		return versionNumber(versions[i]) > versionNumber(versions[j])
+	})
+}
+
+func versionNumber(v Version) int {
+	n, err := strconv.Atoi(strings.TrimPrefix(string(v), "v"))
+	if err != nil {
+		return -1
+	}
+	return n
+}
+
+// v1 statuses are free-form strings; these are the values describers send today.
+var v1StatusToV2 = map[string]describev2.DescribeJobStatus{
+	"IN_PROGRESS": describev2.DescribeJobStatus_DESCRIBE_JOB_STATUS_IN_PROGRESS,
+	"SUCCEEDED":   describev2.DescribeJobStatus_DESCRIBE_JOB_STATUS_SUCCEEDED,
+	"FAILED":      describev2.DescribeJobStatus_DESCRIBE_JOB_STATUS_FAILED,
+	"TIMEOUT":     describev2.DescribeJobStatus_DESCRIBE_JOB_STATUS_TIMEOUT,
+}
+
+// UpgradeDeliverResult converts a v1 DeliverResultRequest to v2, so servers can handle
+// both versions with a single implementation. Unknown statuses map to UNSPECIFIED.
+func UpgradeDeliverResult(in *golang.DeliverResultRequest) *describev2.DeliverResultRequest {
+	if in == nil {
+		return nil
+	}
+	out := &describev2.DeliverResultRequest{
+		JobId:                in.GetJobId(),
+		ParentJobId:          in.GetParentJobId(),
+		Status:               v1StatusToV2[strings.ToUpper(in.GetStatus())],
+		DescribeJob:          in.GetDescribeJob(),
+		DescribedResourceIds: in.GetDescribedResourceIds(),
+	}
+	if in.GetError() != "" || in.GetErrorCode() != "" {
+		out.Error = &describev2.DescribeError{Code: in.GetErrorCode(), Message: in.GetError()}
+	}
+	return out
+}
+
+// DowngradeDeliverResult converts a v2 DeliverResultRequest to v1 for servers that only
+// speak v1. The retryable flag has no v1 equivalent and is dropped.
+func DowngradeDeliverResult(in *describev2.DeliverResultRequest) *golang.DeliverResultRequest {
+	if in == nil {
+		return nil
+	}
+	out := &golang.DeliverResultRequest{
+		JobId:                in.GetJobId(),
+		ParentJobId:          in.GetParentJobId(),
+		DescribeJob:          in.GetDescribeJob(),
+		DescribedResourceIds: in.GetDescribedResourceIds(),
+		Error:                in.GetError().GetMessage(),
+		ErrorCode:            in.GetError().GetCode(),
+	}
+	if in.GetStatus() != describev2.DescribeJobStatus_DESCRIBE_JOB_STATUS_UNSPECIFIED {
+		out.Status = strings.TrimPrefix(in.GetStatus().String(), "DESCRIBE_JOB_STATUS_")
+	}
+	return out
+}
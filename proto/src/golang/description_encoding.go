@@ -0,0 +1,370 @@
+package golang
+
+import (
+	"bytes"
+	"compress/gzip"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/klauspost/compress/zstd"
+	"google.golang.org/protobuf/proto"
+)
+
+// Supported values of the content_encoding field on resource messages. An empty
+// content_encoding means the description is carried uncompressed in description_json.
+const (
+	ContentEncodingIdentity = "identity"
+	ContentEncodingGzip     = "gzip"
+	ContentEncodingZstd     = "zstd"
+)
+
+// MaxDescriptionBytes is the largest description DescriptionJSON decompresses; larger
+// payloads fail with ErrDescriptionTooLarge instead of exhausting memory.
+const MaxDescriptionBytes = 64 << 20
+
+// ErrDescriptionTooLarge is returned for descriptions above MaxDescriptionBytes.
+var ErrDescriptionTooLarge = errors.New("description too large")
+
+// DescribedResource is implemented by resource messages that carry a description_json
+// payload (AWSResource, AzureResource).
+type DescribedResource interface {
+	proto.Message
+	GetDescriptionJson() string
+	GetDescriptionPayload() []byte
+	GetContentEncoding() string
+	GetChunk() *DescriptionChunk
+	setDescription(descriptionJSON string, payload []byte, encoding string)
+	setChunk(chunk *DescriptionChunk)
+}
+
+func (x *AWSResource) setDescription(descriptionJSON string, payload []byte, encoding string) {
+	x.DescriptionJson, x.DescriptionPayload, x.ContentEncoding = descriptionJSON, payload, encoding
+}
+
+func (x *AWSResource) setChunk(chunk *DescriptionChunk) { x.Chunk = chunk }
+
+func (x *AzureResource) setDescription(descriptionJSON string, payload []byte, encoding string) {
+	x.DescriptionJson, x.DescriptionPayload, x.ContentEncoding = descriptionJSON, payload, encoding
+}
+
+func (x *AzureResource) setChunk(chunk *DescriptionChunk) { x.Chunk = chunk }
+
+// CompressDescription moves the resource's description_json into description_payload,
+// compressed with encoding, if it is at least minSize bytes. Smaller descriptions are
+// left untouched, since compression would not pay for itself.
+func CompressDescription[T DescribedResource](r T, encoding string, minSize int) error {
+	if r.GetContentEncoding() != "" {
+		return fmt.Errorf("description is already encoded with %s", r.GetContentEncoding())
+	}
+	description := r.GetDescriptionJson()
+	if len(description) < minSize {
+		return nil
+	}
+	payload, err := encodePayload([]byte(description), encoding)
+	if err != nil {
+		return err
+	}
+	r.setDescription("", payload, encoding)
+	return nil
+}
+
+// DescriptionJSON returns the resource's description, decompressing description_payload
+// when content_encoding is set. It must be called on a complete (reassembled) resource.
+func DescriptionJSON[T DescribedResource](r T) (string, error) {
+	if r.GetChunk() != nil && r.GetChunk().GetTotal() > 1 {
+		return "", fmt.Errorf("resource is chunk %d of %d; reassemble it first", r.GetChunk().GetSequence()+1, r.GetChunk().GetTotal())
+	}
+	if r.GetContentEncoding() == "" {
+		return r.GetDescriptionJson(), nil
+	}
+	data, err := decodePayload(r.GetDescriptionPayload(), r.GetContentEncoding(), MaxDescriptionBytes)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// SplitIntoChunks splits the resource's description_payload into messages whose payload
+// is at most maxChunkBytes. Every chunk carries the resource's other fields. A description
+// still in description_json is first moved to the payload with the identity encoding.
+// Resources that fit are returned as a single, unchunked message.
+func SplitIntoChunks[T DescribedResource](r T, maxChunkBytes int) ([]T, error) {
+	if maxChunkBytes <= 0 {
+		return nil, fmt.Errorf("invalid max chunk size: %d", maxChunkBytes)
+	}
+	if r.GetChunk() != nil {
+		return nil, fmt.Errorf("resource is already chunked")
+	}
+	if r.GetContentEncoding() == "" {
+		if len(r.GetDescriptionJson()) <= maxChunkBytes {
+			return []T{r}, nil
+		}
+		r = proto.Clone(r).(T)
+		r.setDescription("", []byte(r.GetDescriptionJson()), ContentEncodingIdentity)
+	}
+
+	payload := r.GetDescriptionPayload()
+	if len(payload) <= maxChunkBytes {
+		return []T{r}, nil
+	}
+
+	streamID, err := newStreamID()
+	if err != nil {
+		return nil, err
+	}
+	total := (len(payload) + maxChunkBytes - 1) / maxChunkBytes
+	chunks := make([]T, 0, total)
+	for i := 0; i < total; i++ {
+		end := min((i+1)*maxChunkBytes, len(payload))
+		chunk := proto.Clone(r).(T)
+		chunk.setDescription("", payload[i*maxChunkBytes:end], r.GetContentEncoding())
+		chunk.setChunk(&DescriptionChunk{StreamId: streamID, Sequence: uint32(i), Total: uint32(total)})
+		chunks = append(chunks, chunk)
+	}
+	return chunks, nil
+}
+
+// ChunkAssemblerOptions bounds the memory of a ChunkAssembler. Zero fields use the
+// defaults below.
+type ChunkAssemblerOptions struct {
+	// StreamTTL is how long a stream may wait for its missing chunks, counted from its
+	// first chunk; DefaultChunkStreamTTL if zero.
+	StreamTTL time.Duration
+	// MaxPendingStreams is the number of incomplete streams kept; when a new stream
+	// exceeds it the oldest one is dropped. DefaultMaxPendingStreams if zero.
+	MaxPendingStreams int
+	// MaxBufferedBytes caps the payload bytes held across incomplete streams; the oldest
+	// streams are dropped to make room. DefaultMaxBufferedBytes if zero.
+	MaxBufferedBytes int
+}
+
+const (
+	DefaultChunkStreamTTL    = 5 * time.Minute
+	DefaultMaxPendingStreams = 1024
+	DefaultMaxBufferedBytes  = 256 << 20
+)
+
+// ChunkAssembler reassembles resources split by SplitIntoChunks on the receiving side.
+// Chunks may arrive in any order. Streams whose chunks never all arrive are dropped
+// according to its ChunkAssemblerOptions. It is safe for concurrent use.
+type ChunkAssembler[T DescribedResource] struct {
+	mu       sync.Mutex
+	options  ChunkAssemblerOptions
+	streams  map[string]*chunkStream[T]
+	buffered int
+	evicted  int
+	now      func() time.Time
+}
+
+type chunkStream[T DescribedResource] struct {
+	parts   map[uint32]T
+	total   uint32
+	size    int
+	started time.Time
+}
+
+func NewChunkAssembler[T DescribedResource]() *ChunkAssembler[T] {
+	return NewChunkAssemblerWithOptions[T](ChunkAssemblerOptions{})
+}
+
+func NewChunkAssemblerWithOptions[T DescribedResource](options ChunkAssemblerOptions) *ChunkAssembler[T] {
+	if options.StreamTTL <= 0 {
+		options.StreamTTL = DefaultChunkStreamTTL
+	}
+	if options.MaxPendingStreams <= 0 {
+		options.MaxPendingStreams = DefaultMaxPendingStreams
+	}
+	if options.MaxBufferedBytes <= 0 {
+		options.MaxBufferedBytes = DefaultMaxBufferedBytes
+	}
+	return &ChunkAssembler[T]{
+		options: options,
+		streams: make(map[string]*chunkStream[T]),
+		now:     time.Now,
+	}
+}
+
+// Add records r and returns the reassembled resource and true once all chunks of its
+// stream have arrived. Unchunked resources are returned immediately.
+func (a *ChunkAssembler[T]) Add(r T) (T, bool, error) {
+	var zero T
+	chunk := r.GetChunk()
+	if chunk == nil {
+		return r, true, nil
+	}
+	if chunk.GetStreamId() == "" || chunk.GetTotal() == 0 || chunk.GetSequence() >= chunk.GetTotal() {
+		return zero, false, fmt.Errorf("invalid chunk info: stream=%q sequence=%d total=%d", chunk.GetStreamId(), chunk.GetSequence(), chunk.GetTotal())
+	}
+	size := len(r.GetDescriptionPayload())
+	if size > a.options.MaxBufferedBytes {
+		return zero, false, fmt.Errorf("chunk of %d bytes for stream %s exceeds the %d bytes buffer", size, chunk.GetStreamId(), a.options.MaxBufferedBytes)
+	}
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+
+	now := a.now()
+	a.evictExpired(now)
+	stream, ok := a.streams[chunk.GetStreamId()]
+	if !ok {
+		if len(a.streams) >= a.options.MaxPendingStreams {
+			a.evictOldest()
+		}
+		stream = &chunkStream[T]{parts: make(map[uint32]T), total: chunk.GetTotal(), started: now}
+		a.streams[chunk.GetStreamId()] = stream
+	} else if stream.total != chunk.GetTotal() {
+		return zero, false, fmt.Errorf("chunk total %d for stream %s does not match earlier chunks (%d)", chunk.GetTotal(), chunk.GetStreamId(), stream.total)
+	}
+	if previous, ok := stream.parts[chunk.GetSequence()]; ok {
+		stream.size -= len(previous.GetDescriptionPayload())
+		a.buffered -= len(previous.GetDescriptionPayload())
+	}
+	stream.parts[chunk.GetSequence()] = r
+	stream.size += size
+	a.buffered += size
+	for a.buffered > a.options.MaxBufferedBytes {
+		if a.evictOldest() == chunk.GetStreamId() {
+			return zero, false, fmt.Errorf("stream %s exceeds the %d bytes buffer", chunk.GetStreamId(), a.options.MaxBufferedBytes)
+		}
+	}
+	if len(stream.parts) < int(stream.total) {
+		return zero, false, nil
+	}
+	a.remove(chunk.GetStreamId())
+
+	payload := make([]byte, 0, stream.size)
+	for i := uint32(0); i < stream.total; i++ {
+		payload = append(payload, stream.parts[i].GetDescriptionPayload()...)
+	}
+	assembled := proto.Clone(stream.parts[0]).(T)
+	assembled.setChunk(nil)
+	if assembled.GetContentEncoding() == ContentEncodingIdentity {
+		assembled.setDescription(string(payload), nil, "")
+	} else {
+		assembled.setDescription("", payload, assembled.GetContentEncoding())
+	}
+	return assembled, true, nil
+}
+
+// Pending returns the number of streams with chunks still outstanding.
+func (a *ChunkAssembler[T]) Pending() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return len(a.streams)
+}
+
+// Evicted returns the number of incomplete streams dropped so far.
+func (a *ChunkAssembler[T]) Evicted() int {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	return a.evicted
+}
+
+func (a *ChunkAssembler[T]) evictExpired(now time.Time) {
+	for id, stream := range a.streams {
+		if now.Sub(stream.started) >= a.options.StreamTTL {
+			a.remove(id)
+			a.evicted++
+		}
+	}
+}
+
+// evictOldest drops the stream that started first and returns its id.
+func (a *ChunkAssembler[T]) evictOldest() string {
+	var oldest string
+	for id, stream := range a.streams {
+		if oldest == "" || stream.started.Before(a.streams[oldest].started) {
+			oldest = id
+		}
+	}
+	if oldest != "" {
+		a.remove(oldest)
+		a.evicted++
+	}
+	return oldest
+}
+
+func (a *ChunkAssembler[T]) remove(id string) {
+	a.buffered -= a.streams[id].size
+	delete(a.streams, id)
+}
+
+func encodePayload(data []byte, encoding string) ([]byte, error) {
+	switch encoding {
+	case ContentEncodingIdentity:
+		return data, nil
+	case ContentEncodingGzip:
+		var buf bytes.Buffer
+		w := gzip.NewWriter(&buf)
+		if _, err := w.Write(data); err != nil {
+			return nil, fmt.Errorf("gzip description: %w", err)
+		}
+		if err := w.Close(); err != nil {
+			return nil, fmt.Errorf("gzip description: %w", err)
+		}
+		return buf.Bytes(), nil
+	case ContentEncodingZstd:
+		encoder, err := zstd.NewWriter(nil)
+		if err != nil {
+			return nil, fmt.Errorf("zstd description: %w", err)
+		}
+		defer encoder.Close()
+		return encoder.EncodeAll(data, nil), nil
+	default:
+		return nil, fmt.Errorf("unsupported content encoding: %q", encoding)
+	}
+}
+
+// decodePayload decompresses data, failing with ErrDescriptionTooLarge if the result
+// exceeds limit bytes.
+func decodePayload(data []byte, encoding string, limit int) ([]byte, error) {
+	switch encoding {
+	case ContentEncodingIdentity:
+		if len(data) > limit {
+			return nil, fmt.Errorf("%w: over %d bytes", ErrDescriptionTooLarge, limit)
+		}
+		return data, nil
+	case ContentEncodingGzip:
+		r, err := gzip.NewReader(bytes.NewReader(data))
+		if err != nil {
+			return nil, fmt.Errorf("gunzip description: %w", err)
+		}
+		defer r.Close()
+		out, err := io.ReadAll(io.LimitReader(r, int64(limit)+1))
+		if err != nil {
+			return nil, fmt.Errorf("gunzip description: %w", err)
+		}
+		if len(out) > limit {
+			return nil, fmt.Errorf("%w: over %d bytes", ErrDescriptionTooLarge, limit)
+		}
+		return out, nil
+	case ContentEncodingZstd:
+		decoder, err := zstd.NewReader(nil, zstd.WithDecoderMaxMemory(uint64(limit)))
+		if err != nil {
+			return nil, fmt.Errorf("zstd description: %w", err)
+		}
+		defer decoder.Close()
+		out, err := decoder.DecodeAll(data, nil)
+		if errors.Is(err, zstd.ErrDecoderSizeExceeded) || errors.Is(err, zstd.ErrWindowSizeExceeded) || len(out) > limit {
+			return nil, fmt.Errorf("%w: over %d bytes", ErrDescriptionTooLarge, limit)
+		} else if err != nil {
+			return nil, fmt.Errorf("zstd description: %w", err)
+		}
+		return out, nil
+	default:
+		return nil, fmt.Errorf("unsupported content encoding: %q", encoding)
+	}
+}
+
+func newStreamID() (string, error) {
+	b := make([]byte, 16)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("generate chunk stream id: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
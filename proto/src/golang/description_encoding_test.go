@@ -0,0 +1,102 @@
+package golang
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestChunkAssemblerRoundTrip(t *testing.T) {
+	r := require.New(t)
+
+	description := `{"name":"` + strings.Repeat("x", 100) + `"}`
+	chunks, err := SplitIntoChunks(&AWSResource{UniqueId: "a", DescriptionJson: description}, 16)
+	r.NoError(err)
+	r.Greater(len(chunks), 1)
+
+	a := NewChunkAssembler[*AWSResource]()
+	for i := len(chunks) - 1; i > 0; i-- {
+		_, done, err := a.Add(chunks[i])
+		r.NoError(err)
+		r.False(done)
+	}
+	assembled, done, err := a.Add(chunks[0])
+	r.NoError(err)
+	r.True(done)
+	r.Equal("a", assembled.GetUniqueId())
+	r.Equal(description, assembled.GetDescriptionJson())
+	r.Zero(a.Pending())
+}
+
+func TestChunkAssemblerEviction(t *testing.T) {
+	r := require.New(t)
+
+	now := time.Now()
+	a := NewChunkAssemblerWithOptions[*AWSResource](ChunkAssemblerOptions{
+		StreamTTL:         time.Minute,
+		MaxPendingStreams: 2,
+		MaxBufferedBytes:  100,
+	})
+	a.now = func() time.Time { return now }
+	chunk := func(stream string, sequence uint32, payload []byte) *AWSResource {
+		return &AWSResource{
+			DescriptionPayload: payload,
+			ContentEncoding:    ContentEncodingIdentity,
+			Chunk:              &DescriptionChunk{StreamId: stream, Sequence: sequence, Total: 2},
+		}
+	}
+	add := func(res *AWSResource) {
+		_, done, err := a.Add(res)
+		r.NoError(err)
+		r.False(done)
+	}
+
+	// Expired streams are dropped
+	add(chunk("s1", 0, []byte("a")))
+	now = now.Add(time.Minute)
+	add(chunk("s2", 0, []byte("b")))
+	r.Equal(1, a.Pending())
+	r.Equal(1, a.Evicted())
+
+	// A stream beyond MaxPendingStreams drops the oldest one
+	now = now.Add(time.Second)
+	add(chunk("s3", 0, []byte("c")))
+	now = now.Add(time.Second)
+	add(chunk("s4", 0, []byte("d")))
+	r.Equal(2, a.Pending())
+	r.Equal(2, a.Evicted())
+	_, done, err := a.Add(chunk("s2", 1, []byte("b")))
+	r.NoError(err)
+	r.False(done, "s2 was evicted and starts over")
+
+	// Buffered bytes beyond MaxBufferedBytes drop the oldest streams
+	now = now.Add(time.Second)
+	add(chunk("s5", 0, bytes.Repeat([]byte("e"), 100)))
+	r.Equal(1, a.Pending())
+	r.LessOrEqual(a.buffered, 100)
+
+	// A single stream larger than the buffer is refused
+	_, _, err = a.Add(chunk("s5", 1, []byte("e")))
+	r.ErrorContains(err, "exceeds the 100 bytes buffer")
+	r.Zero(a.Pending())
+	_, _, err = a.Add(chunk("s6", 0, bytes.Repeat([]byte("f"), 101)))
+	r.ErrorContains(err, "exceeds the 100 bytes buffer")
+}
+
+func TestDecodePayloadLimit(t *testing.T) {
+	r := require.New(t)
+
+	data := bytes.Repeat([]byte("a"), 1<<20)
+	for _, encoding := range []string{ContentEncodingIdentity, ContentEncodingGzip, ContentEncodingZstd} {
+		payload, err := encodePayload(data, encoding)
+		r.NoError(err)
+		out, err := decodePayload(payload, encoding, len(data))
+		r.NoError(err, encoding)
+		r.Equal(data, out)
+		_, err = decodePayload(payload, encoding, len(data)-1)
+		r.ErrorIs(err, ErrDescriptionTooLarge, encoding)
+	}
+}
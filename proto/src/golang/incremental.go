@@ -0,0 +1,20 @@
+package golang
+
+import "google.golang.org/protobuf/proto"
+
+// NewIncrementalDescribeJob returns a copy of job that only describes changes since
+// lastCursor, the next_cursor reported by the previous run. An empty lastCursor yields a
+// full describe, which is how a provider's first run establishes its baseline.
+func NewIncrementalDescribeJob(job *DescribeJob, lastCursor string) *DescribeJob {
+	out := proto.Clone(job).(*DescribeJob)
+	out.LastCursor = lastCursor
+	out.ChangesOnly = lastCursor != ""
+	return out
+}
+
+// IsIncremental reports whether the job asks for changes since a cursor only. Describers
+// that do not support incremental runs must treat such a job as a full describe and
+// report no next cursor.
+func (x *DescribeJob) IsIncremental() bool {
+	return x.GetChangesOnly() && x.GetLastCursor() != ""
+}
@@ -1,8 +1,15 @@
 currentDir=$(pwd)
 cd proto || exit 1
-protoc --go_out=src/golang --go_opt=paths=source_relative \
-    --go-grpc_out=src/golang --go-grpc_opt=paths=source_relative \
-    ./*.proto
+if command -v buf >/dev/null 2>&1; then
+  # Fail on lint errors or wire-incompatible changes against main before generating
+  buf lint || exit 1
+  buf breaking --against "$(git rev-parse --show-toplevel)/.git#branch=main,subdir=proto" || exit 1
+  buf generate || exit 1
+else
+  protoc -I . --go_out=src/golang --go_opt=paths=source_relative \
+      --go-grpc_out=src/golang --go-grpc_opt=paths=source_relative \
+      ./*.proto describe/v2/*.proto
+fi
 #mv src/golang/proto/* proto/src/golang/
 #rm -rf src/golang/proto
-cd "$currentDir" || exit 1
\ No newline at end of file
+cd "$currentDir" || exit 1